@@ -98,6 +98,10 @@ func (l logger) MsgHandled(_ llrp.Header) {
 func (l logger) MsgUnhandled(_ llrp.Header) {
 }
 
+func (l logger) MsgUnmatched(header llrp.Header) {
+	l.errlg.Printf("reply doesn't match an outstanding request: %+v", header)
+}
+
 func (l logger) HandlerPanic(header llrp.Header, err error) {
 	l.errlg.Printf("handler panic on %+v: %+v", header, err)
 }
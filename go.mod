@@ -3,9 +3,11 @@ module github.com/edgexfoundry-holding/device-rfid-llrp-go
 go 1.15
 
 require (
+	github.com/eclipse/paho.mqtt.golang v1.2.0
 	github.com/edgexfoundry/device-sdk-go v1.2.2
 	github.com/edgexfoundry/go-mod-bootstrap v0.0.33
 	github.com/edgexfoundry/go-mod-configuration v0.0.3
 	github.com/edgexfoundry/go-mod-core-contracts v0.1.58
+	github.com/fxamacker/cbor/v2 v2.2.0
 	github.com/pkg/errors v0.9.1
 )
@@ -0,0 +1,135 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"strconv"
+)
+
+// How the JSON the service emits represents optional LLRP parameters a Reader didn't send,
+// selected by the AbsentFields driver configuration.
+//
+// The LLRP library decodes optional parameters as pointers (or slices, if they may repeat),
+// which are nil when the parameter is absent, so a present parameter with a zero value
+// is always distinguishable from an absent one.
+const (
+	AbsentFieldsNull = "null" // include absent parameters' fields as null
+	AbsentFieldsOmit = "omit" // omit absent parameters' fields entirely
+)
+
+// checkAbsentFields returns an error if policy isn't a known AbsentFields policy.
+func checkAbsentFields(policy string) error {
+	switch policy {
+	case AbsentFieldsNull, AbsentFieldsOmit:
+		return nil
+	default:
+		return errors.Errorf("unknown absent fields policy %q; policies are %s or %s",
+			policy, AbsentFieldsNull, AbsentFieldsOmit)
+	}
+}
+
+// marshalJSON marshals v to JSON, omitting object fields that are null if omitNulls is set.
+func marshalJSON(v interface{}, omitNulls bool) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || !omitNulls {
+		return data, err
+	}
+	return omitNullFields(data)
+}
+
+// marshalJSON marshals v to JSON according to the configured AbsentFields policy.
+func (d *Driver) marshalJSON(v interface{}) ([]byte, error) {
+	return marshalJSON(v, d.omitAbsentFields())
+}
+
+// omitAbsentFields returns true if the configured AbsentFields policy omits them.
+func (d *Driver) omitAbsentFields() bool {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+	return d.config != nil && d.config.AbsentFields == AbsentFieldsOmit
+}
+
+// omitNullFields returns the JSON without the object fields whose values are null,
+// at any depth, leaving the rest of it, including nulls in arrays, in order.
+func omitNullFields(data []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	type container struct {
+		object bool // true for an object; false for an array
+		empty  bool // true until a value is written to it
+	}
+
+	out := make([]byte, 0, len(data))
+	var open []container // the objects and arrays that are open, innermost last
+	var key []byte       // the encoded name of an object field awaiting its value
+	for {
+		tok, err := d.Token()
+		if err == io.EOF && len(open) == 0 {
+			return out, nil
+		}
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if delim, ok := tok.(json.Delim); ok && (delim == '}' || delim == ']') {
+			open = open[:len(open)-1]
+			out = append(out, byte(delim))
+			continue
+		}
+
+		inObject := len(open) != 0 && open[len(open)-1].object
+		if inObject && key == nil {
+			// The decoder only returns field names as strings.
+			if key, err = json.Marshal(tok.(string)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if tok == nil && inObject {
+			key = nil
+			continue
+		}
+
+		if len(open) != 0 {
+			if !open[len(open)-1].empty {
+				out = append(out, ',')
+			}
+			open[len(open)-1].empty = false
+		}
+		if key != nil {
+			out = append(out, key...)
+			out = append(out, ':')
+			key = nil
+		}
+
+		switch tok := tok.(type) {
+		case json.Delim:
+			open = append(open, container{object: tok == '{', empty: true})
+			out = append(out, byte(tok))
+		case string:
+			s, err := json.Marshal(tok)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, s...)
+		case json.Number:
+			out = append(out, tok...)
+		case bool:
+			out = strconv.AppendBool(out, tok)
+		case nil:
+			out = append(out, "null"...)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strings"
+	"testing"
+)
+
+func TestOmitNullFields(t *testing.T) {
+	for _, testCase := range []struct {
+		in, expected string
+	}{
+		{in: `{}`, expected: `{}`},
+		{in: `null`, expected: `null`},
+		{in: `{"a":null}`, expected: `{}`},
+		{in: `{"a":null,"b":0,"c":null}`, expected: `{"b":0}`},
+		{in: `{"a":1,"b":null,"c":false}`, expected: `{"a":1,"c":false}`},
+		{in: `{"a":{"b":null,"c":{"d":null}},"e":[]}`, expected: `{"a":{"c":{}},"e":[]}`},
+		{in: `[null,{"a":null},[null]]`, expected: `[null,{},[null]]`},
+		{in: `{"z":18446744073709551615,"y":-1.5e-7,"x":"\u003cnull\u003e","w":"\"null\""}`,
+			expected: `{"z":18446744073709551615,"y":-1.5e-7,"x":"\u003cnull\u003e","w":"\"null\""}`},
+	} {
+		got, err := omitNullFields([]byte(testCase.in))
+		if err != nil {
+			t.Errorf("%s: %+v", testCase.in, err)
+			continue
+		}
+		if string(got) != testCase.expected {
+			t.Errorf("%s: expected %s; got %s", testCase.in, testCase.expected, got)
+		}
+	}
+
+	for _, bad := range []string{`{"a":`, `[1,2`, `{"a" 1}`} {
+		if _, err := omitNullFields([]byte(bad)); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
+
+func TestMarshalJSON_absentParameters(t *testing.T) {
+	zero := llrp.ROSpecID(0)
+	tr := llrp.TagReportData{
+		EPC96:    llrp.EPC96{EPC: make([]byte, 12)},
+		ROSpecID: &zero, // present, but zero
+	}
+
+	data, err := marshalJSON(tr, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"C1G2PC":null`) {
+		t.Errorf("expected absent parameters to be null by default; got %s", data)
+	}
+
+	data, err = marshalJSON(tr, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "null") {
+		t.Errorf("expected absent parameters to be omitted; got %s", data)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := fields["ROSpecID"]; !ok || string(id) != "0" {
+		t.Errorf("expected the zero ROSpecID to be present; got %s", data)
+	}
+	if _, ok := fields["C1G2PC"]; ok {
+		t.Errorf("expected the absent C1G2PC to be omitted; got %s", data)
+	}
+}
+
+func TestReportCodec_omitNulls(t *testing.T) {
+	report := testReport(3)
+
+	for _, encoding := range []string{ReportEncodingJSON, ReportEncodingCBOR} {
+		withNulls, err := newReportCodec(encoding, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		withoutNulls, err := newReportCodec(encoding, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		full, err := withNulls.encode(1, report)
+		if err != nil {
+			t.Fatal(err)
+		}
+		omitted, err := withoutNulls.encode(1, report)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fullSize := len(full.BinValue) + len(full.ValueToString())
+		omittedSize := len(omitted.BinValue) + len(omitted.ValueToString())
+		if omittedSize >= fullSize {
+			t.Errorf("%s: expected omitting nulls to shrink the report; got %d >= %d bytes",
+				encoding, omittedSize, fullSize)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"strconv"
+)
+
+// accessStopFields are the optional fields a request may include alongside an AccessSpec
+// to set its stop trigger without spelling out the AccessSpecStopTrigger.
+type accessStopFields struct {
+	// StopAfter sets the AccessSpec to stop after its OpSpecs run this many times;
+	// use 1 for a one-shot operation. Without it, the AccessSpec's Trigger is used as-is.
+	StopAfter *uint16
+}
+
+// describedAccessSpec is an AccessSpec with its state and stop trigger spelled out.
+type describedAccessSpec struct {
+	llrp.AccessSpec
+	// State is the AccessSpec's IsActive as a number, like an ROSpec's ROSpecCurrentState,
+	// and StateName is its name: Disabled or Active.
+	State     llrp.AccessSpecState
+	StateName string
+	// StopTrigger is None if the AccessSpec runs until it's deleted,
+	// or OperationCount if it stops after its OpSpecs run OperationCount times.
+	StopTrigger    string
+	OperationCount *uint16 `json:",omitempty"`
+}
+
+// describedAccessSpecs is a GetAccessSpecsResponse with its AccessSpecs' states and stop triggers spelled out.
+type describedAccessSpecs struct {
+	LLRPStatus  llrp.LLRPStatus
+	AccessSpecs []describedAccessSpec
+}
+
+// accessStopTriggerName returns the name of an AccessSpecStopTriggerType,
+// or its number if it isn't one LLRP defines.
+func accessStopTriggerName(t llrp.AccessSpecStopTriggerType) string {
+	switch t {
+	case llrp.AccessSpecStopTriggerNone:
+		return "None"
+	case llrp.AccessSpecStopTriggerOperationCount:
+		return "OperationCount"
+	}
+	return "Unknown(" + strconv.Itoa(int(t)) + ")"
+}
+
+// describeAccessSpecs returns the response with each AccessSpec's state and stop trigger spelled out.
+func describeAccessSpecs(resp *llrp.GetAccessSpecsResponse) *describedAccessSpecs {
+	described := &describedAccessSpecs{
+		LLRPStatus:  resp.LLRPStatus,
+		AccessSpecs: make([]describedAccessSpec, len(resp.AccessSpecs)),
+	}
+
+	for i, spec := range resp.AccessSpecs {
+		described.AccessSpecs[i] = describedAccessSpec{
+			AccessSpec:  spec,
+			State:       spec.State(),
+			StateName:   spec.State().String(),
+			StopTrigger: accessStopTriggerName(spec.Trigger.Trigger),
+		}
+		if spec.Trigger.Trigger == llrp.AccessSpecStopTriggerOperationCount {
+			count := spec.Trigger.OperationCountValue
+			described.AccessSpecs[i].OperationCount = &count
+		}
+	}
+	return described
+}
+
+// applyStopAfter sets the AccessSpec's stop trigger from the StopAfter
+// in the request's JSON data, if it has one.
+// It returns an error if StopAfter is 0 or the request also has a Trigger of its own.
+func applyStopAfter(spec *llrp.AccessSpec, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	fields := accessStopFields{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return errors.Wrap(err, "failed to unmarshal StopAfter")
+	}
+	if fields.StopAfter == nil {
+		return nil
+	}
+
+	if *fields.StopAfter == 0 {
+		return errors.New("StopAfter must be at least 1; " +
+			"to run the AccessSpec until it's deleted, leave StopAfter out")
+	}
+
+	if spec.Trigger != (llrp.AccessSpecStopTrigger{}) {
+		return errors.New("the request has both a StopAfter and an AccessSpec Trigger; " +
+			"use one or the other")
+	}
+
+	spec.Trigger = llrp.AccessSpecStopAfter(*fields.StopAfter)
+	return nil
+}
+
+// checkAccessStopTrigger returns an error if the trigger's type isn't one LLRP defines,
+// if it counts operations but its count is 0,
+// or if it doesn't count operations but has a count anyway,
+// which usually means the AccessSpec was meant to stop but would run until it's deleted.
+func checkAccessStopTrigger(trigger llrp.AccessSpecStopTrigger) error {
+	switch {
+	case !trigger.Trigger.IsValid():
+		return errors.Errorf("invalid AccessSpec stop trigger %d: it must be %d (none) or %d (operation count)",
+			trigger.Trigger, llrp.AccessSpecStopTriggerNone, llrp.AccessSpecStopTriggerOperationCount)
+	case trigger.Trigger == llrp.AccessSpecStopTriggerOperationCount && trigger.OperationCountValue == 0:
+		return errors.New("invalid AccessSpec stop trigger: its OperationCountValue must be at least 1")
+	case trigger.Trigger == llrp.AccessSpecStopTriggerNone && trigger.OperationCountValue != 0:
+		return errors.Errorf("invalid AccessSpec stop trigger: it has an OperationCountValue of %d, "+
+			"but its Trigger is %d (none), so it would run until it's deleted; "+
+			"set the Trigger to %d to stop after that many operations",
+			trigger.OperationCountValue, llrp.AccessSpecStopTriggerNone, llrp.AccessSpecStopTriggerOperationCount)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strings"
+	"testing"
+)
+
+func TestApplyStopAfter(t *testing.T) {
+	spec := llrp.AccessSpec{}
+	if err := applyStopAfter(&spec, []byte(`{"StopAfter": 1}`)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if spec.Trigger != llrp.AccessSpecStopAfter(1) {
+		t.Errorf("expected a one-shot trigger; got %+v", spec.Trigger)
+	}
+
+	// A request with a trigger of its own is ambiguous.
+	if err := applyStopAfter(&spec, []byte(`{"StopAfter": 2}`)); err == nil {
+		t.Error("expected an error for a request with both a StopAfter and a Trigger")
+	}
+
+	if err := applyStopAfter(&llrp.AccessSpec{}, []byte(`{"StopAfter": 0}`)); err == nil ||
+		!strings.Contains(err.Error(), "StopAfter") {
+		t.Errorf("expected an error about the StopAfter; got %v", err)
+	}
+
+	// Requests without a StopAfter are unchanged.
+	unchanged := llrp.AccessSpec{}
+	if err := applyStopAfter(&unchanged, []byte(`{"AccessSpecID": 1}`)); err != nil {
+		t.Errorf("%+v", err)
+	}
+	if err := applyStopAfter(&unchanged, nil); err != nil {
+		t.Errorf("expected no error without request data; got %v", err)
+	}
+	if unchanged.Trigger != (llrp.AccessSpecStopTrigger{}) {
+		t.Errorf("expected no trigger; got %+v", unchanged.Trigger)
+	}
+}
+
+func TestCheckValid_accessStopTrigger(t *testing.T) {
+	for _, testCase := range []struct {
+		name    string
+		trigger llrp.AccessSpecStopTrigger
+		valid   bool
+	}{
+		{name: "none", valid: true},
+		{name: "oneShot", valid: true, trigger: llrp.AccessSpecStopAfter(1)},
+		{name: "zeroCount", trigger: llrp.AccessSpecStopAfter(0)},
+		{name: "countWithoutTrigger", trigger: llrp.AccessSpecStopTrigger{OperationCountValue: 3}},
+		{name: "unknownTrigger", trigger: llrp.AccessSpecStopTrigger{Trigger: 2}},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			err := checkValid(&llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{Trigger: testCase.trigger}})
+			if testCase.valid && err != nil {
+				t.Errorf("expected no error; got %v", err)
+			}
+			if !testCase.valid && (err == nil || !strings.Contains(err.Error(), "stop trigger")) {
+				t.Errorf("expected a stop trigger error; got %v", err)
+			}
+		})
+	}
+}
+
+func TestDescribeAccessSpecs(t *testing.T) {
+	described := describeAccessSpecs(&llrp.GetAccessSpecsResponse{AccessSpecs: []llrp.AccessSpec{
+		{AccessSpecID: 1},
+		{AccessSpecID: 2, Trigger: llrp.AccessSpecStopAfter(5), IsActive: true},
+		{AccessSpecID: 3, Trigger: llrp.AccessSpecStopTrigger{Trigger: 9}},
+	}})
+
+	if len(described.AccessSpecs) != 3 {
+		t.Fatalf("expected 3 AccessSpecs; got %d", len(described.AccessSpecs))
+	}
+
+	for i, expected := range []struct {
+		name  string
+		count uint16
+		state string
+	}{
+		{name: "None", state: "Disabled"},
+		{name: "OperationCount", count: 5, state: "Active"},
+		{name: "Unknown(9)", state: "Disabled"},
+	} {
+		spec := described.AccessSpecs[i]
+		if spec.StateName != expected.state || spec.State.String() != expected.state {
+			t.Errorf("AccessSpec %d: expected state %s; got %d (%s)",
+				spec.AccessSpecID, expected.state, spec.State, spec.StateName)
+		}
+		if spec.StopTrigger != expected.name {
+			t.Errorf("AccessSpec %d: expected %s; got %s", spec.AccessSpecID, expected.name, spec.StopTrigger)
+		}
+		if (spec.OperationCount == nil) != (expected.count == 0) ||
+			(spec.OperationCount != nil && *spec.OperationCount != expected.count) {
+			t.Errorf("AccessSpec %d: expected an OperationCount of %d; got %v",
+				spec.AccessSpecID, expected.count, spec.OperationCount)
+		}
+	}
+
+	// The described specs still unmarshal as a GetAccessSpecsResponse.
+	data, err := json.Marshal(described)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	resp := llrp.GetAccessSpecsResponse{}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(resp.AccessSpecs) != 3 || resp.AccessSpecs[1].Trigger != llrp.AccessSpecStopAfter(5) {
+		t.Errorf("expected the original AccessSpecs; got %+v", resp.AccessSpecs)
+	}
+}
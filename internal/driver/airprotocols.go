@@ -0,0 +1,103 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+)
+
+// ResourceAirProtocols reports the air protocols each of a Reader's antennas supports.
+const ResourceAirProtocols = "AirProtocols"
+
+// AntennaAirProtocols lists the air protocols one of a Reader's antennas supports.
+type AntennaAirProtocols struct {
+	AntennaID    llrp.AntennaID
+	AirProtocols []AirProtocol
+}
+
+// AirProtocol is an LLRP air protocol.
+type AirProtocol struct {
+	ID   llrp.AirProtocolIDType
+	Name string // e.g., EPCGlobalClass1Gen2, or Unknown(ID) if LLRP doesn't define it
+}
+
+// airProtocolName returns the name of an air protocol,
+// or its number if it isn't one LLRP defines.
+func airProtocolName(id llrp.AirProtocolIDType) string {
+	switch id {
+	case llrp.AirProtoUnspecified:
+		return "Unspecified"
+	case llrp.AirProtoEPCGlobalClass1Gen2:
+		return "EPCGlobalClass1Gen2"
+	}
+	return "Unknown(" + strconv.Itoa(int(id)) + ")"
+}
+
+// AirProtocols returns the air protocols each of the Reader's antennas supports,
+// ordered by AntennaID, from the Reader's GeneralDeviceCapabilities.
+func (l *LLRPDevice) AirProtocols(ctx context.Context) ([]AntennaAirProtocols, error) {
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get the Reader's air protocols")
+	}
+	return airProtocols(caps), nil
+}
+
+// airProtocols returns the air protocols of each antenna in the capabilities, ordered by AntennaID.
+func airProtocols(caps *llrp.GetReaderCapabilitiesResponse) []AntennaAirProtocols {
+	if caps.GeneralDeviceCapabilities == nil {
+		return []AntennaAirProtocols{}
+	}
+
+	perAntenna := caps.GeneralDeviceCapabilities.PerAntennaAirProtocols
+	antennas := make([]AntennaAirProtocols, len(perAntenna))
+	for i, pa := range perAntenna {
+		antennas[i] = AntennaAirProtocols{
+			AntennaID:    pa.AntennaID,
+			AirProtocols: make([]AirProtocol, len(pa.AirProtocolIDs)),
+		}
+		for j, id := range pa.AirProtocolIDs {
+			antennas[i].AirProtocols[j] = AirProtocol{ID: id, Name: airProtocolName(id)}
+		}
+	}
+
+	sort.Slice(antennas, func(i, j int) bool { return antennas[i].AntennaID < antennas[j].AntennaID })
+	return antennas
+}
+
+// checkAirProtocol returns an error if the capabilities list the air protocols
+// of the antenna, or of every antenna if the antennaID is 0, and one of them doesn't support it.
+// Readers that don't list their antennas' air protocols are left to decide for themselves,
+// as are requests with an unspecified air protocol.
+func checkAirProtocol(caps *llrp.GetReaderCapabilitiesResponse, antennaID llrp.AntennaID,
+	protocol llrp.AirProtocolIDType) error {
+	if protocol == llrp.AirProtoUnspecified {
+		return nil
+	}
+
+	for _, a := range airProtocols(caps) {
+		if antennaID != llrp.AllAntennas && a.AntennaID != antennaID {
+			continue
+		}
+
+		supported := false
+		for _, p := range a.AirProtocols {
+			if p.ID == protocol {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return errors.Errorf("Reader's antenna %d does not support air protocol %s",
+				a.AntennaID, airProtocolName(protocol))
+		}
+	}
+	return nil
+}
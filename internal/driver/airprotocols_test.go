@@ -0,0 +1,104 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_AirProtocols(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgGetReaderCapabilities, &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			MaxSupportedAntennas: 2,
+			ReceiveSensitivities: []llrp.ReceiveSensitivityTableEntry{{Index: 1}},
+			PerAntennaAirProtocols: []llrp.PerAntennaAirProtocol{
+				{AntennaID: 2, AirProtocolIDs: []llrp.AirProtocolIDType{llrp.AirProtoEPCGlobalClass1Gen2, 7}},
+				{AntennaID: 1, AirProtocolIDs: []llrp.AirProtocolIDType{llrp.AirProtoEPCGlobalClass1Gen2}},
+			},
+		},
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	dev := &LLRPDevice{name: "localReader", client: c, lc: edgexCompatTestLogger{t}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	antennas, err := dev.AirProtocols(ctx)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	c1g2 := AirProtocol{ID: llrp.AirProtoEPCGlobalClass1Gen2, Name: "EPCGlobalClass1Gen2"}
+	expected := []AntennaAirProtocols{
+		{AntennaID: 1, AirProtocols: []AirProtocol{c1g2}},
+		{AntennaID: 2, AirProtocols: []AirProtocol{c1g2, {ID: 7, Name: "Unknown(7)"}}},
+	}
+	if !reflect.DeepEqual(antennas, expected) {
+		t.Errorf("expected %+v; got %+v", expected, antennas)
+	}
+}
+
+func TestCheckSupported_airProtocol(t *testing.T) {
+	caps := &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			MaxSupportedAntennas: 2,
+			PerAntennaAirProtocols: []llrp.PerAntennaAirProtocol{
+				{AntennaID: 1, AirProtocolIDs: []llrp.AirProtocolIDType{llrp.AirProtoEPCGlobalClass1Gen2}},
+				{AntennaID: 2, AirProtocolIDs: []llrp.AirProtocolIDType{7}},
+			},
+		},
+	}
+
+	roSpec := func(antenna llrp.AntennaID, protocol llrp.AirProtocolIDType) *llrp.AddROSpec {
+		return &llrp.AddROSpec{ROSpec: llrp.ROSpec{AISpecs: []llrp.AISpec{{
+			AntennaIDs:              []llrp.AntennaID{antenna},
+			InventoryParameterSpecs: []llrp.InventoryParameterSpec{{AirProtocolID: protocol}},
+		}}}}
+	}
+	accessSpec := func(antenna llrp.AntennaID, protocol llrp.AirProtocolIDType) *llrp.AddAccessSpec {
+		return &llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{AntennaID: antenna, AirProtocolID: protocol}}
+	}
+
+	for _, testCase := range []struct {
+		name        string
+		msg         llrp.Outgoing
+		unsupported string // if set, the error must mention it
+	}{
+		{name: "roSpecSupported", msg: roSpec(1, llrp.AirProtoEPCGlobalClass1Gen2)},
+		{name: "roSpecUnsupported", msg: roSpec(2, llrp.AirProtoEPCGlobalClass1Gen2), unsupported: "antenna 2"},
+		{name: "roSpecAllAntennas", msg: roSpec(llrp.AllAntennas, llrp.AirProtoEPCGlobalClass1Gen2),
+			unsupported: "antenna 2"},
+		{name: "roSpecUnspecified", msg: roSpec(2, llrp.AirProtoUnspecified)},
+		{name: "accessSpecSupported", msg: accessSpec(2, 7)},
+		{name: "accessSpecUnsupported", msg: accessSpec(1, 7), unsupported: "Unknown(7)"},
+	} {
+		err := checkSupported(caps, testCase.msg)
+		switch {
+		case testCase.unsupported == "" && err != nil:
+			t.Errorf("%s: expected no error; got %v", testCase.name, err)
+		case testCase.unsupported != "" && (err == nil || !strings.Contains(err.Error(), testCase.unsupported)):
+			t.Errorf("%s: expected an error mentioning %q; got %v", testCase.name, testCase.unsupported, err)
+		}
+	}
+
+	// Readers that don't list air protocols decide for themselves.
+	if err := checkSupported(&llrp.GetReaderCapabilitiesResponse{}, roSpec(1, 7)); err != nil {
+		t.Errorf("expected no error without air protocols; got %v", err)
+	}
+}
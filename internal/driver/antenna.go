@@ -0,0 +1,84 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"sort"
+	"time"
+)
+
+// ResourceAntennaStatus reports whether each of a Reader's antenna ports
+// has an antenna connected, and its configured gain.
+const ResourceAntennaStatus = "AntennaStatus"
+
+// AntennaStatus is the state of one of a Reader's antenna ports.
+type AntennaStatus struct {
+	AntennaID llrp.AntennaID
+	Connected bool    // whether the Reader detects an antenna on the port
+	GainDBi   float64 // composite forward gain, including cable loss, in dBi
+	Location  string  `json:",omitempty"` // the antenna's label from the location protocol
+	// LastEvent is the most recent AntennaEvent the Reader sent for the port
+	// since the service connected to it, if any.
+	LastEvent *AntennaEventRecord `json:",omitempty"`
+}
+
+// AntennaEventRecord is an AntennaEvent and when the service received it.
+type AntennaEventRecord struct {
+	Connected bool
+	Time      time.Time
+}
+
+// recordAntennaEvent notes the AntennaEvent, if it isn't nil, for AntennaStatus.
+func (l *LLRPDevice) recordAntennaEvent(now time.Time, event *llrp.AntennaEvent) {
+	if event == nil {
+		return
+	}
+
+	l.deviceMu.Lock()
+	defer l.deviceMu.Unlock()
+
+	if l.antennaEvents == nil {
+		l.antennaEvents = make(map[llrp.AntennaID]AntennaEventRecord)
+	}
+	l.antennaEvents[event.AntennaID] = AntennaEventRecord{
+		Connected: event.Event == llrp.AntennaConnected,
+		Time:      now,
+	}
+}
+
+// AntennaStatus returns the state of each of the Reader's antenna ports,
+// ordered by AntennaID, using the AntennaProperties in its ReaderConfig.
+func (l *LLRPDevice) AntennaStatus(ctx context.Context) ([]AntennaStatus, error) {
+	conf := llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		RequestedData: llrp.ReaderConfReqAntennaProperties,
+	}, &conf); err != nil {
+		return nil, errors.WithMessage(err, "failed to get AntennaProperties")
+	}
+
+	l.deviceMu.RLock()
+	locations := l.antennaLocations
+	events := l.antennaEvents
+	statuses := make([]AntennaStatus, len(conf.AntennaProperties))
+	for i, ap := range conf.AntennaProperties {
+		statuses[i] = AntennaStatus{
+			AntennaID: ap.AntennaID,
+			Connected: ap.AntennaConnected,
+			GainDBi:   float64(ap.AntennaGain) / 100,
+			Location:  locations[ap.AntennaID],
+		}
+		if event, ok := events[ap.AntennaID]; ok {
+			statuses[i].LastEvent = &event
+		}
+	}
+	l.deviceMu.RUnlock()
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].AntennaID < statuses[j].AntennaID })
+	return statuses, nil
+}
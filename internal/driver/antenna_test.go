@@ -0,0 +1,100 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestHandleRead_AntennaStatus(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		req := llrp.GetReaderConfig{}
+		if err := msg.UnmarshalTo(&req); err != nil {
+			t.Error(err)
+		}
+		if req.RequestedData != llrp.ReaderConfReqAntennaProperties {
+			t.Errorf("expected a request for AntennaProperties; got %d", req.RequestedData)
+		}
+
+		return &llrp.GetReaderConfigResponse{AntennaProperties: []llrp.AntennaProperties{
+			{AntennaID: 2, AntennaConnected: false, AntennaGain: 600},
+			{AntennaID: 1, AntennaConnected: true, AntennaGain: -250},
+		}}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	clk := newFakeClock()
+	dev := &LLRPDevice{
+		client:           c,
+		ch:               make(chan *dsModels.AsyncValues, 10),
+		clk:              clk,
+		antennaLocations: map[llrp.AntennaID]string{1: "dock-door"},
+	}
+	d := newLocalDriver(t, dev)
+	d.clk = clk
+
+	// The Reader reports antenna 2 was unplugged.
+	payload, err := (&llrp.ReaderEventNotification{ReaderEventNotificationData: llrp.ReaderEventNotificationData{
+		UTCTimestamp: llrp.UTCTimestamp(clk.Now().UnixNano() / 1000),
+		AntennaEvent: &llrp.AntennaEvent{Event: llrp.AntennaDisconnected, AntennaID: 2},
+	}}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := llrp.NewByteMessage(llrp.MsgReaderEventNotification, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventTime := clk.Now()
+	dev.newReaderEventHandler(nil).HandleMessage(nil, msg)
+
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceAntennaStatus,
+		Type:               dsModels.String,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cvs) != 1 || cvs[0] == nil {
+		t.Fatalf("expected exactly one command value; got %v", cvs)
+	}
+
+	s, err := cvs[0].StringValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statuses []AntennaStatus
+	if err := json.Unmarshal([]byte(s), &statuses); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(statuses) != 2 {
+		t.Fatalf("expected 2 antenna ports; got %+v", statuses)
+	}
+
+	if st := statuses[0]; st.AntennaID != 1 || !st.Connected || st.GainDBi != -2.5 ||
+		st.Location != "dock-door" || st.LastEvent != nil {
+		t.Errorf("expected antenna 1 connected at -2.5dBi at the dock door; got %+v", st)
+	}
+
+	if st := statuses[1]; st.AntennaID != 2 || st.Connected || st.GainDBi != 6 ||
+		st.LastEvent == nil || st.LastEvent.Connected || !st.LastEvent.Time.Equal(eventTime) {
+		t.Errorf("expected antenna 2 disconnected at 6dBi with a disconnect event; got %+v", st)
+	}
+}
@@ -0,0 +1,69 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strconv"
+)
+
+const (
+	// ResourceAntennaEventPort and ResourceAntennaEventType are sent together as a reading
+	// when a Reader reports that an antenna was connected to or disconnected from one of its ports:
+	// the port's AntennaID and either Connected or Disconnected.
+	// If the port has a location, ResourceAntennaEventLocation is sent with them.
+	ResourceAntennaEventPort     = "AntennaEventPort"
+	ResourceAntennaEventType     = "AntennaEventType"
+	ResourceAntennaEventLocation = "AntennaEventLocation"
+)
+
+// antennaEventValues returns typed values for an AntennaEvent's port and event type,
+// plus the port's location, if it's in locations.
+func antennaEventValues(ns int64, locations map[llrp.AntennaID]string,
+	event *llrp.AntennaEvent) ([]*dsModels.CommandValue, error) {
+	port, err := dsModels.NewUint16Value(ResourceAntennaEventPort, ns, uint16(event.AntennaID))
+	if err != nil {
+		return nil, err
+	}
+
+	cvs := []*dsModels.CommandValue{
+		port,
+		dsModels.NewStringValue(ResourceAntennaEventType, ns, event.Event.String()),
+	}
+
+	if loc, ok := locations[event.AntennaID]; ok {
+		cvs = append(cvs, dsModels.NewStringValue(ResourceAntennaEventLocation, ns, loc))
+	}
+
+	return cvs, nil
+}
+
+// sendAntennaEvent sends a reading with the port and event type of the AntennaEvent
+// in the notification data, if it has one,
+// so alerts for unplugged antennas needn't decode the whole notification.
+func (l *LLRPDevice) sendAntennaEvent(ns int64, data *llrp.ReaderEventNotificationData) {
+	if data.AntennaEvent == nil {
+		return
+	}
+
+	l.deviceMu.RLock()
+	locations := l.antennaLocations
+	l.deviceMu.RUnlock()
+
+	cvs, err := antennaEventValues(ns, locations, data.AntennaEvent)
+	if err != nil {
+		l.lc.Error("Failed to create antenna event values.", "device", l.name, "error", err.Error())
+		return
+	}
+
+	if data.AntennaEvent.Event == llrp.AntennaDisconnected {
+		l.lc.Warn("Reader reports an antenna was disconnected.", "device", l.name,
+			"antenna", strconv.Itoa(int(data.AntennaEvent.AntennaID)))
+	}
+
+	l.sendAsync(&dsModels.AsyncValues{DeviceName: l.name, CommandValues: cvs})
+}
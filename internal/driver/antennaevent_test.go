@@ -0,0 +1,119 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_sendAntennaEvent(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 10)
+	l := &LLRPDevice{
+		name:             "localReader",
+		lc:               edgexCompatTestLogger{t},
+		ch:               ch,
+		clk:              newFakeClock(),
+		antennaLocations: map[llrp.AntennaID]string{3: "dock door"},
+	}
+	handler := l.newReaderEventHandler(nil)
+
+	for _, testCase := range []struct {
+		name      string
+		port      uint16
+		eventType string
+		location  string
+		// payload is a ReaderEventNotification as a Reader sends it.
+		payload []byte
+	}{
+		{
+			name: "disconnected", port: 3, eventType: "Disconnected", location: "dock door",
+			payload: []byte{
+				0x00, 0xf6, 0x00, 0x17, // ReaderEventNotificationData, 23 bytes
+				0x00, 0x80, 0x00, 0x0c, // UTCTimestamp, 12 bytes
+				0x00, 0x05, 0xaf, 0x31, 0x07, 0xa4, 0x00, 0x00,
+				0x00, 0xff, 0x00, 0x07, // AntennaEvent, 7 bytes
+				0x00,       // Event: disconnected
+				0x00, 0x03, // AntennaID
+			},
+		},
+		{
+			name: "connected", port: 258, eventType: "Connected",
+			payload: []byte{
+				0x00, 0xf6, 0x00, 0x17,
+				0x00, 0x80, 0x00, 0x0c,
+				0x00, 0x05, 0xaf, 0x31, 0x07, 0xa4, 0x00, 0x00,
+				0x00, 0xff, 0x00, 0x07,
+				0x01,       // Event: connected
+				0x01, 0x02, // AntennaID
+			},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			msg, err := llrp.NewByteMessage(llrp.MsgReaderEventNotification, testCase.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handler.HandleMessage(nil, msg)
+
+			for {
+				var av *dsModels.AsyncValues
+				select {
+				case av = <-ch:
+				case <-time.After(time.Second):
+					t.Fatal("expected an antenna event reading")
+				}
+
+				if av.CommandValues[0].DeviceResourceName != ResourceAntennaEventPort {
+					continue
+				}
+
+				expected := 2
+				if testCase.location != "" {
+					expected = 3
+				}
+				if len(av.CommandValues) != expected {
+					t.Fatalf("expected %d values; got %v", expected, av.CommandValues)
+				}
+
+				port, err := av.CommandValues[0].Uint16Value()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if port != testCase.port {
+					t.Errorf("expected port %d; got %d", testCase.port, port)
+				}
+
+				typeCV := av.CommandValues[1]
+				if typeCV.DeviceResourceName != ResourceAntennaEventType {
+					t.Fatalf("expected a %s value; got %s", ResourceAntennaEventType, typeCV.DeviceResourceName)
+				}
+				if eventType := typeCV.ValueToString(); eventType != testCase.eventType {
+					t.Errorf("expected event type %s; got %s", testCase.eventType, eventType)
+				}
+
+				if testCase.location != "" {
+					locCV := av.CommandValues[2]
+					if locCV.DeviceResourceName != ResourceAntennaEventLocation ||
+						locCV.ValueToString() != testCase.location {
+						t.Errorf("expected location %q; got %v", testCase.location, locCV)
+					}
+				}
+				return
+			}
+		})
+	}
+
+	// Notifications without an AntennaEvent don't send one.
+	l.sendAntennaEvent(0, &llrp.ReaderEventNotificationData{})
+	select {
+	case av := <-ch:
+		t.Errorf("expected no values; got %+v", av)
+	default:
+	}
+}
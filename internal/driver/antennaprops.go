@@ -0,0 +1,143 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// ResourceAntennaProperties reads the AntennaProperties the Reader stores for each antenna port,
+// and writing it sets the gains of one or more antennas, then reads them back.
+// Only Readers whose capabilities say they can set AntennaProperties support writing it.
+const ResourceAntennaProperties = "AntennaProperties"
+
+// AntennaProperty is the AntennaProperties a Reader stores for one of its antenna ports.
+type AntennaProperty struct {
+	AntennaID llrp.AntennaID
+	Connected bool    // whether the Reader detects an antenna on the port
+	GainDBi   float64 // composite forward gain, including cable loss, in dBi
+}
+
+// antennaGain is the value written to ResourceAntennaProperties for each antenna.
+type antennaGain struct {
+	AntennaID llrp.AntennaID
+	GainDBi   float64
+}
+
+// AntennaProperties returns the AntennaProperties in the Reader's config, ordered by AntennaID.
+func (l *LLRPDevice) AntennaProperties(ctx context.Context) ([]AntennaProperty, error) {
+	conf := llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		RequestedData: llrp.ReaderConfReqAntennaProperties,
+	}, &conf); err != nil {
+		return nil, errors.WithMessage(err, "failed to get AntennaProperties")
+	}
+
+	props := make([]AntennaProperty, len(conf.AntennaProperties))
+	for i, ap := range conf.AntennaProperties {
+		props[i] = AntennaProperty{
+			AntennaID: ap.AntennaID,
+			Connected: ap.AntennaConnected,
+			GainDBi:   float64(ap.AntennaGain) / 100,
+		}
+	}
+
+	sort.Slice(props, func(i, j int) bool { return props[i].AntennaID < props[j].AntennaID })
+	return props, nil
+}
+
+// SetAntennaProperties sets the gain of each antenna in gains,
+// then reads the Reader's AntennaProperties back to confirm it kept them.
+//
+// LLRP's AntennaProperties hold only an antenna's fixed port number, its gain,
+// and whether it's connected, which Readers ignore when it's set,
+// so the gain is the only property a Reader can store;
+// the location labels in a device's location protocol stay with the service.
+// It returns an error if the Reader's capabilities say it can't set AntennaProperties,
+// if an antenna is 0 or listed twice, or if a gain doesn't fit LLRP's 1/100ths of dBi.
+func (l *LLRPDevice) SetAntennaProperties(ctx context.Context, gains []antennaGain) error {
+	if len(gains) == 0 {
+		return errors.New("no antennas to set AntennaProperties for")
+	}
+
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get the Reader's capabilities")
+	}
+	if err := checkAntennaProperties(caps); err != nil {
+		return err
+	}
+
+	set := &llrp.SetReaderConfig{}
+	expected := make(map[llrp.AntennaID]llrp.MillibelIsotropic, len(gains))
+	for _, g := range gains {
+		if g.AntennaID == 0 {
+			return errors.New("AntennaProperties must name a specific AntennaID")
+		}
+		if _, ok := expected[g.AntennaID]; ok {
+			return errors.Errorf("antenna %d is listed more than once", g.AntennaID)
+		}
+
+		gain := math.Round(g.GainDBi * 100)
+		if gain < math.MinInt16 || gain > math.MaxInt16 {
+			return errors.Errorf("antenna %d's gain %.2f dBi is outside LLRP's range of %.2f to %.2f dBi",
+				g.AntennaID, g.GainDBi, float64(math.MinInt16)/100, float64(math.MaxInt16)/100)
+		}
+
+		expected[g.AntennaID] = llrp.MillibelIsotropic(gain)
+		set.AntennaProperties = append(set.AntennaProperties, llrp.AntennaProperties{
+			AntennaID:   g.AntennaID,
+			AntennaGain: llrp.MillibelIsotropic(gain),
+		})
+	}
+
+	if err := l.checkSupported(ctx, set); err != nil {
+		return err
+	}
+
+	if err := l.TrySend(ctx, set, &llrp.SetReaderConfigResponse{}); err != nil {
+		return err
+	}
+
+	props, err := l.AntennaProperties(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to read back AntennaProperties")
+	}
+
+	for _, p := range props {
+		gain, ok := expected[p.AntennaID]
+		if !ok {
+			continue
+		}
+		delete(expected, p.AntennaID)
+
+		if got := math.Round(p.GainDBi * 100); got != float64(gain) {
+			return errors.Errorf("Reader reports antenna %d's gain as %.2f dBi after setting it to %.2f dBi",
+				p.AntennaID, p.GainDBi, float64(gain)/100)
+		}
+	}
+
+	for id := range expected {
+		return errors.Errorf("Reader has no AntennaProperties for antenna %d", id)
+	}
+
+	l.lc.Info("Set AntennaProperties.", "device", l.name, "antennas", strconv.Itoa(len(gains)))
+	return nil
+}
+
+// checkAntennaProperties returns an error if the capabilities
+// say the Reader can't set AntennaProperties.
+func checkAntennaProperties(caps *llrp.GetReaderCapabilitiesResponse) error {
+	if gen := caps.GeneralDeviceCapabilities; gen != nil && !gen.CanSetAntennaProperties {
+		return errors.New("Reader does not support setting AntennaProperties")
+	}
+	return nil
+}
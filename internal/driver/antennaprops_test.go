@@ -0,0 +1,139 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_SetAntennaProperties(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgGetReaderCapabilities, &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			MaxSupportedAntennas:    2,
+			CanSetAntennaProperties: true,
+			ReceiveSensitivities:    []llrp.ReceiveSensitivityTableEntry{{Index: 1}},
+		},
+	})
+
+	// The Reader stores the gains it's sent, except for antenna 2's, which it keeps at 0.
+	var mu sync.Mutex
+	props := []llrp.AntennaProperties{
+		{AntennaID: 2, AntennaGain: 0},
+		{AntennaID: 1, AntennaConnected: true, AntennaGain: 600},
+	}
+	sets := make(chan []llrp.AntennaProperties, 10)
+	rfid.SetResponseFunc(llrp.MsgSetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		conf := &llrp.SetReaderConfig{}
+		if err := msg.UnmarshalTo(conf); err != nil {
+			t.Errorf("expected a SetReaderConfig; got %v", err)
+		}
+		sets <- conf.AntennaProperties
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, ap := range conf.AntennaProperties {
+			for i := range props {
+				if props[i].AntennaID == ap.AntennaID && ap.AntennaID != 2 {
+					props[i].AntennaGain = ap.AntennaGain
+				}
+			}
+		}
+		return &llrp.SetReaderConfigResponse{}
+	})
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		req := &llrp.GetReaderConfig{}
+		if err := msg.UnmarshalTo(req); err != nil {
+			t.Errorf("expected a GetReaderConfig; got %v", err)
+		}
+		if req.RequestedData != llrp.ReaderConfReqAntennaProperties {
+			t.Errorf("expected a request for AntennaProperties; got %v", req.RequestedData)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		return &llrp.GetReaderConfigResponse{
+			AntennaProperties: append([]llrp.AntennaProperties(nil), props...),
+		}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	dev := &LLRPDevice{name: "localReader", client: c, lc: edgexCompatTestLogger{t}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if err := dev.SetAntennaProperties(ctx, []antennaGain{{AntennaID: 1, GainDBi: 8.254}}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectedSet := []llrp.AntennaProperties{{AntennaID: 1, AntennaGain: 825}}
+	if got := <-sets; !reflect.DeepEqual(got, expectedSet) {
+		t.Errorf("expected %+v; got %+v", expectedSet, got)
+	}
+
+	got, err := dev.AntennaProperties(ctx)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := []AntennaProperty{
+		{AntennaID: 1, Connected: true, GainDBi: 8.25},
+		{AntennaID: 2, GainDBi: 0},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v; got %+v", expected, got)
+	}
+
+	// Reading back catches gains the Reader didn't keep or antennas it doesn't have.
+	if err := dev.SetAntennaProperties(ctx, []antennaGain{{AntennaID: 2, GainDBi: 3}}); err == nil ||
+		!strings.Contains(err.Error(), "antenna 2's gain as 0.00 dBi") {
+		t.Errorf("expected an error for the gain the Reader didn't keep; got %v", err)
+	}
+	<-sets
+	if err := dev.SetAntennaProperties(ctx, []antennaGain{{AntennaID: 3, GainDBi: 3}}); err == nil ||
+		!strings.Contains(err.Error(), "no AntennaProperties for antenna 3") {
+		t.Errorf("expected an error for an antenna the Reader doesn't have; got %v", err)
+	}
+	<-sets
+
+	// Invalid gains aren't sent.
+	for _, gains := range [][]antennaGain{
+		nil,
+		{{AntennaID: 0, GainDBi: 3}},
+		{{AntennaID: 1, GainDBi: 3}, {AntennaID: 1, GainDBi: 4}},
+		{{AntennaID: 1, GainDBi: 400}},
+	} {
+		if err := dev.SetAntennaProperties(ctx, gains); err == nil {
+			t.Errorf("expected an error for %+v", gains)
+		}
+	}
+
+	// Neither are gains for Readers that can't set AntennaProperties.
+	dev.setCapabilities(&llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{MaxSupportedAntennas: 2},
+	})
+	if err := dev.SetAntennaProperties(ctx, []antennaGain{{AntennaID: 1, GainDBi: 3}}); err == nil ||
+		!strings.Contains(err.Error(), "does not support setting AntennaProperties") {
+		t.Errorf("expected an error for a Reader that can't set AntennaProperties; got %v", err)
+	}
+
+	select {
+	case got := <-sets:
+		t.Errorf("expected no SetReaderConfig; got %+v", got)
+	default:
+	}
+}
@@ -0,0 +1,239 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// ResourceAsyncValuesDropped is sent as an event once a device can send to EdgeX again
+// after dropping values because the channel to EdgeX was full.
+const ResourceAsyncValuesDropped = "AsyncValuesDropped"
+
+// Policies for values sent to EdgeX while the channel to it is full.
+const (
+	AsyncOverflowBlock = "block" // wait for room in the channel
+	AsyncOverflowDrop  = "drop"  // drop the values
+	AsyncOverflowQueue = "queue" // queue the values, dropping them if the queue is full
+)
+
+// checkAsyncOverflow returns an error if the policy isn't a known AsyncOverflow policy.
+func checkAsyncOverflow(policy string) error {
+	switch policy {
+	case AsyncOverflowBlock, AsyncOverflowDrop, AsyncOverflowQueue:
+		return nil
+	default:
+		return errors.Errorf("unknown async overflow policy %q; policies are %s, %s, or %s",
+			policy, AsyncOverflowBlock, AsyncOverflowDrop, AsyncOverflowQueue)
+	}
+}
+
+// checkAsyncQueueSize returns an error if the size isn't positive.
+func checkAsyncQueueSize(size int) error {
+	if size < 1 {
+		return errors.Errorf("async queue size must be at least 1; got %d", size)
+	}
+	return nil
+}
+
+// asyncDropsEvent is the value of ResourceAsyncValuesDropped events.
+type asyncDropsEvent struct {
+	Policy  string // the device's AsyncOverflow policy
+	Dropped uint64 // values dropped since the last event
+	Total   uint64 // values dropped since the device was created
+}
+
+// asyncQueue holds a device's values waiting for room in the channel to EdgeX,
+// so the goroutines handling its Reader's messages needn't wait for it.
+// A single goroutine forwards them in order while the queue isn't empty.
+type asyncQueue struct {
+	mu         sync.Mutex
+	size       int
+	values     []queuedValues
+	bytes      int64 // the estimated size of the values
+	forwarding bool  // true while a goroutine is forwarding values
+
+	// budget limits the memory all devices' queues use; unlimited if nil.
+	budget *queueBudget
+	share  *budgetShare // the device's share of the budget
+
+	unreported uint64 // values dropped since the last ResourceAsyncValuesDropped event
+	total      uint64 // values dropped since the device was created
+}
+
+// queuedValues are values in an asyncQueue and their estimated size.
+type queuedValues struct {
+	av   *dsModels.AsyncValues
+	size int64
+}
+
+// clearLocked empties the queue, releasing its values' memory from the budget.
+func (q *asyncQueue) clearLocked() {
+	q.budget.release(q.share, q.bytes)
+	q.values = nil
+	q.bytes = 0
+}
+
+// dropped records a dropped value and returns true if it's the first since values were last sent,
+// in which case the caller should log it.
+func (q *asyncQueue) dropped() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.unreported++
+	q.total++
+	return q.unreported == 1
+}
+
+// sent records that values reached EdgeX and returns the drops to report, if any.
+func (q *asyncQueue) sent() (event asyncDropsEvent, report bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.unreported == 0 {
+		return asyncDropsEvent{}, false
+	}
+	event = asyncDropsEvent{Dropped: q.unreported, Total: q.total}
+	q.unreported = 0
+	return event, true
+}
+
+// sendAsync sends values to EdgeX according to the device's AsyncOverflow policy,
+// or drops them if the device is force-stopped first.
+func (l *LLRPDevice) sendAsync(av *dsModels.AsyncValues) {
+	switch l.asyncOverflow {
+	case AsyncOverflowDrop:
+		select {
+		case l.ch <- av:
+			l.asyncSent()
+		default:
+			l.dropAsync()
+		}
+	case AsyncOverflowQueue:
+		l.queueAsync(av)
+	default:
+		select {
+		case l.ch <- av:
+			l.asyncSent()
+		case <-l.sends.dropChan():
+			l.lc.Debug("Dropping values for stopped device.", "device", l.name)
+		}
+	}
+}
+
+// dropAsync records values dropped because the channel to EdgeX or the device's queue is full.
+func (l *LLRPDevice) dropAsync() {
+	if l.queue.dropped() {
+		l.lc.Warn("Dropping values because EdgeX isn't keeping up with them.",
+			"device", l.name, "policy", l.asyncOverflow)
+	}
+}
+
+// asyncSent sends a ResourceAsyncValuesDropped event
+// if the device dropped values before sending these.
+func (l *LLRPDevice) asyncSent() {
+	event, report := l.queue.sent()
+	if !report {
+		return
+	}
+	event.Policy = l.asyncOverflow
+	l.lc.Warn("Dropped values because EdgeX wasn't keeping up with them.",
+		"device", l.name, "dropped", event.Dropped, "total", event.Total)
+	l.goSend(func() {
+		l.sendEdgeXEvent(ResourceAsyncValuesDropped, l.clock().Now().UnixNano(), event)
+	})
+}
+
+// queueAsync adds values to the device's queue, or drops them if it's full
+// or they don't fit in the AsyncQueueBudgetKiB all devices share,
+// and starts forwarding the queue if it isn't already.
+// If the budget blocks, it waits for room unless the device is force-stopped.
+// Stop waits for the queue to empty when it drains the device's reports.
+func (l *LLRPDevice) queueAsync(av *dsModels.AsyncValues) {
+	q := &l.queue
+	size := asyncValuesSize(av)
+	if !q.budget.acquire(q.share, size, l.sends.dropChan()) {
+		l.dropAsync()
+		return
+	}
+
+	q.mu.Lock()
+	if len(q.values) >= q.size {
+		q.mu.Unlock()
+		q.budget.release(q.share, size)
+		l.dropAsync()
+		return
+	}
+
+	q.values = append(q.values, queuedValues{av: av, size: size})
+	q.bytes += size
+	if q.forwarding {
+		q.mu.Unlock()
+		return
+	}
+
+	if !l.sends.start() {
+		q.clearLocked()
+		q.mu.Unlock()
+		l.lc.Debug("Dropping values for stopped device.", "device", l.name)
+		return
+	}
+	q.forwarding = true
+	q.mu.Unlock()
+
+	go func() {
+		defer l.sends.done()
+		l.forwardQueue()
+	}()
+}
+
+// forwardQueue sends the device's queued values to EdgeX in order until the queue is empty,
+// or drops them if the device is force-stopped first.
+func (l *LLRPDevice) forwardQueue() {
+	q := &l.queue
+	for {
+		q.mu.Lock()
+		if len(q.values) == 0 {
+			q.forwarding = false
+			q.mu.Unlock()
+			return
+		}
+		next := q.values[0]
+		q.values[0] = queuedValues{}
+		q.values = q.values[1:]
+		q.mu.Unlock()
+
+		select {
+		case l.ch <- next.av:
+			q.mu.Lock()
+			q.bytes -= next.size
+			q.mu.Unlock()
+			q.budget.release(q.share, next.size)
+			l.asyncSent()
+		case <-l.sends.dropChan():
+			q.mu.Lock()
+			q.bytes -= next.size
+			q.budget.release(q.share, next.size)
+			q.clearLocked()
+			q.forwarding = false
+			q.mu.Unlock()
+			l.lc.Debug("Dropping values for stopped device.", "device", l.name)
+			return
+		}
+	}
+}
+
+// AsyncQueueUsage returns how much the device has queued for EdgeX
+// and, if devices share an AsyncQueueBudgetKiB, the state of the budget.
+func (l *LLRPDevice) AsyncQueueUsage() AsyncQueueUsage {
+	q := &l.queue
+	q.mu.Lock()
+	u := AsyncQueueUsage{Values: len(q.values), Bytes: q.bytes, Dropped: q.total}
+	q.mu.Unlock()
+
+	q.budget.usage(&u)
+	return u
+}
@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// numberedValues returns AsyncValues whose DeviceName identifies them.
+func numberedValues(i int) *dsModels.AsyncValues {
+	return &dsModels.AsyncValues{DeviceName: strconv.Itoa(i)}
+}
+
+// receiveAsync returns the next values on the channel, or fails the test if none arrive.
+func receiveAsync(t *testing.T, ch <-chan *dsModels.AsyncValues) *dsModels.AsyncValues {
+	t.Helper()
+	select {
+	case av := <-ch:
+		return av
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for values")
+		return nil
+	}
+}
+
+// expectDropsEvent fails the test unless the values are an AsyncValuesDropped event
+// reporting the number of dropped values.
+func expectDropsEvent(t *testing.T, av *dsModels.AsyncValues, dropped uint64) {
+	t.Helper()
+	if len(av.CommandValues) != 1 || av.CommandValues[0].DeviceResourceName != ResourceAsyncValuesDropped {
+		t.Fatalf("expected a %s event; got %+v", ResourceAsyncValuesDropped, av)
+	}
+	event := asyncDropsEvent{}
+	if err := json.Unmarshal([]byte(av.CommandValues[0].ValueToString()), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Dropped != dropped || event.Total != dropped {
+		t.Errorf("expected %d dropped values; got %+v", dropped, event)
+	}
+}
+
+func TestLLRPDevice_sendAsync_drop(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 2)
+	dev := &LLRPDevice{name: "droppingReader", lc: edgexCompatTestLogger{t}, ch: ch,
+		asyncOverflow: AsyncOverflowDrop}
+
+	// None of these wait for the channel.
+	for i := 0; i < 4; i++ {
+		dev.sendAsync(numberedValues(i))
+	}
+	for i := 0; i < 2; i++ {
+		if av := receiveAsync(t, ch); av.DeviceName != strconv.Itoa(i) {
+			t.Errorf("expected values %d; got %s", i, av.DeviceName)
+		}
+	}
+
+	// Once there's room, the device reports what it dropped.
+	dev.sendAsync(numberedValues(4))
+	if av := receiveAsync(t, ch); av.DeviceName != "4" {
+		t.Errorf("expected values 4; got %s", av.DeviceName)
+	}
+	expectDropsEvent(t, receiveAsync(t, ch), 2)
+}
+
+func TestLLRPDevice_sendAsync_queue(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues)
+	dev := &LLRPDevice{name: "queueingReader", lc: edgexCompatTestLogger{t}, ch: ch,
+		asyncOverflow: AsyncOverflowQueue, queue: asyncQueue{size: 2}}
+
+	// Nothing reads the channel yet, so the third value overflows the queue.
+	for i := 0; i < 3; i++ {
+		dev.sendAsync(numberedValues(i))
+	}
+
+	// Queued values arrive in order, then the event reporting the dropped one.
+	for i := 0; i < 2; i++ {
+		if av := receiveAsync(t, ch); av.DeviceName != strconv.Itoa(i) {
+			t.Errorf("expected values %d; got %s", i, av.DeviceName)
+		}
+	}
+	expectDropsEvent(t, receiveAsync(t, ch), 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := dev.sends.wait(ctx); err != nil {
+		t.Errorf("expected the queue to be empty: %v", err)
+	}
+}
+
+func TestLLRPDevice_sendAsync_queueDrains(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues)
+	dev := &LLRPDevice{name: "queueingReader", lc: edgexCompatTestLogger{t}, ch: ch,
+		asyncOverflow: AsyncOverflowQueue, queue: asyncQueue{size: 10}}
+	dev.sendAsync(numberedValues(0))
+
+	// Draining waits for queued values, just as it does for pending sends.
+	go func() {
+		time.Sleep(shutdownGrace / 10)
+		<-ch
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := dev.drainReports(ctx); err != nil {
+		t.Fatalf("expected the queued values to drain: %v", err)
+	}
+
+	// Once drained, values are dropped rather than queued.
+	dev.sendAsync(numberedValues(1))
+	if err := dev.sends.wait(ctx); err != nil {
+		t.Errorf("expected values to be dropped after draining: %v", err)
+	}
+}
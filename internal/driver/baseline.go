@@ -0,0 +1,210 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+const (
+	// ResourceResetROSpecs replaces all of a Reader's ROSpecs with a baseline ROSpec.
+	ResourceResetROSpecs = "ResetROSpecs"
+
+	// ResourceROSpecReset is sent as an event with the result of each step of a ResetROSpecs.
+	ResourceROSpecReset = "ROSpecReset"
+
+	// BaselineDefault is written to ResourceResetROSpecs to use the configured BaselineROSpec.
+	BaselineDefault = "default"
+)
+
+// Outcomes of ROSpec resets.
+const (
+	// ResetDone means the Reader's only ROSpec is now the baseline.
+	ResetDone = "Reset"
+	// ResetRolledBack means the reset failed after deleting the Reader's ROSpecs,
+	// so the service restored the ones it had.
+	ResetRolledBack = "RolledBack"
+	// ResetFailed means the reset failed, and so did restoring the Reader's ROSpecs,
+	// or it failed before changing anything.
+	ResetFailed = "Failed"
+)
+
+// roSpecReset is the value of ResourceROSpecReset events.
+type roSpecReset struct {
+	BaselineROSpecID uint32
+	Outcome          string // e.g., Reset or RolledBack
+	Steps            []resetStep
+}
+
+// resetStep is the result of one step of resetting a Reader's ROSpecs.
+type resetStep struct {
+	Step     string // e.g., DeleteROSpecs or AddBaseline
+	ROSpecID uint32 `json:",omitempty"` // the ROSpec the step affected, if just one
+	Error    string `json:",omitempty"` // why the step failed, if it did
+}
+
+// defaultBaselineROSpec returns the ROSpec ResetROSpecs uses if BaselineROSpec isn't configured:
+// it inventories Gen2 tags with all antennas from when it's started until it's stopped,
+// and reports according to the Reader's ReaderConfig.
+func defaultBaselineROSpec() llrp.ROSpec {
+	return llrp.ROSpec{
+		ROSpecID: 1,
+		AISpecs: []llrp.AISpec{{
+			AntennaIDs: []llrp.AntennaID{0},
+			InventoryParameterSpecs: []llrp.InventoryParameterSpec{{
+				InventoryParameterSpecID: 1,
+				AirProtocolID:            llrp.AirProtoEPCGlobalClass1Gen2,
+			}},
+		}},
+	}
+}
+
+// parseBaselineROSpec returns the ROSpec in a BaselineROSpec config value,
+// or the default baseline if it's empty.
+func parseBaselineROSpec(data string) (llrp.ROSpec, error) {
+	if strings.TrimSpace(data) == "" {
+		return defaultBaselineROSpec(), nil
+	}
+
+	ros := llrp.ROSpec{}
+	if err := json.Unmarshal([]byte(data), &ros); err != nil {
+		return llrp.ROSpec{}, errors.Wrap(err, "baseline ROSpec isn't a JSON ROSpec")
+	}
+	if ros.ROSpecID == 0 {
+		return llrp.ROSpec{}, errors.New("baseline ROSpec must have a non-zero ROSpecID")
+	}
+	return ros, nil
+}
+
+// checkBaselineROSpec returns an error if the BaselineROSpec config value isn't valid.
+func checkBaselineROSpec(data string) error {
+	_, err := parseBaselineROSpec(data)
+	return err
+}
+
+// baselineROSpec returns the ROSpec to write for the ResourceResetROSpecs value:
+// the configured BaselineROSpec for BaselineDefault, or else the JSON ROSpec in the value.
+func (d *Driver) baselineROSpec(value string) (llrp.ROSpec, error) {
+	if strings.TrimSpace(value) != BaselineDefault {
+		ros, err := parseBaselineROSpec(value)
+		return ros, errors.WithMessage(err, "invalid "+ResourceResetROSpecs+" value")
+	}
+
+	var configured string
+	d.configMu.RLock()
+	if d.config != nil {
+		configured = d.config.BaselineROSpec
+	}
+	d.configMu.RUnlock()
+
+	ros, err := parseBaselineROSpec(configured)
+	return ros, errors.WithMessage(err, "invalid BaselineROSpec config")
+}
+
+// ResetROSpecs deletes all the Reader's ROSpecs and adds the baseline.
+//
+// It first checks the baseline against the Reader's capabilities,
+// then saves its current ROSpecs, so if deleting them or adding the baseline fails,
+// it can add them back and restore their states.
+// It sends a ResourceROSpecReset event with each step's result
+// and returns an error if the Reader didn't end up with just the baseline.
+func (l *LLRPDevice) ResetROSpecs(ctx context.Context, baseline llrp.ROSpec) error {
+	result := roSpecReset{BaselineROSpecID: baseline.ROSpecID}
+
+	// step records a step's result and returns its error.
+	step := func(name string, id uint32, err error) error {
+		s := resetStep{Step: name, ROSpecID: id}
+		if err != nil {
+			s.Error = err.Error()
+		}
+		result.Steps = append(result.Steps, s)
+		return err
+	}
+
+	finish := func(outcome string, err error) error {
+		result.Outcome = outcome
+		l.sendEdgeXEvent(ResourceROSpecReset, l.clock().Now().UnixNano(), result)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to reset ROSpecs (%s)", outcome)
+		}
+		return nil
+	}
+
+	// Readers only accept new ROSpecs that are disabled.
+	baseline.ROSpecCurrentState = llrp.ROSpecStateDisabled
+	add := baseline.Add()
+	if err := step("CheckBaseline", baseline.ROSpecID, l.checkSupported(ctx, add)); err != nil {
+		return finish(ResetFailed, err)
+	}
+
+	saved := llrp.GetROSpecsResponse{}
+	if err := step("GetROSpecs", 0, l.TrySend(ctx, &llrp.GetROSpecs{}, &saved)); err != nil {
+		return finish(ResetFailed, err)
+	}
+
+	err := step("DeleteROSpecs", 0, l.DeleteROSpecVerified(ctx, 0))
+	if err == nil {
+		err = step("AddBaseline", baseline.ROSpecID, l.TrySend(ctx, add, &llrp.AddROSpecResponse{}))
+		if err == nil {
+			return finish(ResetDone, nil)
+		}
+	}
+
+	if rbErr := l.restoreROSpecs(ctx, saved.ROSpecs, step); rbErr != nil {
+		return finish(ResetFailed, errors.Errorf("%v; restoring the ROSpecs also failed: %v", err, rbErr))
+	}
+	return finish(ResetRolledBack, err)
+}
+
+// restoreROSpecs deletes all the Reader's ROSpecs, then adds the given ones
+// and enables or starts them to match their states,
+// recording each step's result. It keeps going after a failure,
+// to restore as much as it can, and returns the first error.
+func (l *LLRPDevice) restoreROSpecs(ctx context.Context, specs []llrp.ROSpec,
+	step func(name string, id uint32, err error) error) error {
+	var first error
+	keep := func(err error) {
+		if first == nil {
+			first = err
+		}
+	}
+
+	keep(step("RestoreDeleteROSpecs", 0,
+		l.TrySend(ctx, &llrp.DeleteROSpec{}, &llrp.DeleteROSpecResponse{})))
+
+	for _, ros := range specs {
+		state := ros.ROSpecCurrentState
+		id := ros.ROSpecID
+
+		ros.ROSpecCurrentState = llrp.ROSpecStateDisabled
+		if err := step("RestoreROSpec", id, l.TrySend(ctx, ros.Add(), &llrp.AddROSpecResponse{})); err != nil {
+			keep(err)
+			continue
+		}
+
+		if state == llrp.ROSpecStateDisabled {
+			continue
+		}
+		if err := step("RestoreEnable", id, l.TrySend(ctx, &llrp.EnableROSpec{ROSpecID: id},
+			&llrp.EnableROSpecResponse{})); err != nil {
+			keep(err)
+			continue
+		}
+
+		// ROSpecs with start triggers become active on their own.
+		if state == llrp.ROSpecStateActive &&
+			ros.ROBoundarySpec.StartTrigger.Trigger == llrp.ROStartTriggerNone {
+			keep(step("RestoreStart", id, l.TrySend(ctx, &llrp.StartROSpec{ROSpecID: id},
+				&llrp.StartROSpecResponse{})))
+		}
+	}
+
+	return first
+}
@@ -0,0 +1,257 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDriver_baselineROSpec(t *testing.T) {
+	d := &Driver{}
+
+	ros, err := d.baselineROSpec(BaselineDefault)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !reflect.DeepEqual(ros, defaultBaselineROSpec()) {
+		t.Errorf("expected the default baseline without a config; got %+v", ros)
+	}
+
+	d.config = &driverConfiguration{BaselineROSpec: `{"ROSpecID": 7, "Priority": 1}`}
+	if ros, err := d.baselineROSpec(" default "); err != nil || ros.ROSpecID != 7 || ros.Priority != 1 {
+		t.Errorf("expected the configured baseline; got %+v, %v", ros, err)
+	}
+	if ros, err := d.baselineROSpec(`{"ROSpecID": 8}`); err != nil || ros.ROSpecID != 8 {
+		t.Errorf("expected the inline baseline; got %+v, %v", ros, err)
+	}
+
+	for _, invalid := range []string{`{"ROSpecID": 0}`, `not JSON`, `{"ROSpecID": -1}`} {
+		if _, err := d.baselineROSpec(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+		if err := checkBaselineROSpec(invalid); err == nil {
+			t.Errorf("%s: expected a config error", invalid)
+		}
+	}
+}
+
+// specReader holds the ROSpecs of a Reader impersonated by a TestDevice.
+type specReader struct {
+	mu     sync.Mutex
+	specs  map[uint32]llrp.ROSpec
+	reject uint32 // the ID of an ROSpec the Reader refuses to add
+}
+
+func newSpecReader(t *testing.T, rfid *llrp.TestDevice, specs ...llrp.ROSpec) *specReader {
+	r := &specReader{specs: map[uint32]llrp.ROSpec{}}
+	for _, ros := range specs {
+		r.specs[ros.ROSpecID] = ros
+	}
+
+	// handle returns a handler that unmarshals the message into msg,
+	// then calls f with the Reader locked and returns its status.
+	handle := func(msg encoding.BinaryUnmarshaler, resp func(llrp.LLRPStatus) llrp.Outgoing,
+		f func() llrp.LLRPStatus) func(llrp.Message) llrp.Outgoing {
+		return func(m llrp.Message) llrp.Outgoing {
+			if err := m.UnmarshalTo(msg); err != nil {
+				t.Error(err)
+			}
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			return resp(f())
+		}
+	}
+	failed := llrp.LLRPStatus{Status: llrp.StatusFieldInvalid, ErrorDescription: "no"}
+
+	add := &llrp.AddROSpec{}
+	rfid.SetResponseFunc(llrp.MsgAddROSpec, handle(add,
+		func(s llrp.LLRPStatus) llrp.Outgoing { return &llrp.AddROSpecResponse{LLRPStatus: s} },
+		func() llrp.LLRPStatus {
+			if add.ROSpec.ROSpecID == r.reject {
+				return failed
+			}
+			r.specs[add.ROSpec.ROSpecID] = add.ROSpec
+			return llrp.LLRPStatus{}
+		}))
+
+	del := &llrp.DeleteROSpec{}
+	rfid.SetResponseFunc(llrp.MsgDeleteROSpec, handle(del,
+		func(s llrp.LLRPStatus) llrp.Outgoing { return &llrp.DeleteROSpecResponse{LLRPStatus: s} },
+		func() llrp.LLRPStatus {
+			if del.ROSpecID == 0 {
+				r.specs = map[uint32]llrp.ROSpec{}
+			}
+			delete(r.specs, del.ROSpecID)
+			return llrp.LLRPStatus{}
+		}))
+
+	// setState returns a function that sets the state of the ROSpec with the ID.
+	setState := func(id *uint32, state llrp.ROSpecCurrentStateType) func() llrp.LLRPStatus {
+		return func() llrp.LLRPStatus {
+			ros, ok := r.specs[*id]
+			if !ok {
+				return failed
+			}
+			ros.ROSpecCurrentState = state
+			r.specs[*id] = ros
+			return llrp.LLRPStatus{}
+		}
+	}
+
+	enable := &llrp.EnableROSpec{}
+	rfid.SetResponseFunc(llrp.MsgEnableROSpec, handle(enable,
+		func(s llrp.LLRPStatus) llrp.Outgoing { return &llrp.EnableROSpecResponse{LLRPStatus: s} },
+		setState(&enable.ROSpecID, llrp.ROSpecStateInactive)))
+
+	disable := &llrp.DisableROSpec{}
+	rfid.SetResponseFunc(llrp.MsgDisableROSpec, handle(disable,
+		func(s llrp.LLRPStatus) llrp.Outgoing { return &llrp.DisableROSpecResponse{LLRPStatus: s} },
+		setState(&disable.ROSpecID, llrp.ROSpecStateDisabled)))
+
+	start := &llrp.StartROSpec{}
+	rfid.SetResponseFunc(llrp.MsgStartROSpec, handle(start,
+		func(s llrp.LLRPStatus) llrp.Outgoing { return &llrp.StartROSpecResponse{LLRPStatus: s} },
+		setState(&start.ROSpecID, llrp.ROSpecStateActive)))
+
+	rfid.SetResponseFunc(llrp.MsgGetROSpecs, handle(&llrp.GetROSpecs{},
+		func(llrp.LLRPStatus) llrp.Outgoing {
+			resp := &llrp.GetROSpecsResponse{}
+			for _, ros := range r.specs {
+				resp.ROSpecs = append(resp.ROSpecs, ros)
+			}
+			sort.Slice(resp.ROSpecs, func(i, j int) bool {
+				return resp.ROSpecs[i].ROSpecID < resp.ROSpecs[j].ROSpecID
+			})
+			return resp
+		},
+		func() llrp.LLRPStatus { return llrp.LLRPStatus{} }))
+
+	return r
+}
+
+// states returns the states of the Reader's ROSpecs by ID.
+func (r *specReader) states() map[uint32]llrp.ROSpecCurrentStateType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	states := map[uint32]llrp.ROSpecCurrentStateType{}
+	for id, ros := range r.specs {
+		states[id] = ros.ROSpecCurrentState
+	}
+	return states
+}
+
+func TestLLRPDevice_ResetROSpecs(t *testing.T) {
+	existing := []llrp.ROSpec{
+		{ROSpecID: 3, ROSpecCurrentState: llrp.ROSpecStateActive},
+		{ROSpecID: 4},
+		{ROSpecID: 5, ROSpecCurrentState: llrp.ROSpecStateInactive},
+	}
+	existingStates := map[uint32]llrp.ROSpecCurrentStateType{
+		3: llrp.ROSpecStateActive,
+		4: llrp.ROSpecStateDisabled,
+		5: llrp.ROSpecStateInactive,
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		baseline llrp.ROSpec
+		reject   uint32
+		outcome  string
+		steps    []string
+		states   map[uint32]llrp.ROSpecCurrentStateType
+	}{
+		{
+			name:     "reset",
+			baseline: llrp.ROSpec{ROSpecID: 1, ROSpecCurrentState: llrp.ROSpecStateActive},
+			outcome:  ResetDone,
+			steps:    []string{"CheckBaseline", "GetROSpecs", "DeleteROSpecs", "AddBaseline"},
+			states:   map[uint32]llrp.ROSpecCurrentStateType{1: llrp.ROSpecStateDisabled},
+		},
+		{
+			name:     "rolledBack",
+			baseline: llrp.ROSpec{ROSpecID: 9},
+			reject:   9,
+			outcome:  ResetRolledBack,
+			steps: []string{"CheckBaseline", "GetROSpecs", "DeleteROSpecs", "AddBaseline",
+				"RestoreDeleteROSpecs", "RestoreROSpec", "RestoreEnable", "RestoreStart",
+				"RestoreROSpec", "RestoreROSpec", "RestoreEnable"},
+			states: existingStates,
+		},
+		{
+			name:     "unsupported",
+			baseline: llrp.ROSpec{ROSpecID: 1, Priority: 5},
+			outcome:  ResetFailed,
+			steps:    []string{"CheckBaseline"},
+			states:   existingStates,
+		},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+			if err != nil {
+				t.Fatal(err)
+			}
+			reader := newSpecReader(t, rfid, existing...)
+			reader.reject = testCase.reject
+
+			go rfid.ImpersonateReader()
+			ch := make(chan *dsModels.AsyncValues, 1)
+			dev := &LLRPDevice{
+				name:   "localReader",
+				client: rfid.ConnectClient(t),
+				lc:     edgexCompatTestLogger{t},
+				ch:     ch,
+				caps: &llrp.GetReaderCapabilitiesResponse{
+					LLRPCapabilities: &llrp.LLRPCapabilities{MaxPriorityLevelSupported: 1},
+				},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err = dev.ResetROSpecs(ctx, testCase.baseline)
+			if (err == nil) != (testCase.outcome == ResetDone) {
+				t.Errorf("unexpected error for outcome %s: %v", testCase.outcome, err)
+			}
+
+			if states := reader.states(); !reflect.DeepEqual(states, testCase.states) {
+				t.Errorf("expected ROSpec states %v; got %v", testCase.states, states)
+			}
+
+			var av *dsModels.AsyncValues
+			select {
+			case av = <-ch:
+			case <-time.After(time.Second):
+				t.Fatalf("expected a %s event", ResourceROSpecReset)
+			}
+			reset := roSpecReset{}
+			if err := json.Unmarshal([]byte(av.CommandValues[0].ValueToString()), &reset); err != nil {
+				t.Fatal(err)
+			}
+			if reset.Outcome != testCase.outcome || reset.BaselineROSpecID != testCase.baseline.ROSpecID {
+				t.Errorf("expected a %s reset to ROSpec %d; got %+v",
+					testCase.outcome, testCase.baseline.ROSpecID, reset)
+			}
+
+			var steps []string
+			for _, s := range reset.Steps {
+				steps = append(steps, s.Step)
+			}
+			if !reflect.DeepEqual(steps, testCase.steps) {
+				t.Errorf("expected steps %v; got %v", testCase.steps, steps)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// ResourceDeviceCircuitOpen is sent as an event when a device's circuit breaker opens.
+const ResourceDeviceCircuitOpen = "DeviceCircuitOpen"
+
+var (
+	// ErrCircuitOpen is returned for commands that fail fast
+	// because a device's circuit breaker is open.
+	ErrCircuitOpen = errors.New("device circuit open")
+
+	// errNoClient is returned when a device has no client to send on.
+	errNoClient = errors.New("no client available")
+)
+
+// circuitState is the state of a circuitBreaker.
+type circuitState int
+
+const (
+	circuitClosed   = circuitState(iota) // commands are sent normally
+	circuitOpen                          // commands fail fast until the cooldown passes
+	circuitHalfOpen                      // a single command probes whether the Reader recovered
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// circuitBreaker protects callers from the latency of a Reader that's persistently failing.
+//
+// After threshold consecutive commands fail to reach the Reader, the circuit opens,
+// and commands fail fast with ErrCircuitOpen until the cooldown passes.
+// The circuit then half-opens, allowing a single command through as a probe:
+// if it reaches the Reader, the circuit closes; otherwise, it opens again.
+//
+// Only failures to communicate with the Reader count against it;
+// a command the Reader rejects is a sign the Reader is working.
+//
+// A nil circuitBreaker, or one with a threshold of 0, never opens.
+type circuitBreaker struct {
+	threshold int           // consecutive failures that open the circuit
+	cooldown  time.Duration // how long the circuit stays open before probing
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int       // consecutive failures while closed
+	openedAt time.Time // when the circuit last opened
+	probing  bool      // true while half-open with a probe in flight
+}
+
+// circuitOpenEvent is the value of ResourceDeviceCircuitOpen events.
+type circuitOpenEvent struct {
+	Failures        int    // consecutive failures that opened the circuit
+	CooldownSeconds int    // seconds before a command is allowed through to probe the Reader
+	Error           string // the most recent failure
+}
+
+// newCircuitBreaker returns a circuitBreaker with the given settings,
+// or nil if the threshold is 0, disabling it.
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow returns nil if a command may be sent now,
+// or an error wrapping ErrCircuitOpen if it should fail fast.
+// If it returns nil, the caller must report the command's result to done.
+func (cb *circuitBreaker) allow(now time.Time) error {
+	if cb == nil {
+		return nil
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if retryAt := cb.openedAt.Add(cb.cooldown); now.Before(retryAt) {
+			return errors.Wrapf(ErrCircuitOpen, "retry after %v", retryAt.Sub(now).Round(time.Second))
+		}
+		cb.state = circuitHalfOpen
+		cb.probing = true
+		return nil
+	case circuitHalfOpen:
+		if cb.probing {
+			return errors.Wrap(ErrCircuitOpen, "waiting for the Reader to recover")
+		}
+		cb.probing = true
+		return nil
+	}
+
+	return nil
+}
+
+// done records the result of a command allowed by allow.
+// It returns true if the result opened a closed circuit,
+// in which case the caller should announce it;
+// a failed probe reopens the circuit without announcing it again.
+func (cb *circuitBreaker) done(now time.Time, err error) (opened bool) {
+	if cb == nil {
+		return false
+	}
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	wasProbe := cb.probing
+	cb.probing = false
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller gave up, so it says nothing about the Reader.
+		return false
+	case !isConnFailure(err):
+		cb.state = circuitClosed
+		cb.failures = 0
+		return false
+	case wasProbe || cb.state == circuitHalfOpen:
+		cb.state = circuitOpen
+		cb.openedAt = now
+		return false
+	}
+
+	cb.failures++
+	if cb.state == circuitClosed && cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = now
+		return true
+	}
+	return false
+}
+
+// reset closes the circuit, e.g., when the Reader reconnects.
+func (cb *circuitBreaker) reset() {
+	if cb == nil {
+		return
+	}
+
+	cb.mu.Lock()
+	cb.state = circuitClosed
+	cb.failures = 0
+	cb.probing = false
+	cb.mu.Unlock()
+}
+
+// isConnFailure returns true if err indicates a command didn't reach the Reader
+// or the Reader didn't reply, as opposed to the Reader rejecting it.
+// Sends are only retried for connection failures,
+// so exceeding the retries is one, too.
+func isConnFailure(err error) bool {
+	return errors.Is(err, llrp.ErrClientClosed) ||
+		errors.Is(err, errNoClient) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, retry.ErrRetriesExceeded) ||
+		errors.Is(err, retry.ErrWaitExceedsDeadline)
+}
+
+// withBreaker runs send if the device's circuit breaker allows it
+// and records its result, announcing when the circuit opens.
+func (l *LLRPDevice) withBreaker(send func() error) error {
+	if err := l.breaker.allow(l.clock().Now()); err != nil {
+		l.lc.Debug("Failing command fast.", "device", l.name, "error", err.Error())
+		return err
+	}
+
+	err := send()
+	if l.breaker.done(l.clock().Now(), err) {
+		l.lc.Warn("Device circuit open; failing commands fast until the Reader recovers.",
+			"device", l.name, "failures", l.breaker.threshold,
+			"cooldown", l.breaker.cooldown.String(), "error", err.Error())
+		go l.sendEdgeXEvent(ResourceDeviceCircuitOpen, l.clock().Now().UnixNano(), circuitOpenEvent{
+			Failures:        l.breaker.threshold,
+			CooldownSeconds: int(l.breaker.cooldown / time.Second),
+			Error:           err.Error(),
+		})
+	}
+	return err
+}
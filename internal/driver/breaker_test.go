@@ -0,0 +1,195 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	const cooldown = 30 * time.Second
+	now := time.Now()
+	cb := newCircuitBreaker(3, cooldown)
+
+	fail := func(err error) bool {
+		t.Helper()
+		if err := cb.allow(now); err != nil {
+			t.Fatalf("expected the command to be allowed in state %v; got %v", cb.state, err)
+		}
+		return cb.done(now, err)
+	}
+
+	expectState := func(state circuitState) {
+		t.Helper()
+		if cb.state != state {
+			t.Fatalf("expected the circuit to be %v; got %v", state, cb.state)
+		}
+	}
+
+	// Rejections and cancellations don't count against the Reader.
+	for i := 0; i < 5; i++ {
+		fail(&llrp.StatusError{Status: llrp.StatusMsgParamError})
+		fail(context.Canceled)
+	}
+	expectState(circuitClosed)
+
+	// Consecutive failures open the circuit only at the threshold,
+	// and a success resets the count.
+	fail(errNoClient)
+	fail(context.DeadlineExceeded)
+	fail(nil)
+	fail(llrp.ErrClientClosed)
+	if fail(llrp.ErrClientClosed) {
+		t.Fatal("expected the circuit to stay closed below the threshold")
+	}
+	if !fail(errNoClient) {
+		t.Fatal("expected the circuit to open at the threshold")
+	}
+	expectState(circuitOpen)
+
+	// While open, commands fail fast.
+	now = now.Add(cooldown - time.Second)
+	if err := cb.allow(now); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the command to fail fast; got %v", err)
+	}
+
+	// After the cooldown, the circuit half-opens to allow exactly one probe.
+	now = now.Add(time.Second)
+	if err := cb.allow(now); err != nil {
+		t.Fatalf("expected a probe to be allowed; got %v", err)
+	}
+	expectState(circuitHalfOpen)
+	if err := cb.allow(now); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected only one probe at a time; got %v", err)
+	}
+
+	// A failed probe reopens the circuit without announcing it again.
+	if cb.done(now, context.DeadlineExceeded) {
+		t.Error("expected a failed probe not to be announced")
+	}
+	expectState(circuitOpen)
+	if err := cb.allow(now.Add(time.Second)); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the cooldown to restart after a failed probe; got %v", err)
+	}
+
+	// A canceled probe lets another command probe.
+	now = now.Add(cooldown)
+	if err := cb.allow(now); err != nil {
+		t.Fatalf("expected a probe to be allowed; got %v", err)
+	}
+	cb.done(now, context.Canceled)
+	expectState(circuitHalfOpen)
+
+	// A probe that reaches the Reader closes the circuit.
+	fail(&llrp.StatusError{Status: llrp.StatusMsgParamError})
+	expectState(circuitClosed)
+	if cb.failures != 0 {
+		t.Errorf("expected the failure count to reset; got %d", cb.failures)
+	}
+
+	// reset closes an open circuit, as when the Reader reconnects.
+	for i := 0; i < 3; i++ {
+		fail(errNoClient)
+	}
+	expectState(circuitOpen)
+	cb.reset()
+	expectState(circuitClosed)
+	if err := cb.allow(now); err != nil {
+		t.Errorf("expected commands to be allowed after a reset; got %v", err)
+	}
+
+	// Disabled breakers never open.
+	disabled := newCircuitBreaker(0, cooldown)
+	for i := 0; i < 10; i++ {
+		if err := disabled.allow(now); err != nil {
+			t.Fatalf("expected a disabled breaker to allow commands; got %v", err)
+		}
+		if disabled.done(now, errNoClient) {
+			t.Fatal("expected a disabled breaker never to open")
+		}
+	}
+}
+
+func TestLLRPDevice_circuitBreaker(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 1)
+	clk := newFakeClock()
+	dev := &LLRPDevice{
+		name:    "deadReader",
+		lc:      edgexCompatTestLogger{t},
+		ch:      ch,
+		breaker: newCircuitBreaker(2, time.Hour),
+		clk:     clk,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Without a client, each send fails after its retries.
+	for i := 0; i < 2; i++ {
+		err := dev.TrySend(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{})
+		if !errors.Is(err, errNoClient) {
+			t.Fatalf("expected send %d to fail without a client; got %v", i, err)
+		}
+	}
+
+	// Then the circuit is open, so sends of every kind fail fast.
+	start := time.Now()
+	if err := dev.TrySend(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected TrySend to fail fast; got %v", err)
+	}
+	if _, _, err := dev.TrySendRaw(ctx, llrp.MsgGetReaderConfig, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected TrySendRaw to fail fast; got %v", err)
+	}
+	if err := dev.TrySendNoWait(ctx, &llrp.KeepAliveAck{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected TrySendNoWait to fail fast; got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected sends to fail fast; took %v", elapsed)
+	}
+
+	// Opening the circuit sends a single event.
+	select {
+	case av := <-ch:
+		if len(av.CommandValues) != 1 || av.CommandValues[0].DeviceResourceName != ResourceDeviceCircuitOpen {
+			t.Fatalf("expected a %s event; got %+v", ResourceDeviceCircuitOpen, av)
+		}
+
+		var event circuitOpenEvent
+		s, err := av.CommandValues[0].StringValue()
+		if err == nil {
+			err = json.Unmarshal([]byte(s), &event)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.Failures != 2 || event.CooldownSeconds != 3600 || event.Error == "" {
+			t.Errorf("unexpected event: %+v", event)
+		}
+	case <-ctx.Done():
+		t.Fatalf("expected a %s event", ResourceDeviceCircuitOpen)
+	}
+
+	select {
+	case av := <-ch:
+		t.Errorf("expected only one event; got %+v", av)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// After the cooldown, a send probes the Reader.
+	clk.Advance(time.Hour)
+	if _, _, err := dev.TrySendRaw(ctx, llrp.MsgGetReaderConfig, nil); !errors.Is(err, errNoClient) {
+		t.Errorf("expected the probe to be sent after the cooldown; got %v", err)
+	}
+	if _, _, err := dev.TrySendRaw(ctx, llrp.MsgGetReaderConfig, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected the failed probe to reopen the circuit; got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+)
+
+// ResourceReportBufferLevel is the percentage of the Reader's report buffer in use,
+// as of the Reader's most recent report buffer event.
+const ResourceReportBufferLevel = "ReportBufferLevel"
+
+// recordBufferLevel notes the report buffer level in the notification data, if it has one.
+// LLRP has no request for the level, so it's only known from these events:
+// a ReportBufferLevelWarningEvent gives the level, and a ReportBufferOverflowErrorEvent means it's full.
+func (l *LLRPDevice) recordBufferLevel(data *llrp.ReaderEventNotificationData) {
+	var level uint8
+	switch {
+	case data.ReportBufferOverflowErrorEvent != nil:
+		level = 100
+	case data.ReportBufferLevelWarningEvent != nil:
+		level = uint8(*data.ReportBufferLevelWarningEvent)
+	default:
+		return
+	}
+
+	l.deviceMu.Lock()
+	l.bufferLevel = &level
+	l.deviceMu.Unlock()
+}
+
+// ReportBufferLevel returns the percentage of the Reader's report buffer in use,
+// as of the most recent report buffer event the Reader sent since the service connected.
+// It returns an error if the Reader's capabilities show it doesn't send them,
+// or if it hasn't sent one, e.g. because the event isn't enabled in its ReaderConfig
+// or the buffer hasn't yet filled to the Reader's warning threshold.
+func (l *LLRPDevice) ReportBufferLevel(ctx context.Context) (uint8, error) {
+	if caps, err := l.capabilities(ctx); err == nil {
+		if err := checkReportBufferLevel(caps); err != nil {
+			return 0, err
+		}
+	}
+
+	l.deviceMu.RLock()
+	level := l.bufferLevel
+	l.deviceMu.RUnlock()
+
+	if level == nil {
+		return 0, errors.New("Reader hasn't reported its report buffer level since the service connected")
+	}
+	return *level, nil
+}
+
+// checkReportBufferLevel returns an error if the capabilities show
+// the Reader doesn't send report buffer fill warnings.
+func checkReportBufferLevel(caps *llrp.GetReaderCapabilitiesResponse) error {
+	if lacksCapability(caps, ResourceCanReportBufferFillWarning) {
+		return errors.New("Reader does not support report buffer fill warnings")
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_ReportBufferLevel(t *testing.T) {
+	l := &LLRPDevice{
+		name: "localReader",
+		lc:   edgexCompatTestLogger{t},
+		ch:   make(chan *dsModels.AsyncValues, 10),
+		clk:  newFakeClock(),
+		caps: &llrp.GetReaderCapabilitiesResponse{
+			LLRPCapabilities: &llrp.LLRPCapabilities{CanReportBufferFillWarning: true},
+		},
+	}
+	handler := l.newReaderEventHandler(nil)
+
+	notify := func(data llrp.ReaderEventNotificationData) {
+		t.Helper()
+		data.UTCTimestamp = llrp.UTCTimestamp(l.clock().Now().UnixNano() / 1000)
+		payload, err := (&llrp.ReaderEventNotification{ReaderEventNotificationData: data}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := llrp.NewByteMessage(llrp.MsgReaderEventNotification, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler.HandleMessage(nil, msg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if level, err := l.ReportBufferLevel(ctx); err == nil {
+		t.Errorf("expected an error before the Reader reports its level; got %d", level)
+	}
+
+	warning := llrp.ReportBufferLevelWarningEvent(85)
+	notify(llrp.ReaderEventNotificationData{ReportBufferLevelWarningEvent: &warning})
+	if level, err := l.ReportBufferLevel(ctx); err != nil || level != 85 {
+		t.Errorf("expected 85%%; got %d, %v", level, err)
+	}
+
+	// Other events don't change it.
+	notify(llrp.ReaderEventNotificationData{
+		AntennaEvent: &llrp.AntennaEvent{Event: llrp.AntennaConnected, AntennaID: 1},
+	})
+	if level, err := l.ReportBufferLevel(ctx); err != nil || level != 85 {
+		t.Errorf("expected 85%%; got %d, %v", level, err)
+	}
+
+	// An overflow means the buffer is full.
+	notify(llrp.ReaderEventNotificationData{ReportBufferOverflowErrorEvent: &llrp.ReportBufferOverflowErrorEvent{}})
+	if level, err := l.ReportBufferLevel(ctx); err != nil || level != 100 {
+		t.Errorf("expected 100%%; got %d, %v", level, err)
+	}
+
+	// Readers that can't report the level are unsupported.
+	l.caps.LLRPCapabilities.CanReportBufferFillWarning = false
+	if level, err := l.ReportBufferLevel(ctx); err == nil {
+		t.Errorf("expected an error for an unsupported Reader; got %d", level)
+	}
+}
@@ -0,0 +1,111 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// DeviceDefinition describes a device to add with AddDevices.
+type DeviceDefinition struct {
+	Name       string
+	Protocols  protocolMap
+	AdminState contract.AdminState
+}
+
+// AddDevices adds a batch of devices, as AddDevice does for each,
+// working on at most MaxConcurrentDeviceChanges of them at once.
+//
+// It returns the result for each device by name: nil if it was added,
+// or else the reason it wasn't. One device's failure doesn't stop the others.
+// A name that's empty or listed more than once fails without being added.
+func (d *Driver) AddDevices(devices []DeviceDefinition) map[string]error {
+	names := make([]string, len(devices))
+	for i := range devices {
+		names[i] = devices[i].Name
+	}
+
+	return d.forEachDevice(names, func(i int) error {
+		return d.AddDevice(devices[i].Name, devices[i].Protocols, devices[i].AdminState)
+	})
+}
+
+// RemoveDevices removes a batch of devices, as RemoveDevice does for each,
+// working on at most MaxConcurrentDeviceChanges of them at once.
+//
+// It returns the result for each device by name: nil if it was removed,
+// or else the reason it wasn't, such as the Driver not managing a device by that name.
+// As with RemoveDevice, a connection that doesn't shut down gracefully is closed
+// and only logged. One device's failure doesn't stop the others.
+func (d *Driver) RemoveDevices(names []string) map[string]error {
+	return d.forEachDevice(names, func(i int) error {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+
+		if !d.removeDevice(ctx, names[i]) {
+			return errors.Errorf("device %q isn't managed by this service", names[i])
+		}
+		return nil
+	})
+}
+
+// forEachDevice calls fn with the index of each device name,
+// concurrently up to the configured MaxConcurrentDeviceChanges,
+// and returns the result for each name.
+// Empty and duplicate names fail without calling fn.
+func (d *Driver) forEachDevice(names []string, fn func(i int) error) map[string]error {
+	var maxChanges int
+	d.configMu.RLock()
+	if d.config != nil {
+		maxChanges = d.config.MaxConcurrentDeviceChanges
+	}
+	d.configMu.RUnlock()
+
+	counts := make(map[string]int, len(names))
+	for _, name := range names {
+		counts[name]++
+	}
+
+	results := make(map[string]error, len(names))
+	for name, n := range counts {
+		switch {
+		case name == "":
+			results[name] = errors.New("device name is empty")
+		case n > 1:
+			results[name] = errors.Errorf("device %q is listed %d times", name, n)
+		}
+	}
+
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+	limiter := newCommandLimiter(maxChanges, false)
+
+	for i, name := range names {
+		if name == "" || counts[name] > 1 {
+			continue
+		}
+
+		// This never fails, as the limiter waits and the context is never done.
+		_ = limiter.acquire(context.Background())
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer limiter.release()
+
+			err := fn(i)
+
+			resultsMu.Lock()
+			results[name] = err
+			resultsMu.Unlock()
+		}(i, name)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"strconv"
+	"testing"
+)
+
+func TestDriver_AddRemoveDevices(t *testing.T) {
+	d := newTestDriver(nil)
+	d.config = &driverConfiguration{MaxConcurrentDeviceChanges: 2}
+
+	// Nothing listens on these ports, so the devices just keep trying to connect.
+	protocols := func() protocolMap {
+		return protocolMap{"tcp": {"host": "127.0.0.1", "port": strconv.Itoa(freePort(t))}}
+	}
+
+	defs := []DeviceDefinition{
+		{Name: "reader1", Protocols: protocols(), AdminState: contract.Unlocked},
+		{Name: "reader2", Protocols: protocols(), AdminState: contract.Unlocked},
+		{Name: "reader3", Protocols: protocols(), AdminState: contract.Unlocked},
+		{Name: "noAddress", Protocols: protocolMap{}, AdminState: contract.Unlocked},
+		{Name: "twice", Protocols: protocols(), AdminState: contract.Unlocked},
+		{Name: "twice", Protocols: protocols(), AdminState: contract.Unlocked},
+		{Name: "", Protocols: protocols(), AdminState: contract.Unlocked},
+	}
+
+	results := d.AddDevices(defs)
+	if len(results) != 6 {
+		t.Errorf("expected a result for each unique name; got %v", results)
+	}
+
+	for name, shouldAdd := range map[string]bool{
+		"reader1": true, "reader2": true, "reader3": true,
+		"noAddress": false, "twice": false, "": false,
+	} {
+		err, ok := results[name]
+		if !ok {
+			t.Errorf("%q: expected a result", name)
+			continue
+		}
+		if shouldAdd != (err == nil) {
+			t.Errorf("%q: expected added: %v; got %v", name, shouldAdd, err)
+		}
+
+		d.devicesMu.RLock()
+		_, managed := d.activeDevices[name]
+		d.devicesMu.RUnlock()
+		if managed != shouldAdd {
+			t.Errorf("%q: expected managed: %v; got %v", name, shouldAdd, managed)
+		}
+	}
+
+	results = d.RemoveDevices([]string{"reader1", "reader2", "reader3", "unknown"})
+	for name, shouldRemove := range map[string]bool{
+		"reader1": true, "reader2": true, "reader3": true, "unknown": false,
+	} {
+		if err, ok := results[name]; !ok || shouldRemove != (err == nil) {
+			t.Errorf("%q: expected removed: %v; got %v", name, shouldRemove, err)
+		}
+	}
+
+	d.devicesMu.RLock()
+	defer d.devicesMu.RUnlock()
+	if len(d.activeDevices) != 0 {
+		t.Errorf("expected no devices to remain; got %v", d.activeDevices)
+	}
+}
@@ -0,0 +1,152 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"sync"
+	"time"
+)
+
+// tagReadCache is a fixed-size ring buffer of the most recent TagReportData
+// received from a Reader, allowing clients to poll for reads
+// rather than consume the async stream.
+//
+// It is safe for concurrent use.
+// A cache with a capacity of 0 discards everything added to it.
+type tagReadCache struct {
+	mu    sync.Mutex
+	reads []llrp.TagReportData
+	next  int  // index at which the next read will be written
+	full  bool // true once next has wrapped around at least once
+}
+
+// newTagReadCache returns a tagReadCache holding at most size reads.
+// If size is less than 1, the cache discards all reads.
+func newTagReadCache(size int) *tagReadCache {
+	if size < 0 {
+		size = 0
+	}
+	return &tagReadCache{reads: make([]llrp.TagReportData, size)}
+}
+
+// add appends the reads to the cache, overwriting the oldest ones if it's full.
+func (c *tagReadCache) add(reads ...llrp.TagReportData) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.reads) == 0 {
+		return
+	}
+
+	for _, r := range reads {
+		c.reads[c.next] = r
+		c.next++
+		if c.next == len(c.reads) {
+			c.next = 0
+			c.full = true
+		}
+	}
+}
+
+// latest returns a copy of the cached reads, ordered oldest to newest.
+// If no reads have been cached, it returns an empty, non-nil slice.
+func (c *tagReadCache) latest() []llrp.TagReportData {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.full {
+		return append([]llrp.TagReportData{}, c.reads[:c.next]...)
+	}
+
+	out := make([]llrp.TagReportData, 0, len(c.reads))
+	out = append(out, c.reads[c.next:]...)
+	return append(out, c.reads[:c.next]...)
+}
+
+// cachedReport returns the device's cached reads as an ROAccessReport
+// labeled just as the ROAccessReport events it sends to EdgeX are,
+// so polling clients and event consumers can parse them the same way.
+func (l *LLRPDevice) cachedReport() (reads []llrp.TagReportData, report interface{}) {
+	reads = l.reads.latest()
+
+	l.deviceMu.RLock()
+	locations := l.antennaLocations
+	l.deviceMu.RUnlock()
+
+	var readOps map[uint32]llrp.C1G2Read
+	if hasReadResults(reads) {
+		readOps = l.specs.readOps()
+	}
+
+	return reads, withLocations(locations, readOps, &llrp.ROAccessReport{TagReportData: reads})
+}
+
+// tagCounter tracks the unique tags a Reader has reported recently,
+// so clients can poll for a tag population count without receiving every EPC.
+//
+// If its window is positive, it counts the tags seen within that much time
+// before the count; otherwise, it counts the tags seen since the previous count.
+//
+// It is safe for concurrent use.
+type tagCounter struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string]time.Time // EPC -> when we last received a report with it
+}
+
+// newTagCounter returns a tagCounter using the given window.
+func newTagCounter(window time.Duration) *tagCounter {
+	return &tagCounter{window: window, seen: make(map[string]time.Time)}
+}
+
+// add records the reads' EPCs as seen at the given time.
+// Reads without an EPC are ignored.
+func (tc *tagCounter) add(now time.Time, reads ...llrp.TagReportData) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	for i := range reads {
+		epc := reads[i].EPC()
+		if len(epc) == 0 {
+			continue
+		}
+		tc.seen[string(epc)] = now
+	}
+
+	tc.pruneLocked(now)
+}
+
+// count returns the number of unique tags seen in the current window.
+// If the window isn't positive, it also resets the count.
+func (tc *tagCounter) count(now time.Time) int {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.window <= 0 {
+		n := len(tc.seen)
+		tc.seen = make(map[string]time.Time)
+		return n
+	}
+
+	tc.pruneLocked(now)
+	return len(tc.seen)
+}
+
+// pruneLocked removes tags last seen before the window.
+// The caller must hold the lock.
+func (tc *tagCounter) pruneLocked(now time.Time) {
+	if tc.window <= 0 {
+		return
+	}
+
+	cutoff := now.Add(-tc.window)
+	for epc, lastSeen := range tc.seen {
+		if lastSeen.Before(cutoff) {
+			delete(tc.seen, epc)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+)
+
+// capabilities returns the Reader's capabilities,
+// requesting them from the Reader if they haven't been cached since it connected.
+// If they can't be retrieved, this returns nil and the error.
+func (l *LLRPDevice) capabilities(ctx context.Context) (*llrp.GetReaderCapabilitiesResponse, error) {
+	l.deviceMu.RLock()
+	caps := l.caps
+	l.deviceMu.RUnlock()
+
+	if caps != nil {
+		return caps, nil
+	}
+
+	caps = &llrp.GetReaderCapabilitiesResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderCapabilities{}, caps); err != nil {
+		return nil, err
+	}
+
+	l.setCapabilities(caps)
+	return caps, nil
+}
+
+// setCapabilities caches the Reader's capabilities until it reconnects.
+func (l *LLRPDevice) setCapabilities(caps *llrp.GetReaderCapabilitiesResponse) {
+	l.deviceMu.Lock()
+	l.caps = caps
+	l.deviceMu.Unlock()
+}
+
+// checkSupported returns an error if the message has invalid values
+// or the Reader's capabilities show it can't handle the message,
+// so we can reject it rather than send a request the Reader is sure to refuse.
+//
+// If the capabilities aren't available, this only checks the values
+// and lets the Reader decide the rest.
+func (l *LLRPDevice) checkSupported(ctx context.Context, msg llrp.Outgoing) error {
+	if err := checkValid(msg); err != nil {
+		return err
+	}
+
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		l.lc.Debug("Reader capabilities unavailable; skipping capability checks.",
+			"device", l.name, "error", err.Error())
+		return nil
+	}
+
+	return checkSupported(caps, msg)
+}
+
+// checkSupported returns an error describing the first feature
+// the message requires that the capabilities say the Reader lacks.
+// Messages that don't require any particular capability always pass,
+// as do features governed by capabilities the Reader didn't report.
+func checkSupported(caps *llrp.GetReaderCapabilitiesResponse, msg llrp.Outgoing) error {
+	gen := caps.GeneralDeviceCapabilities
+	llrpCaps := caps.LLRPCapabilities
+
+	checkGPI := func(what string, port uint16) error {
+		if gen == nil {
+			return nil
+		}
+		if gen.GPIOCapabilities.NumGPIs == 0 {
+			return errors.Errorf("Reader does not support %s: it has no GPI ports", what)
+		}
+		if port > gen.GPIOCapabilities.NumGPIs {
+			return errors.Errorf("Reader does not support %s on GPI port %d: it has only %d",
+				what, port, gen.GPIOCapabilities.NumGPIs)
+		}
+		return nil
+	}
+
+	checkAntenna := func(what string, id llrp.AntennaID) error {
+		// AntennaID 0 means "all antennas".
+		if gen != nil && gen.MaxSupportedAntennas != 0 && uint16(id) > gen.MaxSupportedAntennas {
+			return errors.Errorf("Reader does not support %s on antenna %d: it supports only %d",
+				what, id, gen.MaxSupportedAntennas)
+		}
+		return nil
+	}
+
+	switch m := msg.(type) {
+	case *llrp.AddROSpec:
+		spec := &m.ROSpec
+
+		if llrpCaps != nil && spec.Priority > llrpCaps.MaxPriorityLevelSupported {
+			return errors.Errorf("Reader does not support ROSpec priority %d: its max is %d",
+				spec.Priority, llrpCaps.MaxPriorityLevelSupported)
+		}
+
+		if len(spec.RFSurveySpecs) != 0 && lacksCapability(caps, ResourceCanDoRFSurvey) {
+			return errors.New("Reader does not support RFSurveySpecs")
+		}
+
+		nSpecs := len(spec.AISpecs) + len(spec.RFSurveySpecs)
+		if llrpCaps != nil && llrpCaps.MaxSpecsPerROSpec != 0 && uint32(nSpecs) > llrpCaps.MaxSpecsPerROSpec {
+			return errors.Errorf("Reader does not support %d specs per ROSpec: its max is %d",
+				nSpecs, llrpCaps.MaxSpecsPerROSpec)
+		}
+
+		start := spec.ROBoundarySpec.StartTrigger
+		if start.Trigger == llrp.ROStartTriggerGPI && start.GPITrigger != nil {
+			if err := checkGPI("ROSpec GPI start triggers", start.GPITrigger.Port); err != nil {
+				return err
+			}
+		}
+
+		stop := spec.ROBoundarySpec.StopTrigger
+		if stop.Trigger == llrp.ROStopTriggerGPI && stop.GPITriggerValue != nil {
+			if err := checkGPI("ROSpec GPI stop triggers", stop.GPITriggerValue.Port); err != nil {
+				return err
+			}
+		}
+
+		for _, ai := range spec.AISpecs {
+			nInv := len(ai.InventoryParameterSpecs)
+			if llrpCaps != nil && llrpCaps.MaxInventoryParameterSpecsPerAISpec != 0 &&
+				uint32(nInv) > llrpCaps.MaxInventoryParameterSpecsPerAISpec {
+				return errors.Errorf("Reader does not support %d InventoryParameterSpecs per AISpec: "+
+					"its max is %d", nInv, llrpCaps.MaxInventoryParameterSpecsPerAISpec)
+			}
+
+			if ai.StopTrigger.Trigger == llrp.AIStopTriggerGPI && ai.StopTrigger.GPITrigger != nil {
+				if err := checkGPI("AISpec GPI stop triggers", ai.StopTrigger.GPITrigger.Port); err != nil {
+					return err
+				}
+			}
+
+			for _, id := range ai.AntennaIDs {
+				if err := checkAntenna("AISpecs", id); err != nil {
+					return err
+				}
+				for _, ips := range ai.InventoryParameterSpecs {
+					if err := checkAirProtocol(caps, id, ips.AirProtocolID); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		for _, rf := range spec.RFSurveySpecs {
+			if err := checkAntenna("RFSurveySpecs", rf.AntennaID); err != nil {
+				return err
+			}
+		}
+
+	case *llrp.AddAccessSpec:
+		spec := &m.AccessSpec
+
+		if spec.AccessCommand.ClientRequestOpSpec != nil &&
+			lacksCapability(caps, ResourceSupportsClientRequestOpSpec) {
+			return errors.New("Reader does not support ClientRequestOpSpecs")
+		}
+
+		if err := checkAntenna("AccessSpecs", spec.AntennaID); err != nil {
+			return err
+		}
+		if err := checkAirProtocol(caps, spec.AntennaID, spec.AirProtocolID); err != nil {
+			return err
+		}
+
+	case *llrp.SetReaderConfig:
+		if gen != nil && len(m.AntennaProperties) != 0 && !gen.CanSetAntennaProperties {
+			return errors.New("Reader does not support setting AntennaProperties")
+		}
+
+		for _, ac := range m.AntennaConfigurations {
+			if ac.RFReceiver == nil && ac.RFTransmitter == nil {
+				continue
+			}
+			if err := checkAntenna("AntennaConfigurations", ac.AntennaID); err != nil {
+				return err
+			}
+			if gen != nil && ac.RFReceiver != nil {
+				if err := checkSensitivityIndex(gen, ac.AntennaID, uint16(*ac.RFReceiver)); err != nil {
+					return err
+				}
+			}
+			if ac.RFTransmitter != nil {
+				if err := checkPowerIndex(caps, ac.RFTransmitter.TransmitPowerIndex); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, gpo := range m.GPOWriteData {
+			if gen == nil {
+				break
+			}
+			if gen.GPIOCapabilities.NumGPOs == 0 {
+				return errors.New("Reader does not support GPOWriteData: it has no GPO ports")
+			}
+			if gpo.Port > gen.GPIOCapabilities.NumGPOs {
+				return errors.Errorf("Reader does not support GPOWriteData on GPO port %d: it has only %d",
+					gpo.Port, gen.GPIOCapabilities.NumGPOs)
+			}
+		}
+
+		for _, gpi := range m.GPIPortCurrentStates {
+			if err := checkGPI("GPIPortCurrentStates", gpi.Port); err != nil {
+				return err
+			}
+		}
+
+		if m.EventsAndReports != nil && bool(*m.EventsAndReports) &&
+			lacksCapability(caps, ResourceSupportsEventsAndReportHolding) {
+			return errors.New("Reader does not support holding EventsAndReports upon reconnect")
+		}
+	}
+
+	return nil
+}
+
+// checkValid returns an error if the message has values LLRP doesn't define
+// in fields the service can check without asking the Reader.
+func checkValid(msg llrp.Outgoing) error {
+	var ars *llrp.AccessReportSpec
+	switch m := msg.(type) {
+	case *llrp.AddAccessSpec:
+		ars = m.AccessSpec.AccessReportSpec
+	case *llrp.SetReaderConfig:
+		ars = m.AccessReportSpec
+	}
+
+	if ars != nil && !llrp.AccessReportTriggerType(*ars).IsValid() {
+		return errors.Errorf("invalid AccessReportSpec trigger %d: it must be %d (with ROReports) "+
+			"or %d (at the end of the AccessSpec)", *ars,
+			llrp.AccessReportWithROReport, llrp.AccessReportEndOfAccessSpec)
+	}
+
+	if add, ok := msg.(*llrp.AddAccessSpec); ok {
+		if err := checkAccessStopTrigger(add.AccessSpec.Trigger); err != nil {
+			return err
+		}
+	}
+
+	if add, ok := msg.(*llrp.AddROSpec); ok {
+		for i := range add.ROSpec.AISpecs {
+			if err := checkAntennaIDs(add.ROSpec.AISpecs[i].AntennaIDs); err != nil {
+				return errors.WithMessagef(err, "invalid AISpec %d", i+1)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkAntennaIDs returns an error if an AISpec's AntennaIDs are empty,
+// in which case the Reader wouldn't read with any antennas,
+// or if they combine llrp.AllAntennas with other IDs.
+// Whether the Reader has the antennas is up to checkSupported.
+func checkAntennaIDs(ids []llrp.AntennaID) error {
+	if len(ids) == 0 {
+		return errors.Errorf("it has no AntennaIDs; use [%d] for all antennas", llrp.AllAntennas)
+	}
+
+	if len(ids) > 1 {
+		for _, id := range ids {
+			if id == llrp.AllAntennas {
+				return errors.Errorf("its AntennaIDs %v include %d (all antennas) with other IDs; "+
+					"use either [%d] alone or the specific antennas", ids, llrp.AllAntennas, llrp.AllAntennas)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strings"
+	"testing"
+)
+
+func TestCheckSupported(t *testing.T) {
+	caps := &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			MaxSupportedAntennas: 4,
+			GPIOCapabilities:     llrp.GPIOCapabilities{NumGPIs: 0, NumGPOs: 2},
+		},
+		LLRPCapabilities: &llrp.LLRPCapabilities{
+			MaxPriorityLevelSupported:           1,
+			MaxSpecsPerROSpec:                   2,
+			MaxInventoryParameterSpecsPerAISpec: 1,
+		},
+	}
+
+	hold := llrp.EventsAndReports(true)
+	opSpecID := llrp.ClientRequestOpSpec(1)
+
+	for _, testCase := range []struct {
+		name        string
+		msg         llrp.Outgoing
+		unsupported string // if set, the error must mention it
+	}{
+		{name: "simpleROSpec", msg: &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+			AISpecs: []llrp.AISpec{{AntennaIDs: []llrp.AntennaID{1, 4}}},
+		}}},
+		{name: "rfSurvey", unsupported: "RFSurveySpecs", msg: &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+			RFSurveySpecs: []llrp.RFSurveySpec{{AntennaID: 1}},
+		}}},
+		{name: "priority", unsupported: "priority", msg: &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+			Priority: 2,
+		}}},
+		{name: "antenna", unsupported: "antenna 5", msg: &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+			AISpecs: []llrp.AISpec{{AntennaIDs: []llrp.AntennaID{5}}},
+		}}},
+		{name: "tooManySpecs", unsupported: "specs per ROSpec", msg: &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+			AISpecs: []llrp.AISpec{{AntennaIDs: []llrp.AntennaID{1}}, {AntennaIDs: []llrp.AntennaID{2}},
+				{AntennaIDs: []llrp.AntennaID{3}}},
+		}}},
+		{name: "tooManyInvSpecs", unsupported: "InventoryParameterSpecs", msg: &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+			AISpecs: []llrp.AISpec{{
+				AntennaIDs:              []llrp.AntennaID{1},
+				InventoryParameterSpecs: []llrp.InventoryParameterSpec{{}, {}},
+			}},
+		}}},
+		{name: "gpiStart", unsupported: "GPI", msg: &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+			ROBoundarySpec: llrp.ROBoundarySpec{StartTrigger: llrp.ROSpecStartTrigger{
+				Trigger:    llrp.ROStartTriggerGPI,
+				GPITrigger: &llrp.GPITriggerValue{Port: 1},
+			}},
+		}}},
+		{name: "clientRequestOp", unsupported: "ClientRequestOpSpecs", msg: &llrp.AddAccessSpec{
+			AccessSpec: llrp.AccessSpec{AccessCommand: llrp.AccessCommand{
+				ClientRequestOpSpec: &opSpecID,
+			}},
+		}},
+		{name: "gpoWrite", msg: &llrp.SetReaderConfig{
+			GPOWriteData: []llrp.GPOWriteData{{Port: 2}},
+		}},
+		{name: "gpoWritePort", unsupported: "GPO port 3", msg: &llrp.SetReaderConfig{
+			GPOWriteData: []llrp.GPOWriteData{{Port: 3}},
+		}},
+		{name: "antennaProperties", unsupported: "AntennaProperties", msg: &llrp.SetReaderConfig{
+			AntennaProperties: []llrp.AntennaProperties{{AntennaID: 1}},
+		}},
+		{name: "holdEvents", unsupported: "EventsAndReports", msg: &llrp.SetReaderConfig{
+			EventsAndReports: &hold,
+		}},
+		{name: "keepAlive", msg: &llrp.SetReaderConfig{
+			KeepAliveSpec: &llrp.KeepAliveSpec{Trigger: llrp.KATriggerPeriodic},
+		}},
+		{name: "otherMessage", msg: &llrp.StartROSpec{ROSpecID: 1}},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			err := checkSupported(caps, testCase.msg)
+
+			if testCase.unsupported == "" {
+				if err != nil {
+					t.Errorf("expected no error; got %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected an error, but didn't get one")
+			}
+
+			if !strings.Contains(err.Error(), "does not support") ||
+				!strings.Contains(err.Error(), testCase.unsupported) {
+				t.Errorf("expected error about %q; got %q", testCase.unsupported, err.Error())
+			}
+		})
+	}
+
+	// If the Reader doesn't report a capability, let it decide.
+	if err := checkSupported(&llrp.GetReaderCapabilitiesResponse{}, &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+		RFSurveySpecs: []llrp.RFSurveySpec{{AntennaID: 1}},
+	}}); err != nil {
+		t.Errorf("expected no error without capabilities; got %v", err)
+	}
+}
+
+func TestCheckValid(t *testing.T) {
+	endOfSpec := llrp.AccessReportSpec(llrp.AccessReportEndOfAccessSpec)
+	invalid := llrp.AccessReportSpec(2)
+
+	for _, testCase := range []struct {
+		name  string
+		msg   llrp.Outgoing
+		valid bool
+	}{
+		{name: "noAccessReportSpec", valid: true, msg: &llrp.AddAccessSpec{}},
+		{name: "endOfAccessSpec", valid: true, msg: &llrp.AddAccessSpec{
+			AccessSpec: llrp.AccessSpec{AccessReportSpec: &endOfSpec}}},
+		{name: "invalidAccessSpecTrigger", msg: &llrp.AddAccessSpec{
+			AccessSpec: llrp.AccessSpec{AccessReportSpec: &invalid}}},
+		{name: "readerConfig", valid: true, msg: &llrp.SetReaderConfig{AccessReportSpec: &endOfSpec}},
+		{name: "invalidReaderConfigTrigger", msg: &llrp.SetReaderConfig{AccessReportSpec: &invalid}},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			err := checkValid(testCase.msg)
+			if testCase.valid && err != nil {
+				t.Errorf("expected no error; got %v", err)
+			}
+			if !testCase.valid && (err == nil || !strings.Contains(err.Error(), "AccessReportSpec")) {
+				t.Errorf("expected an AccessReportSpec error; got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckValid_antennaIDs(t *testing.T) {
+	for _, testCase := range []struct {
+		name  string
+		ids   []llrp.AntennaID
+		valid bool
+	}{
+		{name: "allAntennas", valid: true, ids: []llrp.AntennaID{llrp.AllAntennas}},
+		{name: "someAntennas", valid: true, ids: []llrp.AntennaID{1, 3}},
+		{name: "noAntennas", ids: nil},
+		{name: "allAndSome", ids: []llrp.AntennaID{1, llrp.AllAntennas}},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			err := checkValid(&llrp.AddROSpec{ROSpec: llrp.ROSpec{AISpecs: []llrp.AISpec{
+				{AntennaIDs: []llrp.AntennaID{1}},
+				{AntennaIDs: testCase.ids},
+			}}})
+			if testCase.valid && err != nil {
+				t.Errorf("expected no error; got %v", err)
+			}
+			if !testCase.valid && (err == nil || !strings.Contains(err.Error(), "AISpec 2")) {
+				t.Errorf("expected an error about AISpec 2; got %v", err)
+			}
+		})
+	}
+}
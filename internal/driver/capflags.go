@@ -0,0 +1,67 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+)
+
+// Read-only resources that report whether the Reader's LLRPCapabilities advertise a feature.
+// Each is named for the LLRPCapabilities flag it reports.
+const (
+	ResourceCanDoRFSurvey                          = "CanDoRFSurvey"
+	ResourceCanReportBufferFillWarning             = "CanReportBufferFillWarning"
+	ResourceSupportsClientRequestOpSpec            = "SupportsClientRequestOpSpec"
+	ResourceCanDoTagInventoryStateAwareSingulation = "CanDoTagInventoryStateAwareSingulation"
+	ResourceSupportsEventsAndReportHolding         = "SupportsEventsAndReportHolding"
+)
+
+// capabilityFlags return the LLRPCapabilities flags, by resource name.
+var capabilityFlags = map[string]func(*llrp.LLRPCapabilities) bool{
+	ResourceCanDoRFSurvey: func(c *llrp.LLRPCapabilities) bool {
+		return c.CanDoRFSurvey
+	},
+	ResourceCanReportBufferFillWarning: func(c *llrp.LLRPCapabilities) bool {
+		return c.CanReportBufferFillWarning
+	},
+	ResourceSupportsClientRequestOpSpec: func(c *llrp.LLRPCapabilities) bool {
+		return c.SupportsClientRequestOpSpec
+	},
+	ResourceCanDoTagInventoryStateAwareSingulation: func(c *llrp.LLRPCapabilities) bool {
+		return c.CanDoTagInventoryStateAwareSingulation
+	},
+	ResourceSupportsEventsAndReportHolding: func(c *llrp.LLRPCapabilities) bool {
+		return c.SupportsEventsAndReportHolding
+	},
+}
+
+// lacksCapability returns true if the capabilities show the Reader lacks the feature
+// reported by the flag's resource. If the Reader didn't report its LLRPCapabilities,
+// it returns false, leaving the Reader to decide.
+func lacksCapability(caps *llrp.GetReaderCapabilitiesResponse, flag string) bool {
+	return caps != nil && caps.LLRPCapabilities != nil && !capabilityFlags[flag](caps.LLRPCapabilities)
+}
+
+// CapabilityFlag returns whether the Reader's LLRPCapabilities advertise the feature
+// reported by the flag's resource, requesting the capabilities if they aren't cached.
+func (l *LLRPDevice) CapabilityFlag(ctx context.Context, flag string) (bool, error) {
+	get, ok := capabilityFlags[flag]
+	if !ok {
+		return false, errors.Errorf("unknown capability flag %q", flag)
+	}
+
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if caps.LLRPCapabilities == nil {
+		return false, errors.New("Reader's capabilities are missing its LLRPCapabilities")
+	}
+	return get(caps.LLRPCapabilities), nil
+}
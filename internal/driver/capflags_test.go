@@ -0,0 +1,92 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strings"
+	"testing"
+)
+
+func TestHandleRead_capabilityFlags(t *testing.T) {
+	dev := &LLRPDevice{
+		clk: newFakeClock(),
+		caps: &llrp.GetReaderCapabilitiesResponse{LLRPCapabilities: &llrp.LLRPCapabilities{
+			CanDoRFSurvey:               true,
+			SupportsClientRequestOpSpec: true,
+		}},
+	}
+	d := newLocalDriver(t, dev)
+	d.clk = dev.clk
+
+	expected := map[string]bool{
+		ResourceCanDoRFSurvey:                          true,
+		ResourceCanReportBufferFillWarning:             false,
+		ResourceSupportsClientRequestOpSpec:            true,
+		ResourceCanDoTagInventoryStateAwareSingulation: false,
+		ResourceSupportsEventsAndReportHolding:         false,
+	}
+
+	var reqs []dsModels.CommandRequest
+	for name := range expected {
+		reqs = append(reqs, dsModels.CommandRequest{DeviceResourceName: name, Type: dsModels.Bool})
+	}
+
+	// The capabilities are cached, so the device needn't be connected.
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, reqs)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(cvs) != len(reqs) {
+		t.Fatalf("expected %d values; got %v", len(reqs), cvs)
+	}
+	for _, cv := range cvs {
+		supported, err := cv.BoolValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if supported != expected[cv.DeviceResourceName] {
+			t.Errorf("expected %s to be %v; got %v",
+				cv.DeviceResourceName, expected[cv.DeviceResourceName], supported)
+		}
+	}
+
+	// The flags also gate commands that need them.
+	cv, err := dsModels.NewBoolValue(ResourceHoldEventsAndReports, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.HandleWriteCommands("localReader", protocolMap{},
+		[]dsModels.CommandRequest{{DeviceResourceName: ResourceHoldEventsAndReports, Type: dsModels.Bool}},
+		[]*dsModels.CommandValue{cv})
+	if err == nil || !strings.Contains(err.Error(), "does not support") {
+		t.Errorf("expected the Reader not to support holding events and reports; got %v", err)
+	}
+
+	// Without the LLRPCapabilities, the flags are unknown.
+	dev.setCapabilities(&llrp.GetReaderCapabilitiesResponse{})
+	if _, err := d.HandleReadCommands("localReader", protocolMap{}, reqs[:1]); err == nil {
+		t.Error("expected an error without the Reader's LLRPCapabilities")
+	}
+}
+
+func TestLacksCapability(t *testing.T) {
+	caps := &llrp.GetReaderCapabilitiesResponse{LLRPCapabilities: &llrp.LLRPCapabilities{
+		CanReportBufferFillWarning: true,
+	}}
+
+	if lacksCapability(caps, ResourceCanReportBufferFillWarning) {
+		t.Error("expected the Reader to have the advertised capability")
+	}
+	if !lacksCapability(caps, ResourceCanDoRFSurvey) {
+		t.Error("expected the Reader to lack the capability it didn't advertise")
+	}
+	if lacksCapability(&llrp.GetReaderCapabilitiesResponse{}, ResourceCanDoRFSurvey) ||
+		lacksCapability(nil, ResourceCanDoRFSurvey) {
+		t.Error("expected unknown capabilities to be left to the Reader")
+	}
+}
@@ -0,0 +1,211 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+)
+
+// ResourceCommandCatalog is a read-only resource listing the commands the service handles
+// and whether the Reader's capabilities show it supports them.
+const ResourceCommandCatalog = "CommandCatalog"
+
+// Command actions in the CommandCatalog.
+const (
+	CommandRead  = "read"
+	CommandWrite = "write"
+)
+
+// AnyResource stands in for a resource name in the CommandCatalog
+// for commands the service handles on resources with any other name.
+const AnyResource = "*"
+
+// CommandCatalog lists the commands the service handles for a device.
+type CommandCatalog struct {
+	// CapabilitiesKnown is false if the service couldn't get the Reader's capabilities,
+	// in which case every command is listed as Supported,
+	// and the Reader decides whether it can handle them.
+	CapabilitiesKnown bool
+	Commands          []CommandInfo
+}
+
+// CommandInfo describes a command the service handles.
+type CommandInfo struct {
+	Resource   string
+	Action     string   // CommandRead or CommandWrite
+	Parameter  string   `json:",omitempty"` // what a write's parameter holds
+	Attributes []string `json:",omitempty"` // resource attributes the command requires
+	Requires   string   `json:",omitempty"` // the Reader capability the command requires, if any
+	Supported  bool     // false if the Reader's capabilities show it can't handle the command
+	Reason     string   `json:",omitempty"` // why the command isn't Supported
+}
+
+// catalogCommand is a command in the CommandCatalog,
+// along with the check its handler uses to reject it for Readers that lack a capability.
+type catalogCommand struct {
+	CommandInfo
+	check func(caps *llrp.GetReaderCapabilitiesResponse) error // nil if it needs no capability
+}
+
+// catalogCommands lists the commands the service handles, in the order they're reported.
+// Commands added to the read and write handlers should be added here, too.
+var catalogCommands = []catalogCommand{
+	{CommandInfo: CommandInfo{Resource: ResourceReaderCap, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceReaderConfig, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceReaderID, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceROSpec, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceAccessSpec, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceROAccessReport, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceTagCount, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceAntennaStatus, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceAntennaProperties, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceReceiverSensitivity, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceReceiverSensitivityRange, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceTransmitPower, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceAirProtocols, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceSelfTest, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceClockSkew, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceCommandCatalog, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceHoldEventsAndReports, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceCommandLatency, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourcePendingRequests, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceAsyncQueueUsage, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceCanDoRFSurvey, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceCanReportBufferFillWarning, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceSupportsClientRequestOpSpec, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceCanDoTagInventoryStateAwareSingulation, Action: CommandRead}},
+	{CommandInfo: CommandInfo{Resource: ResourceSupportsEventsAndReportHolding, Action: CommandRead}},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceRFSurvey, Action: CommandRead, Requires: ResourceCanDoRFSurvey},
+		check:       checkRFSurvey,
+	},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceReportBufferLevel, Action: CommandRead,
+			Requires: ResourceCanReportBufferFillWarning},
+		check: checkReportBufferLevel,
+	},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceEventHistory, Action: CommandRead,
+			Requires: "a vendor with a known event history format"},
+		check: checkEventHistory,
+	},
+	{CommandInfo: CommandInfo{Resource: AnyResource, Action: CommandRead,
+		Attributes: []string{AttribRequestedData}}},
+
+	{CommandInfo: CommandInfo{Resource: ResourceReaderConfig, Action: CommandWrite,
+		Parameter: "JSON SetReaderConfig"}},
+	{CommandInfo: CommandInfo{Resource: ResourceROSpec, Action: CommandWrite,
+		Parameter: "JSON ROSpec"}},
+	{CommandInfo: CommandInfo{Resource: ResourceAccessSpec, Action: CommandWrite,
+		Parameter: "JSON AccessSpec"}},
+	{CommandInfo: CommandInfo{Resource: ResourceVerifiedAccessSpec, Action: CommandWrite,
+		Parameter: "JSON AccessSpec"}},
+	{CommandInfo: CommandInfo{Resource: ResourceDwellROSpec, Action: CommandWrite,
+		Parameter: "JSON object with an ROSpec and a list of AntennaDwell"}},
+	{CommandInfo: CommandInfo{Resource: ResourceROSpecID, Action: CommandWrite,
+		Parameter: "ROSpecID with an Action of " + ActionEnable + ", " + ActionStart + ", " +
+			ActionStop + ", " + ActionDisable + ", " + ActionDelete + ", or " + ActionDeleteVerified}},
+	{CommandInfo: CommandInfo{Resource: ResourceAccessSpecID, Action: CommandWrite,
+		Parameter: "AccessSpecID with an Action of " + ActionEnable + ", " +
+			ActionDisable + ", or " + ActionDelete}},
+	{CommandInfo: CommandInfo{Resource: ResourceEventsAndReports, Action: CommandWrite,
+		Parameter: ActionEnable + " or " + ActionDisable}},
+	{CommandInfo: CommandInfo{Resource: ResourceHoldEventsAndReports, Action: CommandWrite,
+		Parameter: "true or false", Requires: ResourceSupportsEventsAndReportHolding + " to set it"}},
+	{CommandInfo: CommandInfo{Resource: ResourceRawMessage, Action: CommandWrite,
+		Parameter: "hex-encoded message payload with a RawMessageType"}},
+	{CommandInfo: CommandInfo{Resource: ResourceResetROSpecs, Action: CommandWrite,
+		Parameter: "JSON ROSpec, or " + BaselineDefault + " for the configured BaselineROSpec"}},
+	{CommandInfo: CommandInfo{Resource: ResourceReading, Action: CommandWrite,
+		Parameter: ActionPause + " or " + ActionResume}},
+	{CommandInfo: CommandInfo{Resource: ResourceMigrateAddress, Action: CommandWrite,
+		Parameter: "JSON object with the new Host and Port"}},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceRFSurvey, Action: CommandWrite,
+			Parameter: "JSON RFSurveySpec", Requires: ResourceCanDoRFSurvey},
+		check: checkRFSurvey,
+	},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceGPOPulse, Action: CommandWrite,
+			Parameter: "JSON object with a Port and DurationMillis", Requires: "NumGPOs"},
+		check: checkGPOPulse,
+	},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceAntennaProperties, Action: CommandWrite,
+			Parameter: "JSON list of objects with an AntennaID and GainDBi", Requires: "CanSetAntennaProperties"},
+		check: checkAntennaProperties,
+	},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceReceiverSensitivity, Action: CommandWrite,
+			Parameter: "JSON object with an AntennaID and Index", Requires: "ReceiveSensitivities"},
+		check: checkReceiverSensitivity,
+	},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceTransmitPower, Action: CommandWrite,
+			Parameter: "JSON object with an AntennaID and PowerDBm", Requires: "TransmitPowerLevels"},
+		check: checkTransmitPower,
+	},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceFastIDROSpec, Action: CommandWrite,
+			Parameter: "JSON ROSpec", Requires: "an Impinj Reader"},
+		check: checkFastID,
+	},
+	{
+		CommandInfo: CommandInfo{Resource: ResourceEventHistory, Action: CommandWrite,
+			Parameter: ActionClear, Requires: "a vendor with a known event history format"},
+		check: checkEventHistory,
+	},
+	{CommandInfo: CommandInfo{Resource: AnyResource, Action: CommandWrite,
+		Parameter: "base64-encoded CustomMessage payload", Attributes: []string{AttribVendor, AttribSubtype}}},
+}
+
+// checkEventHistory returns an error if the capabilities show
+// the Reader's vendor has no known event history format.
+func checkEventHistory(caps *llrp.GetReaderCapabilitiesResponse) error {
+	_, _, err := eventLogFormatFor(caps)
+	return err
+}
+
+// checkGPOPulse returns an error if the capabilities show the Reader has no GPO ports.
+func checkGPOPulse(caps *llrp.GetReaderCapabilitiesResponse) error {
+	return checkSupported(caps, gpoWrite(1, true))
+}
+
+// CommandCatalog returns the commands the service handles for the device,
+// checking each against the Reader's capabilities, if they're available.
+func (l *LLRPDevice) CommandCatalog(ctx context.Context) CommandCatalog {
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		l.lc.Debug("Reader capabilities unavailable; listing all commands as supported.",
+			"device", l.name, "error", err.Error())
+	}
+
+	return newCommandCatalog(caps)
+}
+
+// newCommandCatalog returns the CommandCatalog for a Reader with the capabilities,
+// which may be nil if they're unknown.
+func newCommandCatalog(caps *llrp.GetReaderCapabilitiesResponse) CommandCatalog {
+	catalog := CommandCatalog{
+		CapabilitiesKnown: caps != nil,
+		Commands:          make([]CommandInfo, len(catalogCommands)),
+	}
+
+	for i, cmd := range catalogCommands {
+		info := cmd.CommandInfo
+		info.Supported = true
+		if caps != nil && cmd.check != nil {
+			if err := cmd.check(caps); err != nil {
+				info.Supported = false
+				info.Reason = err.Error()
+			}
+		}
+		catalog.Commands[i] = info
+	}
+
+	return catalog
+}
@@ -0,0 +1,156 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strings"
+	"testing"
+)
+
+func findCommand(t *testing.T, catalog CommandCatalog, resource, action string) CommandInfo {
+	t.Helper()
+	for _, cmd := range catalog.Commands {
+		if cmd.Resource == resource && cmd.Action == action {
+			return cmd
+		}
+	}
+	t.Fatalf("expected the catalog to list %s %s", action, resource)
+	return CommandInfo{}
+}
+
+func TestNewCommandCatalog(t *testing.T) {
+	// Without capabilities, the Reader decides.
+	catalog := newCommandCatalog(nil)
+	if catalog.CapabilitiesKnown {
+		t.Error("expected unknown capabilities")
+	}
+	for _, cmd := range catalog.Commands {
+		if !cmd.Supported || cmd.Reason != "" {
+			t.Errorf("expected %s %s to be supported; got %+v", cmd.Action, cmd.Resource, cmd)
+		}
+	}
+
+	// A Reader without these capabilities doesn't support the commands that require them.
+	catalog = newCommandCatalog(&llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			DeviceManufacturer: uint32(Zebra),
+		},
+		LLRPCapabilities: &llrp.LLRPCapabilities{
+			CanReportBufferFillWarning: true,
+		},
+	})
+	if !catalog.CapabilitiesKnown {
+		t.Error("expected known capabilities")
+	}
+
+	for _, testCase := range []struct {
+		resource, action string
+		supported        bool
+	}{
+		{ResourceReaderConfig, CommandRead, true},
+		{ResourceROSpec, CommandWrite, true},
+		{ResourceReportBufferLevel, CommandRead, true},
+		{ResourceRFSurvey, CommandRead, false},
+		{ResourceRFSurvey, CommandWrite, false},
+		{ResourceGPOPulse, CommandWrite, false},
+		{ResourceAntennaProperties, CommandRead, true},
+		{ResourceAntennaProperties, CommandWrite, false},
+		{ResourceFastIDROSpec, CommandWrite, false},
+		{ResourceEventHistory, CommandRead, false},
+	} {
+		cmd := findCommand(t, catalog, testCase.resource, testCase.action)
+		if cmd.Supported != testCase.supported {
+			t.Errorf("%s %s: expected supported: %v; got %+v",
+				testCase.action, testCase.resource, testCase.supported, cmd)
+		}
+		if !cmd.Supported && !strings.Contains(cmd.Reason, "does not support") {
+			t.Errorf("%s %s: expected a reason; got %+v", testCase.action, testCase.resource, cmd)
+		}
+	}
+}
+
+// TestCommandCatalog_matchesHandlers checks that the catalog's reasons
+// match the errors the handlers return for a Reader lacking the capability.
+func TestCommandCatalog_matchesHandlers(t *testing.T) {
+	dev := &LLRPDevice{
+		name: "localReader",
+		lc:   edgexCompatTestLogger{t},
+		caps: &llrp.GetReaderCapabilitiesResponse{
+			GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+				DeviceManufacturer: uint32(Zebra),
+			},
+			LLRPCapabilities: &llrp.LLRPCapabilities{},
+		},
+	}
+
+	ctx := context.Background()
+	catalog := dev.CommandCatalog(ctx)
+
+	for _, testCase := range []struct {
+		resource, action string
+		err              error
+	}{
+		{resource: ResourceRFSurvey, action: CommandRead,
+			err: func() error { _, err := dev.RFSurveyResults(ctx); return err }()},
+		{resource: ResourceReportBufferLevel, action: CommandRead,
+			err: func() error { _, err := dev.ReportBufferLevel(ctx); return err }()},
+		{resource: ResourceFastIDROSpec, action: CommandWrite,
+			err: dev.AddFastIDROSpec(ctx, llrp.ROSpec{})},
+		{resource: ResourceAntennaProperties, action: CommandWrite,
+			err: dev.SetAntennaProperties(ctx, []antennaGain{{AntennaID: 1, GainDBi: 3}})},
+		{resource: ResourceEventHistory, action: CommandRead,
+			err: func() error { _, err := dev.EventHistory(ctx); return err }()},
+	} {
+		cmd := findCommand(t, catalog, testCase.resource, testCase.action)
+		if testCase.err == nil || cmd.Reason != testCase.err.Error() {
+			t.Errorf("%s %s: expected the reason to match the handler's error %v; got %+v",
+				testCase.action, testCase.resource, testCase.err, cmd)
+		}
+	}
+}
+
+func TestHandleRead_CommandCatalog(t *testing.T) {
+	d := newLocalDriver(t, &LLRPDevice{
+		caps: &llrp.GetReaderCapabilitiesResponse{
+			LLRPCapabilities: &llrp.LLRPCapabilities{CanDoRFSurvey: true},
+		},
+	})
+
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceCommandCatalog,
+		Type:               dsModels.String,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cvs) != 1 || cvs[0] == nil {
+		t.Fatalf("expected exactly one command value; got %v", cvs)
+	}
+
+	data, err := cvs[0].StringValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var catalog CommandCatalog
+	if err := json.Unmarshal([]byte(data), &catalog); err != nil {
+		t.Fatal(err)
+	}
+
+	if !catalog.CapabilitiesKnown || len(catalog.Commands) != len(catalogCommands) {
+		t.Fatalf("expected the full catalog; got %s", data)
+	}
+
+	if cmd := findCommand(t, catalog, ResourceRFSurvey, CommandWrite); !cmd.Supported ||
+		cmd.Parameter == "" || cmd.Requires == "" {
+		t.Errorf("expected RFSurvey writes to be supported and described; got %+v", cmd)
+	}
+}
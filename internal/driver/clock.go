@@ -0,0 +1,69 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"time"
+)
+
+// clock tells the Driver and its devices the time and schedules their timers,
+// so tests can control timing-dependent behavior instead of waiting for it.
+type clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) ticker
+	AfterFunc(d time.Duration, f func()) timer
+}
+
+// ticker is the part of a time.Ticker the driver uses.
+type ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+// timer is the part of a time.Timer the driver uses.
+type timer interface {
+	Stop() bool
+}
+
+// realClock is a clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(d time.Duration) ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) timer {
+	return time.AfterFunc(d, f)
+}
+
+// realTicker adapts a time.Ticker to the ticker interface.
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) Chan() <-chan time.Time {
+	return t.C
+}
+
+// clock returns the Driver's clock, which is the real clock unless a test set it.
+func (d *Driver) clock() clock {
+	if d.clk == nil {
+		return realClock{}
+	}
+	return d.clk
+}
+
+// clock returns the device's clock, which is the real clock unless a test set it.
+func (l *LLRPDevice) clock() clock {
+	if l.clk == nil {
+		return realClock{}
+	}
+	return l.clk
+}
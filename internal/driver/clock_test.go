@@ -0,0 +1,201 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock for tests whose time only moves when Advance is called.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+	timers  []*fakeTimer
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1600000000, 0)}
+}
+
+// fakeTicker ticks on an unbuffered channel,
+// so Advance blocks until the receiver takes each tick,
+// which means it's also finished handling the previous one.
+type fakeTicker struct {
+	clock  *fakeClock
+	period time.Duration
+	next   time.Time
+	ch     chan time.Time
+	stop   chan struct{}
+	once   sync.Once
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	at    time.Time
+	f     func()
+	done  bool
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{clock: c, period: d, next: c.now.Add(d),
+		ch: make(chan time.Time), stop: make(chan struct{})}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// numTickers returns the number of tickers created so far,
+// so tests can wait for code under test to create one.
+func (c *fakeClock) numTickers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.tickers)
+}
+
+// numTimers returns the number of timers created so far,
+// so tests can wait for code under test to create one.
+func (c *fakeClock) numTimers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// Advance moves the clock forward, running the timers that come due
+// and ticking each ticker at most once, as a time.Ticker drops ticks for slow receivers.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+
+	var ticks []*fakeTicker
+	for _, t := range c.tickers {
+		if now.Before(t.next) {
+			continue
+		}
+		ticks = append(ticks, t)
+		for !now.Before(t.next) {
+			t.next = t.next.Add(t.period)
+		}
+	}
+
+	var due []func()
+	for _, t := range c.timers {
+		if !t.done && !now.Before(t.at) {
+			t.done = true
+			due = append(due, t.f)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, t := range ticks {
+		select {
+		case t.ch <- now:
+		case <-t.stop:
+		}
+	}
+
+	// Like time.AfterFunc, each function runs in its own goroutine.
+	for _, f := range due {
+		go f()
+	}
+}
+
+func (t *fakeTicker) Chan() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.once.Do(func() { close(t.stop) })
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := !t.done
+	t.done = true
+	return active
+}
+
+func TestLLRPDevice_closeWhenIdle(t *testing.T) {
+	clk := newFakeClock()
+	l := &LLRPDevice{
+		name:         "idleReader",
+		lc:           edgexCompatTestLogger{t},
+		idleTimeout:  time.Minute,
+		lastActivity: clk.Now(),
+		wake:         make(chan struct{}, 1),
+		clk:          clk,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go l.closeWhenIdle(ctx)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for clk.numTickers() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("closeWhenIdle never started its ticker")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	isIdle := func() bool {
+		l.deviceMu.RLock()
+		defer l.deviceMu.RUnlock()
+		return l.idle
+	}
+
+	// The ticker checks every quarter of the idleTimeout,
+	// and activity restarts the timeout.
+	for i := 0; i < 3; i++ {
+		clk.Advance(15 * time.Second)
+	}
+	l.markActive()
+	for i := 0; i < 3; i++ {
+		clk.Advance(15 * time.Second)
+	}
+	if isIdle() {
+		t.Fatal("expected the device to stay active within the idleTimeout of its last activity")
+	}
+
+	clk.Advance(15 * time.Second)
+	for !isIdle() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the device to become idle after the idleTimeout")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Activity wakes the device.
+	l.markActive()
+	if isIdle() {
+		t.Error("expected markActive to wake the device")
+	}
+	select {
+	case <-l.wake:
+	default:
+		t.Error("expected markActive to signal the reconnect loop")
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// ResourceClockSkew compares a Reader's clock to the host's.
+	ResourceClockSkew = "ClockSkew"
+
+	// clockSkewWarning is how far apart the Reader's and host's clocks may be
+	// before the SelfTest warns about it.
+	clockSkewWarning = time.Second
+)
+
+// ClockSkew is the difference between a Reader's clock and the host's,
+// which affects the timestamps and ordering of the Reader's tag reads.
+//
+// LLRP has no request for a Reader's current time,
+// so the skew is computed from the UTCTimestamp of the most recent
+// ReaderEventNotification, which Readers send at least each time they connect.
+// It includes the network delay of that notification, typically a few milliseconds.
+type ClockSkew struct {
+	Computed         bool       // false if the skew can't be computed; see Reason
+	Reason           string     `json:",omitempty"` // why the skew can't be computed
+	ReaderTime       *time.Time `json:",omitempty"` // the Reader's timestamp in its notification
+	HostTime         *time.Time `json:",omitempty"` // when the service received the notification
+	SkewMillis       int64      // ReaderTime minus HostTime; positive if the Reader is ahead
+	SampleAgeSeconds int64      // how long ago the service received the notification
+}
+
+// readerClockSample records a Reader's timestamp and when the service received it.
+type readerClockSample struct {
+	reader     time.Time // the Reader's UTCTimestamp; zero if uptimeOnly
+	host       time.Time // zero if the Reader hasn't sent a notification
+	uptimeOnly bool      // true if the Reader sent an Uptime instead of a UTCTimestamp
+}
+
+// ClockSkew returns the difference between the Reader's clock and the host's,
+// as of the Reader's most recent ReaderEventNotification.
+func (l *LLRPDevice) ClockSkew() ClockSkew {
+	l.deviceMu.RLock()
+	s := l.clockSample
+	l.deviceMu.RUnlock()
+
+	switch {
+	case s.host.IsZero():
+		return ClockSkew{Reason: "the Reader hasn't sent a timestamped event notification since the service connected"}
+	case s.uptimeOnly:
+		return ClockSkew{Reason: "the Reader reports its Uptime instead of UTC time, " +
+			"so its clock can't be compared to the host's"}
+	}
+
+	return ClockSkew{
+		Computed:         true,
+		ReaderTime:       &s.reader,
+		HostTime:         &s.host,
+		SkewMillis:       s.reader.Sub(s.host).Milliseconds(),
+		SampleAgeSeconds: int64(l.clock().Now().Sub(s.host) / time.Second),
+	}
+}
+
+// clockSelfTestCheck reports the Reader's ClockSkew as a SelfTestCheck.
+// It warns if the skew is large or can't be computed,
+// but since it doesn't communicate with the Reader, it doesn't fail.
+func (l *LLRPDevice) clockSelfTestCheck() SelfTestCheck {
+	check := SelfTestCheck{Name: "Clock", Passed: true}
+
+	skew := l.ClockSkew()
+	if !skew.Computed {
+		check.Warnings = []string{"unable to compute clock skew: " + skew.Reason}
+		return check
+	}
+
+	d := time.Duration(skew.SkewMillis) * time.Millisecond
+	direction := "ahead of"
+	if d < 0 {
+		d, direction = -d, "behind"
+	}
+	check.Details = fmt.Sprintf("reader clock is %v %s the host's, as of %ds ago",
+		d, direction, skew.SampleAgeSeconds)
+	if d > clockSkewWarning {
+		check.Warnings = []string{fmt.Sprintf("reader clock differs from the host's by more than %v; "+
+			"tag read timestamps may be misordered", clockSkewWarning)}
+	}
+	return check
+}
@@ -0,0 +1,73 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_ClockSkew(t *testing.T) {
+	clk := newFakeClock()
+	l := &LLRPDevice{
+		name: "skewedReader",
+		lc:   edgexCompatTestLogger{t},
+		ch:   make(chan *dsModels.AsyncValues, 10),
+		clk:  clk,
+	}
+	handler := l.newReaderEventHandler(nil)
+
+	notify := func(data llrp.ReaderEventNotificationData) {
+		t.Helper()
+		payload, err := (&llrp.ReaderEventNotification{ReaderEventNotificationData: data}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := llrp.NewByteMessage(llrp.MsgReaderEventNotification, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler.HandleMessage(nil, msg)
+	}
+
+	if skew := l.ClockSkew(); skew.Computed || skew.Reason == "" {
+		t.Errorf("expected no skew before a notification; got %+v", skew)
+	}
+
+	// The Reader's clock is 2.5s ahead of the host's.
+	readerNow := clk.Now().Add(2500 * time.Millisecond)
+	notify(llrp.ReaderEventNotificationData{
+		UTCTimestamp: llrp.UTCTimestamp(readerNow.UnixNano() / 1000),
+		AntennaEvent: &llrp.AntennaEvent{Event: llrp.AntennaConnected, AntennaID: 1},
+	})
+	clk.Advance(10 * time.Second)
+
+	skew := l.ClockSkew()
+	if !skew.Computed || skew.SkewMillis != 2500 || skew.SampleAgeSeconds != 10 ||
+		skew.ReaderTime == nil || !skew.ReaderTime.Equal(readerNow) {
+		t.Errorf("expected a 2500ms skew sampled 10s ago; got %+v", skew)
+	}
+
+	check := l.clockSelfTestCheck()
+	if !check.Passed || !strings.Contains(check.Details, "2.5s ahead of") || len(check.Warnings) != 1 {
+		t.Errorf("expected the clock check to warn about a large skew; got %+v", check)
+	}
+
+	// Readers without a UTC clock only report their Uptime.
+	notify(llrp.ReaderEventNotificationData{
+		Uptime:       123456,
+		AntennaEvent: &llrp.AntennaEvent{Event: llrp.AntennaConnected, AntennaID: 1},
+	})
+	if skew := l.ClockSkew(); skew.Computed || !strings.Contains(skew.Reason, "Uptime") {
+		t.Errorf("expected no skew for an uptime-only Reader; got %+v", skew)
+	}
+	if check := l.clockSelfTestCheck(); !check.Passed || len(check.Warnings) != 1 {
+		t.Errorf("expected the clock check to warn that skew can't be computed; got %+v", check)
+	}
+}
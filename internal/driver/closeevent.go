@@ -0,0 +1,76 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResourceConnectionClosed is sent as an event when a Reader's connection closes,
+	// unless the service closed it itself.
+	ResourceConnectionClosed = "ConnectionClosed"
+
+	// Why a Reader's connection closed.
+	CloseReaderInitiated = "ReaderInitiated" // the Reader sent a ConnectionCloseEvent first
+	CloseUnexpected      = "Unexpected"      // the connection dropped without warning
+)
+
+// connectionClosed is the value of ResourceConnectionClosed events.
+type connectionClosed struct {
+	Reason string
+	Error  string `json:",omitempty"` // what ended the connection, if it closed unexpectedly
+}
+
+// onReaderClose handles a ConnectionCloseEvent, which a Reader sends
+// just before it closes the connection, e.g. because it's shutting down.
+// Rather than wait for the connection to drop, it closes the Client,
+// so the device starts reconnecting right away.
+func (l *LLRPDevice) onReaderClose(c *llrp.Client) {
+	l.deviceMu.Lock()
+	l.readerClosed = true
+	l.deviceMu.Unlock()
+
+	l.lc.Info("Reader is closing the connection.", "device", l.name)
+	if c != nil {
+		_ = c.Close()
+	}
+}
+
+// onDisconnect is called when a connection to the Reader closes
+// with the error its Client returned, if any.
+// If the connection was open, it sends a ResourceConnectionClosed event
+// saying whether the Reader closed it or it closed unexpectedly,
+// unless the service closed it normally.
+func (l *LLRPDevice) onDisconnect(clientErr error) {
+	l.deviceMu.Lock()
+	wasConnected := l.connected
+	readerClosed := l.readerClosed
+	l.connected = false
+	l.readerClosed = false
+	l.deviceMu.Unlock()
+
+	if !wasConnected {
+		return
+	}
+
+	closed := connectionClosed{}
+	switch {
+	case readerClosed:
+		closed.Reason = CloseReaderInitiated
+	case clientErr != nil && !errors.Is(clientErr, llrp.ErrClientClosed):
+		closed.Reason = CloseUnexpected
+		closed.Error = clientErr.Error()
+	default:
+		return
+	}
+
+	now := l.clock().Now().UnixNano()
+	l.goSend(func() {
+		l.sendEdgeXEvent(ResourceConnectionClosed, now, closed)
+	})
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_readerClose(t *testing.T) {
+	emu, port := startEmulator(t, 0)
+
+	closes := make(chan connectionClosed, 10)
+	d := newTestDriver(func(av *dsModels.AsyncValues) {
+		for _, cv := range av.CommandValues {
+			if cv.DeviceResourceName != ResourceConnectionClosed {
+				continue
+			}
+			c := connectionClosed{}
+			if err := json.Unmarshal([]byte(cv.ValueToString()), &c); err != nil {
+				t.Errorf("failed to unmarshal %s: %+v", ResourceConnectionClosed, err)
+			}
+			closes <- c
+		}
+	})
+
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}
+	dev := d.NewLLRPDevice("closingReader", addr, contract.Enabled)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		_ = dev.Stop(ctx)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// waitForConnection waits for the device to connect with a client other than prev.
+	waitForConnection := func(prev *llrp.Client) *llrp.Client {
+		t.Helper()
+		var c *llrp.Client
+		if err := retry.Quick.RetryWithCtx(ctx, retry.Forever, func(context.Context) (bool, error) {
+			dev.clientLock.RLock()
+			c = dev.client
+			dev.clientLock.RUnlock()
+			dev.deviceMu.RLock()
+			connected := dev.connected
+			dev.deviceMu.RUnlock()
+			if !connected || c == prev {
+				return true, errors.New("device isn't connected")
+			}
+			return false, nil
+		}); err != nil {
+			t.Fatalf("device didn't connect: %+v", err)
+		}
+		return c
+	}
+
+	c := waitForConnection(nil)
+
+	// The Reader announces it's closing the connection, but leaves it open.
+	if err := emu.SendCloseEvents(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case closed := <-closes:
+		if closed != (connectionClosed{Reason: CloseReaderInitiated}) {
+			t.Errorf("expected a reader-initiated close; got %+v", closed)
+		}
+	case <-ctx.Done():
+		t.Fatalf("expected a %s event", ResourceConnectionClosed)
+	}
+
+	// The service closes the connection rather than waiting for the Reader to,
+	// then reconnects.
+	waitForConnection(c)
+}
+
+func TestLLRPDevice_onDisconnect(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 1)
+	l := &LLRPDevice{
+		name: "localReader",
+		lc:   edgexCompatTestLogger{t},
+		ch:   ch,
+		clk:  newFakeClock(),
+	}
+
+	for _, testCase := range []struct {
+		name      string
+		connected bool
+		clientErr error
+		expected  *connectionClosed
+	}{
+		{name: "closed by the service", connected: true, clientErr: nil},
+		{name: "client closed", connected: true, clientErr: errors.Wrap(llrp.ErrClientClosed, "closed")},
+		{name: "never connected", clientErr: errors.New("connection refused")},
+		{
+			name: "dropped", connected: true, clientErr: errors.New("EOF"),
+			expected: &connectionClosed{Reason: CloseUnexpected, Error: "EOF"},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			l.deviceMu.Lock()
+			l.connected = testCase.connected
+			l.deviceMu.Unlock()
+
+			l.onDisconnect(testCase.clientErr)
+
+			var av *dsModels.AsyncValues
+			select {
+			case av = <-ch:
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			if testCase.expected == nil {
+				if av != nil {
+					t.Errorf("expected no event; got %s", av.CommandValues[0].ValueToString())
+				}
+				return
+			}
+
+			if av == nil {
+				t.Fatalf("expected a %s event", ResourceConnectionClosed)
+			}
+			c := connectionClosed{}
+			if err := json.Unmarshal([]byte(av.CommandValues[0].ValueToString()), &c); err != nil {
+				t.Fatal(err)
+			}
+			if c != *testCase.expected {
+				t.Errorf("expected %+v; got %+v", *testCase.expected, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// Policies for commands beyond a device's MaxConcurrentCommands.
+const (
+	CommandOverflowQueue = "queue" // wait for an earlier command to finish
+	CommandOverflowFail  = "fail"  // fail immediately with ErrTooManyCommands
+)
+
+// ErrTooManyCommands is returned for commands that fail fast
+// because their device already has its maximum number of commands in flight.
+var ErrTooManyCommands = errors.New("too many concurrent commands")
+
+// checkCommandOverflow returns an error if the policy isn't a known CommandOverflow policy.
+func checkCommandOverflow(policy string) error {
+	switch policy {
+	case CommandOverflowQueue, CommandOverflowFail:
+		return nil
+	default:
+		return errors.Errorf("unknown command overflow policy %q; policies are %s or %s",
+			policy, CommandOverflowQueue, CommandOverflowFail)
+	}
+}
+
+// commandQueue runs a device's commands one at a time, in the order they join it.
+//
+// Each command's ticket holds a channel closed when the command finishes,
+// and the next command waits on it, so the queue is a chain of channels.
+// Unlike a sync.Mutex, this guarantees waiters proceed in FIFO order.
+type commandQueue struct {
+	mu   sync.Mutex
+	tail chan struct{} // closed when the most recently queued command finishes
+}
+
+// commandTicket is a command's place in a commandQueue.
+type commandTicket struct {
+	prev <-chan struct{} // closed when the previous command finishes; nil if none
+	done chan struct{}   // closed when this command finishes
+}
+
+// join adds a command to the end of the queue.
+// The caller must call wait, then finish when the command is done.
+func (q *commandQueue) join() commandTicket {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	t := commandTicket{prev: q.tail, done: make(chan struct{})}
+	q.tail = t.done
+	return t
+}
+
+// wait blocks until the commands ahead of this one finish or ctx is done.
+// If ctx is done first, it returns ctx's error and gives up its place
+// without letting later commands skip ahead of the earlier ones,
+// in which case the caller must not call finish.
+func (t commandTicket) wait(ctx context.Context) error {
+	if t.prev == nil {
+		return nil
+	}
+
+	select {
+	case <-t.prev:
+		return nil
+	case <-ctx.Done():
+		go func() {
+			<-t.prev
+			close(t.done)
+		}()
+		return ctx.Err()
+	}
+}
+
+// finish lets the next command in the queue run.
+func (t commandTicket) finish() {
+	close(t.done)
+}
+
+// commandLimiter limits how many commands a device has in flight at once,
+// to protect Readers that can't cope with a flood of them.
+// A nil commandLimiter doesn't limit them.
+type commandLimiter struct {
+	slots    chan struct{} // holds a value for each command in flight
+	failFast bool          // if true, commands beyond the limit fail instead of waiting
+}
+
+// newCommandLimiter returns a commandLimiter allowing max commands in flight,
+// or nil if max is 0, disabling it.
+func newCommandLimiter(max int, failFast bool) *commandLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &commandLimiter{slots: make(chan struct{}, max), failFast: failFast}
+}
+
+// acquire waits until fewer than the maximum commands are in flight or ctx is done.
+// If the limiter fails fast, it instead returns ErrTooManyCommands immediately.
+// If it returns nil, the caller must call release when its command is done.
+func (cl *commandLimiter) acquire(ctx context.Context) error {
+	if cl == nil {
+		return nil
+	}
+
+	select {
+	case cl.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if cl.failFast {
+		return errors.Wrapf(ErrTooManyCommands, "device already has %d commands in flight", cap(cl.slots))
+	}
+
+	select {
+	case cl.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "timed out waiting for commands in flight")
+	}
+}
+
+// release lets another command start.
+func (cl *commandLimiter) release() {
+	if cl != nil {
+		<-cl.slots
+	}
+}
+
+// startCommand waits for the device's earlier commands to finish
+// if the device orders its commands, then for room under its command limit,
+// and returns a function the caller must call when its command is done.
+// If the device neither orders nor limits its commands, it returns immediately.
+func (l *LLRPDevice) startCommand(ctx context.Context) (finish func(), err error) {
+	l.deviceMu.RLock()
+	ordered := l.orderedCommands
+	l.deviceMu.RUnlock()
+
+	if !ordered {
+		if err := l.limiter.acquire(ctx); err != nil {
+			return nil, err
+		}
+		return l.limiter.release, nil
+	}
+
+	t := l.commands.join()
+	if err := t.wait(ctx); err != nil {
+		return nil, errors.WithMessage(err, "timed out waiting for earlier commands")
+	}
+
+	if err := l.limiter.acquire(ctx); err != nil {
+		t.finish()
+		return nil, err
+	}
+	return func() {
+		l.limiter.release()
+		t.finish()
+	}, nil
+}
@@ -0,0 +1,287 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_startCommand_FIFO(t *testing.T) {
+	l := &LLRPDevice{orderedCommands: true}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Hold the queue while the others join it.
+	finishFirst, err := l.startCommand(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tail := func() chan struct{} {
+		l.commands.mu.Lock()
+		defer l.commands.mu.Unlock()
+		return l.commands.tail
+	}
+
+	const n = 20
+	var mu sync.Mutex
+	var order []int
+	running := 0
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		prev := tail()
+		go func(i int) {
+			defer wg.Done()
+			finish, err := l.startCommand(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer finish()
+
+			mu.Lock()
+			running++
+			if running != 1 {
+				t.Errorf("command %d ran concurrently with another", i)
+			}
+			order = append(order, i)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}(i)
+
+		// Wait for the command to join before submitting the next one.
+		for tail() == prev {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	finishFirst()
+	wg.Wait()
+
+	if len(order) != n {
+		t.Fatalf("expected %d commands to run; got %v", n, order)
+	}
+	for i := range order {
+		if order[i] != i {
+			t.Fatalf("expected commands to run in submission order; got %v", order)
+		}
+	}
+}
+
+func TestCommandQueue_canceledWait(t *testing.T) {
+	var q commandQueue
+	first := q.join()
+	if err := first.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A command that gives up waiting mustn't let later ones skip ahead.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := q.join().wait(ctx); err == nil {
+		t.Fatal("expected the canceled command to stop waiting")
+	}
+
+	third := q.join()
+	waited := make(chan error, 1)
+	go func() { waited <- third.wait(context.Background()) }()
+
+	select {
+	case <-waited:
+		t.Fatal("expected the third command to wait for the first")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	first.finish()
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the third command to run after the first finished")
+	}
+	third.finish()
+}
+
+func TestLLRPDevice_startCommand_unordered(t *testing.T) {
+	l := &LLRPDevice{}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	finish1, err := l.startCommand(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer finish1()
+
+	// Without ordering, commands don't wait for each other.
+	finish2, err := l.startCommand(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finish2()
+}
+
+func TestLLRPDevice_startCommand_limitQueues(t *testing.T) {
+	const max = 3
+	l := &LLRPDevice{limiter: newCommandLimiter(max, false)}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const n = 20
+	var mu sync.Mutex
+	running, peak := 0, 0
+	var wg sync.WaitGroup
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			finish, err := l.startCommand(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer finish()
+
+			mu.Lock()
+			running++
+			if running > peak {
+				peak = running
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if peak > max {
+		t.Errorf("expected at most %d commands in flight; got %d", max, peak)
+	}
+
+	// A queued command gives up when its context is done.
+	finishes := make([]func(), max)
+	for i := range finishes {
+		var err error
+		if finishes[i], err = l.startCommand(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	shortCtx, shortCancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer shortCancel()
+	if _, err := l.startCommand(shortCtx); err == nil {
+		t.Fatal("expected the command beyond the limit to time out")
+	}
+
+	// Once one finishes, another may start.
+	finishes[0]()
+	finish, err := l.startCommand(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	finish()
+	for _, finish := range finishes[1:] {
+		finish()
+	}
+}
+
+func TestLLRPDevice_startCommand_limitFailsFast(t *testing.T) {
+	l := &LLRPDevice{limiter: newCommandLimiter(1, true)}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	finish, err := l.startCommand(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.startCommand(ctx); !errors.Is(err, ErrTooManyCommands) {
+		t.Fatalf("expected ErrTooManyCommands; got %v", err)
+	}
+
+	finish()
+	finish, err = l.startCommand(ctx)
+	if err != nil {
+		t.Fatalf("expected the command to start after the first finished; got %v", err)
+	}
+	finish()
+}
+
+func TestLLRPDevice_startCommand_orderedAndLimited(t *testing.T) {
+	// Ordered commands that fail the limit must not block the queue.
+	l := &LLRPDevice{orderedCommands: true, limiter: newCommandLimiter(1, true)}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Take the only slot out from under the queue.
+	if err := l.limiter.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.startCommand(ctx); !errors.Is(err, ErrTooManyCommands) {
+		t.Fatalf("expected ErrTooManyCommands; got %v", err)
+	}
+	l.limiter.release()
+
+	finish, err := l.startCommand(ctx)
+	if err != nil {
+		t.Fatalf("expected the queue to proceed after a failed command; got %v", err)
+	}
+	finish()
+}
+
+func TestCheckCommandOverflow(t *testing.T) {
+	for _, policy := range []string{CommandOverflowQueue, CommandOverflowFail} {
+		if err := checkCommandOverflow(policy); err != nil {
+			t.Errorf("expected %q to be valid; got %v", policy, err)
+		}
+	}
+	if err := checkCommandOverflow("drop"); err == nil {
+		t.Error("expected an unknown policy to be invalid")
+	}
+}
+
+func TestGetOrderedCommands(t *testing.T) {
+	for _, testCase := range []struct {
+		ordered  string
+		expected bool
+		err      bool
+	}{
+		{ordered: "", expected: false},
+		{ordered: "true", expected: true},
+		{ordered: "false", expected: false},
+		{ordered: "sometimes", err: true},
+	} {
+		protocols := protocolMap{}
+		if testCase.ordered != "" {
+			protocols[ProtocolCommands] = contract.ProtocolProperties{"ordered": testCase.ordered}
+		}
+
+		ordered, err := getOrderedCommands(protocols)
+		if (err != nil) != testCase.err || ordered != testCase.expected {
+			t.Errorf("ordered %q: expected %v (error: %v); got %v, %v",
+				testCase.ordered, testCase.expected, testCase.err, ordered, err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// AttribTimeout is an optional resource attribute overriding how long its commands
+// may take, as a Go duration (e.g., "90s" or "5m"), for operations such as
+// large RF surveys that legitimately take longer than CommandTimeoutSeconds.
+// It must be positive and no more than MaxCommandTimeoutSeconds.
+const AttribTimeout = "timeout"
+
+// checkCommandTimeouts returns an error if the default command timeout isn't positive
+// or exceeds the maximum an AttribTimeout may request.
+func checkCommandTimeouts(timeoutSecs, maxSecs int) error {
+	if timeoutSecs <= 0 {
+		return errors.Errorf("command timeout must be positive; got %d seconds", timeoutSecs)
+	}
+	if maxSecs < timeoutSecs {
+		return errors.Errorf("maximum command timeout of %d seconds "+
+			"is less than the default of %d seconds", maxSecs, timeoutSecs)
+	}
+	return nil
+}
+
+// commandTimeout returns how long a command for the requests may take:
+// the longest of their AttribTimeout overrides, if they have any,
+// or else the configured default.
+// It returns an error if an override isn't a positive duration
+// or exceeds the configured maximum.
+func (d *Driver) commandTimeout(reqs []dsModels.CommandRequest) (time.Duration, error) {
+	timeout, maxTimeout := sendTimeout, sendTimeout
+	d.configMu.RLock()
+	if d.config != nil {
+		timeout = time.Duration(d.config.CommandTimeoutSeconds) * time.Second
+		maxTimeout = time.Duration(d.config.MaxCommandTimeoutSeconds) * time.Second
+	}
+	d.configMu.RUnlock()
+
+	var override time.Duration
+	for i := range reqs {
+		v, ok := reqs[i].Attributes[AttribTimeout]
+		if !ok {
+			continue
+		}
+
+		t, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid %s attribute for resource %q",
+				AttribTimeout, reqs[i].DeviceResourceName)
+		}
+		if t <= 0 {
+			return 0, errors.Errorf("%s attribute for resource %q must be positive; got %v",
+				AttribTimeout, reqs[i].DeviceResourceName, t)
+		}
+		if t > maxTimeout {
+			return 0, errors.Errorf("%s attribute for resource %q exceeds the maximum of %v; got %v",
+				AttribTimeout, reqs[i].DeviceResourceName, maxTimeout, t)
+		}
+		if t > override {
+			override = t
+		}
+	}
+
+	if override > 0 {
+		return override, nil
+	}
+	return timeout, nil
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestDriver_commandTimeout(t *testing.T) {
+	d := &Driver{config: &driverConfiguration{CommandTimeoutSeconds: 20, MaxCommandTimeoutSeconds: 300}}
+
+	for _, testCase := range []struct {
+		timeouts []string // one request per timeout attribute; "" for none
+		expected time.Duration
+		err      bool
+	}{
+		{timeouts: []string{""}, expected: 20 * time.Second},
+		{timeouts: []string{"90s"}, expected: 90 * time.Second},
+		{timeouts: []string{"5s"}, expected: 5 * time.Second},
+		{timeouts: []string{"5m"}, expected: 5 * time.Minute},
+		{timeouts: []string{"", "2m", "90s"}, expected: 2 * time.Minute},
+		{timeouts: []string{"0s"}, err: true},
+		{timeouts: []string{"-1m"}, err: true},
+		{timeouts: []string{"6m"}, err: true},
+		{timeouts: []string{"90"}, err: true},
+		{timeouts: []string{"90s", "forever"}, err: true},
+	} {
+		reqs := make([]dsModels.CommandRequest, len(testCase.timeouts))
+		for i, timeout := range testCase.timeouts {
+			reqs[i].DeviceResourceName = ResourceReaderID
+			if timeout != "" {
+				reqs[i].Attributes = map[string]string{AttribTimeout: timeout}
+			}
+		}
+
+		got, err := d.commandTimeout(reqs)
+		if (err != nil) != testCase.err {
+			t.Errorf("timeouts %q: expected error: %v; got %v", testCase.timeouts, testCase.err, err)
+			continue
+		}
+
+		if got != testCase.expected {
+			t.Errorf("timeouts %q: expected %v; got %v", testCase.timeouts, testCase.expected, got)
+		}
+	}
+}
+
+func TestHandleRead_timeoutOverride(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*10, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader takes longer to answer than the default timeout.
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(llrp.Message) llrp.Outgoing {
+		time.Sleep(1500 * time.Millisecond)
+		return &llrp.GetReaderConfigResponse{
+			Identification: &llrp.Identification{
+				IDType:   llrp.ID_MAC_EUI64,
+				ReaderID: []byte{0x00, 0x16, 0x25, 0xff, 0xfe, 0x12, 0x34, 0x56},
+			},
+		}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	d := newLocalDriver(t, &LLRPDevice{client: c})
+	d.config = &driverConfiguration{CommandTimeoutSeconds: 1, MaxCommandTimeoutSeconds: 10}
+
+	read := func(attributes map[string]string) error {
+		_, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+			DeviceResourceName: ResourceReaderID,
+			Type:               dsModels.String,
+			Attributes:         attributes,
+		}})
+		return err
+	}
+
+	if err := read(nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the read to time out with the default timeout; got %+v", err)
+	}
+
+	if err := read(map[string]string{AttribTimeout: "5s"}); err != nil {
+		t.Fatalf("expected the read to succeed with an extended timeout; got %+v", err)
+	}
+
+	if err := read(map[string]string{AttribTimeout: "1m"}); err == nil {
+		t.Fatal("expected an error for a timeout beyond the maximum")
+	}
+}
@@ -0,0 +1,195 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/pkg/errors"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Encodings for the ROAccessReports the service sends to EdgeX,
+// selected by the ReportEncoding driver configuration.
+const (
+	ReportEncodingJSON = "json"
+	ReportEncodingCBOR = "cbor"
+
+	// CBOR-encoded reports are sent as Binary readings of this resource,
+	// since their JSON counterparts are String readings of ResourceROAccessReport.
+	ResourceROAccessReportCBOR = "ROAccessReportCBOR"
+)
+
+// reportCodec encodes ROAccessReports as EdgeX CommandValues.
+type reportCodec interface {
+	encode(ns int64, report interface{}) (*dsModels.CommandValue, error)
+}
+
+// newReportCodec returns the reportCodec for the encoding,
+// which omits null object fields if omitNulls is set.
+// An empty encoding uses the default, ReportEncodingJSON.
+func newReportCodec(encoding string, omitNulls bool) (reportCodec, error) {
+	switch encoding {
+	case "", ReportEncodingJSON:
+		return jsonCodec{omitNulls: omitNulls}, nil
+	case ReportEncodingCBOR:
+		return cborCodec{omitNulls: omitNulls}, nil
+	default:
+		return nil, errors.Errorf("unknown report encoding %q; encodings are %s or %s",
+			encoding, ReportEncodingJSON, ReportEncodingCBOR)
+	}
+}
+
+// jsonCodec encodes reports as JSON String readings of ResourceROAccessReport.
+type jsonCodec struct {
+	omitNulls bool // if set, null object fields are omitted
+}
+
+func (c jsonCodec) encode(ns int64, report interface{}) (*dsModels.CommandValue, error) {
+	data, err := marshalJSON(report, c.omitNulls)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal report to JSON")
+	}
+	return dsModels.NewStringValue(ResourceROAccessReport, ns, string(data)), nil
+}
+
+// cborCodec encodes reports as CBOR Binary readings of ResourceROAccessReportCBOR.
+//
+// The result has the same structure as the JSON encoding.
+// LLRP's types implement encoding.BinaryMarshaler,
+// which CBOR libraries use to encode them as opaque LLRP byte strings,
+// so the report is instead marshaled to JSON, then transcoded to CBOR.
+type cborCodec struct {
+	omitNulls bool // if set, null map entries are omitted
+}
+
+func (c cborCodec) encode(ns int64, report interface{}) (*dsModels.CommandValue, error) {
+	data, err := marshalJSON(report, c.omitNulls)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal report to JSON")
+	}
+
+	data, err = jsonToCBOR(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert report to CBOR")
+	}
+	return dsModels.NewBinaryValue(ResourceROAccessReportCBOR, ns, data)
+}
+
+// CBOR major types (RFC 7049, Section 2.1).
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborSimple = 7 << 5
+
+	cborIndefinite = 31 // additional information for indefinite-length items
+	cborFalse      = cborSimple | 20
+	cborTrue       = cborSimple | 21
+	cborNull       = cborSimple | 22
+	cborFloat64    = cborSimple | 27
+	cborBreak      = cborSimple | 31
+)
+
+// jsonToCBOR transcodes JSON to the equivalent CBOR.
+//
+// Objects and arrays become indefinite-length maps and arrays,
+// so they can be written as they're read.
+// Integral numbers become CBOR integers; others become float64s.
+func jsonToCBOR(data []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	out := make([]byte, 0, len(data))
+	depth := 0 // the number of open objects and arrays
+	for {
+		tok, err := d.Token()
+		if err == io.EOF && depth == 0 {
+			return out, nil
+		}
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case json.Delim:
+			switch tok {
+			case '{':
+				depth++
+				out = append(out, cborMap|cborIndefinite)
+			case '[':
+				depth++
+				out = append(out, cborArray|cborIndefinite)
+			default:
+				depth--
+				out = append(out, cborBreak)
+			}
+		case string:
+			out = appendCBORHead(out, cborText, uint64(len(tok)))
+			out = append(out, tok...)
+		case json.Number:
+			if i, err := tok.Int64(); err == nil {
+				if i < 0 {
+					out = appendCBORHead(out, cborNegInt, uint64(-1-i))
+				} else {
+					out = appendCBORHead(out, cborUint, uint64(i))
+				}
+				break
+			}
+			if u, err := strconv.ParseUint(tok.String(), 10, 64); err == nil {
+				out = appendCBORHead(out, cborUint, u)
+				break
+			}
+			f, err := tok.Float64()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, cborFloat64)
+			out = appendUint(out, math.Float64bits(f), 8)
+		case bool:
+			if tok {
+				out = append(out, cborTrue)
+			} else {
+				out = append(out, cborFalse)
+			}
+		case nil:
+			out = append(out, cborNull)
+		}
+	}
+}
+
+// appendCBORHead appends the head of a CBOR data item
+// with the given major type and argument.
+func appendCBORHead(out []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(out, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(out, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return appendUint(append(out, major|25), arg, 2)
+	case arg <= math.MaxUint32:
+		return appendUint(append(out, major|26), arg, 4)
+	default:
+		return appendUint(append(out, major|27), arg, 8)
+	}
+}
+
+// appendUint appends the low n bytes of v in big-endian order.
+func appendUint(out []byte, v uint64, n int) []byte {
+	for i := n - 1; i >= 0; i-- {
+		out = append(out, byte(v>>(8*uint(i))))
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/fxamacker/cbor/v2"
+	"reflect"
+	"testing"
+)
+
+// testReport returns an ROAccessReport with n tag reads.
+func testReport(n int) *llrp.ROAccessReport {
+	report := &llrp.ROAccessReport{TagReportData: make([]llrp.TagReportData, n)}
+	for i := range report.TagReportData {
+		ant := llrp.AntennaID(i%4 + 1)
+		rssi := llrp.PeakRSSI(-40 - i%30)
+		seen := llrp.LastSeenUTC(1600000000000000 + uint64(i))
+		report.TagReportData[i] = llrp.TagReportData{
+			EPC96:       llrp.EPC96{EPC: []byte{0x30, 0x08, 0x33, 0xb2, 0xdd, 0xd9, 0x01, 0x40, 0, 0, byte(i >> 8), byte(i)}},
+			AntennaID:   &ant,
+			PeakRSSI:    &rssi,
+			LastSeenUTC: &seen,
+		}
+	}
+	return report
+}
+
+func TestReportCodecs(t *testing.T) {
+	report := testReport(3)
+
+	jsonCV, err := jsonCodec{}.encode(1, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jsonCV.DeviceResourceName != ResourceROAccessReport || jsonCV.Type != dsModels.String {
+		t.Errorf("expected a String %s; got %+v", ResourceROAccessReport, jsonCV)
+	}
+
+	cborCV, err := cborCodec{}.encode(1, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cborCV.DeviceResourceName != ResourceROAccessReportCBOR || cborCV.Type != dsModels.Binary {
+		t.Errorf("expected a Binary %s; got %+v", ResourceROAccessReportCBOR, cborCV)
+	}
+
+	// Both encodings should decode to the same structure.
+	type decoded struct {
+		TagReportData []struct {
+			EPC96       struct{ EPC string }
+			AntennaID   *uint16
+			PeakRSSI    *int8
+			LastSeenUTC *uint64
+		}
+	}
+
+	var fromJSON, fromCBOR decoded
+	s, err := jsonCV.StringValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(s), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if err := cbor.Unmarshal(cborCV.BinValue, &fromCBOR); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fromJSON.TagReportData) != 3 || !reflect.DeepEqual(fromJSON, fromCBOR) {
+		t.Errorf("CBOR report doesn't match the JSON report:\nJSON: %+v\nCBOR: %+v", fromJSON, fromCBOR)
+	}
+
+	if len(cborCV.BinValue) >= len(s) {
+		t.Errorf("expected CBOR to be smaller than JSON; got %d >= %d bytes", len(cborCV.BinValue), len(s))
+	}
+
+	if _, err := newReportCodec("protobuf", false); err == nil {
+		t.Error("expected an error for an unknown encoding")
+	}
+}
+
+// BenchmarkReportCodec compares the codecs' throughput on a large report
+// and reports the size of the encoded result.
+func BenchmarkReportCodec(b *testing.B) {
+	report := withLocations(map[llrp.AntennaID]string{1: "Dock Door 3"}, nil, testReport(1000))
+
+	for _, encoding := range []string{ReportEncodingJSON, ReportEncodingCBOR} {
+		codec, err := newReportCodec(encoding, false)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(encoding, func(b *testing.B) {
+			var size int
+			for i := 0; i < b.N; i++ {
+				cv, err := codec.encode(1, report)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(cv.BinValue) + len(cv.ValueToString())
+			}
+			b.ReportMetric(float64(size), "bytes/report")
+		})
+	}
+}
+
+func TestJSONToCBOR(t *testing.T) {
+	data, err := jsonToCBOR([]byte(
+		`{"neg":-500,"big":18446744073709551615,"frac":1.5,"ok":true,"no":false,"nil":null,"list":["a",1]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v struct {
+		Neg  int64
+		Big  uint64
+		Frac float64
+		OK   bool
+		No   bool
+		Nil  *int
+		List []interface{}
+	}
+	if err := cbor.Unmarshal(data, &v); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if v.Neg != -500 || v.Big != 18446744073709551615 || v.Frac != 1.5 || !v.OK || v.No || v.Nil != nil ||
+		!reflect.DeepEqual(v.List, []interface{}{"a", uint64(1)}) {
+		t.Errorf("unexpected result: %+v", v)
+	}
+
+	if _, err := jsonToCBOR([]byte(`{"unterminated":`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,100 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// reportCollection accumulates the ROAccessReports that arrive while it's open.
+type reportCollection struct {
+	mu      sync.Mutex
+	reports []*llrp.ROAccessReport
+}
+
+// reportCollectors tracks the open reportCollections,
+// so the ROAccessReport handler can give each of them a copy of every report.
+type reportCollectors struct {
+	mu   sync.Mutex
+	open map[*reportCollection]struct{}
+}
+
+// add opens a new reportCollection.
+func (rc *reportCollectors) add() *reportCollection {
+	c := &reportCollection{}
+	rc.mu.Lock()
+	if rc.open == nil {
+		rc.open = make(map[*reportCollection]struct{})
+	}
+	rc.open[c] = struct{}{}
+	rc.mu.Unlock()
+	return c
+}
+
+// remove closes the reportCollection and returns the reports it collected.
+func (rc *reportCollectors) remove(c *reportCollection) []*llrp.ROAccessReport {
+	rc.mu.Lock()
+	delete(rc.open, c)
+	rc.mu.Unlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reports
+}
+
+// deliver adds the report to each open reportCollection.
+// It doesn't block, so the handler can call it without holding up the connection.
+func (rc *reportCollectors) deliver(report *llrp.ROAccessReport) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for c := range rc.open {
+		c.mu.Lock()
+		c.reports = append(c.reports, report)
+		c.mu.Unlock()
+	}
+}
+
+// TrySendCollect works like TrySend, but after the Reader responds,
+// it collects the ROAccessReports the Reader sends within the given window
+// and returns them in the order they arrived.
+// It suits commands that produce reports right away,
+// such as StartROSpec on an ROSpec that reports at the end of a short inventory.
+//
+// Collection starts before the request is sent, so it includes reports
+// that arrive before the response. The reports are still sent to EdgeX as usual.
+// If no reports arrive, it returns an empty slice and no error.
+// If the context ends during the window, it returns the reports collected so far
+// along with the context's error.
+func (l *LLRPDevice) TrySendCollect(ctx context.Context, request llrp.Outgoing, reply llrp.Incoming,
+	window time.Duration) ([]*llrp.ROAccessReport, error) {
+	c := l.collectors.add()
+
+	if err := l.TrySend(ctx, request, reply); err != nil {
+		l.collectors.remove(c)
+		return nil, err
+	}
+
+	windowDone := make(chan struct{})
+	t := l.clock().AfterFunc(window, func() { close(windowDone) })
+	defer t.Stop()
+
+	var err error
+	select {
+	case <-windowDone:
+	case <-ctx.Done():
+		err = errors.WithMessagef(ctx.Err(), "stopped collecting reports after %v", request.Type())
+	}
+
+	reports := l.collectors.remove(c)
+	if reports == nil {
+		reports = []*llrp.ROAccessReport{}
+	}
+	return reports, err
+}
@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_TrySendCollect(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clk := newFakeClock()
+	dev := &LLRPDevice{name: "localReader", lc: edgexCompatTestLogger{t}, clk: clk}
+
+	// The Reader sends a report before its response.
+	first := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{{EPC96: llrp.EPC96{EPC: []byte{1}}}}}
+	rfid.SetResponseFunc(llrp.MsgStartROSpec, func(msg llrp.Message) llrp.Outgoing {
+		dev.collectors.deliver(first)
+		return &llrp.StartROSpecResponse{}
+	})
+
+	go rfid.ImpersonateReader()
+	dev.client = rfid.ConnectClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	type result struct {
+		reports []*llrp.ROAccessReport
+		err     error
+	}
+	collect := func(ctx context.Context) chan result {
+		results := make(chan result, 1)
+		go func() {
+			reports, err := dev.TrySendCollect(ctx, &llrp.StartROSpec{ROSpecID: 1},
+				&llrp.StartROSpecResponse{}, time.Second)
+			results <- result{reports, err}
+		}()
+		return results
+	}
+
+	// waitForWindow waits until the window's timer has started, which happens after the response.
+	waitForWindow := func(n int) {
+		deadline := time.Now().Add(5 * time.Second)
+		for clk.numTimers() < n {
+			if time.Now().After(deadline) {
+				t.Fatal("TrySendCollect never started its window")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	results := collect(ctx)
+	waitForWindow(1)
+
+	second := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{{EPC96: llrp.EPC96{EPC: []byte{2}}}}}
+	dev.collectors.deliver(second)
+	clk.Advance(time.Second)
+
+	r := <-results
+	if r.err != nil {
+		t.Fatalf("%+v", r.err)
+	}
+	if len(r.reports) != 2 || r.reports[0] != first || r.reports[1] != second {
+		t.Errorf("expected the reports before and during the window; got %+v", r.reports)
+	}
+
+	// Reports after the window aren't collected.
+	dev.collectors.deliver(&llrp.ROAccessReport{})
+	if n := len(dev.collectors.open); n != 0 {
+		t.Errorf("expected no open collections; got %d", n)
+	}
+
+	// If the context ends during the window, the reports so far are returned with its error.
+	cancelCtx, cancelCollect := context.WithCancel(ctx)
+	results = collect(cancelCtx)
+	waitForWindow(2)
+	cancelCollect()
+
+	r = <-results
+	if !errors.Is(r.err, context.Canceled) {
+		t.Errorf("expected the context's error; got %v", r.err)
+	}
+	if len(r.reports) != 1 || r.reports[0] != first {
+		t.Errorf("expected the report before the response; got %+v", r.reports)
+	}
+}
+
+func TestLLRPDevice_TrySendCollect_noReports(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rfid.SetResponse(llrp.MsgStartROSpec, &llrp.StartROSpecResponse{})
+
+	go rfid.ImpersonateReader()
+	dev := &LLRPDevice{name: "localReader", client: rfid.ConnectClient(t), lc: edgexCompatTestLogger{t}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	reports, err := dev.TrySendCollect(ctx, &llrp.StartROSpec{ROSpecID: 1},
+		&llrp.StartROSpecResponse{}, time.Millisecond*10)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if reports == nil || len(reports) != 0 {
+		t.Errorf("expected an empty, non-nil slice; got %#v", reports)
+	}
+}
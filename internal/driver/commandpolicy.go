@@ -0,0 +1,95 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// ErrCommandNotPermitted is returned for commands that read or write a resource
+// the device's allow or deny list forbids; the command isn't sent to the Reader.
+var ErrCommandNotPermitted = errors.New("command not permitted on this device")
+
+// commandPolicy restricts the resources a device's commands may read or write.
+// Its entries are resource names, optionally limited to one operation
+// by following the name with a colon and CommandRead or CommandWrite, e.g., "ROSpec:write".
+// Its zero value permits everything.
+type commandPolicy struct {
+	allow map[string]bool // if non-nil, only these are permitted
+	deny  map[string]bool // these are forbidden, even if allowed
+}
+
+// permits returns true if the policy permits the operation on the resource.
+func (p commandPolicy) permits(op, resource string) bool {
+	qualified := resource + ":" + op
+	if p.deny[resource] || p.deny[qualified] {
+		return false
+	}
+	return p.allow == nil || p.allow[resource] || p.allow[qualified]
+}
+
+// getCommandPolicy returns the policy in the commands protocol's
+// allow and deny properties, each a comma-separated list of entries.
+// If either is invalid, it returns a policy that permits nothing and an error,
+// rather than risk permitting a command the list was meant to forbid.
+func getCommandPolicy(protocols protocolMap) (commandPolicy, error) {
+	allow, err := parseCommandList("allow", protocols[ProtocolCommands]["allow"])
+	if err != nil {
+		return commandPolicy{allow: map[string]bool{}}, err
+	}
+
+	deny, err := parseCommandList("deny", protocols[ProtocolCommands]["deny"])
+	if err != nil {
+		return commandPolicy{allow: map[string]bool{}}, err
+	}
+
+	return commandPolicy{allow: allow, deny: deny}, nil
+}
+
+// parseCommandList returns the set of entries in a commandPolicy list,
+// or nil if it has none.
+func parseCommandList(property, list string) (map[string]bool, error) {
+	var entries map[string]bool
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if i := strings.IndexByte(entry, ':'); i >= 0 {
+			switch op := entry[i+1:]; {
+			case i == 0:
+				return nil, errors.Errorf("%s %s entry %q is missing a resource name",
+					ProtocolCommands, property, entry)
+			case op != CommandRead && op != CommandWrite:
+				return nil, errors.Errorf("%s %s entry %q has unknown operation %q; operations are %s or %s",
+					ProtocolCommands, property, entry, op, CommandRead, CommandWrite)
+			}
+		}
+
+		if entries == nil {
+			entries = map[string]bool{}
+		}
+		entries[entry] = true
+	}
+	return entries, nil
+}
+
+// checkPermitted returns an error wrapping ErrCommandNotPermitted
+// if the device's policy forbids the operation on any of the resources.
+func (l *LLRPDevice) checkPermitted(op string, resources ...string) error {
+	l.deviceMu.RLock()
+	policy := l.commandPolicy
+	l.deviceMu.RUnlock()
+
+	for _, resource := range resources {
+		if !policy.permits(op, resource) {
+			return errors.Wrapf(ErrCommandNotPermitted, "%s %s", op, resource)
+		}
+	}
+	return nil
+}
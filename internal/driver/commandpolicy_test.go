@@ -0,0 +1,136 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"testing"
+)
+
+func TestGetCommandPolicy(t *testing.T) {
+	type check struct {
+		op, resource string
+		permitted    bool
+	}
+
+	for _, testCase := range []struct {
+		name        string
+		allow, deny string
+		err         bool
+		checks      []check
+	}{
+		{
+			name: "default",
+			checks: []check{
+				{CommandWrite, ResourceROSpecID, true},
+				{CommandRead, ResourceReaderConfig, true},
+			},
+		},
+		{
+			name: "deny",
+			deny: "ROSpecID, AccessSpec:write",
+			checks: []check{
+				{CommandWrite, ResourceROSpecID, false},
+				{CommandRead, ResourceROSpecID, false},
+				{CommandWrite, ResourceAccessSpec, false},
+				{CommandRead, ResourceAccessSpec, true},
+				{CommandWrite, ResourceROSpec, true},
+			},
+		},
+		{
+			name:  "allow",
+			allow: "ReaderConfig:read,ROSpec",
+			deny:  "ROSpec:write",
+			checks: []check{
+				{CommandRead, ResourceReaderConfig, true},
+				{CommandWrite, ResourceReaderConfig, false},
+				{CommandRead, ResourceROSpec, true},
+				{CommandWrite, ResourceROSpec, false},
+				{CommandRead, ResourceReaderCap, false},
+			},
+		},
+		{
+			name:  "emptyAllow",
+			allow: " , ",
+			checks: []check{
+				{CommandWrite, ResourceROSpecID, true},
+			},
+		},
+		{
+			name: "unknownOperation",
+			deny: "ROSpecID:delete",
+			err:  true,
+			checks: []check{
+				{CommandRead, ResourceReaderConfig, false},
+			},
+		},
+		{
+			name:  "missingResource",
+			allow: ":read",
+			err:   true,
+			checks: []check{
+				{CommandRead, ResourceReaderConfig, false},
+			},
+		},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			protocols := protocolMap{ProtocolCommands: contract.ProtocolProperties{
+				"allow": testCase.allow,
+				"deny":  testCase.deny,
+			}}
+
+			policy, err := getCommandPolicy(protocols)
+			if (err != nil) != testCase.err {
+				t.Errorf("expected error: %v; got %v", testCase.err, err)
+			}
+
+			for _, c := range testCase.checks {
+				if got := policy.permits(c.op, c.resource); got != c.permitted {
+					t.Errorf("%s %s: expected permitted: %v; got %v", c.op, c.resource, c.permitted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDriver_deniedCommand(t *testing.T) {
+	dev := &LLRPDevice{clk: newFakeClock()}
+	d := newLocalDriver(t, dev)
+	d.clk = dev.clk
+
+	d.setProtocolOptions(dev, protocolMap{ProtocolCommands: contract.ProtocolProperties{
+		"deny": "ROSpecID:write",
+	}})
+
+	// The device has no connection, so the command fails unless it's rejected first.
+	params := []*dsModels.CommandValue{
+		dsModels.NewStringValue(ResourceROSpecID, 0, "1"),
+		dsModels.NewStringValue(ResourceAction, 0, ActionDelete),
+	}
+	reqs := []dsModels.CommandRequest{
+		{DeviceResourceName: ResourceROSpecID},
+		{DeviceResourceName: ResourceAction},
+	}
+	err := d.HandleWriteCommands("localReader", protocolMap{}, reqs, params)
+	if !errors.Is(err, ErrCommandNotPermitted) {
+		t.Errorf("expected %v; got %+v", ErrCommandNotPermitted, err)
+	}
+
+	// Other commands are still permitted.
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceCommandLatency,
+		Type:               dsModels.String,
+	}})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(cvs) != 1 {
+		t.Errorf("expected one value; got %v", cvs)
+	}
+}
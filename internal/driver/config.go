@@ -8,6 +8,7 @@ package driver
 import (
 	"fmt"
 	"github.com/pkg/errors"
+	"math"
 	"strconv"
 )
 
@@ -26,17 +27,159 @@ type driverConfiguration struct {
 	// MaxDiscoverDurationSeconds is the maximum amount of seconds for a discovery to run. It is important
 	// to have this configured in the case of larger subnets such as /16 and /8
 	MaxDiscoverDurationSeconds int
+	// MissingReaders is what a complete discovery does with disabled devices whose Readers
+	// it didn't find: "flag" sends a ReaderPresence event for them, while "remove" also removes them.
+	MissingReaders string
+	// MaxDiscoveryBackoff is the most discovery intervals between scans while discoveries find no changes:
+	// each complete discovery in a row that changes nothing doubles the intervals between scans,
+	// up to this many, skipping the discoveries in between. Any change resets it.
+	// If 0 or 1, every discovery scans.
+	MaxDiscoveryBackoff int
+	// IdleTimeoutMinutes is the number of minutes a Reader connection may go unused
+	// (no commands and no reports) before it's closed. The connection is reopened
+	// the next time a command targets the device. If 0, connections are never closed for inactivity.
+	IdleTimeoutMinutes int
+	// KeepAliveSeconds is how often the service asks Readers to send KeepAlives.
+	// A connection that misses two of them is considered dead and reset.
+	// If 0, the service leaves Readers' KeepAliveSpec alone and connections never time out,
+	// for deployments that rely on TCP keepalive instead.
+	KeepAliveSeconds int
+	// ReportCacheSize is the number of most recent tag reads kept for each device
+	// and returned when reading the ROAccessReport resource. If 0, reads aren't cached.
+	ReportCacheSize int
+	// TagCountWindowSeconds is the number of seconds in which a tag must have been seen
+	// to be included when reading the TagCount resource. If 0, TagCount instead returns
+	// the number of unique tags seen since the previous read.
+	TagCountWindowSeconds int
+	// ReportEncoding is the encoding of the ROAccessReports sent to EdgeX:
+	// "json" sends String readings of ROAccessReport, while "cbor" sends
+	// smaller Binary readings of ROAccessReportCBOR.
+	ReportEncoding string
+	// AbsentFields is how the JSON the service emits represents optional LLRP parameters
+	// a Reader didn't send: "null" includes their fields as null, while "omit" leaves them out.
+	AbsentFields string
+	// ReadingOrigin is the source of the Origin timestamp of tag read readings:
+	// "host" uses when the service received them, while "firstSeen" and "lastSeen"
+	// use the Reader's FirstSeenUTC and LastSeenUTC timestamps.
+	ReadingOrigin string
+	// CircuitBreakerFailures is the number of consecutive commands that must fail
+	// to reach a Reader before further commands fail fast. If 0, commands never fail fast.
+	CircuitBreakerFailures int
+	// CircuitBreakerCooldownSeconds is the number of seconds commands fail fast
+	// before one is allowed through to check whether the Reader has recovered.
+	CircuitBreakerCooldownSeconds int
+	// MaxConcurrentCommands is the maximum number of commands each device may have
+	// in flight at once. If 0, the number isn't limited.
+	MaxConcurrentCommands int
+	// MaxConcurrentDeviceChanges is the maximum number of devices AddDevices and RemoveDevices
+	// connect or disconnect at once. If 0, the number isn't limited.
+	MaxConcurrentDeviceChanges int
+	// CommandOverflow is what happens to commands beyond MaxConcurrentCommands:
+	// "queue" waits for an earlier one to finish, while "fail" fails them immediately.
+	CommandOverflow string
+	// CommandTimeoutSeconds is the number of seconds a command may take,
+	// including any time it waits behind other commands, before it fails.
+	CommandTimeoutSeconds int
+	// MaxCommandTimeoutSeconds is the most seconds a resource's timeout attribute
+	// may extend its commands' timeout to.
+	MaxCommandTimeoutSeconds int
+	// VersionMismatch is what to do when a Reader sends a message with a different
+	// LLRP version than it negotiated: "warn" processes it anyway, while "reject"
+	// closes the connection. Either way, the service sends a VersionMismatch event.
+	VersionMismatch string
+	// MaxDiscardKiB is the most KiB of a message nothing reads that the service discards
+	// to move on to the Reader's next message. Larger messages reset the connection instead.
+	// If 0, messages are always discarded, however large.
+	MaxDiscardKiB int
+	// MessageIDStart is the ID each connection gives the first message it sends a Reader;
+	// later messages count up from it. Starting connections at distinct IDs
+	// can make it easier to correlate their messages, e.g., in a packet capture.
+	MessageIDStart uint32
+	// AsyncOverflow is what happens to readings and events while EdgeX isn't keeping up with them:
+	// "block" waits for it, "drop" drops them, and "queue" queues up to AsyncQueueSize per device,
+	// dropping them beyond that. The latter two send an AsyncValuesDropped event after dropping some.
+	AsyncOverflow string
+	// AsyncQueueSize is the number of readings and events each device queues
+	// for the "queue" AsyncOverflow policy.
+	AsyncQueueSize int
+	// AsyncQueueBudgetKiB is the most KiB of readings and events all devices together queue
+	// for the "queue" AsyncOverflow policy, with each device guaranteed half an even share.
+	// If 0, only AsyncQueueSize limits the queues.
+	AsyncQueueBudgetKiB int
+	// AsyncQueueBudgetOverflow is what happens to readings and events that don't fit
+	// in the AsyncQueueBudgetKiB: "drop" drops them, while "block" waits for room.
+	AsyncQueueBudgetOverflow string
+	// BaselineROSpec is the JSON ROSpec ResetROSpecs adds when it's written "default".
+	// If empty, it's an ROSpec with ID 1 that inventories with all antennas until stopped.
+	BaselineROSpec string
+	// ConnectSequence is a JSON list of write commands each device runs, in order,
+	// every time it connects to its Reader. If empty, devices don't run any.
+	ConnectSequence string
+	// ReportSink is where the service sends ROAccessReports: "edgex" sends them
+	// to EdgeX as readings, while "mqtt" publishes them as JSON directly to an MQTT broker,
+	// bypassing core-data. Changing it rebuilds every device's connection.
+	ReportSink string
+	// ReportSinkAddress is the host:port of the MQTT broker for the "mqtt" ReportSink.
+	// Prefixing it with "tls://" or "ssl://" connects with TLS.
+	ReportSinkAddress string
+	// ReportSinkTopic is the topic to which the "mqtt" ReportSink publishes reports.
+	// "{device}" in it is replaced by the name of the device that sent the report.
+	ReportSinkTopic string
+	// ReportSinkClientID is the MQTT client ID of the "mqtt" ReportSink.
+	// If empty, it's the service's name with a random suffix,
+	// so instances of the service sharing a broker don't disconnect each other.
+	ReportSinkClientID string
+	// ReportSinkUsername and ReportSinkPassword authenticate the "mqtt" ReportSink
+	// with the broker; if empty, it connects without them.
+	// A password requires a username, and the password is never logged.
+	ReportSinkUsername string
+	ReportSinkPassword string
+	// ReportSinkCAFile is a PEM file of the certificate authorities the "mqtt" ReportSink
+	// trusts for TLS connections. If empty, it trusts the host's.
+	ReportSinkCAFile string
 }
 
 var (
 	// defaultConfig holds default values for each configurable item in case
 	// they are not present in the configuration
 	defaultConfig = map[string]string{
-		"DiscoverySubnets":           "",
-		"ProbeAsyncLimit":            "5000",
-		"ProbeTimeoutSeconds":        "2",
-		"ScanPort":                   "5084",
-		"MaxDiscoverDurationSeconds": "300",
+		"DiscoverySubnets":              "",
+		"ProbeAsyncLimit":               "5000",
+		"ProbeTimeoutSeconds":           "2",
+		"ScanPort":                      "5084",
+		"MaxDiscoverDurationSeconds":    "300",
+		"MissingReaders":                MissingReadersFlag,
+		"MaxDiscoveryBackoff":           "8",
+		"IdleTimeoutMinutes":            "0",
+		"KeepAliveSeconds":              "30",
+		"ReportCacheSize":               "100",
+		"TagCountWindowSeconds":         "60",
+		"ReportEncoding":                ReportEncodingJSON,
+		"AbsentFields":                  AbsentFieldsNull,
+		"ReadingOrigin":                 OriginHost,
+		"CircuitBreakerFailures":        "5",
+		"CircuitBreakerCooldownSeconds": "30",
+		"MaxConcurrentCommands":         "4",
+		"CommandOverflow":               CommandOverflowQueue,
+		"MaxConcurrentDeviceChanges":    "16",
+		"CommandTimeoutSeconds":         "20",
+		"MaxCommandTimeoutSeconds":      "300",
+		"VersionMismatch":               VersionMismatchWarn,
+		"MaxDiscardKiB":                 "0",
+		"MessageIDStart":                "0",
+		"AsyncOverflow":                 AsyncOverflowBlock,
+		"AsyncQueueSize":                "1000",
+		"AsyncQueueBudgetKiB":           "0",
+		"AsyncQueueBudgetOverflow":      AsyncOverflowDrop,
+		"BaselineROSpec":                "",
+		"ConnectSequence":               "",
+		"ReportSink":                    ReportSinkEdgeX,
+		"ReportSinkAddress":             "localhost:1883",
+		"ReportSinkTopic":               "llrp/" + ReportSinkTopicDevice + "/reports",
+		"ReportSinkClientID":            "",
+		"ReportSinkUsername":            "",
+		"ReportSinkPassword":            "",
+		"ReportSinkCAFile":              "",
 	}
 
 	// ErrUnexpectedConfigItems is returned when the input configuration map has extra keys
@@ -48,6 +191,15 @@ var (
 	ErrMissingRequiredKey = errors.New("missing required key")
 )
 
+// String returns the configuration with its ReportSinkPassword masked, for logging.
+func (c driverConfiguration) String() string {
+	if c.ReportSinkPassword != "" {
+		c.ReportSinkPassword = passwordMask
+	}
+	type plain driverConfiguration // without the String method
+	return fmt.Sprintf("%+v", plain(c))
+}
+
 // CreateDriverConfig creates a driverConfiguration object from the strings map provided by EdgeX
 func CreateDriverConfig(configMap map[string]string) (*driverConfiguration, error) {
 	config := new(driverConfiguration)
@@ -98,6 +250,217 @@ func load(configMap map[string]string, config *driverConfiguration) error {
 		return wrapParseError(err, "MaxDiscoverDurationSeconds")
 	}
 
+	config.MissingReaders, err = pop(cloneMap, "MissingReaders")
+	if err == nil {
+		err = checkMissingReaders(config.MissingReaders)
+	}
+	if err != nil {
+		return wrapParseError(err, "MissingReaders")
+	}
+
+	config.MaxDiscoveryBackoff, err = popInt(cloneMap, "MaxDiscoveryBackoff")
+	if err == nil {
+		err = checkMaxDiscoveryBackoff(config.MaxDiscoveryBackoff)
+	}
+	if err != nil {
+		return wrapParseError(err, "MaxDiscoveryBackoff")
+	}
+
+	config.IdleTimeoutMinutes, err = popInt(cloneMap, "IdleTimeoutMinutes")
+	if err != nil {
+		return wrapParseError(err, "IdleTimeoutMinutes")
+	}
+
+	config.KeepAliveSeconds, err = popInt(cloneMap, "KeepAliveSeconds")
+	if err == nil {
+		err = checkKeepAlive(config.KeepAliveSeconds)
+	}
+	if err != nil {
+		return wrapParseError(err, "KeepAliveSeconds")
+	}
+
+	config.ReportCacheSize, err = popInt(cloneMap, "ReportCacheSize")
+	if err != nil {
+		return wrapParseError(err, "ReportCacheSize")
+	}
+
+	config.TagCountWindowSeconds, err = popInt(cloneMap, "TagCountWindowSeconds")
+	if err != nil {
+		return wrapParseError(err, "TagCountWindowSeconds")
+	}
+
+	config.ReportEncoding, err = pop(cloneMap, "ReportEncoding")
+	if err == nil {
+		_, err = newReportCodec(config.ReportEncoding, false)
+	}
+	if err != nil {
+		return wrapParseError(err, "ReportEncoding")
+	}
+
+	config.AbsentFields, err = pop(cloneMap, "AbsentFields")
+	if err == nil {
+		err = checkAbsentFields(config.AbsentFields)
+	}
+	if err != nil {
+		return wrapParseError(err, "AbsentFields")
+	}
+
+	config.ReadingOrigin, err = pop(cloneMap, "ReadingOrigin")
+	if err == nil {
+		err = checkReadingOrigin(config.ReadingOrigin)
+	}
+	if err != nil {
+		return wrapParseError(err, "ReadingOrigin")
+	}
+
+	config.CircuitBreakerFailures, err = popInt(cloneMap, "CircuitBreakerFailures")
+	if err != nil {
+		return wrapParseError(err, "CircuitBreakerFailures")
+	}
+
+	config.CircuitBreakerCooldownSeconds, err = popInt(cloneMap, "CircuitBreakerCooldownSeconds")
+	if err != nil {
+		return wrapParseError(err, "CircuitBreakerCooldownSeconds")
+	}
+
+	config.MaxConcurrentCommands, err = popInt(cloneMap, "MaxConcurrentCommands")
+	if err != nil {
+		return wrapParseError(err, "MaxConcurrentCommands")
+	}
+
+	config.CommandOverflow, err = pop(cloneMap, "CommandOverflow")
+	if err == nil {
+		err = checkCommandOverflow(config.CommandOverflow)
+	}
+	if err != nil {
+		return wrapParseError(err, "CommandOverflow")
+	}
+
+	config.MaxConcurrentDeviceChanges, err = popInt(cloneMap, "MaxConcurrentDeviceChanges")
+	if err != nil {
+		return wrapParseError(err, "MaxConcurrentDeviceChanges")
+	}
+
+	config.CommandTimeoutSeconds, err = popInt(cloneMap, "CommandTimeoutSeconds")
+	if err != nil {
+		return wrapParseError(err, "CommandTimeoutSeconds")
+	}
+
+	config.MaxCommandTimeoutSeconds, err = popInt(cloneMap, "MaxCommandTimeoutSeconds")
+	if err == nil {
+		err = checkCommandTimeouts(config.CommandTimeoutSeconds, config.MaxCommandTimeoutSeconds)
+	}
+	if err != nil {
+		return wrapParseError(err, "MaxCommandTimeoutSeconds")
+	}
+
+	config.VersionMismatch, err = pop(cloneMap, "VersionMismatch")
+	if err == nil {
+		err = checkVersionMismatch(config.VersionMismatch)
+	}
+	if err != nil {
+		return wrapParseError(err, "VersionMismatch")
+	}
+
+	config.MaxDiscardKiB, err = popInt(cloneMap, "MaxDiscardKiB")
+	if err == nil {
+		err = checkMaxDiscardKiB(config.MaxDiscardKiB)
+	}
+	if err != nil {
+		return wrapParseError(err, "MaxDiscardKiB")
+	}
+
+	config.MessageIDStart, err = popUint32(cloneMap, "MessageIDStart")
+	if err != nil {
+		return wrapParseError(err, "MessageIDStart")
+	}
+
+	config.AsyncOverflow, err = pop(cloneMap, "AsyncOverflow")
+	if err == nil {
+		err = checkAsyncOverflow(config.AsyncOverflow)
+	}
+	if err != nil {
+		return wrapParseError(err, "AsyncOverflow")
+	}
+
+	config.AsyncQueueSize, err = popInt(cloneMap, "AsyncQueueSize")
+	if err == nil {
+		err = checkAsyncQueueSize(config.AsyncQueueSize)
+	}
+	if err != nil {
+		return wrapParseError(err, "AsyncQueueSize")
+	}
+
+	config.AsyncQueueBudgetKiB, err = popInt(cloneMap, "AsyncQueueBudgetKiB")
+	if err == nil {
+		err = checkAsyncQueueBudgetKiB(config.AsyncQueueBudgetKiB)
+	}
+	if err != nil {
+		return wrapParseError(err, "AsyncQueueBudgetKiB")
+	}
+
+	config.AsyncQueueBudgetOverflow, err = pop(cloneMap, "AsyncQueueBudgetOverflow")
+	if err == nil {
+		err = checkAsyncQueueBudgetOverflow(config.AsyncQueueBudgetOverflow)
+	}
+	if err != nil {
+		return wrapParseError(err, "AsyncQueueBudgetOverflow")
+	}
+
+	config.BaselineROSpec, err = pop(cloneMap, "BaselineROSpec")
+	if err == nil {
+		err = checkBaselineROSpec(config.BaselineROSpec)
+	}
+	if err != nil {
+		return wrapParseError(err, "BaselineROSpec")
+	}
+
+	config.ConnectSequence, err = pop(cloneMap, "ConnectSequence")
+	if err == nil {
+		err = checkConnectSequence(config.ConnectSequence)
+	}
+	if err != nil {
+		return wrapParseError(err, "ConnectSequence")
+	}
+
+	config.ReportSink, err = pop(cloneMap, "ReportSink")
+	if err == nil {
+		err = checkReportSink(config.ReportSink)
+	}
+	if err != nil {
+		return wrapParseError(err, "ReportSink")
+	}
+
+	config.ReportSinkAddress, err = pop(cloneMap, "ReportSinkAddress")
+	if err != nil {
+		return wrapParseError(err, "ReportSinkAddress")
+	}
+
+	config.ReportSinkTopic, err = pop(cloneMap, "ReportSinkTopic")
+	if err != nil {
+		return wrapParseError(err, "ReportSinkTopic")
+	}
+
+	config.ReportSinkClientID, err = pop(cloneMap, "ReportSinkClientID")
+	if err != nil {
+		return wrapParseError(err, "ReportSinkClientID")
+	}
+
+	config.ReportSinkUsername, err = pop(cloneMap, "ReportSinkUsername")
+	if err != nil {
+		return wrapParseError(err, "ReportSinkUsername")
+	}
+
+	config.ReportSinkPassword, err = pop(cloneMap, "ReportSinkPassword")
+	if err != nil {
+		return wrapParseError(err, "ReportSinkPassword")
+	}
+
+	config.ReportSinkCAFile, err = pop(cloneMap, "ReportSinkCAFile")
+	if err != nil {
+		return wrapParseError(err, "ReportSinkCAFile")
+	}
+
 	// in this case there were extra fields that are not in our config map.
 	// these could either be outdated config options or typos
 	if len(cloneMap) > 0 {
@@ -139,3 +502,31 @@ func popInt(cloneMap map[string]string, key string) (int, error) {
 	}
 	return strconv.Atoi(val)
 }
+
+// popUint32 functions the same way as pop, except it will attempt to convert the value to a uint32
+func popUint32(cloneMap map[string]string, key string) (uint32, error) {
+	val, err := pop(cloneMap, key)
+	if err != nil {
+		return 0, err
+	}
+	u, err := strconv.ParseUint(val, 10, 32)
+	return uint32(u), err
+}
+
+// checkMaxDiscoveryBackoff returns an error if the MaxDiscoveryBackoff is negative.
+func checkMaxDiscoveryBackoff(intervals int) error {
+	if intervals < 0 {
+		return errors.Errorf("max discovery backoff must not be negative; got %d", intervals)
+	}
+	return nil
+}
+
+// checkMaxDiscardKiB returns an error if the MaxDiscardKiB is negative
+// or larger than the largest LLRP message.
+func checkMaxDiscardKiB(kib int) error {
+	const maxKiB = math.MaxUint32 / 1024
+	if kib < 0 || kib > maxKiB {
+		return errors.Errorf("max discard must be from 0 to %d KiB; got %d", maxKiB, kib)
+	}
+	return nil
+}
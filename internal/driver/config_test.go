@@ -17,11 +17,43 @@ import (
 func testConfig() map[string]string {
 	// NOTE: If you change this, you MUST update `TestLoad`!
 	return map[string]string{
-		"DiscoverySubnets":           "127.0.0.1/32,127.0.1.1/32",
-		"ProbeAsyncLimit":            "2257",
-		"ProbeTimeoutSeconds":        "5",
-		"ScanPort":                   "5084",
-		"MaxDiscoverDurationSeconds": "100",
+		"DiscoverySubnets":              "127.0.0.1/32,127.0.1.1/32",
+		"ProbeAsyncLimit":               "2257",
+		"ProbeTimeoutSeconds":           "5",
+		"ScanPort":                      "5084",
+		"MaxDiscoverDurationSeconds":    "100",
+		"MissingReaders":                "remove",
+		"MaxDiscoveryBackoff":           "4",
+		"IdleTimeoutMinutes":            "15",
+		"KeepAliveSeconds":              "10",
+		"ReportCacheSize":               "20",
+		"TagCountWindowSeconds":         "30",
+		"ReportEncoding":                "cbor",
+		"AbsentFields":                  "omit",
+		"ReadingOrigin":                 "lastSeen",
+		"CircuitBreakerFailures":        "3",
+		"CircuitBreakerCooldownSeconds": "10",
+		"MaxConcurrentCommands":         "2",
+		"CommandOverflow":               "fail",
+		"MaxConcurrentDeviceChanges":    "8",
+		"CommandTimeoutSeconds":         "30",
+		"MaxCommandTimeoutSeconds":      "600",
+		"VersionMismatch":               "reject",
+		"MaxDiscardKiB":                 "512",
+		"MessageIDStart":                "4000000000",
+		"AsyncOverflow":                 "queue",
+		"AsyncQueueSize":                "50",
+		"AsyncQueueBudgetKiB":           "4096",
+		"AsyncQueueBudgetOverflow":      "block",
+		"BaselineROSpec":                `{"ROSpecID": 7}`,
+		"ConnectSequence":               `[{"Resource": "HoldEventsAndReports", "Value": "true"}, {"Resource": "ROSpecID", "Value": "1", "Action": "Enable", "OnError": "continue"}]`,
+		"ReportSink":                    "mqtt",
+		"ReportSinkAddress":             "broker:1883",
+		"ReportSinkTopic":               "readers/{device}",
+		"ReportSinkClientID":            "llrp-east",
+		"ReportSinkUsername":            "reports",
+		"ReportSinkPassword":            "secret",
+		"ReportSinkCAFile":              "/etc/ssl/broker-ca.pem",
 	}
 }
 
@@ -38,6 +70,38 @@ func TestLoad(t *testing.T) {
 		c.ProbeTimeoutSeconds != 5 ||
 		c.ScanPort != "5084" ||
 		c.MaxDiscoverDurationSeconds != 100 ||
+		c.MissingReaders != "remove" ||
+		c.MaxDiscoveryBackoff != 4 ||
+		c.IdleTimeoutMinutes != 15 ||
+		c.KeepAliveSeconds != 10 ||
+		c.ReportCacheSize != 20 ||
+		c.TagCountWindowSeconds != 30 ||
+		c.ReportEncoding != "cbor" ||
+		c.AbsentFields != "omit" ||
+		c.ReadingOrigin != "lastSeen" ||
+		c.CircuitBreakerFailures != 3 ||
+		c.CircuitBreakerCooldownSeconds != 10 ||
+		c.MaxConcurrentCommands != 2 ||
+		c.CommandOverflow != "fail" ||
+		c.MaxConcurrentDeviceChanges != 8 ||
+		c.CommandTimeoutSeconds != 30 ||
+		c.MaxCommandTimeoutSeconds != 600 ||
+		c.VersionMismatch != "reject" ||
+		c.MaxDiscardKiB != 512 ||
+		c.MessageIDStart != 4000000000 ||
+		c.AsyncOverflow != "queue" ||
+		c.AsyncQueueSize != 50 ||
+		c.AsyncQueueBudgetKiB != 4096 ||
+		c.AsyncQueueBudgetOverflow != "block" ||
+		c.BaselineROSpec != `{"ROSpecID": 7}` ||
+		c.ConnectSequence != `[{"Resource": "HoldEventsAndReports", "Value": "true"}, {"Resource": "ROSpecID", "Value": "1", "Action": "Enable", "OnError": "continue"}]` ||
+		c.ReportSink != "mqtt" ||
+		c.ReportSinkAddress != "broker:1883" ||
+		c.ReportSinkTopic != "readers/{device}" ||
+		c.ReportSinkClientID != "llrp-east" ||
+		c.ReportSinkUsername != "reports" ||
+		c.ReportSinkPassword != "secret" ||
+		c.ReportSinkCAFile != "/etc/ssl/broker-ca.pem" ||
 		len(subnets) != 2 ||
 		subnets[0] != "127.0.0.1/32" ||
 		subnets[1] != "127.0.1.1/32" {
@@ -89,6 +153,198 @@ func TestMissingFieldDefaults(t *testing.T) {
 				return strconv.Itoa(d.MaxDiscoverDurationSeconds)
 			},
 		},
+		{
+			key: "MissingReaders",
+			valueFn: func(d driverConfiguration) string {
+				return d.MissingReaders
+			},
+		},
+		{
+			key: "IdleTimeoutMinutes",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.IdleTimeoutMinutes)
+			},
+		},
+		{
+			key: "ReportCacheSize",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.ReportCacheSize)
+			},
+		},
+		{
+			key: "TagCountWindowSeconds",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.TagCountWindowSeconds)
+			},
+		},
+		{
+			key: "ReportEncoding",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReportEncoding
+			},
+		},
+		{
+			key: "ReadingOrigin",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReadingOrigin
+			},
+		},
+		{
+			key: "CircuitBreakerFailures",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.CircuitBreakerFailures)
+			},
+		},
+		{
+			key: "CircuitBreakerCooldownSeconds",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.CircuitBreakerCooldownSeconds)
+			},
+		},
+		{
+			key: "MaxConcurrentCommands",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.MaxConcurrentCommands)
+			},
+		},
+		{
+			key: "CommandOverflow",
+			valueFn: func(d driverConfiguration) string {
+				return d.CommandOverflow
+			},
+		},
+		{
+			key: "MaxConcurrentDeviceChanges",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.MaxConcurrentDeviceChanges)
+			},
+		},
+		{
+			key: "MaxDiscoveryBackoff",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.MaxDiscoveryBackoff)
+			},
+		},
+		{
+			key: "KeepAliveSeconds",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.KeepAliveSeconds)
+			},
+		},
+		{
+			key: "CommandTimeoutSeconds",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.CommandTimeoutSeconds)
+			},
+		},
+		{
+			key: "MaxCommandTimeoutSeconds",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.MaxCommandTimeoutSeconds)
+			},
+		},
+		{
+			key: "AbsentFields",
+			valueFn: func(d driverConfiguration) string {
+				return d.AbsentFields
+			},
+		},
+		{
+			key: "VersionMismatch",
+			valueFn: func(d driverConfiguration) string {
+				return d.VersionMismatch
+			},
+		},
+		{
+			key: "MaxDiscardKiB",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.MaxDiscardKiB)
+			},
+		},
+		{
+			key: "MessageIDStart",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.FormatUint(uint64(d.MessageIDStart), 10)
+			},
+		},
+		{
+			key: "AsyncOverflow",
+			valueFn: func(d driverConfiguration) string {
+				return d.AsyncOverflow
+			},
+		},
+		{
+			key: "AsyncQueueSize",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.AsyncQueueSize)
+			},
+		},
+		{
+			key: "AsyncQueueBudgetKiB",
+			valueFn: func(d driverConfiguration) string {
+				return strconv.Itoa(d.AsyncQueueBudgetKiB)
+			},
+		},
+		{
+			key: "AsyncQueueBudgetOverflow",
+			valueFn: func(d driverConfiguration) string {
+				return d.AsyncQueueBudgetOverflow
+			},
+		},
+		{
+			key: "BaselineROSpec",
+			valueFn: func(d driverConfiguration) string {
+				return d.BaselineROSpec
+			},
+		},
+		{
+			key: "ConnectSequence",
+			valueFn: func(d driverConfiguration) string {
+				return d.ConnectSequence
+			},
+		},
+		{
+			key: "ReportSink",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReportSink
+			},
+		},
+		{
+			key: "ReportSinkAddress",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReportSinkAddress
+			},
+		},
+		{
+			key: "ReportSinkTopic",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReportSinkTopic
+			},
+		},
+		{
+			key: "ReportSinkClientID",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReportSinkClientID
+			},
+		},
+		{
+			key: "ReportSinkUsername",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReportSinkUsername
+			},
+		},
+		{
+			key: "ReportSinkPassword",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReportSinkPassword
+			},
+		},
+		{
+			key: "ReportSinkCAFile",
+			valueFn: func(d driverConfiguration) string {
+				return d.ReportSinkCAFile
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.key, func(t *testing.T) {
@@ -119,3 +375,117 @@ func TestErrUnexpectedConfigItems(t *testing.T) {
 		t.Fatalf("expected ErrUnexpectedConfigItems, but got: %v", err)
 	}
 }
+
+func TestInvalidCommandTimeouts(t *testing.T) {
+	for _, timeouts := range [][2]string{
+		{"0", "300"},
+		{"-5", "300"},
+		{"60", "30"},
+	} {
+		cfg := testConfig()
+		cfg["CommandTimeoutSeconds"] = timeouts[0]
+		cfg["MaxCommandTimeoutSeconds"] = timeouts[1]
+
+		var driverCfg driverConfiguration
+		if err := load(cfg, &driverCfg); err == nil {
+			t.Errorf("timeouts %v: expected an error", timeouts)
+		}
+	}
+}
+
+func TestInvalidAsyncOverflow(t *testing.T) {
+	for key, value := range map[string]string{
+		"AsyncOverflow":            "spill",
+		"AsyncQueueSize":           "0",
+		"AsyncQueueBudgetKiB":      "-1",
+		"AsyncQueueBudgetOverflow": "queue",
+	} {
+		cfg := testConfig()
+		cfg[key] = value
+
+		var driverCfg driverConfiguration
+		if err := load(cfg, &driverCfg); err == nil {
+			t.Errorf("%s %q: expected an error", key, value)
+		}
+	}
+}
+
+func TestInvalidMissingReaders(t *testing.T) {
+	cfg := testConfig()
+	cfg["MissingReaders"] = "forget"
+
+	var driverCfg driverConfiguration
+	if err := load(cfg, &driverCfg); err == nil {
+		t.Error("expected an error for an unknown MissingReaders policy")
+	}
+}
+
+func TestInvalidMaxDiscoveryBackoff(t *testing.T) {
+	for _, intervals := range []string{"-1", "often"} {
+		cfg := testConfig()
+		cfg["MaxDiscoveryBackoff"] = intervals
+		var driverCfg driverConfiguration
+		if err := load(cfg, &driverCfg); err == nil {
+			t.Errorf("MaxDiscoveryBackoff %q: expected an error", intervals)
+		}
+	}
+}
+
+func TestInvalidKeepAlive(t *testing.T) {
+	for _, secs := range []string{"-1", "4294968", "soon"} {
+		cfg := testConfig()
+		cfg["KeepAliveSeconds"] = secs
+
+		var driverCfg driverConfiguration
+		if err := load(cfg, &driverCfg); err == nil {
+			t.Errorf("KeepAliveSeconds %q: expected an error", secs)
+		}
+	}
+
+	// 0 disables KeepAlives.
+	cfg := testConfig()
+	cfg["KeepAliveSeconds"] = "0"
+	var driverCfg driverConfiguration
+	if err := load(cfg, &driverCfg); err != nil || driverCfg.KeepAliveSeconds != 0 {
+		t.Errorf("expected KeepAliveSeconds 0 to be valid; got %d, %v", driverCfg.KeepAliveSeconds, err)
+	}
+}
+
+func TestCheckMaxDiscardKiB(t *testing.T) {
+	for _, kib := range []int{0, 1, 4194303} {
+		if err := checkMaxDiscardKiB(kib); err != nil {
+			t.Errorf("%d: expected no error; got %v", kib, err)
+		}
+	}
+	for _, kib := range []int{-1, 4194304} {
+		if err := checkMaxDiscardKiB(kib); err == nil {
+			t.Errorf("%d: expected an error", kib)
+		}
+	}
+}
+
+func TestInvalidMessageIDStart(t *testing.T) {
+	for _, id := range []string{"-1", "4294967296", "first"} {
+		cfg := testConfig()
+		cfg["MessageIDStart"] = id
+		var driverCfg driverConfiguration
+		if err := load(cfg, &driverCfg); err == nil {
+			t.Errorf("MessageIDStart %q: expected an error", id)
+		}
+	}
+}
+
+func TestDriverConfiguration_String(t *testing.T) {
+	c := driverConfiguration{ReportSinkUsername: "reports", ReportSinkPassword: "secret"}
+	for _, logged := range []string{c.String(), fmt.Sprintf("%+v", &c)} {
+		if strings.Contains(logged, "secret") || !strings.Contains(logged, "ReportSinkPassword:"+passwordMask) {
+			t.Errorf("expected the password to be masked; got %s", logged)
+		}
+		if !strings.Contains(logged, "ReportSinkUsername:reports") {
+			t.Errorf("expected the other fields to be logged; got %s", logged)
+		}
+	}
+	if c.ReportSinkPassword != "secret" {
+		t.Error("expected String not to change the configuration")
+	}
+}
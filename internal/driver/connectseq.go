@@ -0,0 +1,170 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ResourceConnectSequence is sent as an event with the result of each step
+	// of the ConnectSequence the service runs after connecting to a Reader.
+	ResourceConnectSequence = "ConnectSequence"
+
+	// What a ConnectSequence does after a step fails.
+	OnErrorAbort    = "abort"    // skip the remaining steps
+	OnErrorContinue = "continue" // run the remaining steps anyway
+)
+
+// connectStep is one step of the ConnectSequence:
+// a write command, as if a deviceCommand set the Resource to the Value.
+type connectStep struct {
+	Resource string
+	Value    string
+	Action   string `json:",omitempty"` // the Action written with ROSpecID or AccessSpecID
+	OnError  string `json:",omitempty"` // OnErrorAbort (the default) or OnErrorContinue
+}
+
+// connectSequenceResult is the value of ResourceConnectSequence events.
+type connectSequenceResult struct {
+	Completed bool // false if a step failed and aborted the rest
+	Steps     []connectStepResult
+}
+
+// connectStepResult is the result of one step of a ConnectSequence.
+type connectStepResult struct {
+	Resource string
+	Action   string `json:",omitempty"`
+	Error    string `json:",omitempty"` // why the step failed, if it did
+}
+
+// parseConnectSequence returns the steps of a ConnectSequence,
+// a JSON list of steps, or nil if it's empty.
+//
+// Each step must write a resource the CommandCatalog lists as writable,
+// other than RawMessage, which needs attributes a step can't provide,
+// and MigrateAddress, which changes the address the service connects to.
+// ROSpecID and AccessSpecID steps need an Action.
+func parseConnectSequence(data string) ([]connectStep, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, nil
+	}
+
+	var steps []connectStep
+	if err := json.Unmarshal([]byte(data), &steps); err != nil {
+		return nil, errors.Wrap(err, "ConnectSequence must be a JSON list of steps")
+	}
+
+	writable := map[string]bool{}
+	for _, cmd := range catalogCommands {
+		if cmd.Action == CommandWrite {
+			writable[cmd.Resource] = true
+		}
+	}
+	delete(writable, AnyResource)
+	delete(writable, ResourceRawMessage)
+	delete(writable, ResourceMigrateAddress)
+
+	for i, step := range steps {
+		switch {
+		case !writable[step.Resource]:
+			return nil, errors.Errorf("ConnectSequence step %d: %q isn't a resource it can write",
+				i+1, step.Resource)
+		case step.OnError != "" && step.OnError != OnErrorAbort && step.OnError != OnErrorContinue:
+			return nil, errors.Errorf("ConnectSequence step %d: unknown OnError %q; it must be %s or %s",
+				i+1, step.OnError, OnErrorAbort, OnErrorContinue)
+		case (step.Resource == ResourceROSpecID || step.Resource == ResourceAccessSpecID) && step.Action == "":
+			return nil, errors.Errorf("ConnectSequence step %d: %s needs an Action", i+1, step.Resource)
+		case step.Action != "" && step.Resource != ResourceROSpecID && step.Resource != ResourceAccessSpecID:
+			return nil, errors.Errorf("ConnectSequence step %d: only %s and %s take an Action",
+				i+1, ResourceROSpecID, ResourceAccessSpecID)
+		}
+	}
+
+	return steps, nil
+}
+
+// checkConnectSequence returns an error if the ConnectSequence is invalid.
+func checkConnectSequence(data string) error {
+	_, err := parseConnectSequence(data)
+	return err
+}
+
+// command returns the requests and parameters for the step's write command.
+func (step connectStep) command(ns int64) ([]dsModels.CommandRequest, []*dsModels.CommandValue, error) {
+	var cv *dsModels.CommandValue
+	var err error
+	switch step.Resource {
+	case ResourceHoldEventsAndReports:
+		var hold bool
+		if hold, err = strconv.ParseBool(step.Value); err == nil {
+			cv, err = dsModels.NewBoolValue(step.Resource, ns, hold)
+		}
+	case ResourceROSpecID, ResourceAccessSpecID:
+		var id uint64
+		if id, err = strconv.ParseUint(step.Value, 10, 32); err == nil {
+			cv, err = dsModels.NewUint32Value(step.Resource, ns, uint32(id))
+		}
+	default:
+		cv = dsModels.NewStringValue(step.Resource, ns, step.Value)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "invalid %s value %q", step.Resource, step.Value)
+	}
+
+	reqs := []dsModels.CommandRequest{{DeviceResourceName: step.Resource}}
+	params := []*dsModels.CommandValue{cv}
+	if step.Action != "" {
+		reqs = append(reqs, dsModels.CommandRequest{DeviceResourceName: ResourceAction})
+		params = append(params, dsModels.NewStringValue(ResourceAction, ns, step.Action))
+	}
+	return reqs, params, nil
+}
+
+// runConnectSequence runs the steps of the ConnectSequence on the device, in order,
+// the same way as write commands from EdgeX,
+// so they're subject to the device's command policy and capabilities.
+// A failed step stops the sequence unless its OnError is OnErrorContinue,
+// as does the context ending.
+// It sends a ResourceConnectSequence event with each step's result.
+func (d *Driver) runConnectSequence(ctx context.Context, dev *LLRPDevice, steps []connectStep) {
+	result := connectSequenceResult{Completed: true}
+	for _, step := range steps {
+		if ctx.Err() != nil {
+			result.Completed = false
+			break
+		}
+
+		reqs, params, err := step.command(d.clock().Now().UnixNano())
+		if err == nil {
+			err = d.writeDevice(dev, nil, reqs, params)
+		}
+
+		sr := connectStepResult{Resource: step.Resource, Action: step.Action}
+		if err != nil {
+			sr.Error = err.Error()
+		}
+		result.Steps = append(result.Steps, sr)
+
+		if err == nil {
+			continue
+		}
+
+		dev.lc.Error("Connect sequence step failed.", "device", dev.name,
+			"resource", step.Resource, "error", err.Error())
+		if step.OnError != OnErrorContinue {
+			result.Completed = false
+			break
+		}
+	}
+
+	dev.sendEdgeXEvent(ResourceConnectSequence, d.clock().Now().UnixNano(), result)
+}
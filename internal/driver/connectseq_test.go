@@ -0,0 +1,129 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseConnectSequence(t *testing.T) {
+	for _, empty := range []string{"", "  ", "[]"} {
+		if steps, err := parseConnectSequence(empty); err != nil || len(steps) != 0 {
+			t.Errorf("%q: expected no steps; got %+v, %v", empty, steps, err)
+		}
+	}
+
+	steps, err := parseConnectSequence(`[
+		{"Resource": "HoldEventsAndReports", "Value": "true"},
+		{"Resource": "ROSpecID", "Value": "1", "Action": "Enable", "OnError": "continue"}
+	]`)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := []connectStep{
+		{Resource: ResourceHoldEventsAndReports, Value: "true"},
+		{Resource: ResourceROSpecID, Value: "1", Action: ActionEnable, OnError: OnErrorContinue},
+	}
+	if !reflect.DeepEqual(steps, expected) {
+		t.Errorf("expected %+v; got %+v", expected, steps)
+	}
+
+	for _, invalid := range []string{
+		`{"Resource": "ROSpec"}`,
+		`[{"Resource": "ReaderCapabilities"}]`,
+		`[{"Resource": "RawMessage", "Value": "{}"}]`,
+		`[{"Resource": "MigrateAddress", "Value": "{}"}]`,
+		`[{"Resource": "ROSpec", "Value": "{}", "OnError": "retry"}]`,
+		`[{"Resource": "ROSpecID", "Value": "1"}]`,
+		`[{"Resource": "ROSpec", "Value": "{}", "Action": "Enable"}]`,
+	} {
+		if _, err := parseConnectSequence(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestDriver_runConnectSequence(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := newSpecReader(t, rfid, llrp.ROSpec{ROSpecID: 1}, llrp.ROSpec{ROSpecID: 2})
+
+	go rfid.ImpersonateReader()
+	ch := make(chan *dsModels.AsyncValues, 1)
+	dev := &LLRPDevice{
+		client: rfid.ConnectClient(t),
+		ch:     ch,
+		clk:    newFakeClock(),
+		caps:   &llrp.GetReaderCapabilitiesResponse{},
+	}
+	d := newLocalDriver(t, dev)
+	d.clk = dev.clk
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// run runs the sequence and returns its ResourceConnectSequence event.
+	run := func(sequence string) connectSequenceResult {
+		t.Helper()
+		steps, err := parseConnectSequence(sequence)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		go d.runConnectSequence(ctx, dev, steps)
+
+		// The steps' responses are sent, too, so this skips those.
+		var cv *dsModels.CommandValue
+		for cv == nil || cv.DeviceResourceName != ResourceConnectSequence {
+			select {
+			case av := <-ch:
+				cv = av.CommandValues[0]
+			case <-time.After(time.Second):
+				t.Fatalf("expected a %s event", ResourceConnectSequence)
+			}
+		}
+		result := connectSequenceResult{}
+		if err := json.Unmarshal([]byte(cv.ValueToString()), &result); err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	// The Reader has no ROSpec 9, but the sequence continues past it.
+	result := run(`[
+		{"Resource": "ROSpecID", "Value": "9", "Action": "Enable", "OnError": "continue"},
+		{"Resource": "ROSpecID", "Value": "1", "Action": "Enable"}
+	]`)
+	if !result.Completed || len(result.Steps) != 2 ||
+		result.Steps[0].Error == "" || result.Steps[1].Error != "" {
+		t.Errorf("expected only the first step to fail; got %+v", result)
+	}
+
+	// This time, it aborts after the failure, leaving ROSpec 2 disabled.
+	result = run(`[
+		{"Resource": "ROSpecID", "Value": "9", "Action": "Enable"},
+		{"Resource": "ROSpecID", "Value": "2", "Action": "Enable"}
+	]`)
+	if result.Completed || len(result.Steps) != 1 || result.Steps[0].Error == "" {
+		t.Errorf("expected the sequence to abort after the first step; got %+v", result)
+	}
+
+	expectedStates := map[uint32]llrp.ROSpecCurrentStateType{
+		1: llrp.ROSpecStateInactive,
+		2: llrp.ROSpecStateDisabled,
+	}
+	if states := reader.states(); !reflect.DeepEqual(states, expectedStates) {
+		t.Errorf("expected ROSpec states %v; got %v", expectedStates, states)
+	}
+}
@@ -7,7 +7,8 @@ package driver
 
 import (
 	"context"
-	"encoding/json"
+	"encoding"
+	"encoding/hex"
 	"fmt"
 	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
 	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
@@ -15,7 +16,9 @@ import (
 	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
 	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
 	"github.com/pkg/errors"
+	"io"
 	"net"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -24,8 +27,9 @@ const (
 	dialTimeout       = time.Second * 30 // how long to wait after dialing for the Reader to answer
 	sendTimeout       = time.Second * 20 // how long to wait in each send attempt in TrySend
 	shutdownGrace     = time.Second      // time permitted to Shutdown; if exceeded, we call Close
+	forceStopGrace    = time.Second / 4  // time a forced Stop waits for connections to close
 	maxSendAttempts   = 3                // number of times to retry send in TrySend
-	keepAliveInterval = time.Second * 30 // how often the Reader should send us a KeepAlive
+	keepAliveInterval = time.Second * 30 // how often the Reader should send us a KeepAlive, by default
 	maxMissedKAs      = 2                // number of KAs that can be "missed" before resetting a connection
 	maxConnAttempts   = 2                // number of times to retry connecting before considering the device offline
 )
@@ -56,6 +60,11 @@ type LLRPDevice struct {
 	lc   logger.LoggingClient
 	ch   chan<- *dsModels.AsyncValues
 
+	params deviceParams // the Driver's settings the device was created with
+
+	asyncOverflow string     // what sendAsync does while ch is full; it waits if empty
+	queue         asyncQueue // values waiting for room in ch, if asyncOverflow is queue
+
 	deviceMu sync.RWMutex
 	address  net.Addr
 	// readerStart is used for the special case in which a Reader lacks a UTC clock.
@@ -72,10 +81,107 @@ type LLRPDevice struct {
 	// but neither does Go's stdlib Time package.
 	readerStart time.Time
 	enabled     bool // used for managing EdgeX opstate; isn't updated immediately
-
-	clientLock sync.RWMutex // we'll recreate client if it closes; note its lock is independent
-	client     *llrp.Client
-	cancel     context.CancelFunc // stops the reconnect process
+	// caps caches the Reader's capabilities for rejecting unsupported requests.
+	// It's cleared when the Reader reconnects, since it may not be the same Reader.
+	caps *llrp.GetReaderCapabilitiesResponse
+	// antennaLocations labels tag reads with a location based on their AntennaID.
+	antennaLocations map[llrp.AntennaID]string
+	// flatReports sends tag reads to EdgeX as individual, typed values instead of JSON.
+	flatReports bool
+	// orderedCommands runs commands one at a time, in the order received.
+	orderedCommands bool
+	// reprovisionSpecs restores the Reader's specs if it loses them, e.g. after rebooting.
+	reprovisionSpecs bool
+	// replaceROSpecs makes AddROSpec replace an existing ROSpec with the same ID.
+	replaceROSpecs bool
+	// slowThreshold is the round-trip latency over which a command is reported as slow;
+	// if it's 0, commands are never reported as slow.
+	slowThreshold time.Duration
+	// unknownParams is how reports and events with unknown parameters are handled;
+	// see ProtocolDecode. The zero value is treated as UnknownParamsStrict.
+	unknownParams string
+	// commandPolicy restricts the resources the device's commands may read or write.
+	commandPolicy commandPolicy
+	// tagPasswords are the C1G2 passwords used by AccessSpecs that don't specify them.
+	// They must never be logged.
+	tagPasswords tagPasswords
+	// clockSample holds the timestamp of the Reader's most recent event notification,
+	// for comparing its clock to the host's.
+	clockSample readerClockSample
+	// antennaEvents holds the most recent AntennaEvent for each antenna port
+	// since the service connected to the Reader.
+	antennaEvents map[llrp.AntennaID]AntennaEventRecord
+	// bufferLevel is the report buffer percentage full in the Reader's most recent
+	// report buffer event since the service connected; nil if it hasn't sent one.
+	bufferLevel *uint8
+	// holdsEventsAndReports is true if the Reader was last known to hold events and reports
+	// upon reconnect, in which case the service releases them after each reconnect.
+	holdsEventsAndReports bool
+	// connected is true from the Reader's successful ConnectionAttemptEvent
+	// until the connection closes.
+	connected bool
+	// readerClosed is true if the Reader sent a ConnectionCloseEvent
+	// since the connection opened.
+	readerClosed bool
+	// pausedROSpecs holds the IDs of the ROSpecs PauseReading disabled
+	// and whether ResumeReading should start them.
+	pausedROSpecs map[uint32]bool
+	// connectSequence runs the ConnectSequence each time the device connects; nil if it's empty.
+	connectSequence func(ctx context.Context)
+	// survey holds the results of the most recent RF survey.
+	survey []llrp.RFSurveyReportData
+
+	// If keepAlive is non-zero, the Reader is asked to send KeepAlives this often,
+	// and the connection is reset if it misses maxMissedKAs of them.
+	keepAlive time.Duration
+
+	// If idleTimeout is non-zero, the connection is closed after this long
+	// without commands or reports, and reopened the next time TrySend is called.
+	idleTimeout  time.Duration
+	lastActivity time.Time     // last time we sent a command or received a report
+	idle         bool          // true if the connection was closed due to inactivity
+	wake         chan struct{} // signals the reconnect loop when leaving the idle state
+
+	breaker *circuitBreaker // fails commands fast while the Reader is unreachable; disabled if nil
+	clk     clock           // tells the time; the real clock if nil
+
+	codec      reportCodec   // encodes ROAccessReports for EdgeX; JSON if nil
+	omitAbsent bool          // if set, JSON events and published reports omit null fields
+	sink       reportSink    // publishes ROAccessReports instead of sending them to EdgeX, if set
+	origin     string        // source of tag read readings' Origin; host time if empty
+	reads      *tagReadCache // most recent tag reads, for clients that poll for reports
+	counts     *tagCounter   // unique tags recently seen, for clients that poll for counts
+
+	reportMu sync.Mutex // serializes DisableReports and EnableReports
+	pauseMu  sync.Mutex // serializes PauseReading and ResumeReading
+	updateMu sync.Mutex // serializes UpdateAddr
+
+	commands commandQueue    // runs commands in order if orderedCommands is set
+	limiter  *commandLimiter // limits the commands in flight; unlimited if nil
+	sends    sendTracker     // tracks reports on their way to EdgeX, so Stop can drain them
+	specs    specTracker     // tracks the specs the service added, to restore them after reboots
+	latency  latencyTracker  // accumulates command round-trip latency by message type
+
+	collectors reportCollectors // collect ROAccessReports for TrySendCollect
+
+	verifyMu       sync.Mutex                 // guards the fields used to verify tag writes
+	verifiedWrites map[uint32]llrp.AccessSpec // AccessSpecs whose writes we verify, by ID
+	readbacks      map[uint32]*readback       // read-back AccessSpecs awaiting results, by ID
+	nextReadback   uint32                     // used to choose the next read-back AccessSpecID
+	heldReportSpec *llrp.ROReportSpec         // the ROReportSpec replaced by DisableReports; nil if not disabled
+
+	gpoMu     sync.Mutex       // serializes setting and clearing GPO ports for pulses
+	gpoPulses map[uint16]timer // timers ending the GPO pulses in progress, by port
+
+	// If reportPort is set, the device opens a second connection to the Reader on that port
+	// for ROAccessReports; reportWake signals the report connection manager when it changes.
+	reportPort string
+	reportWake chan struct{}
+
+	clientLock   sync.RWMutex // we'll recreate client if it closes; note its lock is independent
+	client       *llrp.Client
+	reportClient *llrp.Client       // the report connection's client, if it's connected
+	cancel       context.CancelFunc // stops the reconnect process
 }
 
 // NewLLRPDevice returns an LLRPDevice which attempts to connect to the given address.
@@ -86,13 +192,90 @@ func (d *Driver) NewLLRPDevice(name string, address net.Addr, opState contract.O
 	ctx, cancel := context.WithCancel(context.Background())
 	// don't defer cancel() here; we only cancel() when Stop() is called.
 
+	var idleTimeout time.Duration
+	keepAlive := keepAliveInterval
+	var cacheSize int
+	var countWindow time.Duration
+	var encoding, origin string
+	var omitAbsent bool
+	var breakerFailures int
+	var breakerCooldown time.Duration
+	var rejectMismatch bool
+	var maxCommands int
+	var failFast bool
+	var discardLimit uint32
+	var firstMsgID uint32
+	var asyncOverflow string
+	var asyncQueueSize int
+	var connectSequence string
+	d.configMu.RLock()
+	if d.config != nil {
+		idleTimeout = time.Duration(d.config.IdleTimeoutMinutes) * time.Minute
+		keepAlive = time.Duration(d.config.KeepAliveSeconds) * time.Second
+		cacheSize = d.config.ReportCacheSize
+		countWindow = time.Duration(d.config.TagCountWindowSeconds) * time.Second
+		encoding = d.config.ReportEncoding
+		omitAbsent = d.config.AbsentFields == AbsentFieldsOmit
+		origin = d.config.ReadingOrigin
+		breakerFailures = d.config.CircuitBreakerFailures
+		breakerCooldown = time.Duration(d.config.CircuitBreakerCooldownSeconds) * time.Second
+		rejectMismatch = d.config.VersionMismatch == VersionMismatchReject
+		maxCommands = d.config.MaxConcurrentCommands
+		failFast = d.config.CommandOverflow == CommandOverflowFail
+		discardLimit = uint32(d.config.MaxDiscardKiB) * 1024
+		firstMsgID = d.config.MessageIDStart
+		asyncOverflow = d.config.AsyncOverflow
+		asyncQueueSize = d.config.AsyncQueueSize
+		connectSequence = d.config.ConnectSequence
+	}
+	params := newDeviceParams(d.config)
+	d.configMu.RUnlock()
+
+	codec, err := newReportCodec(encoding, omitAbsent)
+	if err != nil {
+		d.lc.Warn("Using JSON reports.", "device", name, "error", err.Error())
+		codec = jsonCodec{omitNulls: omitAbsent}
+	}
+
 	l := &LLRPDevice{
-		name:    name,
-		cancel:  cancel,
-		address: address,
-		lc:      d.lc,
-		ch:      d.asyncCh,
-		enabled: opState == contract.Enabled,
+		name:          name,
+		cancel:        cancel,
+		address:       address,
+		lc:            d.lc,
+		ch:            d.asyncCh,
+		asyncOverflow: asyncOverflow,
+		queue:         asyncQueue{size: asyncQueueSize},
+		enabled:       opState == contract.Enabled,
+		keepAlive:     keepAlive,
+		idleTimeout:   idleTimeout,
+		lastActivity:  d.clock().Now(),
+		wake:          make(chan struct{}, 1),
+		reportWake:    make(chan struct{}, 1),
+		reads:         newTagReadCache(cacheSize),
+		counts:        newTagCounter(countWindow),
+		breaker:       newCircuitBreaker(breakerFailures, breakerCooldown),
+		limiter:       newCommandLimiter(maxCommands, failFast),
+		codec:         codec,
+		omitAbsent:    omitAbsent,
+		sink:          d.sink,
+		origin:        origin,
+		clk:           d.clk,
+		params:        params,
+	}
+
+	if asyncOverflow == AsyncOverflowQueue {
+		l.queue.budget = d.queueBudget
+		l.queue.share = d.queueBudget.join()
+	}
+
+	steps, err := parseConnectSequence(connectSequence)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid connect sequence.", "device", name, "error", err.Error())
+	}
+	if len(steps) != 0 {
+		l.connectSequence = func(ctx context.Context) {
+			d.runConnectSequence(ctx, l, steps)
+		}
 	}
 
 	// These options will be used each time we reconnect.
@@ -100,8 +283,22 @@ func (d *Driver) NewLLRPDevice(name string, address net.Addr, opState contract.O
 		llrp.WithLogger(&edgexLLRPClientLogger{devName: name, lc: d.lc}),
 		llrp.WithMessageHandler(llrp.MsgROAccessReport, l.newROHandler()),
 		llrp.WithMessageHandler(llrp.MsgReaderEventNotification, l.newReaderEventHandler(d.svc)),
-		llrp.WithTimeout(keepAliveInterval * maxMissedKAs),
+		llrp.WithTimeout(connTimeout(keepAlive)),
+		llrp.WithVersionCheck(rejectMismatch, l.newVersionMismatchFunc(rejectMismatch)),
+		llrp.WithDiscardLimit(discardLimit),
+		llrp.WithMessageIDStart(firstMsgID),
+	}
+
+	// The report connection only handles reports;
+	// KeepAlives and events are left to the main connection.
+	reportOpts := []llrp.ClientOpt{
+		llrp.WithLogger(&edgexLLRPClientLogger{devName: name, lc: d.lc}),
+		llrp.WithMessageHandler(llrp.MsgROAccessReport, l.newROHandler()),
+		llrp.WithVersionCheck(rejectMismatch, l.newVersionMismatchFunc(rejectMismatch)),
+		llrp.WithDiscardLimit(discardLimit),
+		llrp.WithMessageIDStart(firstMsgID),
 	}
+	go l.manageReportConn(ctx, reportOpts)
 
 	// Create the initial client, which we can immediately make Send requests to,
 	// though they can't be processed until it successfully connects.
@@ -113,15 +310,26 @@ func (d *Driver) NewLLRPDevice(name string, address net.Addr, opState contract.O
 	go func() {
 		defer func() {
 			cancel()
+			l.queue.budget.leave(l.queue.share)
 			rmvCtx, rmvCncl := context.WithTimeout(context.Background(), shutdownGrace)
 			defer rmvCncl()
-			d.removeDevice(rmvCtx, name)
+			d.removeDeviceIf(rmvCtx, name, l)
 		}()
 
 		d.lc.Debug("Starting Reader management.", "device", name)
 
+		if idleTimeout > 0 {
+			go l.closeWhenIdle(ctx)
+		}
+
 		// Until the context is canceled, attempt to dial and connect.
 		for ctx.Err() == nil {
+			// If the connection was closed due to inactivity,
+			// don't redial until someone wants to use it.
+			if err := l.waitWhileIdle(ctx); err != nil {
+				return
+			}
+
 			// If the Client closes in a "normal" way while the context is still alive,
 			// reset the retry/backoff policy and restart the dial/connect loop.
 			_ = retry.Slow.RetryWithCtx(ctx, retry.Forever, func(ctx context.Context) (bool, error) {
@@ -163,6 +371,7 @@ func (d *Driver) NewLLRPDevice(name string, address net.Addr, opState contract.O
 						d.lc.Error("Client disconnected unexpectedly.",
 							"error", clientErr.Error(), "device", name)
 					}
+					l.onDisconnect(clientErr)
 
 					// Replace the client, but don't start it until the next time we're connected.
 					// Doing so allows new Send requests to wait until the connection opens.
@@ -197,6 +406,9 @@ func (d *Driver) NewLLRPDevice(name string, address net.Addr, opState contract.O
 						l.deviceMu.Lock()
 						l.enabled = true
 						l.deviceMu.Unlock()
+					} else {
+						// Look for the Reader at the next discovery.
+						d.resetDiscoveryBackoff()
 					}
 				}
 
@@ -209,12 +421,23 @@ func (d *Driver) NewLLRPDevice(name string, address net.Addr, opState contract.O
 }
 
 // TrySend works like the llrp.Client's SendFor method,
-// but reattempts a send a few times if it fails due to a closed reader.
-// Additionally, it enforces our KeepAlive interval for timeout detection
-// upon SetReaderConfig messages.
+// but reattempts a send a few times if it fails due to a closed reader,
+// or as the context's AttribReconnect mode directs.
+// Additionally, unless KeepAlives are disabled, it enforces our KeepAlive interval
+// for timeout detection upon SetReaderConfig messages.
+//
+// If the device's circuit breaker is open, it fails fast with ErrCircuitOpen.
 func (l *LLRPDevice) TrySend(ctx context.Context, request llrp.Outgoing, reply llrp.Incoming) error {
-	if req, ok := request.(*llrp.SetReaderConfig); ok {
-		ka := llrp.Millisecs32(keepAliveInterval.Milliseconds())
+	return l.withBreaker(func() error {
+		return l.trySend(ctx, request, reply)
+	})
+}
+
+func (l *LLRPDevice) trySend(ctx context.Context, request llrp.Outgoing, reply llrp.Incoming) error {
+	l.markActive()
+
+	if req, ok := request.(*llrp.SetReaderConfig); ok && l.keepAlive > 0 {
+		ka := llrp.Millisecs32(l.keepAlive.Milliseconds())
 		if req.KeepAliveSpec != nil {
 			reqKA := req.KeepAliveSpec
 			if reqKA.Interval != ka || reqKA.Trigger != llrp.KATriggerPeriodic {
@@ -227,27 +450,286 @@ func (l *LLRPDevice) TrySend(ctx context.Context, request llrp.Outgoing, reply l
 			}
 		} else {
 			l.lc.Info("Adding device-service-enforced a KeepAlive spec to ReaderConfig.",
-				"forcedKA", keepAliveInterval)
-			req.KeepAliveSpec = &llrp.KeepAliveSpec{
-				Trigger:  llrp.KATriggerPeriodic,
-				Interval: ka,
-			}
+				"forcedKA", l.keepAlive)
+			req.KeepAliveSpec = keepAliveSpec(l.keepAlive)
 		}
 	}
 
-	return retry.Quick.RetryWithCtx(ctx, maxSendAttempts, func(ctx context.Context) (bool, error) {
+	var elapsed time.Duration // the round trip of the last attempt
+	err := retrySend(ctx, func(ctx context.Context) (bool, error) {
 		l.lc.Debug("Attempting send.", "device", l.name, "message", request.Type().String())
 
 		l.clientLock.RLock()
 		c := l.client
 		l.clientLock.RUnlock()
 		if c == nil {
-			return true, errors.New("no client available")
+			return true, errNoClient
 		}
 
+		start := l.clock().Now()
 		err := c.SendFor(ctx, request, reply)
+		elapsed = l.clock().Now().Sub(start)
 		return err != nil && errors.Is(err, llrp.ErrClientClosed), err
 	})
+	if err != nil {
+		return err
+	}
+
+	l.recordLatency(request.Type(), elapsed)
+
+	l.specs.record(request)
+	l.warnStatus(request, reply)
+	return nil
+}
+
+// TrySendRaw works like TrySend, but uses the llrp.Client's SendMessage method
+// to send an arbitrary payload with the given message type.
+// It returns the response type and its payload as-is,
+// so it's up to the caller to interpret them.
+func (l *LLRPDevice) TrySendRaw(ctx context.Context, typ llrp.MessageType, data []byte) (respType llrp.MessageType, respData []byte, err error) {
+	err = l.withBreaker(func() error {
+		l.markActive()
+
+		var elapsed time.Duration // the round trip of the last attempt
+		err := retrySend(ctx, func(ctx context.Context) (bool, error) {
+			l.lc.Debug("Attempting raw send.", "device", l.name, "message", typ.String())
+
+			l.clientLock.RLock()
+			c := l.client
+			l.clientLock.RUnlock()
+			if c == nil {
+				return true, errNoClient
+			}
+
+			var err error
+			start := l.clock().Now()
+			respType, respData, err = c.SendMessage(ctx, typ, data)
+			elapsed = l.clock().Now().Sub(start)
+			return err != nil && errors.Is(err, llrp.ErrClientClosed), err
+		})
+		if err == nil {
+			l.recordLatency(typ, elapsed)
+		}
+		return err
+	})
+	return respType, respData, err
+}
+
+// TrySendNoWait works like TrySend, but doesn't wait for a reply,
+// for messages that have no response (e.g., KeepAliveAck),
+// whose response is asynchronous (e.g., GetReport's ROAccessReport),
+// or whose response the caller doesn't need.
+//
+// It returns once the llrp.Client queues the message to send,
+// so if the connection closes before it's written, the message is lost.
+// As with other messages, the Client assigns the message ID
+// and writes the message whole, so it's never interleaved with others.
+// If the Reader replies anyway, the reply isn't correlated with the request,
+// so it goes to the Client's handler for its type, if it has one.
+func (l *LLRPDevice) TrySendNoWait(ctx context.Context, request llrp.Outgoing) error {
+	data, err := request.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	m, err := llrp.NewByteMessage(request.Type(), data)
+	if err != nil {
+		return err
+	}
+
+	return l.sendNoWait(ctx, m)
+}
+
+// sendNoWait sends a message to the Reader without waiting for a reply,
+// reattempting it like TrySend if it fails due to a closed Reader.
+func (l *LLRPDevice) sendNoWait(ctx context.Context, m llrp.Message) error {
+	return l.withBreaker(func() error {
+		l.markActive()
+
+		return retrySend(ctx, func(ctx context.Context) (bool, error) {
+			l.lc.Debug("Attempting send without waiting.", "device", l.name, "message", m.Type().String())
+
+			l.clientLock.RLock()
+			c := l.client
+			l.clientLock.RUnlock()
+			if c == nil {
+				return true, errNoClient
+			}
+
+			err := c.SendNoWait(ctx, m)
+			return err != nil && errors.Is(err, llrp.ErrClientClosed), err
+		})
+	})
+}
+
+// ReaderID returns the Reader's Identification,
+// which holds either its MAC address in EUI-64 format or an EPC.
+//
+// LLRP doesn't define a message for changing a Reader's Identification,
+// so setting it, if the Reader permits, is done with the Reader's own tools.
+func (l *LLRPDevice) ReaderID(ctx context.Context) (llrp.Identification, error) {
+	conf := llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		RequestedData: llrp.ReaderConfReqIdentification,
+	}, &conf); err != nil {
+		return llrp.Identification{}, err
+	}
+
+	if conf.Identification == nil {
+		return llrp.Identification{}, errors.New("Reader did not report its Identification")
+	}
+	return *conf.Identification, nil
+}
+
+// DisableReports stops the Reader from sending ROAccessReports
+// without changing its ROSpecs or AccessSpecs.
+//
+// LLRP doesn't have a message for this, so instead it sets the Reader's
+// ROReportSpec trigger to None, which makes the Reader hold reports until requested.
+// It saves the Reader's current ROReportSpec so EnableReports can restore it.
+// ROSpecs with their own ROReportSpec aren't affected.
+// If reports are already disabled, this does nothing.
+func (l *LLRPDevice) DisableReports(ctx context.Context) error {
+	l.reportMu.Lock()
+	defer l.reportMu.Unlock()
+
+	if l.heldReportSpec != nil {
+		return nil
+	}
+
+	conf := &llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		RequestedData: llrp.ReaderConfReqROReportSpec,
+	}, conf); err != nil {
+		return errors.WithMessage(err, "failed to get current ROReportSpec")
+	}
+
+	if conf.ROReportSpec == nil {
+		return errors.New("Reader didn't return its ROReportSpec")
+	}
+
+	hold := *conf.ROReportSpec
+	hold.Trigger = llrp.None
+	if err := l.TrySend(ctx, &llrp.SetReaderConfig{ROReportSpec: &hold},
+		&llrp.SetReaderConfigResponse{}); err != nil {
+		return errors.WithMessage(err, "failed to set ROReportSpec trigger to None")
+	}
+
+	l.heldReportSpec = conf.ROReportSpec
+	return nil
+}
+
+// EnableReports undoes DisableReports by restoring the Reader's ROReportSpec,
+// then requests any reports the Reader held while they were disabled.
+//
+// Regardless of whether reports were disabled, it sends EnableEventsAndReports,
+// which releases events and reports a Reader is holding
+// because its EventsAndReports config says to hold them upon reconnect.
+func (l *LLRPDevice) EnableReports(ctx context.Context) error {
+	l.reportMu.Lock()
+	defer l.reportMu.Unlock()
+
+	if l.heldReportSpec != nil {
+		if err := l.TrySend(ctx, &llrp.SetReaderConfig{ROReportSpec: l.heldReportSpec},
+			&llrp.SetReaderConfigResponse{}); err != nil {
+			return errors.WithMessage(err, "failed to restore ROReportSpec")
+		}
+		l.heldReportSpec = nil
+
+		// The Reader replies with an ROAccessReport, which goes to our usual handler.
+		if err := l.TrySendNoWait(ctx, &llrp.GetReport{}); err != nil {
+			return errors.WithMessage(err, "failed to request held reports")
+		}
+	}
+
+	return errors.WithMessage(
+		l.TrySendNoWait(ctx, &llrp.EnableEventsAndReports{}),
+		"failed to send EnableEventsAndReports")
+}
+
+// ErrROSpecNotDeleted is returned by DeleteROSpecVerified
+// if the Reader still lists an ROSpec after reporting it deleted.
+var ErrROSpecNotDeleted = errors.New("Reader still lists the deleted ROSpec")
+
+// DeleteROSpecVerified deletes the ROSpec with the given ID,
+// then gets the Reader's ROSpecs to confirm it's gone,
+// since some firmware reports success without deleting the ROSpec.
+// As with DeleteROSpec, an ID of 0 deletes all ROSpecs,
+// in which case it confirms the Reader lists none.
+//
+// Confirming the delete costs an extra round trip,
+// so it's meant for automated provisioning, where leftover ROSpecs cause subtle problems.
+func (l *LLRPDevice) DeleteROSpecVerified(ctx context.Context, id uint32) error {
+	if err := l.TrySend(ctx, &llrp.DeleteROSpec{ROSpecID: id},
+		&llrp.DeleteROSpecResponse{}); err != nil {
+		return err
+	}
+
+	specs := llrp.GetROSpecsResponse{}
+	if err := l.TrySend(ctx, &llrp.GetROSpecs{}, &specs); err != nil {
+		return errors.WithMessage(err, "failed to get ROSpecs to confirm the delete")
+	}
+
+	for _, spec := range specs.ROSpecs {
+		if id == 0 || spec.ROSpecID == id {
+			return errors.Wrapf(ErrROSpecNotDeleted, "ROSpec %d", spec.ROSpecID)
+		}
+	}
+	return nil
+}
+
+// ErrROSpecExists is returned by AddROSpec if the Reader rejects an ROSpec
+// because it already has one with the same ID and the device doesn't replace ROSpecs.
+var ErrROSpecExists = errors.New("ROSpec ID already exists")
+
+// AddROSpec adds an ROSpec to the Reader.
+//
+// If the Reader rejects it because it already has an ROSpec with the same ID,
+// then if the device replaces ROSpecs, this deletes the existing one and adds it again;
+// otherwise, it returns ErrROSpecExists.
+// Replacing lets provisioning scripts run more than once,
+// but it's opt-in, since it discards the existing ROSpec and its state.
+func (l *LLRPDevice) AddROSpec(ctx context.Context, add *llrp.AddROSpec, resp *llrp.AddROSpecResponse) error {
+	err := l.TrySend(ctx, add, resp)
+	if err == nil || !isDuplicateROSpecID(add.ROSpec.ROSpecID, err) {
+		return err
+	}
+
+	l.deviceMu.RLock()
+	replace := l.replaceROSpecs
+	l.deviceMu.RUnlock()
+
+	id := add.ROSpec.ROSpecID
+	if !replace {
+		return errors.Wrapf(ErrROSpecExists, "ROSpec %d (set the %s protocol's replaceROSpecs to replace it)",
+			id, ProtocolCommands)
+	}
+
+	l.lc.Info("Replacing existing ROSpec.", "device", l.name, "roSpecID", id)
+	if err := l.TrySend(ctx, &llrp.DeleteROSpec{ROSpecID: id},
+		&llrp.DeleteROSpecResponse{}); err != nil {
+		return errors.WithMessagef(err, "failed to delete existing ROSpec %d to replace it", id)
+	}
+
+	return l.TrySend(ctx, add, resp)
+}
+
+// isDuplicateROSpecID returns true if err is a Reader's rejection
+// of an AddROSpec for the ROSpec with the given ID because the ID is in use.
+//
+// Readers report this as a field error on the ROSpec parameter's ROSpecID field.
+// Since that's also how they reject an ROSpecID of 0, which is never valid,
+// it's not considered a duplicate.
+func isDuplicateROSpecID(id uint32, err error) bool {
+	var se *llrp.StatusError
+	if id == 0 || !errors.As(err, &se) {
+		return false
+	}
+
+	pe := se.ParameterError
+	return se.Status == llrp.StatusMsgParamError &&
+		pe != nil && pe.ParameterType == llrp.ParamROSpec &&
+		pe.FieldError != nil && pe.FieldError.FieldIndex == 0
 }
 
 // Stop closes any open client connection and stops trying to reconnect.
@@ -273,7 +755,13 @@ func (l *LLRPDevice) Stop(ctx context.Context) error {
 // If the device were Stopped, this won't start it, and this has no practical effect.
 // It may return an error if closing the current connection fails for some reason.
 // Nevertheless, it updates the address and will attempt to use it the next time it connects.
+//
+// Concurrent calls are serialized, so each one finishes closing the connection
+// before the next changes the address, and the last call's address is the one used.
 func (l *LLRPDevice) UpdateAddr(ctx context.Context, addr net.Addr) error {
+	l.updateMu.Lock()
+	defer l.updateMu.Unlock()
+
 	l.deviceMu.Lock()
 	old := l.address
 	l.address = addr
@@ -301,6 +789,8 @@ func sameAddr(a1, a2 net.Addr) bool {
 // but doesn't cancel it's context, so it'll restart on the next round.
 // You must be holding the lock when you call this.
 func (l *LLRPDevice) closeLocked(ctx context.Context) error {
+	l.closeReportConnLocked()
+
 	if l.client == nil {
 		return nil
 	}
@@ -339,85 +829,397 @@ func (l *LLRPDevice) resetConn() {
 	}
 }
 
+// markActive records the current time as the last time the device was in use.
+// If the connection was closed due to inactivity, it wakes the reconnect loop.
+func (l *LLRPDevice) markActive() {
+	l.deviceMu.Lock()
+	l.lastActivity = l.clock().Now()
+	wasIdle := l.idle
+	l.idle = false
+	l.deviceMu.Unlock()
+
+	if wasIdle {
+		l.lc.Debug("Reconnecting idle Reader.", "device", l.name)
+		select {
+		case l.wake <- struct{}{}:
+		default: // the loop has already been signaled
+		}
+	}
+}
+
+// waitWhileIdle blocks while the device is idle,
+// returning nil once it's no longer idle or the context's error if it's canceled.
+func (l *LLRPDevice) waitWhileIdle(ctx context.Context) error {
+	for {
+		l.deviceMu.RLock()
+		idle := l.idle
+		l.deviceMu.RUnlock()
+
+		if !idle {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-l.wake:
+		}
+	}
+}
+
+// closeWhenIdle periodically checks the device's last activity,
+// and if it exceeds the idleTimeout, closes the current connection
+// and marks the device idle so that it isn't redialed until it's needed.
+// It runs until the context is canceled.
+func (l *LLRPDevice) closeWhenIdle(ctx context.Context) {
+	ticker := l.clock().NewTicker(l.idleTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.Chan():
+		}
+
+		l.deviceMu.Lock()
+		shouldClose := !l.idle && l.clock().Now().Sub(l.lastActivity) >= l.idleTimeout
+		if shouldClose {
+			l.idle = true
+		}
+		l.deviceMu.Unlock()
+
+		if shouldClose {
+			l.lc.Info("Closing idle Reader connection.", "device", l.name,
+				"idleTimeout", l.idleTimeout.String())
+			l.resetConn()
+		}
+	}
+}
+
 // newReaderEventHandler returns an llrp.MessageHandler for ReaderEventNotifications.
 //
 // If the event is a new successful connection event,
 // it ensures the Reader has our desired configuration state.
+// If it's a ConnectionCloseEvent, it closes the connection without waiting for the Reader.
 func (l *LLRPDevice) newReaderEventHandler(svc ServiceWrapper) llrp.MessageHandler {
 	return llrp.MessageHandlerFunc(func(c *llrp.Client, msg llrp.Message) {
-		now := time.Now()
+		now := l.clock().Now()
+
+		l.markActive()
 
 		event := &llrp.ReaderEventNotification{}
-		if err := msg.UnmarshalTo(event); err != nil {
+		unknown, err := l.unmarshal(msg, event)
+		if err != nil {
 			l.lc.Error("Failed to unmarshal LLRP reader event notification", "error", err.Error())
 			return
 		}
 
-		l.deviceMu.RLock()
-		readerStart := l.readerStart
-		l.deviceMu.RUnlock()
-
 		renData := event.ReaderEventNotificationData
-		if renData.UTCTimestamp == 0 && readerStart.IsZero() {
-			readerStart = now.Add(-1 * time.Microsecond * time.Duration(renData.Uptime))
+		sample := readerClockSample{host: now, uptimeOnly: renData.UTCTimestamp == 0}
+		if !sample.uptimeOnly {
+			sample.reader = time.Unix(0, int64(renData.UTCTimestamp)*int64(time.Microsecond))
 		}
 
-		if !readerStart.IsZero() {
+		connected := renData.ConnectionAttemptEvent != nil &&
+			llrp.ConnectionAttemptEventType(*renData.ConnectionAttemptEvent) == llrp.ConnSuccess
+
+		l.deviceMu.Lock()
+		if connected {
+			// The Reader may have restarted or been replaced,
+			// so its Uptime no longer relates to the previous reference.
+			l.readerStart = time.Time{}
+			l.connected = true
+		}
+		if sample.uptimeOnly && l.readerStart.IsZero() {
+			l.readerStart = now.Add(-1 * time.Microsecond * time.Duration(renData.Uptime))
+		}
+		readerStart := l.readerStart
+		l.clockSample = sample
+		l.deviceMu.Unlock()
+
+		if sample.uptimeOnly && !readerStart.IsZero() {
 			renData.UTCTimestamp = uptimeToUTC(readerStart, renData.Uptime)
 		}
 
-		if renData.ConnectionAttemptEvent != nil &&
-			llrp.ConnectionAttemptEventType(*renData.ConnectionAttemptEvent) == llrp.ConnSuccess {
-			go func() {
+		l.recordAntennaEvent(now, renData.AntennaEvent)
+		l.recordBufferLevel(&renData)
+
+		if connected {
+			l.goSend(func() {
 				// Don't send the event until after processing a possible OpState change.
 				l.onConnect(svc)
-				l.sendEdgeXEvent(ResourceReaderNotification, now.UnixNano(), event)
-			}()
+				l.sendEdgeXEvent(ResourceReaderNotification, now.UnixNano(), withUnknownParams(event, unknown))
+			})
 		} else {
-			go l.sendEdgeXEvent(ResourceReaderNotification, now.UnixNano(), event)
+			l.goSend(func() {
+				l.sendEdgeXEvent(ResourceReaderNotification, now.UnixNano(), withUnknownParams(event, unknown))
+				l.sendSpecEvents(now.UnixNano(), &renData)
+				l.sendGPIEvent(now.UnixNano(), &renData)
+				l.sendAntennaEvent(now.UnixNano(), &renData)
+			})
+		}
+
+		if renData.ConnectionCloseEvent != nil {
+			l.onReaderClose(c)
 		}
 	})
 }
 
+// unknownEventNotification is a ReaderEventNotification
+// with the unknown parameters a device preserved while decoding it.
+type unknownEventNotification struct {
+	llrp.ReaderEventNotification
+	UnknownParams []llrp.UnknownParam
+}
+
+// withUnknownParams returns the event with its preserved unknown parameters,
+// or the event unchanged if there aren't any.
+func withUnknownParams(event *llrp.ReaderEventNotification, unknown []llrp.UnknownParam) interface{} {
+	if len(unknown) == 0 {
+		return event
+	}
+	return &unknownEventNotification{ReaderEventNotification: *event, UnknownParams: unknown}
+}
+
+// unmarshal decodes msg into v according to the device's unknownParams policy.
+// If the policy is UnknownParamsStrict, unknown parameters are an error;
+// otherwise, they're logged and skipped.
+// It returns them only if the policy is UnknownParamsPreserve.
+func (l *LLRPDevice) unmarshal(msg llrp.Message, v encoding.BinaryUnmarshaler) ([]llrp.UnknownParam, error) {
+	l.deviceMu.RLock()
+	policy := l.unknownParams
+	l.deviceMu.RUnlock()
+
+	if policy == "" || policy == UnknownParamsStrict {
+		return nil, msg.UnmarshalTo(v)
+	}
+
+	unknown, err := msg.UnmarshalSkippingUnknown(v)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range unknown {
+		l.lc.Debug("Skipping unknown parameter.", "device", l.name,
+			"type", strconv.Itoa(int(u.Type)), "parent", u.Parent, "length", strconv.Itoa(len(u.Data)))
+	}
+
+	if policy != UnknownParamsPreserve {
+		return nil, nil
+	}
+	return unknown, nil
+}
+
 // sendEdgeXEvent marshals an interface to JSON and sends it as an EdgeX event.
 func (l *LLRPDevice) sendEdgeXEvent(eventName string, ns int64, event interface{}) {
-	data, err := json.Marshal(event)
+	data, err := marshalJSON(event, l.omitAbsent)
 	if err != nil {
 		l.lc.Error("Failed to marshal event to JSON", "error", err.Error(),
 			"event", fmt.Sprintf("%+v", event))
 		return
 	}
 
-	l.ch <- &dsModels.AsyncValues{
+	l.sendAsync(&dsModels.AsyncValues{
 		DeviceName:    l.name,
 		CommandValues: []*dsModels.CommandValue{dsModels.NewStringValue(eventName, ns, string(data))},
-	}
+	})
 }
 
 // newROHandler returns an llrp.MessageHandler to handle ROAccessReports.
 func (l *LLRPDevice) newROHandler() llrp.MessageHandler {
 	return llrp.MessageHandlerFunc(func(c *llrp.Client, msg llrp.Message) {
-		now := time.Now()
+		now := l.clock().Now()
+		l.markActive()
+
 		report := &llrp.ROAccessReport{}
 
-		if err := msg.UnmarshalTo(report); err != nil {
+		unknown, err := l.unmarshal(msg, report)
+		if err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				// The Client resets the connection, so we'll just drop the partial report.
+				l.lc.Warn("Reader connection closed mid-report; discarding partial ROAccessReport.",
+					"device", l.name, "error", err.Error())
+				return
+			}
 			l.lc.Error("Failed to unmarshal async event from LLRP.", "error", err.Error())
 			return
 		}
 
 		l.deviceMu.RLock()
 		readerStart := l.readerStart
+		locations := l.antennaLocations
+		flat := l.flatReports
 		l.deviceMu.RUnlock()
 
-		go func() {
-			if !readerStart.IsZero() {
-				processReport(readerStart, report)
-			}
-			l.sendEdgeXEvent(ResourceROAccessReport, now.UnixNano(), report)
-		}()
+		// Cache the reads before forwarding the report
+		// so that they're stored in the order the Reader sent them.
+		if !readerStart.IsZero() {
+			processReport(readerStart, report)
+		}
+		l.reads.add(report.TagReportData...)
+		l.counts.add(now, report.TagReportData...)
+		l.verifyWrites(report.TagReportData)
+		l.collectors.deliver(report)
+
+		if len(report.RFSurveyReportData) != 0 {
+			l.deviceMu.Lock()
+			l.survey = report.RFSurveyReportData
+			l.deviceMu.Unlock()
+		}
+
+		var readOps map[uint32]llrp.C1G2Read
+		if hasReadResults(report.TagReportData) {
+			readOps = l.specs.readOps()
+		}
+
+		// The sink doesn't block, so publish in the handler to keep reports in order.
+		if l.sink != nil {
+			l.publishReport(withLocations(locations, readOps, report, unknown...))
+			return
+		}
+
+		// RFSurveyReportData doesn't fit the flat format, so those reports are always JSON.
+		// Flat reads don't include preserved unknown parameters.
+		if flat && len(report.RFSurveyReportData) == 0 {
+			l.goSend(func() { l.sendFlatReads(now, locations, report.TagReportData) })
+			return
+		}
+
+		origin := reportOrigin(l.origin, now, report.TagReportData)
+		located := withLocations(locations, readOps, report, unknown...)
+		l.goSend(func() { l.sendReport(origin, located) })
 	})
 }
 
+// sendReport encodes a report with the device's codec and sends it to EdgeX.
+func (l *LLRPDevice) sendReport(ns int64, report interface{}) {
+	codec := l.codec
+	if codec == nil {
+		codec = jsonCodec{}
+	}
+
+	cv, err := codec.encode(ns, report)
+	if err != nil {
+		l.lc.Error("Failed to encode ROAccessReport.", "device", l.name, "error", err.Error())
+		return
+	}
+
+	l.sendAsync(&dsModels.AsyncValues{
+		DeviceName:    l.name,
+		CommandValues: []*dsModels.CommandValue{cv},
+	})
+}
+
+// publishReport marshals a report to JSON and publishes it to the device's sink.
+func (l *LLRPDevice) publishReport(report interface{}) {
+	data, err := marshalJSON(report, l.omitAbsent)
+	if err != nil {
+		l.lc.Error("Failed to marshal report to JSON.", "device", l.name, "error", err.Error())
+		return
+	}
+	l.sink.publish(l.name, data)
+}
+
+// sendFlatReads sends each tag read to EdgeX as an event with individual, typed values,
+// which are easier for rules engines to match than a nested JSON report.
+func (l *LLRPDevice) sendFlatReads(now time.Time, locations map[llrp.AntennaID]string, reads []llrp.TagReportData) {
+	for i := range reads {
+		cvs, err := flatReadValues(now, l.origin, locations, &reads[i])
+		if err != nil {
+			l.lc.Error("Failed to create tag read values.", "device", l.name, "error", err.Error())
+			continue
+		}
+
+		l.sendAsync(&dsModels.AsyncValues{DeviceName: l.name, CommandValues: cvs})
+	}
+}
+
+// flatReadValues returns CommandValues for the fields of a tag read:
+// its EPC as a hex string and its timestamp in Unix nanoseconds,
+// plus its antenna, the antenna's location, peak RSSI, first and last seen times, FastID TID,
+// and Impinj peak RSSI, phase angle, and Doppler frequency, if present.
+//
+// The timestamp is the read's LastSeenUTC, or FirstSeenUTC if that's missing,
+// or the given time if neither is present.
+// The values' Origin depends on the source; see readOrigin.
+func flatReadValues(now time.Time, source string, locations map[llrp.AntennaID]string,
+	tr *llrp.TagReportData) ([]*dsModels.CommandValue, error) {
+	epc := tr.EPC()
+
+	ts := readOrigin(OriginLastSeen, now, tr)
+	ns := readOrigin(source, now, tr)
+
+	tsValue, err := dsModels.NewInt64Value(ResourceTagTimestamp, ns, ts)
+	if err != nil {
+		return nil, err
+	}
+
+	cvs := []*dsModels.CommandValue{
+		dsModels.NewStringValue(ResourceTagEPC, ns, hex.EncodeToString(epc)),
+		tsValue,
+	}
+
+	if tr.AntennaID != nil {
+		id := *tr.AntennaID
+		cv, err := dsModels.NewUint16Value(ResourceTagAntenna, ns, uint16(id))
+		if err != nil {
+			return nil, err
+		}
+		cvs = append(cvs, cv)
+
+		if loc, ok := locations[id]; ok {
+			cvs = append(cvs, dsModels.NewStringValue(ResourceTagLocation, ns, loc))
+		}
+	}
+
+	if tr.PeakRSSI != nil {
+		cv, err := dsModels.NewInt8Value(ResourceTagRSSI, ns, int8(*tr.PeakRSSI))
+		if err != nil {
+			return nil, err
+		}
+		cvs = append(cvs, cv)
+	}
+
+	if first, last, ok := seenPair(tr); ok {
+		firstValue, err := dsModels.NewInt64Value(ResourceTagFirstSeen, ns, first)
+		if err != nil {
+			return nil, err
+		}
+		lastValue, err := dsModels.NewInt64Value(ResourceTagLastSeen, ns, last)
+		if err != nil {
+			return nil, err
+		}
+		cvs = append(cvs, firstValue, lastValue)
+	}
+
+	if tid := tagTIDString(tr); tid != "" {
+		cvs = append(cvs, dsModels.NewStringValue(ResourceTagTID, ns, tid))
+	}
+
+	if tr.C1G2PC != nil {
+		userMem, err := dsModels.NewBoolValue(ResourceTagUserMemory, ns, tr.C1G2PC.HasUserMemory)
+		if err != nil {
+			return nil, err
+		}
+		cvs = append(cvs,
+			dsModels.NewStringValue(ResourceTagPC, ns, fmt.Sprintf("%04x", tr.C1G2PC.Word())),
+			userMem)
+	}
+
+	if tr.C1G2CRC != nil {
+		cvs = append(cvs, dsModels.NewStringValue(ResourceTagCRC, ns, fmt.Sprintf("%04x", uint16(*tr.C1G2CRC))))
+	}
+
+	impinjCVs, err := impinjTagValues(ns, tr)
+	if err != nil {
+		return nil, err
+	}
+	cvs = append(cvs, impinjCVs...)
+
+	return cvs, nil
+}
+
 func uptimeToUTC(readerStart time.Time, uptime llrp.Uptime) llrp.UTCTimestamp {
 	// UTC of event = readerStartUTC + duration between reader start and event.
 	// We have to divide by 1000 to get from nanosecs back to microsecs.
@@ -426,8 +1228,89 @@ func uptimeToUTC(readerStart time.Time, uptime llrp.Uptime) llrp.UTCTimestamp {
 		UnixNano() / 1000)
 }
 
+// locatedTagReportData is TagReportData labeled with the location of its antenna,
+// the tag's TID, if the Reader used Impinj's FastID or an AccessSpec read it,
+// the memory an AccessSpec's C1G2Read read, if any,
+// the named results of the AccessSpec's OpSpecs, if any,
+// and the Impinj-specific fields of the read, if the Reader reported them.
+type locatedTagReportData struct {
+	llrp.TagReportData
+	Location      string         `json:",omitempty"`
+	TID           string         `json:",omitempty"`
+	MemoryRead    *TagMemoryRead `json:",omitempty"`
+	OpSpecResults []OpSpecResult `json:",omitempty"`
+	Impinj        *ImpinjTagData `json:",omitempty"`
+}
+
+// locatedROAccessReport is an ROAccessReport with labeled TagReportData.
+// When marshaled to JSON, its TagReportData replaces the embedded report's,
+// so the result matches the ROAccessReport's,
+// plus each tag's Location, TID, MemoryRead, OpSpecResults, and Impinj data,
+// and any unknown parameters the device preserved while decoding it.
+type locatedROAccessReport struct {
+	llrp.ROAccessReport
+	TagReportData []locatedTagReportData
+	UnknownParams []llrp.UnknownParam `json:",omitempty"`
+}
+
+// withLocations returns the report with each TagReportData labeled
+// by the location of the antenna that read it,
+// or by the antenna's ID if the antenna doesn't have a location.
+// TagReportData without an AntennaID aren't labeled.
+// TagReportData with a FastID TID are labeled with it as a hex string.
+// TagReportData with the result of a C1G2Read in readOps, keyed by AccessSpecID,
+// are labeled with its MemoryRead, and if it read the TID bank from its start,
+// with the TID, unless FastID already provided it.
+// TagReportData with OpSpec results are labeled with their operations' and results' names.
+// TagReportData with Impinj peak RSSI, phase angle, or Doppler frequency fields
+// are labeled with them in conventional units.
+// The report includes the unknown parameters, if any.
+// If there are no locations, TIDs, memory reads, OpSpec results, Impinj fields, or unknown parameters,
+// it returns the report unchanged.
+func withLocations(locations map[llrp.AntennaID]string, readOps map[uint32]llrp.C1G2Read,
+	report *llrp.ROAccessReport, unknown ...llrp.UnknownParam) interface{} {
+	if len(unknown) == 0 && (len(report.TagReportData) == 0 ||
+		(len(locations) == 0 && !hasTIDs(report.TagReportData) && len(readOps) == 0 &&
+			!hasOpSpecResults(report.TagReportData) && !hasImpinjTagData(report.TagReportData))) {
+		return report
+	}
+
+	located := &locatedROAccessReport{
+		ROAccessReport: *report,
+		TagReportData:  make([]locatedTagReportData, len(report.TagReportData)),
+		UnknownParams:  unknown,
+	}
+
+	for i := range report.TagReportData {
+		data := &located.TagReportData[i]
+		data.TagReportData = report.TagReportData[i]
+		data.TID = tagTIDString(&data.TagReportData)
+		data.MemoryRead = tagMemoryRead(readOps, &data.TagReportData)
+		data.OpSpecResults = tagOpSpecResults(&data.TagReportData)
+		data.Impinj = impinjTagData(&data.TagReportData)
+		if mr := data.MemoryRead; data.TID == "" && mr != nil &&
+			mr.MemoryBank == memoryBankName(memoryBankTID) && mr.WordAddress == 0 {
+			data.TID = mr.Data
+		}
+
+		if data.AntennaID == nil || len(locations) == 0 {
+			continue
+		}
+
+		if loc, ok := locations[*data.AntennaID]; ok {
+			data.Location = loc
+		} else {
+			data.Location = strconv.Itoa(int(*data.AntennaID))
+		}
+	}
+
+	return located
+}
+
 // processReport processes an llrp.ROAccessReport
-// by setting UTC parameters from their Uptime values.
+// by setting UTC parameters from their Uptime values,
+// so tag reads from Readers without a UTC clock have the same timestamps as others.
+// Timestamps the Reader reported in UTC are left as they are.
 func processReport(readerStart time.Time, report *llrp.ROAccessReport) {
 	// If the Reader has a UTC clock, we don't need to inspect the survey data.
 	if readerStart.IsZero() {
@@ -445,21 +1328,29 @@ func processReport(readerStart time.Time, report *llrp.ROAccessReport) {
 	for i := range report.TagReportData {
 		data := &report.TagReportData[i] // avoid copying the struct
 
-		if data.FirstSeenUptime != nil {
-			*data.FirstSeenUTC = llrp.FirstSeenUTC(uptimeToUTC(readerStart, llrp.Uptime(*data.FirstSeenUptime)))
+		if data.FirstSeenUptime != nil && data.FirstSeenUTC == nil {
+			first := llrp.FirstSeenUTC(uptimeToUTC(readerStart, llrp.Uptime(*data.FirstSeenUptime)))
+			data.FirstSeenUTC = &first
 		}
 
-		if data.LastSeenUptime != nil {
-			*data.LastSeenUTC = llrp.LastSeenUTC(uptimeToUTC(readerStart, llrp.Uptime(*data.LastSeenUptime)))
+		if data.LastSeenUptime != nil && data.LastSeenUTC == nil {
+			last := llrp.LastSeenUTC(uptimeToUTC(readerStart, llrp.Uptime(*data.LastSeenUptime)))
+			data.LastSeenUTC = &last
 		}
 	}
 }
 
 // onConnect is called when we open a new connection to a Reader.
 func (l *LLRPDevice) onConnect(svc ServiceWrapper) {
-	l.deviceMu.RLock()
+	// The Reader is reachable again, so stop failing commands fast.
+	l.breaker.reset()
+
+	l.deviceMu.Lock()
 	isEnabled := l.enabled
-	l.deviceMu.RUnlock()
+	l.caps = nil
+	l.antennaEvents = nil
+	l.bufferLevel = nil
+	l.deviceMu.Unlock()
 
 	if !isEnabled {
 		l.lc.Info("Device connection restored.", "device", l.name)
@@ -473,18 +1364,24 @@ func (l *LLRPDevice) onConnect(svc ServiceWrapper) {
 		}
 	}
 
-	l.lc.Debug("Setting Reader KeepAlive spec.", "device", l.name)
-	conf := &llrp.SetReaderConfig{
-		KeepAliveSpec: &llrp.KeepAliveSpec{
-			Trigger:  llrp.KATriggerPeriodic,
-			Interval: llrp.Millisecs32(keepAliveInterval.Milliseconds()),
-		},
+	if l.keepAlive > 0 {
+		l.lc.Debug("Setting Reader KeepAlive spec.", "device", l.name)
+		conf := &llrp.SetReaderConfig{KeepAliveSpec: keepAliveSpec(l.keepAlive)}
+
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		if err := l.TrySend(ctx, conf, &llrp.SetReaderConfigResponse{}); err != nil {
+			l.lc.Error("Failed to set KeepAlive interval.", "device", l.name, "error", err.Error())
+			l.resetConn()
+			return
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
-	defer cancel()
-	if err := l.TrySend(ctx, conf, &llrp.SetReaderConfigResponse{}); err != nil {
-		l.lc.Error("Failed to set KeepAlive interval.", "device", l.name, "error", err.Error())
-		l.resetConn()
+	rctx, rcancel := context.WithTimeout(context.Background(), reprovisionTimeout)
+	defer rcancel()
+	l.reprovision(rctx)
+	if l.connectSequence != nil {
+		l.connectSequence(rctx)
 	}
+	l.releaseHeldEventsAndReports(rctx)
 }
@@ -32,6 +32,7 @@ const (
 // discoveryInfo holds information about a discovered device
 type discoveryInfo struct {
 	deviceName string
+	readerID   string
 	host       string
 	port       string
 	vendor     uint32
@@ -48,10 +49,11 @@ func newDiscoveryInfo(host, port string) *discoveryInfo {
 
 // workerParams is a helper struct to store shared parameters to ipWorkers
 type workerParams struct {
-	deviceMap map[string]contract.Device
-	ipCh      <-chan uint32
-	resultCh  chan<- *discoveryInfo
-	ctx       context.Context
+	deviceMap    map[string]contract.Device
+	disconnected map[string]bool
+	ipCh         <-chan uint32
+	resultCh     chan<- *discoveryInfo
+	ctx          context.Context
 
 	timeout  time.Duration
 	scanPort string
@@ -62,6 +64,10 @@ type discoverParams struct {
 	asyncLimit int
 	timeout    time.Duration
 	scanPort   string
+
+	// disconnected has the names of enabled devices that aren't connected to their Readers,
+	// whose addresses are scanned like those of disabled devices.
+	disconnected map[string]bool
 }
 
 // computeNetSz computes the total amount of valid IP addresses for a given subnet size
@@ -76,10 +82,11 @@ func computeNetSz(subnetSz int) uint32 {
 
 // autoDiscover probes all addresses in the configured network to attempt to discover any possible
 // RFID readers that support LLRP.
-func autoDiscover(ctx context.Context, params discoverParams) []dsModels.DiscoveredDevice {
+// It returns the new Readers it found and the names of the existing devices whose Readers it found.
+func autoDiscover(ctx context.Context, params discoverParams) (discovered []dsModels.DiscoveredDevice, found map[string]bool) {
 	if len(params.subnets) == 0 {
 		driver.lc.Warn("Discover was called, but no subnet information has been configured!")
-		return nil
+		return nil, nil
 	}
 
 	ipnets := make([]*net.IPNet, 0, len(params.subnets))
@@ -120,14 +127,15 @@ func autoDiscover(ctx context.Context, params discoverParams) []dsModels.Discove
 	ipCh := make(chan uint32, asyncLimit)
 	resultCh := make(chan *discoveryInfo)
 
-	deviceMap := makeDeviceMap()
+	deviceMap, readerIDMap := makeDeviceMap()
 	wParams := workerParams{
-		deviceMap: deviceMap,
-		ipCh:      ipCh,
-		resultCh:  resultCh,
-		ctx:       ctx,
-		timeout:   params.timeout,
-		scanPort:  params.scanPort,
+		deviceMap:    deviceMap,
+		disconnected: params.disconnected,
+		ipCh:         ipCh,
+		resultCh:     resultCh,
+		ctx:          ctx,
+		timeout:      params.timeout,
+		scanPort:     params.scanPort,
 	}
 
 	// start the workers before adding any ips so they are ready to process
@@ -169,16 +177,24 @@ func autoDiscover(ctx context.Context, params discoverParams) []dsModels.Discove
 	}()
 
 	// this blocks until the resultCh is closed in above go routine
-	return processResultChannel(resultCh, deviceMap)
+	return processResultChannel(resultCh, deviceMap, readerIDMap)
 }
 
 // processResultChannel reads all incoming results until the resultCh is closed.
 // it determines if a device is new or existing, and proceeds accordingly.
 //
+// Existing devices are matched by their ReaderID if they have one,
+// so they're recognized even if they've been renamed,
+// or else by the name discovery would give them.
+//
+// It returns the new devices and the names of the existing ones it rediscovered.
+//
 // Does not check for context cancellation because we still want to
 // process any in-flight results.
-func processResultChannel(resultCh chan *discoveryInfo, deviceMap map[string]contract.Device) []dsModels.DiscoveredDevice {
-	discovered := make([]dsModels.DiscoveredDevice, 0)
+func processResultChannel(resultCh chan *discoveryInfo, deviceMap, readerIDMap map[string]contract.Device) (
+	discovered []dsModels.DiscoveredDevice, rediscovered map[string]bool) {
+	discovered = make([]dsModels.DiscoveredDevice, 0)
+	rediscovered = make(map[string]bool)
 	for info := range resultCh {
 		if info == nil {
 			continue
@@ -186,9 +202,14 @@ func processResultChannel(resultCh chan *discoveryInfo, deviceMap map[string]con
 
 		// check if any devices already exist at that address, and if so disable them
 		existing, found := deviceMap[info.host+":"+info.port]
-		if found && existing.Name != info.deviceName {
-			// disable it and remove its protocol information since it is no longer valid
-			delete(existing.Protocols, "tcp")
+		if found && !info.matches(existing) {
+			// disable it and remove its address since it is no longer valid,
+			// but keep its ReaderID so it can be recognized if it's discovered elsewhere
+			tcpInfo := contract.ProtocolProperties{}
+			if rID := existing.Protocols["tcp"][PropertyReaderID]; rID != "" {
+				tcpInfo[PropertyReaderID] = rID
+			}
+			existing.Protocols["tcp"] = tcpInfo
 			existing.OperatingState = contract.Disabled
 			if err := driver.svc.UpdateDevice(existing); err != nil {
 				driver.lc.Warn("There was an issue trying to disable an existing device.",
@@ -197,17 +218,21 @@ func processResultChannel(resultCh chan *discoveryInfo, deviceMap map[string]con
 			}
 		}
 
-		// check if we have an existing device registered with this name
-		device, err := driver.svc.GetDeviceByName(info.deviceName)
-		if err != nil {
-			// no existing device; add it to the list and move on
-			discovered = append(discovered, newDiscoveredDevice(info))
-			continue
+		// check if we have an existing device registered with this ReaderID or name
+		device, found := readerIDMap[info.readerID]
+		if !found {
+			var err error
+			if device, err = driver.svc.GetDeviceByName(info.deviceName); err != nil {
+				// no existing device; add it to the list and move on
+				discovered = append(discovered, newDiscoveredDevice(info))
+				continue
+			}
 		}
 
 		// this means we have discovered an existing device that is
-		// either disabled or has changed IP addresses.
+		// disabled, disconnected, or has changed IP addresses.
 		// we need to update its protocol information and operating state
+		rediscovered[device.Name] = true
 		if err := info.updateExistingDevice(device); err != nil {
 			driver.lc.Warn("There was an issue trying to update an existing device based on newly discovered details.",
 				"deviceName", device.Name,
@@ -215,7 +240,17 @@ func processResultChannel(resultCh chan *discoveryInfo, deviceMap map[string]con
 				"error", err)
 		}
 	}
-	return discovered
+	return discovered, rediscovered
+}
+
+// matches returns true if the discovered Reader is the existing device:
+// if both have ReaderIDs, they must be the same;
+// otherwise, the device must have the name discovery would give it.
+func (info *discoveryInfo) matches(device contract.Device) bool {
+	if rID := device.Protocols["tcp"][PropertyReaderID]; rID != "" && info.readerID != "" {
+		return rID == info.readerID
+	}
+	return device.Name == info.deviceName
 }
 
 // updateExistingDevice is used when an existing device is discovered
@@ -237,21 +272,35 @@ func (info *discoveryInfo) updateExistingDevice(device contract.Device) error {
 			"discoveredInfo", fmt.Sprintf("%+v", info))
 
 		// todo: double check to make sure EdgeX calls driver.UpdateDevice()
-		device.Protocols["tcp"] = map[string]string{
-			"host": info.host,
-			"port": info.port,
-		}
 		// make sure it is enabled
 		device.OperatingState = contract.Enabled
 		shouldUpdate = true
 	}
 
+	if info.readerID != "" && tcpInfo[PropertyReaderID] != info.readerID {
+		shouldUpdate = true
+	}
+
 	if !shouldUpdate {
-		// the address is the same and device is already enabled, should not reach here
-		driver.lc.Warn("Re-discovered existing device at the same TCP address, nothing to do.")
+		// the device is enabled but disconnected, and its Reader is back at the same address,
+		// so the device's own reconnect attempts will find it
+		driver.lc.Info("Re-discovered disconnected device at the same TCP address, nothing to do.",
+			"deviceName", device.Name)
 		return nil
 	}
 
+	// keep any other properties, such as the vendorPEN
+	updated := contract.ProtocolProperties{}
+	for k, v := range tcpInfo {
+		updated[k] = v
+	}
+	updated["host"] = info.host
+	updated["port"] = info.port
+	if info.readerID != "" {
+		updated[PropertyReaderID] = info.readerID
+	}
+	device.Protocols["tcp"] = updated
+
 	if err := driver.svc.UpdateDevice(device); err != nil {
 		driver.lc.Error("There was an error updating the tcp address for an existing device.",
 			"deviceName", device.Name,
@@ -262,10 +311,13 @@ func (info *discoveryInfo) updateExistingDevice(device contract.Device) error {
 	return nil
 }
 
-// makeDeviceMap creates a lookup table of existing devices by tcp address in order to skip scanning
-func makeDeviceMap() map[string]contract.Device {
+// makeDeviceMap creates lookup tables of existing devices by tcp address,
+// in order to skip scanning, and by ReaderID, in order to recognize Readers
+// that have changed addresses or been renamed.
+func makeDeviceMap() (deviceMap, readerIDMap map[string]contract.Device) {
 	devices := driver.svc.Devices()
-	deviceMap := make(map[string]contract.Device, len(devices))
+	deviceMap = make(map[string]contract.Device, len(devices))
+	readerIDMap = make(map[string]contract.Device, len(devices))
 
 	for _, d := range devices {
 		tcpInfo := d.Protocols["tcp"]
@@ -274,6 +326,10 @@ func makeDeviceMap() map[string]contract.Device {
 			continue
 		}
 
+		if rID := tcpInfo[PropertyReaderID]; rID != "" {
+			readerIDMap[rID] = d
+		}
+
 		host, port := tcpInfo["host"], tcpInfo["port"]
 		if host == "" || port == "" {
 			driver.lc.Warn("Registered device is missing required tcp protocol information.",
@@ -285,7 +341,7 @@ func makeDeviceMap() map[string]contract.Device {
 		deviceMap[host+":"+port] = d
 	}
 
-	return deviceMap
+	return deviceMap, readerIDMap
 }
 
 // ipGenerator generates all valid IP addresses for a given subnet, and
@@ -330,9 +386,10 @@ func ipGenerator(ctx context.Context, inet *net.IPNet, ipCh chan<- uint32) {
 
 // probe attempts to make a connection to a specific ip and port to determine
 // if an LLRP reader exists at that network address
-func probe(host, port string, timeout time.Duration) (*discoveryInfo, error) {
+func probe(ctx context.Context, host, port string, timeout time.Duration) (*discoveryInfo, error) {
 	addr := host + ":" + port
-	conn, err := net.DialTimeout("tcp", addr, timeout)
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
 		return nil, err
 	}
@@ -349,13 +406,17 @@ func probe(host, port string, timeout time.Duration) (*discoveryInfo, error) {
 
 	// send llrp messages in a separate thread and block the main thread until it is complete
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		// Canceling discovery cancels the probe, which closes the connection.
+		ctx, cancel := context.WithTimeout(ctx, sendTimeout)
 		defer cancel()
 
 		defer func() {
 			if err := c.Shutdown(ctx); err != nil && !errors.Is(err, llrp.ErrClientClosed) {
 				driver.lc.Warn("Error closing discovery device.", "error", err.Error())
 				_ = c.Close()
+				// Closing the client doesn't interrupt a Connect
+				// still waiting for the Reader's first message, but this does.
+				_ = conn.Close()
 			}
 		}()
 
@@ -414,6 +475,8 @@ func probe(host, port string, timeout time.Duration) (*discoveryInfo, error) {
 		prefix = ImpinjModelType(info.model).HostnamePrefix()
 	}
 
+	info.readerID = readerConfig.Identification.String()
+
 	var suffix string
 	rID := readerConfig.Identification.ReaderID
 	if readerConfig.Identification.IDType == llrp.ID_MAC_EUI64 && len(rID) >= 3 {
@@ -450,11 +513,11 @@ func ipWorker(params workerParams) {
 			ipStr := ip.String()
 			addr := ipStr + ":" + params.scanPort
 			if d, found := params.deviceMap[addr]; found {
-				if d.OperatingState == contract.Enabled {
+				if d.OperatingState == contract.Enabled && !params.disconnected[d.Name] {
 					driver.lc.Debug("Skip scan of " + addr + ", device already registered.")
 					continue
 				}
-				driver.lc.Info("Existing device in disabled (disconnected) state will be scanned again.",
+				driver.lc.Info("Existing device in disabled or disconnected state will be scanned again.",
 					"address", addr,
 					"deviceName", d.Name)
 			}
@@ -466,7 +529,7 @@ func ipWorker(params workerParams) {
 			default:
 			}
 
-			if info, err := probe(ipStr, params.scanPort, params.timeout); err == nil && info != nil {
+			if info, err := probe(params.ctx, ipStr, params.scanPort, params.timeout); err == nil && info != nil {
 				params.resultCh <- info
 			}
 		}
@@ -502,9 +565,10 @@ func newDiscoveredDevice(info *discoveryInfo) dsModels.DiscoveredDevice {
 		Name: info.deviceName,
 		Protocols: map[string]contract.ProtocolProperties{
 			"tcp": {
-				"host":      info.host,
-				"port":      info.port,
-				"vendorPEN": strconv.FormatUint(uint64(info.vendor), 10),
+				"host":           info.host,
+				"port":           info.port,
+				"vendorPEN":      strconv.FormatUint(uint64(info.vendor), 10),
+				PropertyReaderID: info.readerID,
 			},
 		},
 		Description: "LLRP RFID Reader",
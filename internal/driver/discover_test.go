@@ -10,7 +10,9 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
 	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
 	"net"
 	"os"
 	"strconv"
@@ -61,12 +63,14 @@ func TestAutoDiscoverNaming(t *testing.T) {
 	tests := []struct {
 		name        string
 		description string
+		readerID    string
 		identity    llrp.Identification
 		caps        llrp.GeneralDeviceCapabilities
 	}{
 		{
 			name:        "SpeedwayR-19-C5-D6",
 			description: "Test standard Speedway R420",
+			readerID:    "00:00:00:00:19:c5:d6",
 			identity: llrp.Identification{
 				IDType:   llrp.ID_MAC_EUI64,
 				ReaderID: []byte{0x00, 0x00, 0x00, 0x00, 0x19, 0xC5, 0xD6},
@@ -80,6 +84,7 @@ func TestAutoDiscoverNaming(t *testing.T) {
 		{
 			name:        "xArray-25-9C-D4",
 			description: "Test standard xArray",
+			readerID:    "00:00:00:00:25:9c:d4",
 			identity: llrp.Identification{
 				IDType:   llrp.ID_MAC_EUI64,
 				ReaderID: []byte{0x00, 0x00, 0x00, 0x00, 0x25, 0x9C, 0xD4},
@@ -93,6 +98,7 @@ func TestAutoDiscoverNaming(t *testing.T) {
 		{
 			name:        "LLRP-D2-7F-A1",
 			description: "Test unknown Impinj model with MAC_EUI64 ID type",
+			readerID:    "00:00:00:00:d2:7f:a1",
 			identity: llrp.Identification{
 				IDType:   llrp.ID_MAC_EUI64,
 				ReaderID: []byte{0x00, 0x00, 0x00, 0x00, 0xD2, 0x7F, 0xA1},
@@ -106,6 +112,7 @@ func TestAutoDiscoverNaming(t *testing.T) {
 		{
 			name:        "LLRP-302411f9c92d4f",
 			description: "Test unknown Impinj model with EPC ID type",
+			readerID:    "302411f9c92d4f",
 			identity: llrp.Identification{
 				IDType:   llrp.ID_EPC,
 				ReaderID: []byte{0x30, 0x24, 0x11, 0xF9, 0xC9, 0x2D, 0x4F},
@@ -119,6 +126,7 @@ func TestAutoDiscoverNaming(t *testing.T) {
 		{
 			name:        "LLRP-FC-4D-1A",
 			description: "Test unknown vendor and unknown model with MAC_EUI64 ID type",
+			readerID:    "00:00:00:00:fc:4d:1a",
 			identity: llrp.Identification{
 				IDType:   llrp.ID_MAC_EUI64,
 				ReaderID: []byte{0x00, 0x00, 0x00, 0x00, 0xFC, 0x4D, 0x1A},
@@ -132,6 +140,7 @@ func TestAutoDiscoverNaming(t *testing.T) {
 		{
 			name:        "LLRP-001a004fd9ca2b",
 			description: "Test unknown vendor and unknown model with EPC ID type",
+			readerID:    "001a004fd9ca2b",
 			identity: llrp.Identification{
 				IDType:   llrp.ID_EPC,                                      // test non-mac id types
 				ReaderID: []byte{0x00, 0x1A, 0x00, 0x4F, 0xD9, 0xCA, 0x2B}, // will be used as-is, not parsed
@@ -186,7 +195,7 @@ func TestAutoDiscoverNaming(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			defer cancel()
 
-			discovered := autoDiscover(ctx, params)
+			discovered, _ := autoDiscover(ctx, params)
 			if len(discovered) != 1 {
 				t.Fatalf("expected 1 discovered device, however got: %d", len(discovered))
 			}
@@ -201,6 +210,9 @@ func TestAutoDiscoverNaming(t *testing.T) {
 			if pen != strconv.FormatUint(uint64(test.caps.DeviceManufacturer), 10) {
 				t.Errorf("expected vendorPEN to be %v, but was: %s", test.caps.DeviceManufacturer, pen)
 			}
+			if rID := discovered[0].Protocols["tcp"][PropertyReaderID]; rID != test.readerID {
+				t.Errorf("expected readerID to be %s, but was: %s", test.readerID, rID)
+			}
 		})
 	}
 }
@@ -215,7 +227,7 @@ func TestAutoDiscover(t *testing.T) {
 
 	// attempt to discover without emulator, expect none found
 	svc.clearDevices()
-	discovered := autoDiscover(context.Background(), params)
+	discovered, _ := autoDiscover(context.Background(), params)
 	if len(discovered) != 0 {
 		t.Fatalf("expected 0 discovered devices, however got: %d", len(discovered))
 	}
@@ -250,17 +262,33 @@ func TestAutoDiscover(t *testing.T) {
 	}
 	emu.SetResponse(llrp.MsgGetReaderCapabilities, &readerCaps)
 
-	discovered = autoDiscover(context.Background(), params)
+	discovered, _ = autoDiscover(context.Background(), params)
 	if len(discovered) != 1 {
 		t.Fatalf("expected 1 discovered device, however got: %d", len(discovered))
 	}
 	svc.AddDiscoveredDevices(discovered)
+	name := discovered[0].Name
+	registered, err := svc.GetDeviceByName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registered.OperatingState = contract.Enabled
+	if err := svc.UpdateDevice(registered); err != nil {
+		t.Fatal(err)
+	}
 
 	// attempt to discover again WITH emulator, however expect emulator to be skipped
 	svc.resetAddedCount()
-	discovered = autoDiscover(context.Background(), params)
-	if len(discovered) != 0 {
-		t.Fatalf("expected no devices to be discovered, but was %d", len(discovered))
+	discovered, found := autoDiscover(context.Background(), params)
+	if len(discovered) != 0 || len(found) != 0 {
+		t.Fatalf("expected no devices to be discovered or found, but was %d and %v", len(discovered), found)
+	}
+
+	// once its device is disconnected, the emulator is scanned again and found, but not added
+	params.disconnected = map[string]bool{name: true}
+	discovered, found = autoDiscover(context.Background(), params)
+	if len(discovered) != 0 || !found[name] {
+		t.Fatalf("expected the disconnected device to be found, but was %d and %v", len(discovered), found)
 	}
 
 	if err := emu.Shutdown(); err != nil {
@@ -270,6 +298,173 @@ func TestAutoDiscover(t *testing.T) {
 	svc.clearDevices()
 }
 
+// TestAutoDiscoverReaderID checks that a Reader whose device has been renamed
+// and whose address has changed is recognized by its ReaderID,
+// so its existing device is updated and enabled rather than discovered again.
+func TestAutoDiscoverReaderID(t *testing.T) {
+	params := makeParams()
+	svc.clearDevices()
+	defer svc.clearDevices()
+
+	port, err := strconv.Atoi(params.scanPort)
+	if err != nil {
+		t.Fatalf("Failed to parse driver.config.ScanPort, unable to run discovery tests. value = %v", params.scanPort)
+	}
+	emu := llrp.NewTestEmulator(!testing.Verbose())
+	if err := emu.StartAsync(port); err != nil {
+		t.Fatalf("unable to start emulator: %+v", err)
+	}
+	defer func() {
+		if err := emu.Shutdown(); err != nil {
+			t.Errorf("error shutting down test emulator: %+v", err)
+		}
+	}()
+
+	emu.SetResponse(llrp.MsgGetReaderConfig, &llrp.GetReaderConfigResponse{
+		Identification: &llrp.Identification{
+			IDType:   llrp.ID_MAC_EUI64,
+			ReaderID: []byte{0x00, 0x16, 0x25, 0xff, 0xfe, 0x19, 0xC5, 0xD6},
+		},
+	})
+	emu.SetResponse(llrp.MsgGetReaderCapabilities, &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			DeviceManufacturer: uint32(Impinj),
+			Model:              uint32(SpeedwayR420),
+			FirmwareVersion:    "5.14.0.240",
+		},
+	})
+
+	if _, err := svc.AddDevice(contract.Device{
+		Name:           "DockDoor3",
+		OperatingState: contract.Disabled,
+		Protocols: protocolMap{
+			"tcp": {
+				"host":           "192.0.2.1",
+				"port":           params.scanPort,
+				"vendorPEN":      "25882",
+				PropertyReaderID: "00:16:25:ff:fe:19:c5:d6",
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	discovered, found := autoDiscover(context.Background(), params)
+	if len(discovered) != 0 || !found["DockDoor3"] {
+		t.Fatalf("expected the existing device to be updated rather than discovered; got %+v, %v",
+			discovered, found)
+	}
+
+	dev, err := svc.GetDeviceByName("DockDoor3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dev.OperatingState != contract.Enabled {
+		t.Errorf("expected the device to be enabled; got %v", dev.OperatingState)
+	}
+
+	tcpInfo := dev.Protocols["tcp"]
+	if tcpInfo["host"] != "127.0.0.1" || tcpInfo["port"] != params.scanPort {
+		t.Errorf("expected the device's address to be updated; got %+v", tcpInfo)
+	}
+	if tcpInfo["vendorPEN"] != "25882" || tcpInfo[PropertyReaderID] != "00:16:25:ff:fe:19:c5:d6" {
+		t.Errorf("expected the device's other properties to be kept; got %+v", tcpInfo)
+	}
+}
+
+// TestDriver_Discover_cancel checks that a discovery stuck probing an unresponsive host
+// returns promptly when a new discovery starts or the driver cancels it.
+func TestDriver_Discover_cancel(t *testing.T) {
+	// This accepts connections, but never says anything, so probes hang.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+	defer func() {
+		close(accepted)
+		for conn := range accepted {
+			conn.Close()
+		}
+	}()
+
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deviceCh := make(chan []dsModels.DiscoveredDevice, 10)
+	d := &Driver{
+		lc:       driver.lc,
+		deviceCh: deviceCh,
+		svc:      svc,
+		config: &driverConfiguration{
+			DiscoverySubnets:    "127.0.0.1/32",
+			ProbeAsyncLimit:     1,
+			ProbeTimeoutSeconds: 30,
+			ScanPort:            port,
+		},
+	}
+
+	// discover starts a discovery and waits until it's probing the listener.
+	discover := func() <-chan struct{} {
+		t.Helper()
+		finished := make(chan struct{})
+		go func() {
+			d.Discover()
+			close(finished)
+		}()
+
+		select {
+		case <-accepted:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected discovery to probe the listener")
+		}
+		return finished
+	}
+
+	waitFinished := func(finished <-chan struct{}) {
+		t.Helper()
+		select {
+		case <-finished:
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the discovery to return promptly after it was canceled")
+		}
+
+		select {
+		case <-deviceCh:
+		default:
+			t.Error("expected the discovery to tell the SDK it finished")
+		}
+	}
+
+	first := discover()
+	second := discover() // starting another cancels the first
+	waitFinished(first)
+
+	<-d.cancelDiscovery()
+	waitFinished(second)
+
+	// Nothing's in progress now, so canceling is a no-op.
+	select {
+	case <-d.cancelDiscovery():
+	default:
+		t.Error("expected canceling with no discovery in progress to finish immediately")
+	}
+}
+
 func mockIpWorker(ipCh <-chan uint32, result *inetTest) {
 	ip := net.IP([]byte{0, 0, 0, 0})
 	var last uint32
@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"sync"
+)
+
+// sendTracker tracks the goroutines sending a device's reports to EdgeX,
+// so that Stop can wait for them to finish or make them drop their values.
+// Its zero value is ready to use.
+type sendTracker struct {
+	mu      sync.Mutex
+	pending int           // number of sends that haven't finished
+	idle    chan struct{} // closed when pending drops to 0
+	drop    chan struct{} // closed to make pending sends drop their values
+	dropped bool          // true once drop is closed
+}
+
+// start records a new pending send,
+// or returns false if sends are being dropped.
+// If it returns true, the caller must call done when the send finishes.
+func (t *sendTracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.dropped {
+		return false
+	}
+	if t.pending == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.pending++
+	return true
+}
+
+// done records that a pending send finished.
+func (t *sendTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending--
+	if t.pending == 0 {
+		close(t.idle)
+	}
+}
+
+// wait blocks until there are no pending sends or ctx is done.
+func (t *sendTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	idle := t.idle
+	pending := t.pending
+	t.mu.Unlock()
+
+	if pending == 0 {
+		return nil
+	}
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dropChan returns a channel closed once sends should drop their values.
+func (t *sendTracker) dropChan() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.drop == nil {
+		t.drop = make(chan struct{})
+	}
+	return t.drop
+}
+
+// dropAll makes pending and future sends drop their values.
+func (t *sendTracker) dropAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.dropped {
+		return
+	}
+	if t.drop == nil {
+		t.drop = make(chan struct{})
+	}
+	close(t.drop)
+	t.dropped = true
+}
+
+// goSend runs send in a new goroutine that Stop waits for
+// when it drains the device's reports, unless the device is being force-stopped.
+func (l *LLRPDevice) goSend(send func()) {
+	if !l.sends.start() {
+		return
+	}
+
+	go func() {
+		defer l.sends.done()
+		send()
+	}()
+}
+
+// drainReports waits for the device's pending reports to reach EdgeX,
+// then drops any still pending when ctx is done.
+func (l *LLRPDevice) drainReports(ctx context.Context) error {
+	defer l.sends.dropAll()
+	return l.sends.wait(ctx)
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// stoppingDriver returns a Driver managing a single device with a pending report,
+// and the channel on which the device sends it.
+func stoppingDriver(t *testing.T) (*Driver, *LLRPDevice, chan *dsModels.AsyncValues) {
+	ch := make(chan *dsModels.AsyncValues)
+	dev := &LLRPDevice{ch: ch}
+	d := newLocalDriver(t, dev)
+	d.done = make(chan struct{})
+
+	dev.goSend(func() { dev.sendReport(0, &llrp.ROAccessReport{}) })
+	return d, dev, ch
+}
+
+func TestDriver_Stop_drainsReports(t *testing.T) {
+	d, _, ch := stoppingDriver(t)
+
+	received := make(chan struct{})
+	go func() {
+		// EdgeX is slow to take the report, but within the grace period.
+		time.Sleep(shutdownGrace / 10)
+		<-ch
+		close(received)
+	}()
+
+	if err := d.Stop(false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Had Stop not waited, the report would've been dropped.
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Stop to wait for the pending report")
+	}
+}
+
+func TestDriver_Stop_forceDropsReports(t *testing.T) {
+	d, dev, _ := stoppingDriver(t)
+
+	// Nothing reads the channel, so the report can only be dropped.
+	start := time.Now()
+	if err := d.Stop(true); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > shutdownGrace/2 {
+		t.Errorf("expected a forced Stop to return immediately; took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := dev.sends.wait(ctx); err != nil {
+		t.Errorf("expected the pending report to be dropped: %v", err)
+	}
+
+	// Later reports are dropped, too.
+	dev.goSend(func() { t.Error("expected no sends after a forced Stop") })
+}
+
+func TestLLRPDevice_drainReports_timeout(t *testing.T) {
+	_, dev, _ := stoppingDriver(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := dev.drainReports(ctx); err == nil {
+		t.Error("expected draining to stop at the deadline")
+	}
+
+	// Once the drain gives up, the report is dropped.
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := dev.sends.wait(ctx); err != nil {
+		t.Errorf("expected the pending report to be dropped: %v", err)
+	}
+}
+
+func TestDriver_Stop_startStopCycles(t *testing.T) {
+	emu, port := startEmulator(t, 0)
+
+	d := newTestDriver(nil)
+
+	// waitFor polls until the emulator has the expected number of connections.
+	waitFor := func(expected int) {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for n := emu.NumConnections(); n != expected; n = emu.NumConnections() {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d connections; got %d", expected, n)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	protocols := protocolMap{"tcp": {"host": "127.0.0.1", "port": strconv.Itoa(port)}}
+	for cycle, force := range []bool{false, true, false, true} {
+		// As Initialize does for a Driver that was stopped.
+		d.done = make(chan struct{})
+
+		for _, name := range []string{"reader1", "reader2"} {
+			if err := d.AddDevice(name, protocols, contract.Enabled); err != nil {
+				t.Fatalf("cycle %d: %+v", cycle, err)
+			}
+		}
+		waitFor(2)
+
+		if err := d.Stop(force); err != nil {
+			t.Fatalf("cycle %d: %+v", cycle, err)
+		}
+
+		// Stop doesn't leave devices behind for the next cycle.
+		d.devicesMu.RLock()
+		n := len(d.activeDevices)
+		d.devicesMu.RUnlock()
+		if n != 0 {
+			t.Errorf("cycle %d: expected no active devices after Stop; got %d", cycle, n)
+		}
+
+		d.stopping.Wait()
+		waitFor(0)
+	}
+}
@@ -6,8 +6,10 @@
 package driver
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
@@ -35,14 +37,38 @@ const (
 	ServiceName    = "edgex-device-rfid-llrp"
 	BaseConsulPath = "edgex/devices/1.0/" + ServiceName
 
-	ResourceReaderCap          = "ReaderCapabilities"
-	ResourceReaderConfig       = "ReaderConfig"
-	ResourceReaderNotification = "ReaderEventNotification"
-	ResourceROSpec             = "ROSpec"
-	ResourceROSpecID           = "ROSpecID"
-	ResourceAccessSpec         = "AccessSpec"
-	ResourceAccessSpecID       = "AccessSpecID"
-	ResourceROAccessReport     = "ROAccessReport"
+	ResourceReaderCap            = "ReaderCapabilities"
+	ResourceReaderConfig         = "ReaderConfig"
+	ResourceReaderID             = "ReaderID"
+	ResourceReaderNotification   = "ReaderEventNotification"
+	ResourceROSpec               = "ROSpec"
+	ResourceROSpecID             = "ROSpecID"
+	ResourceAccessSpec           = "AccessSpec"
+	ResourceAccessSpecID         = "AccessSpecID"
+	ResourceROAccessReport       = "ROAccessReport"
+	ResourceTagCount             = "TagCount"
+	ResourceRFSurvey             = "RFSurvey"
+	ResourceRawMessage           = "RawMessage"
+	ResourceRawMessageType       = "RawMessageType"
+	ResourceEventsAndReports     = "EventsAndReports"
+	ResourceEventHistory         = "EventHistory"
+	ResourceVerifiedAccessSpec   = "VerifiedAccessSpec"
+	ResourceDwellROSpec          = "DwellROSpec"
+	ResourceTagWriteVerification = "TagWriteVerification"
+	ResourceSelfTest             = "SelfTest"
+
+	// These resources hold the values of tag reads
+	// for devices that use ReportFormatFlat.
+	ResourceTagEPC        = "TagEPC"
+	ResourceTagAntenna    = "TagAntenna"
+	ResourceTagLocation   = "TagLocation"
+	ResourceTagRSSI       = "TagRSSI"
+	ResourceTagTimestamp  = "TagTimestamp"
+	ResourceTagFirstSeen  = "TagFirstSeen"
+	ResourceTagLastSeen   = "TagLastSeen"
+	ResourceTagPC         = "TagPC"
+	ResourceTagCRC        = "TagCRC"
+	ResourceTagUserMemory = "TagUserMemory"
 
 	ResourceAction = "Action"
 	ActionDelete   = "Delete"
@@ -50,8 +76,69 @@ const (
 	ActionDisable  = "Disable"
 	ActionStart    = "Start"
 	ActionStop     = "Stop"
-	AttribVendor   = "vendor"
-	AttribSubtype  = "subtype"
+	ActionClear    = "Clear"
+	ActionPause    = "Pause"
+	ActionResume   = "Resume"
+
+	// ActionDeleteVerified deletes an ROSpec, then confirms the Reader no longer lists it.
+	ActionDeleteVerified = "DeleteVerified"
+
+	AttribVendor  = "vendor"
+	AttribSubtype = "subtype"
+
+	// AttribRequestedData selects the part of the ReaderConfig a read returns;
+	// its value is a name from readerConfigSelectors or an LLRP RequestedData value.
+	// Reading any resource with this attribute reads the ReaderConfig.
+	AttribRequestedData = "requestedData"
+
+	// PropertyReaderID is the "tcp" protocol property holding a Reader's ID,
+	// formatted by llrp.Identification's String method.
+	// Discovery sets it and uses it to recognize Readers
+	// that have changed addresses or whose devices have been renamed.
+	PropertyReaderID = "readerID"
+
+	// ProtocolLocation is an optional protocol mapping a device's antenna IDs
+	// (as decimal strings) to location labels to include with its tag reads.
+	ProtocolLocation = "location"
+
+	// ProtocolReport is an optional protocol whose "format" property
+	// selects how a device's tag reads are sent to EdgeX:
+	// ReportFormatJSON sends each ROAccessReport as a single JSON value,
+	// while ReportFormatFlat sends each tag read as individual, typed values.
+	// Its optional "port" property opens a second connection to the Reader on that port
+	// dedicated to ROAccessReports, for Readers that support multiple connections.
+	ProtocolReport   = "report"
+	ReportFormatJSON = "json"
+	ReportFormatFlat = "flat"
+
+	// ProtocolCommands is an optional protocol whose "ordered" property,
+	// if "true", makes a device run its commands one at a time, in the order received,
+	// even when they're issued concurrently, e.g., so a SetReaderConfig
+	// finishes before a subsequent AddROSpec is sent.
+	// Otherwise, commands run concurrently.
+	// Its "reprovision" property, if "true", makes the service restore
+	// the ROSpecs and AccessSpecs it added to the Reader, and their states,
+	// when it reconnects to find the Reader lost them, e.g. because it rebooted.
+	// Its "replaceROSpecs" property, if "true", makes adding an ROSpec
+	// with the same ID as one the Reader already has replace the existing one,
+	// rather than fail with ErrROSpecExists.
+	// Its "slowThreshold" property, a duration such as "2s", makes the service
+	// send a ResourceSlowCommand event for each command whose round trip takes longer.
+	// Its "allow" and "deny" properties, comma-separated lists of resource names,
+	// restrict the resources the device's commands may use; see commandPolicy.
+	ProtocolCommands = "commands"
+
+	// ProtocolDecode is an optional protocol whose "unknownParams" property
+	// selects how a device handles parameters in its reports and events
+	// of types the service doesn't know, such as those added by newer Reader firmware:
+	// UnknownParamsStrict discards the whole message (the default),
+	// UnknownParamsLenient logs and skips the unknown parameters,
+	// and UnknownParamsPreserve skips them, but includes their raw bytes
+	// with JSON reports and events.
+	ProtocolDecode        = "decode"
+	UnknownParamsStrict   = "strict"
+	UnknownParamsLenient  = "lenient"
+	UnknownParamsPreserve = "preserve"
 
 	// Note: For now disable the registration of provision watchers since we are not using them
 	registerProvisionWatchers = false
@@ -85,7 +172,9 @@ type Driver struct {
 	done chan struct{}
 
 	activeDevices map[string]*LLRPDevice
+	deviceInits   map[string]*deviceInit // devices getDevice is creating, by name
 	devicesMu     sync.RWMutex
+	stopping      sync.WaitGroup // devices still closing their connections, which Initialize waits for
 
 	config   *driverConfiguration
 	configMu sync.RWMutex
@@ -93,7 +182,27 @@ type Driver struct {
 	addedWatchers bool
 	watchersMu    sync.Mutex
 
+	discoverMu     sync.Mutex
+	discoverCancel context.CancelFunc // cancels the discovery in progress; nil if none
+	discoverDone   chan struct{}      // closed when the discovery in progress finishes
+	missingReaders map[string]bool    // devices discovery reported missing, by name
+	quietDiscovers int                // complete discoveries in a row that changed nothing; guarded by discoverMu
+	discoverSkips  int                // discoveries left to skip to back off; guarded by discoverMu
+
 	svc ServiceWrapper
+
+	// newDevice creates the devices getDevice adds; NewLLRPDevice if nil.
+	newDevice func(name string, address net.Addr, opState contract.OperatingState) *LLRPDevice
+
+	clk clock // tells the time to the Driver and its devices; the real clock if nil
+
+	sink reportSink // publishes devices' reports outside of EdgeX; nil to send them to EdgeX
+
+	queueBudget *queueBudget // limits the memory devices' async queues use; unlimited if nil
+
+	// params are the device settings in effect, or nil if the Driver isn't running.
+	// They're guarded by devicesMu.
+	params *deviceParams
 }
 
 type MultiErr []error
@@ -140,6 +249,13 @@ func (d *Driver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsModels.As
 
 	d.asyncCh = asyncCh
 	d.deviceCh = deviceCh
+
+	// If the Driver was stopped, it's starting over.
+	select {
+	case <-d.done:
+		d.done = make(chan struct{})
+	default:
+	}
 	d.svc = &DeviceSDKService{
 		Service: service.RunningService(),
 		lc:      lc,
@@ -155,29 +271,17 @@ func (d *Driver) Initialize(lc logger.LoggingClient, asyncCh chan<- *dsModels.As
 	d.lc.Debug(fmt.Sprintf("%+v", config))
 	d.configMu.Unlock()
 
-	if err := d.watchForConfigChanges(); err != nil {
-		d.lc.Warn("Unable to watch for configuration changes!", "error", err)
-	}
-
-	registered := d.svc.Devices()
-	d.devicesMu.Lock()
-	defer d.devicesMu.Unlock()
-	for i := range registered {
-		device := &registered[i] // the Device struct is nearly 1kb, so this avoids copying it
-
-		addr, err := getAddr(device.Protocols)
-		if err != nil {
-			d.lc.Error("Unsupported protocol mapping.",
-				"error", err,
-				"protocols", fmt.Sprintf("%v", device.Protocols),
-				"deviceName", device.Name)
-			continue
+	// If it's re-initialized without being stopped, the Driver is already watching.
+	d.devicesMu.RLock()
+	running := d.params != nil
+	d.devicesMu.RUnlock()
+	if !running {
+		if err := d.watchForConfigChanges(); err != nil {
+			d.lc.Warn("Unable to watch for configuration changes!", "error", err)
 		}
-
-		d.lc.Info("Creating a new Reader connection.", "deviceName", device.Name)
-		d.activeDevices[device.Name] = d.NewLLRPDevice(device.Name, addr, device.OperatingState)
 	}
 
+	d.restartDevices(d.svc.Devices())
 	return nil
 }
 
@@ -236,12 +340,22 @@ func (d *Driver) watchForConfigChanges() error {
 				d.lc.Debug(fmt.Sprintf("raw: %+v", raw))
 
 				newCfg, ok := raw.(*driverConfiguration)
-				if ok {
-					d.configMu.Lock()
-					d.config = newCfg
-					d.configMu.Unlock()
-				} else {
+				if !ok {
 					d.lc.Warn("unable to decode incoming configuration from registry")
+					continue
+				}
+
+				d.configMu.Lock()
+				d.config = newCfg
+				d.configMu.Unlock()
+
+				// Devices only use new settings once they're rebuilt.
+				d.devicesMu.RLock()
+				changed := d.params != nil && *d.params != newDeviceParams(newCfg)
+				d.devicesMu.RUnlock()
+				if changed {
+					d.lc.Info("Device settings changed; rebuilding devices that use them.")
+					d.restartDevices(d.svc.Devices())
 				}
 			}
 		}
@@ -252,7 +366,7 @@ func (d *Driver) watchForConfigChanges() error {
 // HandleReadCommands triggers a protocol Read operation for the specified device.
 func (d *Driver) HandleReadCommands(devName string, p protocolMap, reqs []dsModels.CommandRequest) ([]*dsModels.CommandValue, error) {
 	d.lc.Debug(fmt.Sprintf("LLRP-Driver.HandleReadCommands: "+
-		"device: %s protocols: %v reqs: %+v", devName, p, reqs))
+		"device: %s protocols: %v reqs: %+v", devName, maskProtocols(p), reqs))
 
 	results, err := d.handleReadCommands(devName, p, reqs)
 	if err != nil {
@@ -274,19 +388,299 @@ func (d *Driver) handleReadCommands(devName string, p protocolMap, reqs []dsMode
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	resources := make([]string, len(reqs))
+	for i := range reqs {
+		resources[i] = reqs[i].DeviceResourceName
+	}
+	if err := dev.checkPermitted(CommandRead, resources...); err != nil {
+		return nil, err
+	}
+
+	timeout, err := d.commandTimeout(reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	finish, err := dev.startCommand(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	cmdCtx := ctx
 	var responses = make([]*dsModels.CommandValue, len(reqs))
 	for i := range reqs {
 		var llrpReq llrp.Outgoing
 		var llrpResp llrp.Incoming
 
+		ctx, err := withReconnectMode(cmdCtx, reqs[i].Attributes)
+		if err != nil {
+			return nil, err
+		}
+
 		switch reqs[i].DeviceResourceName {
 		default:
-			return nil, errors.Errorf("unknown resource type: %q", reqs[i].DeviceResourceName)
+			if _, ok := reqs[i].Attributes[AttribRequestedData]; !ok {
+				return nil, errors.Errorf("unsupported read resource %q; "+
+					"read %s for the resources the service handles",
+					reqs[i].DeviceResourceName, ResourceCommandCatalog)
+			}
+
+			conf, err := newGetReaderConfig(reqs[i].Attributes)
+			if err != nil {
+				return nil, err
+			}
+			llrpReq = conf
+			llrpResp = &llrp.GetReaderConfigResponse{}
+		case ResourceROAccessReport:
+			// This is served from the device's cache rather than the Reader.
+			reads, report := dev.cachedReport()
+			respData, err := d.marshalJSON(report)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(reqs[i].DeviceResourceName,
+				reportOrigin(dev.origin, d.clock().Now(), reads), string(respData))
+			continue
+		case ResourceEventHistory:
+			events, err := dev.EventHistory(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			respData, err := d.marshalJSON(events)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceRFSurvey:
+			results, err := dev.RFSurveyResults(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			respData, err := d.marshalJSON(results)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceTagCount:
+			// Like the ROAccessReport, this comes from data we've already received.
+			now := d.clock().Now()
+			cv, err := dsModels.NewUint32Value(reqs[i].DeviceResourceName, now.UnixNano(),
+				uint32(dev.counts.count(now)))
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = cv
+			continue
+		case ResourceReaderID:
+			id, err := dev.ReaderID(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), id.String())
+			continue
+		case ResourceCanDoRFSurvey, ResourceCanReportBufferFillWarning, ResourceSupportsClientRequestOpSpec,
+			ResourceCanDoTagInventoryStateAwareSingulation, ResourceSupportsEventsAndReportHolding:
+			supported, err := dev.CapabilityFlag(ctx, reqs[i].DeviceResourceName)
+			if err != nil {
+				return nil, err
+			}
+
+			cv, err := dsModels.NewBoolValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), supported)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = cv
+			continue
+		case ResourceCommandLatency:
+			respData, err := d.marshalJSON(dev.CommandLatency())
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceAsyncQueueUsage:
+			respData, err := d.marshalJSON(dev.AsyncQueueUsage())
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourcePendingRequests:
+			respData, err := d.marshalJSON(dev.PendingRequests())
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceReportBufferLevel:
+			level, err := dev.ReportBufferLevel(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			cv, err := dsModels.NewUint8Value(reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), level)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = cv
+			continue
+		case ResourceAntennaStatus:
+			statuses, err := dev.AntennaStatus(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			respData, err := d.marshalJSON(statuses)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceAntennaProperties:
+			props, err := dev.AntennaProperties(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			respData, err := d.marshalJSON(props)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceReceiverSensitivity:
+			antennas, err := dev.ReceiverSensitivity(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			respData, err := d.marshalJSON(antennas)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceReceiverSensitivityRange:
+			sr, err := dev.ReceiverSensitivityRange(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			respData, err := d.marshalJSON(sr)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceTransmitPower:
+			antennas, err := dev.TransmitPower(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			respData, err := d.marshalJSON(antennas)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceAirProtocols:
+			antennas, err := dev.AirProtocols(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			respData, err := d.marshalJSON(antennas)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceSelfTest:
+			// Failed checks are part of the report, not errors.
+			respData, err := d.marshalJSON(dev.SelfTest(ctx))
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceCommandCatalog:
+			respData, err := d.marshalJSON(dev.CommandCatalog(ctx))
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
+		case ResourceHoldEventsAndReports:
+			hold, err := dev.HoldEventsAndReports(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			cv, err := dsModels.NewBoolValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), hold)
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = cv
+			continue
+		case ResourceClockSkew:
+			respData, err := d.marshalJSON(dev.ClockSkew())
+			if err != nil {
+				return nil, err
+			}
+
+			responses[i] = dsModels.NewStringValue(
+				reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
+			continue
 		case ResourceReaderConfig:
-			llrpReq = &llrp.GetReaderConfig{}
+			conf, err := newGetReaderConfig(reqs[i].Attributes)
+			if err != nil {
+				return nil, err
+			}
+			llrpReq = conf
 			llrpResp = &llrp.GetReaderConfigResponse{}
 		case ResourceReaderCap:
 			llrpReq = &llrp.GetReaderCapabilities{}
@@ -303,13 +697,26 @@ func (d *Driver) handleReadCommands(devName string, p protocolMap, reqs []dsMode
 			return nil, err
 		}
 
-		respData, err := json.Marshal(llrpResp)
+		if caps, ok := llrpResp.(*llrp.GetReaderCapabilitiesResponse); ok {
+			dev.setCapabilities(caps)
+		}
+
+		var resp interface{} = llrpResp
+		switch specs := llrpResp.(type) {
+		case *llrp.GetROSpecsResponse:
+			resp = describeROSpecs(specs)
+		case *llrp.GetAccessSpecsResponse:
+			// AccessSpecs' passwords are secrets, so don't send them to EdgeX.
+			resp = describeAccessSpecs(redactAccessSpecs(specs))
+		}
+
+		respData, err := d.marshalJSON(resp)
 		if err != nil {
 			return nil, err
 		}
 
 		responses[i] = dsModels.NewStringValue(
-			reqs[i].DeviceResourceName, time.Now().UnixNano(), string(respData))
+			reqs[i].DeviceResourceName, d.clock().Now().UnixNano(), string(respData))
 	}
 
 	return responses, nil
@@ -321,7 +728,7 @@ func (d *Driver) handleReadCommands(devName string, p protocolMap, reqs []dsMode
 // command.
 func (d *Driver) HandleWriteCommands(devName string, p protocolMap, reqs []dsModels.CommandRequest, params []*dsModels.CommandValue) error {
 	d.lc.Debug(fmt.Sprintf("LLRP-Driver.HandleWriteCommands: "+
-		"device: %s protocols: %v reqs: %+v", devName, p, reqs))
+		"device: %s protocols: %v reqs: %+v", devName, maskProtocols(p), reqs))
 
 	// kinda surprised EdgeX doesn't do this automatically.
 	err := d.handleWriteCommands(devName, p, reqs, params)
@@ -347,6 +754,17 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 		return err
 	}
 
+	return d.writeDevice(dev, p, reqs, params)
+}
+
+// writeDevice handles write commands for a device handleWriteCommands found,
+// or that's running its ConnectSequence.
+func (d *Driver) writeDevice(dev *LLRPDevice, p protocolMap, reqs []dsModels.CommandRequest, params []*dsModels.CommandValue) error {
+	// The first resource selects the command; the rest are its parameters.
+	if err := dev.checkPermitted(CommandWrite, reqs[0].DeviceResourceName); err != nil {
+		return err
+	}
+
 	getAttrib := func(idx int, key string) (string, error) {
 		m := reqs[idx].Attributes
 		val := m[key]
@@ -366,9 +784,25 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 		return u, errors.Wrapf(err, "unable to parse attribute %q with val %q as uint", key, v)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	timeout, err := d.commandTimeout(reqs)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	ctx, err = withReconnectMode(ctx, reqs[0].Attributes)
+	if err != nil {
+		return err
+	}
+
+	finish, err := dev.startCommand(ctx)
+	if err != nil {
+		return err
+	}
+	defer finish()
+
 	var llrpReq llrp.Outgoing  // the message to send
 	var llrpResp llrp.Incoming // the expected response
 	var reqData []byte         // incoming JSON request data, if present
@@ -378,6 +812,14 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 	default:
 		// assume the resource requires sending a CustomMessage
 		customName := reqs[0].DeviceResourceName
+		_, hasVendor := reqs[0].Attributes[AttribVendor]
+		_, hasSubtype := reqs[0].Attributes[AttribSubtype]
+		if !hasVendor && !hasSubtype {
+			return errors.Errorf("unsupported write resource %q; "+
+				"read %s for the resources the service handles",
+				customName, ResourceCommandCatalog)
+		}
+
 		vendor, err := getUintAttrib(0, AttribVendor)
 		if err != nil {
 			return err
@@ -411,16 +853,230 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 			MessageSubtype: uint8(subtype),
 			Data:           valData,
 		}
-		llrpResp = &llrp.CustomMessage{}
+		llrpResp = &llrp.CustomMessage{}
+
+	case ResourceRawMessage:
+		// This is an escape hatch for messages we don't otherwise support,
+		// so it bypasses the usual Outgoing/Incoming marshaling.
+		return d.sendRawMessage(ctx, dev, reqs, params)
+
+	case ResourceRFSurvey:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get RFSurvey parameter")
+		}
+
+		spec := llrp.RFSurveySpec{}
+		if err := json.Unmarshal([]byte(data), &spec); err != nil {
+			return errors.Wrap(err, "failed to unmarshal RFSurveySpec")
+		}
+
+		return dev.RunRFSurvey(ctx, spec)
+
+	case ResourceDwellROSpec:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get DwellROSpec parameter")
+		}
+
+		var dwellSpec struct {
+			ROSpec       llrp.ROSpec
+			AntennaDwell []llrp.AntennaDwell
+		}
+		if err := json.Unmarshal([]byte(data), &dwellSpec); err != nil {
+			return errors.Wrap(err, "failed to unmarshal request")
+		}
+
+		if len(dwellSpec.AntennaDwell) == 0 {
+			return errors.New("DwellROSpec must have at least one AntennaDwell")
+		}
+
+		dwellSpec.ROSpec.SetAntennaDwell(dwellSpec.AntennaDwell...)
+		if err := applyFilterEPC(&dwellSpec.ROSpec, []byte(data)); err != nil {
+			return err
+		}
+		llrpReq = dwellSpec.ROSpec.Add()
+		llrpResp = &llrp.AddROSpecResponse{}
+
+	case ResourceFastIDROSpec:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get FastIDROSpec parameter")
+		}
+
+		ros := llrp.ROSpec{}
+		if err := json.Unmarshal([]byte(data), &ros); err != nil {
+			return errors.Wrap(err, "failed to unmarshal ROSpec")
+		}
+		if err := applyFilterEPC(&ros, []byte(data)); err != nil {
+			return err
+		}
+
+		return dev.AddFastIDROSpec(ctx, ros)
+
+	case ResourceResetROSpecs:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get ResetROSpecs parameter")
+		}
+
+		baseline, err := d.baselineROSpec(data)
+		if err != nil {
+			return err
+		}
+
+		return dev.ResetROSpecs(ctx, baseline)
+
+	case ResourceReading:
+		action, err := params[0].StringValue()
+		if err != nil {
+			return err
+		}
+
+		switch action {
+		default:
+			return errors.Errorf("unknown Reading action: %q; Reading actions are %s or %s",
+				action, ActionPause, ActionResume)
+		case ActionPause:
+			return dev.PauseReading(ctx)
+		case ActionResume:
+			return dev.ResumeReading(ctx)
+		}
+
+	case ResourceMigrateAddress:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get MigrateAddress parameter")
+		}
+
+		req := migrateAddressRequest{}
+		if err := json.Unmarshal([]byte(data), &req); err != nil {
+			return errors.Wrap(err, "failed to unmarshal MigrateAddress")
+		}
+
+		return d.migrateAddress(ctx, dev, p, req)
+
+	case ResourceGPOPulse:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get GPOPulse parameter")
+		}
+
+		pulse := gpoPulse{}
+		if err := json.Unmarshal([]byte(data), &pulse); err != nil {
+			return errors.Wrap(err, "failed to unmarshal GPOPulse")
+		}
+
+		return dev.PulseGPO(ctx, pulse.Port, time.Duration(pulse.DurationMillis)*time.Millisecond)
+
+	case ResourceAntennaProperties:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get AntennaProperties parameter")
+		}
+
+		var gains []antennaGain
+		if err := json.Unmarshal([]byte(data), &gains); err != nil {
+			return errors.Wrap(err, "failed to unmarshal AntennaProperties")
+		}
+
+		return dev.SetAntennaProperties(ctx, gains)
+
+	case ResourceReceiverSensitivity:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get ReceiverSensitivity parameter")
+		}
+
+		rx := receiverSensitivity{}
+		if err := json.Unmarshal([]byte(data), &rx); err != nil {
+			return errors.Wrap(err, "failed to unmarshal ReceiverSensitivity")
+		}
+
+		return dev.SetReceiverSensitivity(ctx, rx.AntennaID, rx.Index)
+
+	case ResourceTransmitPower:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get TransmitPower parameter")
+		}
+
+		tx := transmitPower{}
+		if err := json.Unmarshal([]byte(data), &tx); err != nil {
+			return errors.Wrap(err, "failed to unmarshal TransmitPower")
+		}
+
+		_, err = dev.SetTransmitPower(ctx, tx.AntennaID, tx.PowerDBm)
+		return err
+
+	case ResourceVerifiedAccessSpec:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get VerifiedAccessSpec parameter")
+		}
+
+		add := &llrp.AddAccessSpec{}
+		if err := json.Unmarshal([]byte(data), &add.AccessSpec); err != nil {
+			return errors.Wrap(err, "failed to unmarshal request")
+		}
+
+		if err := applyTargetEPC(&add.AccessSpec.AccessCommand, []byte(data)); err != nil {
+			return err
+		}
+		if err := applyStopAfter(&add.AccessSpec, []byte(data)); err != nil {
+			return err
+		}
+		dev.applyTagPasswords(&add.AccessSpec.AccessCommand)
+		return dev.AddVerifiedAccessSpec(ctx, add)
+
+	case ResourceEventHistory:
+		action, err := params[0].StringValue()
+		if err != nil {
+			return err
+		}
+
+		if action != ActionClear {
+			return errors.Errorf("unknown EventHistory action: %q; the only action is %s",
+				action, ActionClear)
+		}
+
+		return dev.ClearEventHistory(ctx)
+
+	case ResourceEventsAndReports:
+		action, err := params[0].StringValue()
+		if err != nil {
+			return err
+		}
+
+		switch action {
+		default:
+			return errors.Errorf("unknown EventsAndReports action: %q", action)
+		case ActionEnable:
+			return dev.EnableReports(ctx)
+		case ActionDisable:
+			return dev.DisableReports(ctx)
+		}
+
+	case ResourceHoldEventsAndReports:
+		hold, err := params[0].BoolValue()
+		if err != nil {
+			return err
+		}
+
+		return dev.SetHoldEventsAndReports(ctx, hold)
+
+	case ResourceReaderConfig:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return err
+		}
 
-	case ResourceReaderConfig:
-		data, err := params[0].StringValue()
+		conf, err := decodeReaderConfig([]byte(data))
 		if err != nil {
 			return err
 		}
 
-		reqData = []byte(data)
-		llrpReq = &llrp.SetReaderConfig{}
+		llrpReq = conf
 		llrpResp = &llrp.SetReaderConfigResponse{}
 	case ResourceROSpec:
 		data, err := params[0].StringValue()
@@ -434,6 +1090,18 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 		llrpReq = &addSpec           // but we want to send AddROSpec, not just ROSpec
 		llrpResp = &llrp.AddROSpecResponse{}
 
+	case ResourceAccessSpec:
+		data, err := params[0].StringValue()
+		if err != nil {
+			return errors.Wrap(err, "unable to get AccessSpec parameter")
+		}
+
+		reqData = []byte(data)
+		addSpec := llrp.AddAccessSpec{}
+		dataTarget = &addSpec.AccessSpec // the incoming data is an AccessSpec, not AddAccessSpec
+		llrpReq = &addSpec               // but we want to send AddAccessSpec, not just AccessSpec
+		llrpResp = &llrp.AddAccessSpecResponse{}
+
 	case ResourceROSpecID:
 		if len(params) != 2 {
 			return errors.Errorf("expected 2 resources for ROSpecID op, but got %d", len(params))
@@ -446,7 +1114,7 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 
 		roID, err := params[0].Uint32Value()
 		if err != nil {
-			return errors.Wrap(err, "failed to get access spec ID")
+			return errors.Wrap(err, "failed to get ROSpec ID")
 		}
 
 		action, err := params[1].StringValue()
@@ -472,11 +1140,13 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 		case ActionDelete:
 			llrpReq = &llrp.DeleteROSpec{ROSpecID: roID}
 			llrpResp = &llrp.DeleteROSpecResponse{}
+		case ActionDeleteVerified:
+			return dev.DeleteROSpecVerified(ctx, roID)
 		}
 
 	case ResourceAccessSpecID:
-		if len(reqs) != 2 {
-			return errors.Errorf("expected 2 resources for AccessSpecID op, but got %d", len(reqs))
+		if len(params) != 2 {
+			return errors.Errorf("expected 2 resources for AccessSpecID op, but got %d", len(params))
 		}
 
 		if params[1].DeviceResourceName != ResourceAction {
@@ -494,9 +1164,12 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 			return err
 		}
 
+		// Unlike ROSpecs, AccessSpecs can't be started or stopped.
 		switch action {
 		default:
-			return errors.Errorf("unknown ROSpecID action: %q", action)
+			return errors.Errorf("unknown AccessSpecID action: %q; "+
+				"AccessSpec actions are %s, %s, or %s",
+				action, ActionEnable, ActionDisable, ActionDelete)
 		case ActionEnable:
 			llrpReq = &llrp.EnableAccessSpec{AccessSpecID: asID}
 			llrpResp = &llrp.EnableAccessSpecResponse{}
@@ -506,6 +1179,7 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 		case ActionDelete:
 			llrpReq = &llrp.DeleteAccessSpec{AccessSpecID: asID}
 			llrpResp = &llrp.DeleteAccessSpecResponse{}
+			dev.forgetVerifiedAccessSpec(asID)
 		}
 	}
 
@@ -521,24 +1195,175 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 		}
 	}
 
+	switch add := llrpReq.(type) {
+	case *llrp.AddAccessSpec:
+		if err := applyTargetEPC(&add.AccessSpec.AccessCommand, reqData); err != nil {
+			return err
+		}
+		if err := applyStopAfter(&add.AccessSpec, reqData); err != nil {
+			return err
+		}
+		dev.applyTagPasswords(&add.AccessSpec.AccessCommand)
+	case *llrp.AddROSpec:
+		if err := applyFilterEPC(&add.ROSpec, reqData); err != nil {
+			return err
+		}
+	}
+
+	if err := dev.checkSupported(ctx, llrpReq); err != nil {
+		return err
+	}
+
 	// SendFor will handle turning ErrorMessages and failing LLRPStatuses into errors.
-	if err := dev.TrySend(ctx, llrpReq, llrpResp); err != nil {
+	if add, ok := llrpReq.(*llrp.AddROSpec); ok {
+		err = dev.AddROSpec(ctx, add, llrpResp.(*llrp.AddROSpecResponse))
+	} else {
+		err = dev.TrySend(ctx, llrpReq, llrpResp)
+	}
+	if err != nil {
 		return err
 	}
 
-	go func(resName, devName string, resp llrp.Incoming) {
-		respData, err := json.Marshal(resp)
+	// The device sends the response per its AsyncOverflow policy, and Stop waits for it.
+	resName := reqs[0].DeviceResourceName
+	dev.goSend(func() {
+		respData, err := d.marshalJSON(llrpResp)
 		if err != nil {
 			d.lc.Error("failed to marshal response", "message", resName, "error", err)
 			return
 		}
 
-		cv := dsModels.NewStringValue(resName, time.Now().UnixNano(), string(respData))
-		d.asyncCh <- &dsModels.AsyncValues{
-			DeviceName:    devName,
+		cv := dsModels.NewStringValue(resName, d.clock().Now().UnixNano(), string(respData))
+		dev.sendAsync(&dsModels.AsyncValues{
+			DeviceName:    dev.name,
 			CommandValues: []*dsModels.CommandValue{cv},
+		})
+	})
+
+	return nil
+}
+
+// decodeReaderConfig decodes JSON data into a SetReaderConfig message.
+//
+// SetReaderConfig is inherently selective: the Reader only changes
+// the sub-parameters present in the message and leaves all others untouched,
+// so we send only the sub-parameters the user specified.
+// We don't read the current config and merge the changes into it:
+// GetReaderConfigResponse includes parameters that can't be set,
+// and writing back the full config would race with other clients
+// and could revert changes made between the read and the write.
+//
+// Since a misspelled sub-parameter would otherwise be silently dropped,
+// this rejects data with fields SetReaderConfig doesn't have.
+func decodeReaderConfig(data []byte) (*llrp.SetReaderConfig, error) {
+	conf := &llrp.SetReaderConfig{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(conf); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal ReaderConfig")
+	}
+	return conf, nil
+}
+
+// readerConfigSelectors names the RequestedData values of GetReaderConfig,
+// for use with AttribRequestedData.
+var readerConfigSelectors = map[string]llrp.ReaderConfigRequestedDataType{
+	"All":                         llrp.ReaderConfReqAll,
+	"Identification":              llrp.ReaderConfReqIdentification,
+	"AntennaProperties":           llrp.ReaderConfReqAntennaProperties,
+	"AntennaConfiguration":        llrp.ReaderConfReqAntennaConfig,
+	"ROReportSpec":                llrp.ReaderConfReqROReportSpec,
+	"ReaderEventNotificationSpec": llrp.ReaderConfReqReaderEventNotifSpec,
+	"AccessReportSpec":            llrp.ReaderConfReqAccessReportSpec,
+	"LLRPConfigurationStateValue": llrp.ReaderConfReqLLRPConfStateVal,
+	"KeepAliveSpec":               llrp.ReaderConfReqKeepAliveSpec,
+	"GPIPortCurrentState":         llrp.ReaderConfReqGPIPortCurState,
+	"GPOWriteData":                llrp.ReaderConfReqGPOWriteData,
+	"EventsAndReports":            llrp.ReaderConfReqEventsAndReports,
+}
+
+// newGetReaderConfig returns a GetReaderConfig requesting the part of the config
+// selected by the resource's AttribRequestedData attribute, or all of it if it's missing.
+// The attribute may be a name from readerConfigSelectors or the LLRP value it names.
+func newGetReaderConfig(attributes map[string]string) (*llrp.GetReaderConfig, error) {
+	selector := attributes[AttribRequestedData]
+	if selector == "" {
+		return &llrp.GetReaderConfig{}, nil
+	}
+
+	if rd, ok := readerConfigSelectors[selector]; ok {
+		return &llrp.GetReaderConfig{RequestedData: rd}, nil
+	}
+
+	rd, err := strconv.ParseUint(selector, 10, 8)
+	if err != nil || rd > uint64(llrp.ReaderConfReqEventsAndReports) {
+		return nil, errors.Errorf("unknown %s %q", AttribRequestedData, selector)
+	}
+	return &llrp.GetReaderConfig{RequestedData: llrp.ReaderConfigRequestedDataType(rd)}, nil
+}
+
+// sendRawMessage sends an arbitrary message type with a hex-encoded payload
+// and sends the hex-encoded response payload to EdgeX.
+//
+// It expects the RawMessage resource first, followed by the RawMessageType.
+// If the Reader responds with an ErrorMessage, this returns its LLRPStatus as an error.
+// Messages without a response type are sent without waiting for a reply.
+func (d *Driver) sendRawMessage(ctx context.Context, dev *LLRPDevice, reqs []dsModels.CommandRequest, params []*dsModels.CommandValue) error {
+	if len(params) != 2 {
+		return errors.Errorf("expected 2 resources for RawMessage op, but got %d", len(params))
+	}
+
+	if params[1].DeviceResourceName != ResourceRawMessageType {
+		return errors.Errorf("expected RawMessageType resource with RawMessage, but got %q",
+			params[1].DeviceResourceName)
+	}
+
+	hexPayload, err := params[0].StringValue()
+	if err != nil {
+		return errors.Wrap(err, "unable to get RawMessage parameter")
+	}
+
+	payload, err := hex.DecodeString(hexPayload)
+	if err != nil {
+		return errors.Wrap(err, "unable to hex decode RawMessage parameter")
+	}
+
+	mt, err := params[1].Uint16Value()
+	if err != nil {
+		return errors.Wrap(err, "failed to get raw message type")
+	}
+
+	// Waiting for a reply to a message that has no response would only time out,
+	// so those are sent without waiting; any asynchronous reply goes to its usual handler.
+	if _, ok := llrp.MessageType(mt).ResponseType(); !ok {
+		m, err := llrp.NewByteMessage(llrp.MessageType(mt), payload)
+		if err != nil {
+			return err
+		}
+		return dev.sendNoWait(ctx, m)
+	}
+
+	// TrySendRaw validates the message type before sending.
+	respType, respData, err := dev.TrySendRaw(ctx, llrp.MessageType(mt), payload)
+	if err != nil {
+		return err
+	}
+
+	if respType == llrp.MsgErrorMessage {
+		em := llrp.ErrorMessage{}
+		if err := em.UnmarshalBinary(respData); err != nil {
+			return errors.Wrap(err, "received an error message, but it failed to unmarshal")
 		}
-	}(reqs[0].DeviceResourceName, dev.name, llrpResp)
+		return errors.Wrapf(em.LLRPStatus.Err(), "reader rejected raw message %v", llrp.MessageType(mt))
+	}
+
+	cv := dsModels.NewStringValue(reqs[0].DeviceResourceName, d.clock().Now().UnixNano(), hex.EncodeToString(respData))
+	dev.goSend(func() {
+		dev.sendAsync(&dsModels.AsyncValues{
+			DeviceName:    dev.name,
+			CommandValues: []*dsModels.CommandValue{cv},
+		})
+	})
 
 	return nil
 }
@@ -548,7 +1373,13 @@ func (d *Driver) handleWriteCommands(devName string, p protocolMap, reqs []dsMod
 //
 // If force is false, the Driver attempts to gracefully shutdown active devices
 // by sending them a CloseConnection message and waiting a short time for their response.
-// If force is true, it immediately closes all active connections.
+// It then waits for reports and event notifications it already received
+// to reach EdgeX, so a clean shutdown doesn't lose them;
+// the whole shutdown is bounded by the shutdownGrace period.
+// If force is true, it drops any reports not yet sent to EdgeX
+// and closes all active connections, waiting at most the forceStopGrace period.
+// In either case, connections that haven't closed by the deadline
+// are still tracked, and a subsequent Initialize waits for them.
 // In neither case does it tell devices to stop reading.
 //
 // EdgeX says DeviceServices should close the async readings channel,
@@ -564,34 +1395,87 @@ func (d *Driver) Stop(force bool) error {
 
 	close(d.done)
 
+	// Don't let a long discovery scan hold up shutdown.
+	discoveryDone := d.cancelDiscovery()
+
+	// Even a forced Stop gives connections a moment to close,
+	// so they don't linger into a subsequent Initialize.
+	grace := shutdownGrace
+	if force {
+		grace = forceStopGrace
+	}
+
+	// Close the sink after the devices stop, so it can publish their final reports.
+	// It gets its own grace period, since the devices may use all of theirs.
+	if d.sink != nil {
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), grace)
+			defer cancel()
+			d.sink.close(ctx)
+		}()
+	}
+
 	d.devicesMu.Lock()
 	defer d.devicesMu.Unlock()
 
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
 
-	var wg *sync.WaitGroup
 	if !force {
-		wg = new(sync.WaitGroup)
-		wg.Add(len(d.activeDevices))
-		defer wg.Wait()
-
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, shutdownGrace)
-		defer cancel()
+		defer func() {
+			select {
+			case <-discoveryDone:
+			case <-ctx.Done():
+				d.lc.Warn("Discovery didn't finish before shutdown.")
+			}
+		}()
 	}
 
+	// Deferred after cancel so it runs first, giving devices the whole grace period.
+	var wg sync.WaitGroup
+	wg.Add(len(d.activeDevices))
+	d.stopping.Add(len(d.activeDevices))
+	defer func() {
+		stopped := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(stopped)
+		}()
+
+		// Any stragglers are left to Initialize to wait for.
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			d.lc.Warn("Some devices didn't finish stopping before shutdown.")
+		}
+	}()
+
 	for _, dev := range d.activeDevices {
+		if force {
+			dev.sends.dropAll()
+		}
+
 		go func(dev *LLRPDevice) {
+			defer d.stopping.Done()
+			defer wg.Done()
+
 			if err := dev.Stop(ctx); err != nil {
 				d.lc.Error("Error attempting client shutdown.", "error", err.Error())
 			}
-			if !force {
-				wg.Done()
+			if force {
+				return
+			}
+
+			if err := dev.drainReports(ctx); err != nil {
+				d.lc.Warn("Dropping reports not sent to EdgeX before shutdown.",
+					"device", dev.name, "error", err.Error())
 			}
 		}(dev)
 	}
 
 	d.activeDevices = make(map[string]*LLRPDevice)
+	d.deviceInits = nil
+	d.params = nil
 	return nil
 }
 
@@ -600,14 +1484,27 @@ func (d *Driver) Stop(force bool) error {
 // The Device Service SDK calls this when a new Device
 // associated with this Device Service is added,
 // so this assumes the device is already registered with EdgeX.
-func (d *Driver) AddDevice(deviceName string, protocols protocolMap, adminState contract.AdminState) error {
+//
+// If the Driver already has a device with this name,
+// EdgeX has re-added it, possibly with different protocols,
+// so this updates the existing device just as UpdateDevice does,
+// rather than keeping a connection to a stale address.
+func (d *Driver) AddDevice(deviceName string, protocols protocolMap, adminState contract.AdminState) (err error) {
 	d.lc.Debug(fmt.Sprintf("Adding new device: %s protocols: %v adminState: %v",
-		deviceName, protocols, adminState))
-	_, _, err := d.getDevice(deviceName, protocols)
-	if err != nil {
-		d.lc.Error("Failed to add device.", "error", err, "deviceName", deviceName)
+		deviceName, maskProtocols(protocols), adminState))
+	defer func() {
+		if err != nil {
+			d.lc.Error("Failed to add device.", "error", err, "deviceName", deviceName)
+		}
+	}()
+
+	dev, isNew, err := d.getDevice(deviceName, protocols)
+	if err != nil || isNew {
+		return err
 	}
-	return err
+
+	d.lc.Info("Device added again; updating its protocols.", "device", deviceName)
+	return d.updateProtocols(dev, protocols)
 }
 
 // UpdateDevice updates a device managed by this Driver.
@@ -621,41 +1518,49 @@ func (d *Driver) AddDevice(deviceName string, protocols protocolMap, adminState
 // If the address is the same, nothing happens.
 func (d *Driver) UpdateDevice(deviceName string, protocols protocolMap, adminState contract.AdminState) (err error) {
 	d.lc.Debug(fmt.Sprintf("Updating device: %s protocols: %v adminState: %v",
-		deviceName, protocols, adminState))
+		deviceName, maskProtocols(protocols), adminState))
 	defer func() {
 		if err != nil {
 			d.lc.Error("Failed to update device.",
 				"error", err, "deviceName", deviceName,
-				"protocolMap", fmt.Sprintf("%v", protocols))
+				"protocolMap", fmt.Sprintf("%v", maskProtocols(protocols)))
 		}
 	}()
 
-	// This uses the shutdownGrace period because updating the address
-	// may require closing a current connection to an existing device.
-	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
-	defer cancel()
-
 	var dev *LLRPDevice
 	var isNew bool
 	dev, isNew, err = d.getDevice(deviceName, protocols)
+
 	// No need to call update if the device was just created.
-	if !(err == nil && isNew) {
+	if err != nil || isNew {
 		return err
 	}
 
-	var addr net.Addr
-	addr, err = getAddr(protocols)
+	return d.updateProtocols(dev, protocols)
+}
+
+// updateProtocols updates an existing device's report options and address
+// from its protocols, reconnecting if the address changed.
+func (d *Driver) updateProtocols(dev *LLRPDevice, protocols protocolMap) error {
+	d.setProtocolOptions(dev, protocols)
+
+	addr, err := getAddr(protocols)
 	if err != nil {
-		return err
+		return errors.WithMessagef(err, "invalid address for device %q", dev.name)
 	}
 
+	// This uses the shutdownGrace period because updating the address
+	// may require closing a current connection to an existing device.
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+
 	return dev.UpdateAddr(ctx, addr)
 }
 
 // RemoveDevice is a callback function that is invoked
 // when a Device associated with this Device Service is removed
 func (d *Driver) RemoveDevice(deviceName string, p protocolMap) error {
-	d.lc.Debug(fmt.Sprintf("Removing device: %s protocols: %v", deviceName, p))
+	d.lc.Debug(fmt.Sprintf("Removing device: %s protocols: %v", deviceName, maskProtocols(p)))
 
 	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
 	defer cancel()
@@ -664,6 +1569,14 @@ func (d *Driver) RemoveDevice(deviceName string, p protocolMap) error {
 	return nil
 }
 
+// deviceInit tracks a device getDevice is creating,
+// so other requests for the same name wait for it instead of creating another.
+type deviceInit struct {
+	done chan struct{} // closed once dev or err is set
+	dev  *LLRPDevice
+	err  error
+}
+
 // getDevice returns an LLRPDevice, creating one if needed.
 //
 // If the Driver is already managing an LLRPDevice with this name,
@@ -672,6 +1585,12 @@ func (d *Driver) RemoveDevice(deviceName string, p protocolMap) error {
 // after adding it to its map of managed devices.
 // If the new LLRPDevice is created as a result of this call,
 // the returned boolean `isNew` will be the true.
+//
+// Only one device exists for any name, and all requests that target it use the same one:
+// if two requests arrive at about the same time and target the same device,
+// one creates it while the other waits for it.
+// Creating a device doesn't hold the Driver's device lock, though,
+// so it doesn't hold up requests for other devices.
 func (d *Driver) getDevice(name string, p protocolMap) (dev *LLRPDevice, isNew bool, err error) {
 	// Try with just a read lock.
 	d.devicesMu.RLock()
@@ -683,47 +1602,174 @@ func (d *Driver) getDevice(name string, p protocolMap) (dev *LLRPDevice, isNew b
 
 	addr, err := getAddr(p)
 	if err != nil {
-		return nil, false, err
+		return nil, false, errors.WithMessagef(err, "invalid address for device %q", name)
 	}
 
-	// It's important it holds the lock while creating a device.
-	// If two requests arrive at about the same time and target the same device,
-	// one will block waiting for the lock and the other will create/add it.
-	// When gaining the lock, we recheck the map.
-	// This way, only one device exists for any name,
-	// and all requests that target it use the same one.
+	// Recheck the map, then either wait for the device or claim its creation.
 	d.devicesMu.Lock()
-	defer d.devicesMu.Unlock()
-
-	dev, ok = d.activeDevices[name]
-	if ok {
+	if dev, ok := d.activeDevices[name]; ok {
+		d.devicesMu.Unlock()
 		return dev, false, nil
 	}
 
+	if init, ok := d.deviceInits[name]; ok {
+		d.devicesMu.Unlock()
+		<-init.done
+		return init.dev, false, init.err
+	}
+
+	init := &deviceInit{done: make(chan struct{})}
+	if d.deviceInits == nil {
+		d.deviceInits = make(map[string]*deviceInit)
+	}
+	d.deviceInits[name] = init
+	d.devicesMu.Unlock()
+	defer close(init.done)
+
 	d.lc.Info("Creating new connection for device.", "device", name)
-	dev = d.NewLLRPDevice(name, addr, contract.Enabled)
-	d.activeDevices[name] = dev
+	newDevice := d.newDevice
+	if newDevice == nil {
+		newDevice = d.NewLLRPDevice
+	}
+	dev = newDevice(name, addr, contract.Enabled)
+	d.setProtocolOptions(dev, p)
+
+	d.devicesMu.Lock()
+	// Stop and removeDevice drop the claim if they run while the device is being created.
+	claimed := d.deviceInits[name] == init
+	if claimed {
+		delete(d.deviceInits, name)
+		d.activeDevices[name] = dev
+	}
+	d.devicesMu.Unlock()
+
+	if !claimed {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		if err := dev.Stop(ctx); err != nil {
+			d.lc.Error("Error attempting client shutdown.", "error", err.Error())
+		}
+		init.err = errors.Errorf("device %q was removed while its connection was being created", name)
+		return nil, false, init.err
+	}
+
+	init.dev = dev
 	return dev, true, nil
 }
 
+// setProtocolOptions updates the device's antenna locations, report options,
+// command ordering, reprovisioning, ROSpec replacement, unknown parameter handling,
+// command allow and deny lists, and tag passwords from its protocols,
+// logging (but otherwise ignoring) any that are invalid.
+func (d *Driver) setProtocolOptions(dev *LLRPDevice, protocols protocolMap) {
+	locations, err := getAntennaLocations(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid antenna locations.", "device", dev.name, "error", err.Error())
+	}
+
+	flat, err := getFlatReports(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid report format.", "device", dev.name, "error", err.Error())
+	}
+
+	reportPort, err := getReportPort(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid report port.", "device", dev.name, "error", err.Error())
+	}
+
+	ordered, err := getOrderedCommands(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid command ordering.", "device", dev.name, "error", err.Error())
+	}
+
+	reprovision, err := getReprovision(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid reprovisioning option.", "device", dev.name, "error", err.Error())
+	}
+
+	replaceROSpecs, err := getReplaceROSpecs(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid ROSpec replacement option.", "device", dev.name, "error", err.Error())
+	}
+
+	slowThreshold, err := getSlowThreshold(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid slow command threshold.", "device", dev.name, "error", err.Error())
+	}
+
+	unknownParams, err := getUnknownParams(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid unknown parameter handling.", "device", dev.name, "error", err.Error())
+	}
+
+	policy, err := getCommandPolicy(protocols)
+	if err != nil {
+		d.lc.Warn("Denying all commands due to an invalid allow or deny list.",
+			"device", dev.name, "error", err.Error())
+	}
+
+	passwords, err := getTagPasswords(protocols)
+	if err != nil {
+		d.lc.Warn("Ignoring invalid tag passwords.", "device", dev.name, "error", err.Error())
+	}
+
+	dev.deviceMu.Lock()
+	dev.antennaLocations = locations
+	dev.flatReports = flat
+	dev.orderedCommands = ordered
+	dev.reprovisionSpecs = reprovision
+	dev.replaceROSpecs = replaceROSpecs
+	dev.slowThreshold = slowThreshold
+	dev.unknownParams = unknownParams
+	dev.commandPolicy = policy
+	dev.tagPasswords = passwords
+	dev.deviceMu.Unlock()
+
+	dev.setReportPort(reportPort)
+}
+
 // removeDevice deletes a device from the active devices map
 // and shuts down its client connection to an LLRP device.
-func (d *Driver) removeDevice(ctx context.Context, deviceName string) {
+// It returns false if the device wasn't in the map.
+func (d *Driver) removeDevice(ctx context.Context, deviceName string) bool {
+	return d.removeDeviceIf(ctx, deviceName, nil)
+}
+
+// removeDeviceIf works like removeDevice, but if expected isn't nil,
+// it only removes the device if it's still the one in the map under that name,
+// so a device whose management stopped doesn't remove one added since with the same name.
+//
+// The device is stopped after it's removed from the map and the lock is released,
+// so other devices aren't held up while it closes its connection.
+func (d *Driver) removeDeviceIf(ctx context.Context, deviceName string, expected *LLRPDevice) bool {
 	d.devicesMu.Lock()
-	defer d.devicesMu.Unlock()
+	if expected == nil {
+		delete(d.deviceInits, deviceName)
+	}
 
-	if dev, ok := d.activeDevices[deviceName]; ok {
-		d.lc.Info("Stopping connection for device.", "device", deviceName)
-		if err := dev.Stop(ctx); err != nil {
-			d.lc.Error("Error attempting client shutdown.", "error", err.Error())
-		}
-		delete(d.activeDevices, deviceName)
+	dev, ok := d.activeDevices[deviceName]
+	if !ok || (expected != nil && dev != expected) {
+		d.devicesMu.Unlock()
+		return false
+	}
+
+	delete(d.activeDevices, deviceName)
+	// A subsequent Initialize waits for it to finish closing its connection.
+	d.stopping.Add(1)
+	d.devicesMu.Unlock()
+	defer d.stopping.Done()
+
+	d.lc.Info("Stopping connection for device.", "device", deviceName)
+	if err := dev.Stop(ctx); err != nil {
+		d.lc.Error("Error attempting client shutdown.", "error", err.Error())
 	}
+	return true
 }
 
 // getAddr extracts an address from a protocol mapping.
 //
-// It expects the map to have {"tcp": {"host": "<ip>", "port": "<port>"}}.
+// It expects the map to have {"tcp": {"host": "<ip>", "port": "<port>"}},
+// where the port is an integer from 1 to 65535.
 func getAddr(protocols protocolMap) (net.Addr, error) {
 	if protocols == nil {
 		return nil, errors.New("protocol map is nil")
@@ -739,11 +1785,140 @@ func getAddr(protocols protocolMap) (net.Addr, error) {
 		return nil, errors.Errorf("tcp missing host or port (%q, %q)", host, port)
 	}
 
+	if err := checkPort(port); err != nil {
+		return nil, errors.WithMessage(err, "invalid tcp port")
+	}
+
 	addr, err := net.ResolveTCPAddr("tcp", host+":"+port)
 	return addr, errors.Wrapf(err,
 		"unable to create addr for tcp protocol (%q, %q)", host, port)
 }
 
+// getAntennaLocations returns the antenna ID to location label mapping
+// from the location protocol, or nil if there isn't one.
+//
+// If some antenna IDs aren't valid, it still returns the valid ones,
+// along with an error describing the invalid ones.
+func getAntennaLocations(protocols protocolMap) (map[llrp.AntennaID]string, error) {
+	locInfo := protocols[ProtocolLocation]
+	if len(locInfo) == 0 {
+		return nil, nil
+	}
+
+	locations := make(map[llrp.AntennaID]string, len(locInfo))
+	var invalid []string
+	for antenna, label := range locInfo {
+		id, err := strconv.ParseUint(antenna, 10, 16)
+		if err != nil || id == 0 {
+			invalid = append(invalid, antenna)
+			continue
+		}
+		locations[llrp.AntennaID(id)] = label
+	}
+
+	if len(invalid) != 0 {
+		return locations, errors.Errorf("invalid antenna IDs in %s protocol: %q",
+			ProtocolLocation, invalid)
+	}
+	return locations, nil
+}
+
+// getFlatReports returns true if the report protocol's format is ReportFormatFlat,
+// or false if it's ReportFormatJSON or missing.
+// If the format is something else, it returns false and an error.
+func getFlatReports(protocols protocolMap) (bool, error) {
+	switch format := protocols[ProtocolReport]["format"]; format {
+	case "", ReportFormatJSON:
+		return false, nil
+	case ReportFormatFlat:
+		return true, nil
+	default:
+		return false, errors.Errorf("unknown %s format %q; formats are %s or %s",
+			ProtocolReport, format, ReportFormatJSON, ReportFormatFlat)
+	}
+}
+
+// getReportPort returns the report protocol's port, or an empty string if it's missing.
+func getReportPort(protocols protocolMap) (string, error) {
+	port := protocols[ProtocolReport]["port"]
+	if port == "" {
+		return "", nil
+	}
+
+	if err := checkPort(port); err != nil {
+		return "", errors.WithMessagef(err, "invalid %s port", ProtocolReport)
+	}
+	return port, nil
+}
+
+// getOrderedCommands returns true if the commands protocol's ordered property is true.
+func getOrderedCommands(protocols protocolMap) (bool, error) {
+	ordered := protocols[ProtocolCommands]["ordered"]
+	if ordered == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(ordered)
+	if err != nil {
+		return false, errors.Errorf("%s ordered must be true or false, but is %q",
+			ProtocolCommands, ordered)
+	}
+	return b, nil
+}
+
+// getReprovision returns true if the commands protocol's reprovision property is true.
+func getReprovision(protocols protocolMap) (bool, error) {
+	reprovision := protocols[ProtocolCommands]["reprovision"]
+	if reprovision == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(reprovision)
+	if err != nil {
+		return false, errors.Errorf("%s reprovision must be true or false, but is %q",
+			ProtocolCommands, reprovision)
+	}
+	return b, nil
+}
+
+// getReplaceROSpecs returns true if the commands protocol's replaceROSpecs property is true.
+func getReplaceROSpecs(protocols protocolMap) (bool, error) {
+	replace := protocols[ProtocolCommands]["replaceROSpecs"]
+	if replace == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(replace)
+	if err != nil {
+		return false, errors.Errorf("%s replaceROSpecs must be true or false, but is %q",
+			ProtocolCommands, replace)
+	}
+	return b, nil
+}
+
+// getUnknownParams returns the decode protocol's unknownParams property,
+// or UnknownParamsStrict if it's missing.
+// If it's something else, it returns UnknownParamsStrict and an error.
+func getUnknownParams(protocols protocolMap) (string, error) {
+	switch policy := protocols[ProtocolDecode]["unknownParams"]; policy {
+	case "", UnknownParamsStrict:
+		return UnknownParamsStrict, nil
+	case UnknownParamsLenient, UnknownParamsPreserve:
+		return policy, nil
+	default:
+		return UnknownParamsStrict, errors.Errorf("unknown %s unknownParams %q; options are %s, %s, or %s",
+			ProtocolDecode, policy, UnknownParamsStrict, UnknownParamsLenient, UnknownParamsPreserve)
+	}
+}
+
+// checkPort returns an error if the port isn't an integer from 1 to 65535.
+func checkPort(port string) error {
+	if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+		return errors.Errorf("port must be an integer from 1 to 65535, but is %q", port)
+	}
+	return nil
+}
+
 func (d *Driver) addProvisionWatchers() error {
 	files, err := ioutil.ReadDir(provisionWatcherFolder)
 	if err != nil {
@@ -784,14 +1959,21 @@ func (d *Driver) addProvisionWatchers() error {
 	return nil
 }
 
-// Discover performs a discovery of LLRP readers on the network and passes them to EdgeX to get provisioned
+// Discover performs a discovery of LLRP readers on the network and passes them to EdgeX to get provisioned.
+// Starting a discovery cancels one already in progress, as does stopping the driver.
 func (d *Driver) Discover() {
 	d.lc.Info("Discover was called.")
 
 	d.configMu.RLock()
-	maxSeconds := driver.config.MaxDiscoverDurationSeconds
+	maxSeconds := d.config.MaxDiscoverDurationSeconds
 	d.configMu.RUnlock()
 
+	if d.skipDiscovery() {
+		// The SDK waits for this to know the discovery is done.
+		d.deviceCh <- nil
+		return
+	}
+
 	if registerProvisionWatchers {
 		d.watchersMu.Lock()
 		if !d.addedWatchers {
@@ -808,17 +1990,71 @@ func (d *Driver) Discover() {
 		d.watchersMu.Unlock()
 	}
 
-	ctx := context.Background()
-	if maxSeconds > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(context.Background(),
-			time.Duration(maxSeconds)*time.Second)
-		defer cancel()
-	}
+	ctx, finish := d.startDiscovery(time.Duration(maxSeconds) * time.Second)
+	defer finish()
 
 	d.discover(ctx)
 }
 
+// startDiscovery cancels the discovery in progress, if any, and waits for it to finish,
+// then returns the context for a new one, which is canceled after the timeout (if non-zero),
+// when the driver stops, or when another discovery starts.
+// The caller must call finish when its discovery is done.
+func (d *Driver) startDiscovery(timeout time.Duration) (ctx context.Context, finish func()) {
+	d.discoverMu.Lock()
+	defer d.discoverMu.Unlock()
+
+	if d.discoverCancel != nil {
+		d.lc.Info("Canceling the discovery in progress to start a new one.")
+		d.discoverCancel()
+		<-d.discoverDone
+	}
+
+	ctx = context.Background()
+	cancelTimeout := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+
+	select {
+	case <-d.done:
+		cancel() // the driver is stopping, so don't bother
+	default:
+	}
+
+	done := make(chan struct{})
+	d.discoverCancel, d.discoverDone = cancel, done
+
+	return ctx, func() {
+		cancel()
+		cancelTimeout()
+		close(done)
+
+		d.discoverMu.Lock()
+		if d.discoverDone == done {
+			d.discoverCancel, d.discoverDone = nil, nil
+		}
+		d.discoverMu.Unlock()
+	}
+}
+
+// cancelDiscovery cancels the discovery in progress, if any,
+// and returns a channel closed when it finishes.
+func (d *Driver) cancelDiscovery() <-chan struct{} {
+	d.discoverMu.Lock()
+	defer d.discoverMu.Unlock()
+
+	if d.discoverCancel == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+
+	d.discoverCancel()
+	return d.discoverDone
+}
+
 func (d *Driver) discover(ctx context.Context) {
 	d.configMu.RLock()
 	params := discoverParams{
@@ -828,27 +2064,49 @@ func (d *Driver) discover(ctx context.Context) {
 		timeout:    time.Duration(d.config.ProbeTimeoutSeconds) * time.Second,
 		scanPort:   d.config.ScanPort,
 	}
+	missingPolicy := d.config.MissingReaders
+	maxBackoff := d.config.MaxDiscoveryBackoff
 	d.configMu.RUnlock()
 
-	t1 := time.Now()
-	result := autoDiscover(ctx, params)
-	if ctx.Err() != nil {
+	before := d.svc.Devices()
+	params.disconnected = d.disconnectedDevices()
+	t1 := d.clock().Now()
+	result, found := autoDiscover(ctx, params)
+	complete := ctx.Err() == nil
+	if !complete {
 		d.lc.Warn("Discover process has been cancelled!", "ctxErr", ctx.Err())
 	}
 
 	// Note: We have to send data over this channel to let the SDK know we are done discovering.
 	// see: https://github.com/edgexfoundry/device-sdk-go/issues/609
 	d.deviceCh <- nil
-	d.lc.Info(fmt.Sprintf("Discovered %d new devices in %v.", len(result), time.Now().Sub(t1)))
+
+	// A discovery that reaches its time limit registers what it found,
+	// but one canceled by a new discovery or by stopping the driver doesn't:
+	// the new discovery will find the same Readers, and a stopping driver shouldn't add devices.
+	if errors.Is(ctx.Err(), context.Canceled) {
+		d.lc.Info(fmt.Sprintf("Discarding %d new devices found by the canceled discovery.", len(result)))
+		return
+	}
+	d.lc.Info(fmt.Sprintf("Discovered %d new devices in %v.", len(result), d.clock().Now().Sub(t1)))
 
 	// Note: For now we have to resort to adding our discovered devices ourselves due to multiple bugs in the
 	// provision watcher code, as well as no clear way to tell if a device was matched by a PW or not.
 	// see: https://github.com/edgexfoundry/device-sdk-go/issues/598
 	// see also: https://github.com/edgexfoundry/device-sdk-go/issues/606
+	added := make([]dsModels.DiscoveredDevice, 0, len(result))
 	for _, discovered := range result {
 		if _, err := d.registerDevice(discovered); err != nil {
 			d.lc.Error("Error adding device.", "name", discovered.Name, "error", err)
+			continue
 		}
+		added = append(added, discovered)
+	}
+
+	// A discovery that reached its time limit may not have scanned a missing Reader's address.
+	if complete {
+		changed := d.mergeDiscovery(before, added, found, params, missingPolicy)
+		d.backOffDiscovery(changed, maxBackoff)
 	}
 }
 
@@ -866,7 +2124,7 @@ func (d *Driver) registerDevice(discovered dsModels.DiscoveredDevice) (id string
 	return d.svc.AddDevice(contract.Device{
 		DescribedObject: contract.DescribedObject{
 			Timestamps: contract.Timestamps{
-				Origin: time.Now().UnixNano(),
+				Origin: d.clock().Now().UnixNano(),
 			},
 			Description: discovered.Description,
 		},
@@ -6,12 +6,23 @@
 package driver
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
-	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
-	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
 )
 
 func TestGetTCPAddr(t *testing.T) {
@@ -34,11 +45,23 @@ func TestGetTCPAddr(t *testing.T) {
 
 	t.Run("invalid", func(t *testing.T) {
 		t.Parallel()
-		for _, m := range []protocolMap{{
-			"tcp": {"host": "127.0.0.1", "port": "86492"},
-		}} {
-			if _, err := getAddr(m); err == nil {
-				t.Error("expected an error, but didn't get one")
+		for _, testCase := range []struct {
+			port     string
+			contains string
+		}{
+			{port: "", contains: "missing host or port"},
+			{port: "llrp", contains: `"llrp"`},
+			{port: "0", contains: `"0"`},
+			{port: "-1", contains: `"-1"`},
+			{port: "65536", contains: `"65536"`},
+			{port: "86492", contains: `"86492"`},
+		} {
+			_, err := getAddr(protocolMap{"tcp": {"host": "127.0.0.1", "port": testCase.port}})
+			if err == nil {
+				t.Errorf("port %q: expected an error, but didn't get one", testCase.port)
+			} else if !strings.Contains(err.Error(), testCase.contains) {
+				t.Errorf("port %q: expected an error containing %s; got %v",
+					testCase.port, testCase.contains, err)
 			}
 		}
 	})
@@ -108,17 +131,22 @@ func TestHandleRead(t *testing.T) {
 		name:   "localReader",
 		client: c,
 		lc:     elog,
+		reads:  newTagReadCache(10),
 	}
 
 	for _, testCase := range []struct {
 		name    string
-		target  llrp.Incoming
+		target  interface{}
 		attribs map[string]string
 	}{
 		{name: ResourceReaderCap, target: &llrp.GetReaderCapabilitiesResponse{}},
 		{name: ResourceReaderConfig, target: &llrp.GetReaderConfigResponse{}},
+		{name: "ReaderKeepAliveSpec", target: &llrp.GetReaderConfigResponse{},
+			attribs: map[string]string{AttribRequestedData: "KeepAliveSpec"}},
 		{name: ResourceROSpec, target: &llrp.GetROSpecsResponse{}},
 		{name: ResourceAccessSpec, target: &llrp.GetAccessSpecsResponse{}},
+		{name: ResourceROAccessReport, target: &llrp.ROAccessReport{}},
+		{name: ResourceRFSurvey, target: &[]llrp.RFSurveyReportData{}},
 	} {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
@@ -167,8 +195,21 @@ func TestHandleWrite(t *testing.T) {
 	}
 
 	rfid.SetResponse(llrp.MsgSetReaderConfig, &llrp.SetReaderConfigResponse{})
+	rfid.SetResponse(llrp.MsgGetReaderConfig, &llrp.GetReaderConfigResponse{
+		ROReportSpec: &llrp.ROReportSpec{Trigger: llrp.NTagsOrROEnd, N: 1},
+	})
+	rfid.SetResponse(llrp.MsgGetReaderCapabilities, &llrp.GetReaderCapabilitiesResponse{
+		LLRPCapabilities: &llrp.LLRPCapabilities{CanDoRFSurvey: true},
+	})
+	rfid.SetResponse(llrp.MsgAddROSpec, &llrp.AddROSpecResponse{})
+	rfid.SetResponse(llrp.MsgEnableROSpec, &llrp.EnableROSpecResponse{})
+	rfid.SetResponse(llrp.MsgDeleteROSpec, &llrp.DeleteROSpecResponse{})
 	rfid.SetResponse(llrp.MsgStartROSpec, &llrp.StartROSpecResponse{})
+	rfid.SetResponse(llrp.MsgAddAccessSpec, &llrp.AddAccessSpecResponse{})
 	rfid.SetResponse(llrp.MsgEnableAccessSpec, &llrp.EnableAccessSpecResponse{})
+	rfid.SetResponse(llrp.MsgDisableAccessSpec, &llrp.DisableAccessSpecResponse{})
+	rfid.SetResponse(llrp.MsgDeleteAccessSpec, &llrp.DeleteAccessSpecResponse{})
+	rfid.SetResponse(llrp.MsgGetROSpecs, &llrp.GetROSpecsResponse{})
 	rfid.SetResponse(llrp.MsgCustomMessage, &llrp.CustomMessage{
 		VendorID:       1234,
 		MessageSubtype: 22,
@@ -205,6 +246,16 @@ func TestHandleWrite(t *testing.T) {
 	customData := base64.StdEncoding.EncodeToString([]byte{0, 0, 0, 0})
 	t.Logf("%s", customData)
 
+	rawMsgType, err := dsModels.NewUint16Value(ResourceRawMessageType, 0, uint16(llrp.MsgGetROSpecs))
+	if err != nil {
+		t.Fatalf("failed to make RawMessageType: %+v", err)
+	}
+
+	rawAckType, err := dsModels.NewUint16Value(ResourceRawMessageType, 0, uint16(llrp.MsgKeepAliveAck))
+	if err != nil {
+		t.Fatalf("failed to make RawMessageType: %+v", err)
+	}
+
 	for _, testCase := range []struct {
 		name    string
 		reqs    []dsModels.CommandRequest
@@ -235,6 +286,20 @@ func TestHandleWrite(t *testing.T) {
 				dsModels.NewStringValue(ResourceAction, 0, ActionStart),
 			},
 		},
+		{
+			name: "DeleteVerifiedROSpec1",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceROSpecID,
+				Type:               dsModels.String,
+			}, {
+				DeviceResourceName: ResourceAction,
+				Type:               dsModels.String,
+			}},
+			param: []*dsModels.CommandValue{
+				roSpecID,
+				dsModels.NewStringValue(ResourceAction, 0, ActionDeleteVerified),
+			},
+		},
 		{
 			name: "EnableAccessSpec2",
 			reqs: []dsModels.CommandRequest{{
@@ -248,6 +313,32 @@ func TestHandleWrite(t *testing.T) {
 				accessSpecID,
 				dsModels.NewStringValue(ResourceAction, 0, ActionEnable),
 			}},
+		{
+			name: "DisableAccessSpec2",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceAccessSpecID,
+				Type:               dsModels.String,
+			}, {
+				DeviceResourceName: ResourceAction,
+				Type:               dsModels.String,
+			}},
+			param: []*dsModels.CommandValue{
+				accessSpecID,
+				dsModels.NewStringValue(ResourceAction, 0, ActionDisable),
+			}},
+		{
+			name: "DeleteAccessSpec2",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceAccessSpecID,
+				Type:               dsModels.String,
+			}, {
+				DeviceResourceName: ResourceAction,
+				Type:               dsModels.String,
+			}},
+			param: []*dsModels.CommandValue{
+				accessSpecID,
+				dsModels.NewStringValue(ResourceAction, 0, ActionDelete),
+			}},
 		{
 			name: "CustomMessage",
 			reqs: []dsModels.CommandRequest{{
@@ -262,6 +353,90 @@ func TestHandleWrite(t *testing.T) {
 				dsModels.NewStringValue("MyCustomMessage", 0, customData),
 			},
 		},
+		{
+			name: "RawMessage",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceRawMessage,
+				Type:               dsModels.String,
+			}, {
+				DeviceResourceName: ResourceRawMessageType,
+				Type:               dsModels.Uint16,
+			}},
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceRawMessage, 0, ""),
+				rawMsgType,
+			},
+		},
+		{
+			// KeepAliveAck has no response, so this doesn't wait for one.
+			name: "RawMessageNoResponse",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceRawMessage,
+				Type:               dsModels.String,
+			}, {
+				DeviceResourceName: ResourceRawMessageType,
+				Type:               dsModels.Uint16,
+			}},
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceRawMessage, 0, ""),
+				rawAckType,
+			},
+		},
+		{
+			name: "DisableEventsAndReports",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceEventsAndReports,
+				Type:               dsModels.String,
+			}},
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceEventsAndReports, 0, ActionDisable),
+			},
+		},
+		{
+			name: "EnableEventsAndReports",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceEventsAndReports,
+				Type:               dsModels.String,
+			}},
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceEventsAndReports, 0, ActionEnable),
+			},
+		},
+		{
+			name: "AddAccessSpec",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceAccessSpec,
+				Type:               dsModels.String,
+			}},
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceAccessSpec, 0,
+					`{"AccessSpecID":2,"ROSpecID":1,"AccessReportSpec":1}`),
+			},
+		},
+		{
+			name: "DwellROSpec",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceDwellROSpec,
+				Type:               dsModels.String,
+			}},
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceDwellROSpec, 0,
+					`{"ROSpec":{"ROSpecID":2},"AntennaDwell":[`+
+						`{"AntennaID":1,"DwellTime":500},{"AntennaID":2,"InventoryRounds":3}]}`),
+			},
+		},
+		{
+			name: "RFSurvey",
+			reqs: []dsModels.CommandRequest{{
+				DeviceResourceName: ResourceRFSurvey,
+				Type:               dsModels.String,
+			}},
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceRFSurvey, 0,
+					`{"AntennaID":1,"StartFrequency":902750,"EndFrequency":927250,`+
+						`"Trigger":{"Trigger":1,"Duration":500}}`),
+			},
+		},
 	} {
 		testCase := testCase
 		t.Run(testCase.name, func(t *testing.T) {
@@ -276,3 +451,1204 @@ func TestHandleWrite(t *testing.T) {
 		})
 	}
 }
+
+// newLocalDriver returns a Driver without an EdgeX service that manages the devices.
+// Devices without a name are named "localReader",
+// and those without a logger share the Driver's.
+func newLocalDriver(t *testing.T, devices ...*LLRPDevice) *Driver {
+	d := &Driver{
+		lc:            edgexCompatTestLogger{t},
+		activeDevices: make(map[string]*LLRPDevice, len(devices)),
+	}
+	for _, dev := range devices {
+		if dev.name == "" {
+			dev.name = "localReader"
+		}
+		if dev.lc == nil {
+			dev.lc = d.lc
+		}
+		d.activeDevices[dev.name] = dev
+	}
+	return d
+}
+
+func TestHandleWrite_Invalid(t *testing.T) {
+	// The client is never connected, so if validation fails to reject these,
+	// the send blocks until the test times out.
+	d := newLocalDriver(t, &LLRPDevice{client: llrp.NewClient(llrp.WithLogger(nil))})
+
+	reservedType, err := dsModels.NewUint16Value(ResourceRawMessageType, 0, 900)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessSpecID, err := dsModels.NewUint32Value(ResourceAccessSpecID, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		param    []*dsModels.CommandValue
+		contains string // if set, the error message must contain it
+	}{
+		{
+			name:     "startAccessSpec",
+			contains: "AccessSpecID",
+			param: []*dsModels.CommandValue{
+				accessSpecID,
+				dsModels.NewStringValue(ResourceAction, 0, ActionStart),
+			},
+		},
+		{
+			name:     "stopAccessSpec",
+			contains: "AccessSpecID",
+			param: []*dsModels.CommandValue{
+				accessSpecID,
+				dsModels.NewStringValue(ResourceAction, 0, ActionStop),
+			},
+		},
+		{
+			name:     "unknownAccessSpecAction",
+			contains: "AccessSpecID",
+			param: []*dsModels.CommandValue{
+				accessSpecID,
+				dsModels.NewStringValue(ResourceAction, 0, "Pause"),
+			},
+		},
+		{
+			name:     "accessSpecMissingAction",
+			contains: "AccessSpecID",
+			param: []*dsModels.CommandValue{
+				accessSpecID,
+			},
+		},
+		{
+			name: "unknownEventsAndReportsAction",
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceEventsAndReports, 0, ActionStart),
+			},
+		},
+		{
+			name:     "dwellROSpecNoDwell",
+			contains: "AntennaDwell",
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceDwellROSpec, 0, `{"ROSpec":{"ROSpecID":2}}`),
+			},
+		},
+		{
+			name:     "accessSpecBadReportTrigger",
+			contains: "AccessReportSpec",
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceAccessSpec, 0,
+					`{"AccessSpecID":1,"AccessReportSpec":2}`),
+			},
+		},
+		{
+			name:     "rfSurveyNoStopTrigger",
+			contains: "stop trigger",
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceRFSurvey, 0, `{"AntennaID":1}`),
+			},
+		},
+		{
+			name: "reservedType",
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceRawMessage, 0, "00"),
+				reservedType,
+			},
+		},
+		{
+			name: "badHex",
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceRawMessage, 0, "not hex"),
+				reservedType,
+			},
+		},
+		{
+			name: "missingType",
+			param: []*dsModels.CommandValue{
+				dsModels.NewStringValue(ResourceRawMessage, 0, "00"),
+				dsModels.NewStringValue(ResourceAction, 0, ActionEnable),
+			},
+		},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			reqs := make([]dsModels.CommandRequest, len(testCase.param))
+			for i, p := range testCase.param {
+				reqs[i] = dsModels.CommandRequest{DeviceResourceName: p.DeviceResourceName, Type: p.Type}
+			}
+
+			err := d.HandleWriteCommands("localReader", protocolMap{}, reqs, testCase.param)
+			if err == nil {
+				t.Fatal("expected an error, but didn't get one")
+			}
+
+			if !strings.Contains(err.Error(), testCase.contains) {
+				t.Errorf("expected error containing %q; got %q", testCase.contains, err.Error())
+			}
+		})
+	}
+}
+
+func TestLLRPDevice_waitWhileIdle(t *testing.T) {
+	l := &LLRPDevice{
+		name: "idleReader",
+		lc:   edgexCompatTestLogger{t},
+		idle: true,
+		wake: make(chan struct{}, 1),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	errs := make(chan error, 1)
+	go func() { errs <- l.waitWhileIdle(ctx) }()
+
+	select {
+	case err := <-errs:
+		t.Fatalf("returned while idle: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	l.markActive()
+	if err := <-errs; err != nil {
+		t.Fatalf("expected nil after markActive, but got %v", err)
+	}
+
+	// a canceled context should release the waiter even if still idle
+	l.idle = true
+	cancel()
+	if err := l.waitWhileIdle(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, but got %v", err)
+	}
+}
+
+// freePort returns a TCP port that's available to listen on.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// startEmulator starts an emulated Reader on a free port and returns it and the port.
+// The Reader accepts SetReaderConfig, and its ReaderConfig's Identification
+// has the readerID, so tests can tell emulators apart. It's shut down when the test ends.
+func startEmulator(t *testing.T, readerID byte) (*llrp.TestEmulator, int) {
+	t.Helper()
+	port := freePort(t)
+	emu := llrp.NewTestEmulator(!testing.Verbose())
+	if err := emu.StartAsync(port); err != nil {
+		t.Fatalf("unable to start emulator: %+v", err)
+	}
+	t.Cleanup(func() {
+		if err := emu.Shutdown(); err != nil {
+			t.Errorf("error shutting down test emulator: %+v", err)
+		}
+	})
+
+	emu.SetResponse(llrp.MsgSetReaderConfig, &llrp.SetReaderConfigResponse{})
+	emu.SetResponse(llrp.MsgGetReaderConfig, &llrp.GetReaderConfigResponse{
+		Identification: &llrp.Identification{
+			IDType:   llrp.ID_MAC_EUI64,
+			ReaderID: []byte{readerID},
+		},
+	})
+	return emu, port
+}
+
+// newTestDriver returns a Driver using the mock SDK service with no devices.
+// It passes each of its async values to handle, or discards them if handle is nil.
+func newTestDriver(handle func(av *dsModels.AsyncValues)) *Driver {
+	// Event handlers may still be sending after the test ends, so this is never closed.
+	asyncCh := make(chan *dsModels.AsyncValues)
+	go func() {
+		for av := range asyncCh {
+			if handle != nil {
+				handle(av)
+			}
+		}
+	}()
+
+	return &Driver{
+		lc:            driver.lc,
+		svc:           svc,
+		asyncCh:       asyncCh,
+		activeDevices: make(map[string]*LLRPDevice),
+	}
+}
+
+func TestLLRPDevice_UpdateAddrConcurrent(t *testing.T) {
+	// Start two emulated Readers we can tell apart by their ReaderID.
+	addrs := make([]net.Addr, 2)
+	for i := range addrs {
+		_, port := startEmulator(t, byte(i))
+		addrs[i] = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}
+	}
+
+	d := newTestDriver(nil)
+
+	dev := d.NewLLRPDevice("updatedReader", addrs[0], contract.Enabled)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		_ = dev.Stop(ctx)
+	}()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(addr net.Addr) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			// Errors are expected when a Shutdown races a connection attempt.
+			_ = dev.UpdateAddr(ctx, addr)
+		}(addrs[i%2])
+	}
+	wg.Wait()
+
+	dev.deviceMu.RLock()
+	final := dev.address
+	dev.deviceMu.RUnlock()
+
+	want := byte(0)
+	if sameAddr(final, addrs[1]) {
+		want = 1
+	}
+
+	// The Reader management goroutine replaces the client asynchronously,
+	// so allow a few rounds for it to reconnect.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conf := &llrp.GetReaderConfigResponse{}
+	if err := retry.Quick.RetryWithCtx(ctx, retry.Forever, func(ctx context.Context) (bool, error) {
+		err := dev.TrySend(ctx, &llrp.GetReaderConfig{}, conf)
+		return err != nil, err
+	}); err != nil {
+		t.Fatalf("failed to reach Reader after updates: %+v", err)
+	}
+
+	if conf.Identification == nil || !bytes.Equal(conf.Identification.ReaderID, []byte{want}) {
+		t.Errorf("expected to be connected to Reader %d at %v; got %+v", want, final, conf.Identification)
+	}
+}
+
+func TestDriver_AddDeviceAgain(t *testing.T) {
+	// Start two emulated Readers we can tell apart by their ReaderID.
+	ports := make([]string, 2)
+	for i := range ports {
+		_, port := startEmulator(t, byte(i))
+		ports[i] = strconv.Itoa(port)
+	}
+
+	d := newTestDriver(nil)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		d.removeDevice(ctx, "readdedReader")
+	}()
+
+	// connectedTo returns the ReaderID of the Reader the device reaches.
+	connectedTo := func(dev *LLRPDevice) []byte {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		conf := &llrp.GetReaderConfigResponse{}
+		if err := retry.Quick.RetryWithCtx(ctx, retry.Forever, func(ctx context.Context) (bool, error) {
+			err := dev.TrySend(ctx, &llrp.GetReaderConfig{}, conf)
+			return err != nil, err
+		}); err != nil {
+			t.Fatalf("failed to reach Reader: %+v", err)
+		}
+		if conf.Identification == nil {
+			t.Fatal("expected the Reader's Identification")
+		}
+		return conf.Identification.ReaderID
+	}
+
+	for i, port := range ports {
+		protocols := protocolMap{"tcp": {"host": "127.0.0.1", "port": port}}
+		if err := d.AddDevice("readdedReader", protocols, contract.Enabled); err != nil {
+			t.Fatal(err)
+		}
+
+		// Adding the name again reuses its device, but updates its address.
+		d.devicesMu.RLock()
+		dev := d.activeDevices["readdedReader"]
+		n := len(d.activeDevices)
+		d.devicesMu.RUnlock()
+		if dev == nil || n != 1 {
+			t.Fatalf("expected exactly one device; got %d", n)
+		}
+
+		if id := connectedTo(dev); !bytes.Equal(id, []byte{byte(i)}) {
+			t.Errorf("expected to be connected to Reader %d at port %s; got Reader %v", i, port, id)
+		}
+	}
+
+	// Invalid protocols are rejected.
+	if err := d.AddDevice("readdedReader", protocolMap{"tcp": {"host": "127.0.0.1"}}, contract.Enabled); err == nil {
+		t.Error("expected an error re-adding a device without a port")
+	}
+}
+
+func TestTagReadCache(t *testing.T) {
+	read := func(id uint16) llrp.TagReportData {
+		return llrp.TagReportData{EPC96: llrp.EPC96{EPC: []byte{byte(id >> 8), byte(id)}}}
+	}
+
+	ids := func(reads []llrp.TagReportData) []uint16 {
+		out := make([]uint16, len(reads))
+		for i, r := range reads {
+			out[i] = uint16(r.EPC96.EPC[0])<<8 | uint16(r.EPC96.EPC[1])
+		}
+		return out
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		size     int
+		add      []uint16
+		expected []uint16
+	}{
+		{name: "empty", size: 3, expected: []uint16{}},
+		{name: "partial", size: 3, add: []uint16{1, 2}, expected: []uint16{1, 2}},
+		{name: "full", size: 3, add: []uint16{1, 2, 3}, expected: []uint16{1, 2, 3}},
+		{name: "wrapped", size: 3, add: []uint16{1, 2, 3, 4, 5}, expected: []uint16{3, 4, 5}},
+		{name: "disabled", size: 0, add: []uint16{1, 2}, expected: []uint16{}},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			c := newTagReadCache(testCase.size)
+			for _, id := range testCase.add {
+				c.add(read(id))
+			}
+
+			latest := c.latest()
+			if latest == nil {
+				t.Fatal("expected a non-nil slice")
+			}
+
+			got := ids(latest)
+			if len(got) != len(testCase.expected) {
+				t.Fatalf("expected %v; got %v", testCase.expected, got)
+			}
+			for i := range got {
+				if got[i] != testCase.expected[i] {
+					t.Fatalf("expected %v; got %v", testCase.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLLRPDevice_cachedReport(t *testing.T) {
+	antenna := llrp.AntennaID(2)
+	l := &LLRPDevice{reads: newTagReadCache(10),
+		antennaLocations: map[llrp.AntennaID]string{antenna: "dock door"}}
+
+	// Without reads, it's a report with no TagReportData.
+	_, report := l.cachedReport()
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty := struct{ TagReportData []json.RawMessage }{}
+	if err := json.Unmarshal(data, &empty); err != nil || len(empty.TagReportData) != 0 {
+		t.Errorf("expected an empty report; got %s (%v)", data, err)
+	}
+
+	// Reads are labeled just as they are in the reports sent to EdgeX.
+	l.reads.add(llrp.TagReportData{EPC96: llrp.EPC96{EPC: []byte{1, 2}}, AntennaID: &antenna})
+	reads, report := l.cachedReport()
+	if len(reads) != 1 {
+		t.Fatalf("expected one read; got %+v", reads)
+	}
+
+	expected, err := json.Marshal(withLocations(l.antennaLocations, nil, &llrp.ROAccessReport{TagReportData: reads}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err = json.Marshal(report); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, expected) || !bytes.Contains(data, []byte(`"Location":"dock door"`)) {
+		t.Errorf("expected %s; got %s", expected, data)
+	}
+}
+
+func TestDecodeReaderConfig(t *testing.T) {
+	conf, err := decodeReaderConfig([]byte(`{"KeepAliveSpec": {"Trigger": 1, "Interval": 5000}}`))
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if conf.KeepAliveSpec == nil || conf.KeepAliveSpec.Interval != 5000 {
+		t.Errorf("expected KeepAliveSpec with Interval 5000; got %+v", conf.KeepAliveSpec)
+	}
+
+	// Only the specified sub-parameter should be sent,
+	// so the Reader leaves the rest of its configuration untouched.
+	if conf.ResetToFactoryDefaults ||
+		conf.ReaderEventNotificationSpec != nil ||
+		conf.AntennaProperties != nil ||
+		conf.AntennaConfigurations != nil ||
+		conf.ROReportSpec != nil ||
+		conf.AccessReportSpec != nil ||
+		conf.GPOWriteData != nil ||
+		conf.GPIPortCurrentStates != nil ||
+		conf.EventsAndReports != nil ||
+		conf.Custom != nil {
+		t.Errorf("expected only KeepAliveSpec to be set; got %+v", conf)
+	}
+
+	if _, err := decodeReaderConfig([]byte(`{"KeepAlive": {"Interval": 5000}}`)); err == nil {
+		t.Error("expected an error for an unknown sub-parameter, but didn't get one")
+	}
+}
+
+func TestNewGetReaderConfig(t *testing.T) {
+	for _, testCase := range []struct {
+		selector string
+		expected llrp.ReaderConfigRequestedDataType
+		err      bool
+	}{
+		{selector: "", expected: llrp.ReaderConfReqAll},
+		{selector: "All", expected: llrp.ReaderConfReqAll},
+		{selector: "AntennaConfiguration", expected: llrp.ReaderConfReqAntennaConfig},
+		{selector: "KeepAliveSpec", expected: llrp.ReaderConfReqKeepAliveSpec},
+		{selector: "8", expected: llrp.ReaderConfReqKeepAliveSpec},
+		{selector: "11", expected: llrp.ReaderConfReqEventsAndReports},
+		{selector: "12", err: true},
+		{selector: "-1", err: true},
+		{selector: "keepalivespec", err: true},
+	} {
+		attributes := map[string]string{}
+		if testCase.selector != "" {
+			attributes[AttribRequestedData] = testCase.selector
+		}
+
+		conf, err := newGetReaderConfig(attributes)
+		if testCase.err {
+			if err == nil {
+				t.Errorf("selector %q: expected an error; got %+v", testCase.selector, conf)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("selector %q: %+v", testCase.selector, err)
+		} else if conf.RequestedData != testCase.expected {
+			t.Errorf("selector %q: expected RequestedData %d; got %d",
+				testCase.selector, testCase.expected, conf.RequestedData)
+		}
+	}
+}
+
+func TestTagCounter(t *testing.T) {
+	read := func(epc ...byte) llrp.TagReportData {
+		return llrp.TagReportData{EPC96: llrp.EPC96{EPC: epc}}
+	}
+
+	start := time.Now()
+
+	t.Run("sliding", func(t *testing.T) {
+		tc := newTagCounter(time.Minute)
+		if n := tc.count(start); n != 0 {
+			t.Fatalf("expected 0 tags with no data; got %d", n)
+		}
+
+		tc.add(start, read(1), read(2), read(1), llrp.TagReportData{})
+		tc.add(start.Add(30*time.Second), read(3),
+			llrp.TagReportData{EPCData: llrp.EPCData{EPCNumBits: 8, EPC: []byte{4}}})
+
+		if n := tc.count(start.Add(45 * time.Second)); n != 4 {
+			t.Errorf("expected 4 tags within the window; got %d", n)
+		}
+
+		// reading doesn't reset a sliding window, but old tags age out
+		if n := tc.count(start.Add(75 * time.Second)); n != 2 {
+			t.Errorf("expected 2 tags after the first ones aged out; got %d", n)
+		}
+	})
+
+	t.Run("perPoll", func(t *testing.T) {
+		tc := newTagCounter(0)
+		tc.add(start, read(1), read(2), read(1))
+
+		if n := tc.count(start); n != 2 {
+			t.Errorf("expected 2 tags; got %d", n)
+		}
+
+		if n := tc.count(start); n != 0 {
+			t.Errorf("expected count to reset after reading; got %d", n)
+		}
+	})
+}
+
+func TestHandleRead_TagCount(t *testing.T) {
+	dev := &LLRPDevice{counts: newTagCounter(time.Minute)}
+	d := newLocalDriver(t, dev)
+
+	dev.counts.add(time.Now(),
+		llrp.TagReportData{EPC96: llrp.EPC96{EPC: []byte{1}}},
+		llrp.TagReportData{EPC96: llrp.EPC96{EPC: []byte{2}}},
+	)
+
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceTagCount,
+		Type:               dsModels.Uint32,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cvs) != 1 || cvs[0] == nil {
+		t.Fatalf("expected exactly one command value; got %v", cvs)
+	}
+
+	n, err := cvs[0].Uint32Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n != 2 {
+		t.Errorf("expected a TagCount of 2; got %d", n)
+	}
+}
+
+func TestHandleRead_ReaderID(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgGetReaderConfig, &llrp.GetReaderConfigResponse{
+		Identification: &llrp.Identification{
+			IDType:   llrp.ID_MAC_EUI64,
+			ReaderID: []byte{0x00, 0x16, 0x25, 0xff, 0xfe, 0x12, 0x34, 0x56},
+		},
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	d := newLocalDriver(t, &LLRPDevice{client: c})
+
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceReaderID,
+		Type:               dsModels.String,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cvs) != 1 || cvs[0] == nil {
+		t.Fatalf("expected exactly one command value; got %v", cvs)
+	}
+
+	if id, err := cvs[0].StringValue(); err != nil || id != "00:16:25:ff:fe:12:34:56" {
+		t.Errorf("expected the Reader's MAC; got %q, %v", id, err)
+	}
+}
+
+func TestHandleRead_RFSurvey(t *testing.T) {
+	dev := &LLRPDevice{
+		caps: &llrp.GetReaderCapabilitiesResponse{
+			LLRPCapabilities: &llrp.LLRPCapabilities{CanDoRFSurvey: true},
+		},
+		survey: []llrp.RFSurveyReportData{{
+			FrequencyRSSILevelEntries: []llrp.FrequencyRSSILevelEntry{{
+				Frequency: 902750, Bandwidth: 500, AverageRSSI: -70, PeakRSSI: -65,
+			}},
+		}},
+	}
+	d := newLocalDriver(t, dev)
+
+	reqs := []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceRFSurvey,
+		Type:               dsModels.String,
+	}}
+
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cvs) != 1 || cvs[0] == nil {
+		t.Fatalf("expected exactly one command value; got %v", cvs)
+	}
+
+	s, err := cvs[0].StringValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []llrp.RFSurveyReportData
+	if err := json.Unmarshal([]byte(s), &results); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(results, dev.survey) {
+		t.Errorf("expected %+v; got %+v", dev.survey, results)
+	}
+
+	dev.caps = &llrp.GetReaderCapabilitiesResponse{LLRPCapabilities: &llrp.LLRPCapabilities{}}
+	if _, err := d.HandleReadCommands("localReader", protocolMap{}, reqs); err == nil {
+		t.Error("expected an error for a Reader that can't do RF surveys")
+	}
+}
+
+func TestHandleRead_unsupportedResource(t *testing.T) {
+	// Without a client, any attempt to send would fail with errNoClient.
+	d := newLocalDriver(t, &LLRPDevice{})
+
+	for _, resource := range []string{"NoSuchResource", ResourceReaderNotification} {
+		_, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+			DeviceResourceName: resource,
+			Type:               dsModels.String,
+		}})
+		if err == nil || errors.Is(err, errNoClient) ||
+			!strings.Contains(err.Error(), "unsupported read resource "+strconv.Quote(resource)) {
+			t.Errorf("%s: expected an unsupported read resource error; got %v", resource, err)
+		}
+	}
+}
+
+func TestHandleWrite_unsupportedResource(t *testing.T) {
+	// Without a client, any attempt to send would fail with errNoClient.
+	d := newLocalDriver(t, &LLRPDevice{})
+
+	write := func(resource string, attributes map[string]string) error {
+		return d.HandleWriteCommands("localReader", protocolMap{},
+			[]dsModels.CommandRequest{{
+				DeviceResourceName: resource,
+				Type:               dsModels.String,
+				Attributes:         attributes,
+			}},
+			[]*dsModels.CommandValue{dsModels.NewStringValue(resource, 0, "")})
+	}
+
+	for _, resource := range []string{"NoSuchResource", ResourceReaderCap} {
+		err := write(resource, nil)
+		if err == nil || errors.Is(err, errNoClient) ||
+			!strings.Contains(err.Error(), "unsupported write resource "+strconv.Quote(resource)) {
+			t.Errorf("%s: expected an unsupported write resource error; got %v", resource, err)
+		}
+	}
+
+	// A resource with only some CustomMessage attributes is still treated as one.
+	err := write("PartialCustom", map[string]string{AttribVendor: "25882"})
+	if err == nil || !strings.Contains(err.Error(), "missing custom parameter attribute: "+AttribSubtype) {
+		t.Errorf("expected a missing %s attribute error; got %v", AttribSubtype, err)
+	}
+}
+
+func TestWithLocations(t *testing.T) {
+	ant1, ant2 := llrp.AntennaID(1), llrp.AntennaID(2)
+	report := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{
+		{EPC96: llrp.EPC96{EPC: []byte{1}}, AntennaID: &ant1},
+		{EPC96: llrp.EPC96{EPC: []byte{2}}, AntennaID: &ant2},
+		{EPC96: llrp.EPC96{EPC: []byte{3}}},
+	}}
+
+	if got := withLocations(nil, nil, report); got != report {
+		t.Errorf("expected the report unchanged without locations; got %+v", got)
+	}
+
+	locations, err := getAntennaLocations(protocolMap{
+		ProtocolLocation: {"1": "Dock Door 3", "0": "nowhere", "antenna": "nowhere"},
+	})
+	if err == nil {
+		t.Error("expected an error for invalid antenna IDs")
+	}
+
+	data, err := json.Marshal(withLocations(locations, nil, report))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var located struct {
+		TagReportData []struct {
+			EPC96    llrp.EPC96
+			Location *string
+		}
+	}
+	if err := json.Unmarshal(data, &located); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(located.TagReportData) != 3 {
+		t.Fatalf("expected 3 tags; got %s", data)
+	}
+
+	for i, expected := range []string{"Dock Door 3", "2", ""} {
+		loc := located.TagReportData[i].Location
+		switch {
+		case expected == "" && loc != nil:
+			t.Errorf("tag %d: expected no Location; got %q", i, *loc)
+		case expected != "" && (loc == nil || *loc != expected):
+			t.Errorf("tag %d: expected Location %q; got %s", i, expected, data)
+		}
+	}
+
+	// The result should otherwise match the original report.
+	var plain llrp.ROAccessReport
+	if err := json.Unmarshal(data, &plain); err != nil {
+		t.Fatal(err)
+	}
+	if len(plain.TagReportData) != 3 || plain.TagReportData[1].AntennaID == nil ||
+		*plain.TagReportData[1].AntennaID != ant2 {
+		t.Errorf("expected located report to match the original; got %s", data)
+	}
+}
+
+func TestFlatReadValues(t *testing.T) {
+	for _, testCase := range []struct {
+		protocols protocolMap
+		flat      bool
+		invalid   bool
+	}{
+		{protocols: protocolMap{}},
+		{protocols: protocolMap{ProtocolReport: {"format": ReportFormatJSON}}},
+		{protocols: protocolMap{ProtocolReport: {"format": ReportFormatFlat}}, flat: true},
+		{protocols: protocolMap{ProtocolReport: {"format": "xml"}}, invalid: true},
+	} {
+		flat, err := getFlatReports(testCase.protocols)
+		if flat != testCase.flat || (err != nil) != testCase.invalid {
+			t.Errorf("%v: expected flat=%t, invalid=%t; got %t, %v",
+				testCase.protocols, testCase.flat, testCase.invalid, flat, err)
+		}
+	}
+
+	now := time.Now()
+	ant := llrp.AntennaID(2)
+	rssi := llrp.PeakRSSI(-55)
+	lastSeen := llrp.LastSeenUTC(1600000000123456)
+	crc := llrp.C1G2CRC(0x0BAD)
+	locations := map[llrp.AntennaID]string{2: "Dock Door 3"}
+
+	cvs, err := flatReadValues(now, OriginHost, locations, &llrp.TagReportData{
+		EPC96:       llrp.EPC96{EPC: []byte{0xE2, 0x80, 0x11}},
+		AntennaID:   &ant,
+		PeakRSSI:    &rssi,
+		LastSeenUTC: &lastSeen,
+		C1G2PC:      &llrp.C1G2PC{EPCMemoryLength: 6, HasUserMemory: true},
+		C1G2CRC:     &crc,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]*dsModels.CommandValue, len(cvs))
+	for _, cv := range cvs {
+		values[cv.DeviceResourceName] = cv
+	}
+
+	if epc, err := values[ResourceTagEPC].StringValue(); err != nil || epc != "e28011" {
+		t.Errorf("expected EPC e28011; got %q, %v", epc, err)
+	}
+	if ts, err := values[ResourceTagTimestamp].Int64Value(); err != nil || ts != 1600000000123456000 {
+		t.Errorf("expected LastSeenUTC in nanoseconds; got %d, %v", ts, err)
+	}
+	if id, err := values[ResourceTagAntenna].Uint16Value(); err != nil || id != 2 {
+		t.Errorf("expected antenna 2; got %d, %v", id, err)
+	}
+	if loc, err := values[ResourceTagLocation].StringValue(); err != nil || loc != "Dock Door 3" {
+		t.Errorf("expected location Dock Door 3; got %q, %v", loc, err)
+	}
+	if r, err := values[ResourceTagRSSI].Int8Value(); err != nil || r != -55 {
+		t.Errorf("expected RSSI -55; got %d, %v", r, err)
+	}
+	if pc, err := values[ResourceTagPC].StringValue(); err != nil || pc != "3400" {
+		t.Errorf("expected PC 3400; got %q, %v", pc, err)
+	}
+	if um, err := values[ResourceTagUserMemory].BoolValue(); err != nil || !um {
+		t.Errorf("expected user memory; got %t, %v", um, err)
+	}
+	if c, err := values[ResourceTagCRC].StringValue(); err != nil || c != "0bad" {
+		t.Errorf("expected CRC 0bad; got %q, %v", c, err)
+	}
+
+	// Without optional fields, only the EPC and timestamp are present.
+	// EPCs longer than 96 bits come as EPCData, but are presented the same way.
+	cvs, err = flatReadValues(now, OriginHost, locations, &llrp.TagReportData{
+		EPCData: llrp.EPCData{EPCNumBits: 128, EPC: []byte{
+			0xE2, 0x80, 0x11, 0x00, 0x20, 0x00, 0x71, 0x2B, 0x4C, 0x56, 0x00, 0x9A, 0xDE, 0xAD, 0xBE, 0xEF}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cvs) != 2 {
+		t.Fatalf("expected only EPC and timestamp; got %d values", len(cvs))
+	}
+	if epc, err := cvs[0].StringValue(); err != nil || epc != "e28011002000712b4c56009adeadbeef" {
+		t.Errorf("expected the 128-bit EPC; got %q, %v", epc, err)
+	}
+	if ts, err := cvs[1].Int64Value(); err != nil || ts != now.UnixNano() {
+		t.Errorf("expected the current time without seen timestamps; got %d, %v", ts, err)
+	}
+}
+
+// TestCommandMessagePairs checks that each request/response pair
+// the command handlers send agree with the LLRP response types.
+func TestCommandMessagePairs(t *testing.T) {
+	for _, pair := range []struct {
+		req  llrp.Outgoing
+		resp llrp.Incoming
+	}{
+		{&llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{}},
+		{&llrp.GetReaderCapabilities{}, &llrp.GetReaderCapabilitiesResponse{}},
+		{&llrp.GetROSpecs{}, &llrp.GetROSpecsResponse{}},
+		{&llrp.GetAccessSpecs{}, &llrp.GetAccessSpecsResponse{}},
+		{&llrp.CustomMessage{}, &llrp.CustomMessage{}},
+		{&llrp.SetReaderConfig{}, &llrp.SetReaderConfigResponse{}},
+		{&llrp.AddROSpec{}, &llrp.AddROSpecResponse{}},
+		{&llrp.EnableROSpec{}, &llrp.EnableROSpecResponse{}},
+		{&llrp.StartROSpec{}, &llrp.StartROSpecResponse{}},
+		{&llrp.StopROSpec{}, &llrp.StopROSpecResponse{}},
+		{&llrp.DisableROSpec{}, &llrp.DisableROSpecResponse{}},
+		{&llrp.DeleteROSpec{}, &llrp.DeleteROSpecResponse{}},
+		{&llrp.AddAccessSpec{}, &llrp.AddAccessSpecResponse{}},
+		{&llrp.EnableAccessSpec{}, &llrp.EnableAccessSpecResponse{}},
+		{&llrp.DisableAccessSpec{}, &llrp.DisableAccessSpecResponse{}},
+		{&llrp.DeleteAccessSpec{}, &llrp.DeleteAccessSpecResponse{}},
+	} {
+		if conv, ok := pair.req.Type().ResponseType(); !ok || conv != pair.resp.Type() {
+			t.Errorf("%v expects %v, but its response type is %v",
+				pair.req.Type(), pair.resp.Type(), conv)
+		}
+	}
+}
+
+func TestLLRPDevice_DeleteROSpecVerified(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// This Reader claims to delete ROSpecs, but never does.
+	rfid.SetResponse(llrp.MsgDeleteROSpec, &llrp.DeleteROSpecResponse{})
+	rfid.SetResponse(llrp.MsgGetROSpecs, &llrp.GetROSpecsResponse{
+		ROSpecs: []llrp.ROSpec{{ROSpecID: 1}},
+	})
+
+	go rfid.ImpersonateReader()
+	dev := &LLRPDevice{
+		name:   "buggyReader",
+		client: rfid.ConnectClient(t),
+		lc:     edgexCompatTestLogger{t},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := dev.DeleteROSpecVerified(ctx, 1); !errors.Is(err, ErrROSpecNotDeleted) {
+		t.Errorf("expected ErrROSpecNotDeleted for the listed ROSpec; got %v", err)
+	}
+	if err := dev.DeleteROSpecVerified(ctx, 0); !errors.Is(err, ErrROSpecNotDeleted) {
+		t.Errorf("expected ErrROSpecNotDeleted when deleting all ROSpecs; got %v", err)
+	}
+	if err := dev.DeleteROSpecVerified(ctx, 2); err != nil {
+		t.Errorf("expected the unlisted ROSpec to be confirmed deleted; got %+v", err)
+	}
+}
+
+func TestGetReplaceROSpecs(t *testing.T) {
+	for _, testCase := range []struct {
+		replace  string
+		expected bool
+		err      bool
+	}{
+		{replace: "", expected: false},
+		{replace: "true", expected: true},
+		{replace: "false", expected: false},
+		{replace: "sometimes", err: true},
+	} {
+		protocols := protocolMap{}
+		if testCase.replace != "" {
+			protocols[ProtocolCommands] = contract.ProtocolProperties{"replaceROSpecs": testCase.replace}
+		}
+
+		replace, err := getReplaceROSpecs(protocols)
+		if (err != nil) != testCase.err || replace != testCase.expected {
+			t.Errorf("replaceROSpecs %q: expected %v (error: %v); got %v, %v",
+				testCase.replace, testCase.expected, testCase.err, replace, err)
+		}
+	}
+}
+
+func TestLLRPDevice_AddROSpec_duplicate(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader rejects ROSpecs with IDs it already has.
+	var mu sync.Mutex
+	onReader := map[uint32]bool{}
+	var received []llrp.MessageType
+	rfid.SetResponseFunc(llrp.MsgAddROSpec, func(msg llrp.Message) llrp.Outgoing {
+		add := llrp.AddROSpec{}
+		if err := msg.UnmarshalTo(&add); err != nil {
+			t.Error(err)
+			return &llrp.AddROSpecResponse{}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, llrp.MsgAddROSpec)
+		if onReader[add.ROSpec.ROSpecID] {
+			return &llrp.AddROSpecResponse{LLRPStatus: llrp.LLRPStatus{
+				Status:           llrp.StatusMsgParamError,
+				ErrorDescription: "ROSpecID already in use",
+				ParameterError: &llrp.ParameterError{
+					ParameterType: llrp.ParamROSpec,
+					ErrorCode:     llrp.StatusParamFieldError,
+					FieldError: &llrp.FieldError{
+						FieldIndex: 0,
+						ErrorCode:  llrp.StatusFieldInvalid,
+					},
+				},
+			}}
+		}
+		onReader[add.ROSpec.ROSpecID] = true
+		return &llrp.AddROSpecResponse{}
+	})
+	rfid.SetResponseFunc(llrp.MsgDeleteROSpec, func(msg llrp.Message) llrp.Outgoing {
+		del := llrp.DeleteROSpec{}
+		if err := msg.UnmarshalTo(&del); err != nil {
+			t.Error(err)
+			return &llrp.DeleteROSpecResponse{}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, llrp.MsgDeleteROSpec)
+		delete(onReader, del.ROSpecID)
+		return &llrp.DeleteROSpecResponse{}
+	})
+
+	go rfid.ImpersonateReader()
+	dev := &LLRPDevice{
+		name:   "localReader",
+		client: rfid.ConnectClient(t),
+		lc:     edgexCompatTestLogger{t},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// addAndCheck adds an ROSpec and checks the messages the Reader received.
+	addAndCheck := func(expected ...llrp.MessageType) error {
+		t.Helper()
+		mu.Lock()
+		received = nil
+		mu.Unlock()
+
+		err := dev.AddROSpec(ctx, &llrp.AddROSpec{ROSpec: llrp.ROSpec{ROSpecID: 1}}, &llrp.AddROSpecResponse{})
+
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(received, expected) {
+			t.Errorf("expected the Reader to receive %v; got %v", expected, received)
+		}
+		return err
+	}
+
+	if err := addAndCheck(llrp.MsgAddROSpec); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// By default, adding it again fails.
+	if err := addAndCheck(llrp.MsgAddROSpec); !errors.Is(err, ErrROSpecExists) {
+		t.Errorf("expected ErrROSpecExists; got %v", err)
+	}
+
+	// With the option, it's replaced.
+	dev.replaceROSpecs = true
+	if err := addAndCheck(llrp.MsgAddROSpec, llrp.MsgDeleteROSpec, llrp.MsgAddROSpec); err != nil {
+		t.Errorf("expected the ROSpec to be replaced; got %+v", err)
+	}
+}
+
+func TestIsDuplicateROSpecID(t *testing.T) {
+	fieldErr := func(param llrp.ParamType, field uint16) error {
+		return (&llrp.LLRPStatus{
+			Status: llrp.StatusMsgParamError,
+			ParameterError: &llrp.ParameterError{
+				ParameterType: param,
+				ErrorCode:     llrp.StatusParamFieldError,
+				FieldError:    &llrp.FieldError{FieldIndex: field, ErrorCode: llrp.StatusFieldInvalid},
+			},
+		}).Err()
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		id       uint32
+		err      error
+		expected bool
+	}{
+		{"duplicate", 1, fieldErr(llrp.ParamROSpec, 0), true},
+		{"wrapped", 1, errors.WithMessage(fieldErr(llrp.ParamROSpec, 0), "failed"), true},
+		{"ID 0", 0, fieldErr(llrp.ParamROSpec, 0), false},
+		{"other field", 1, fieldErr(llrp.ParamROSpec, 1), false},
+		{"other parameter", 1, fieldErr(llrp.ParamAISpec, 0), false},
+		{"device error", 1, (&llrp.LLRPStatus{Status: llrp.StatusDeviceError}).Err(), false},
+		{"not a status", 1, errors.New("connection closed"), false},
+	} {
+		if got := isDuplicateROSpecID(testCase.id, testCase.err); got != testCase.expected {
+			t.Errorf("%s: expected %v; got %v", testCase.name, testCase.expected, got)
+		}
+	}
+}
+
+func TestLLRPDevice_TrySendNoWait(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgGetReaderConfig, &llrp.GetReaderConfigResponse{})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	dev := &LLRPDevice{
+		name:   "localReader",
+		client: c,
+		lc:     edgexCompatTestLogger{t},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The TestDevice answers these with an ErrorMessage,
+	// but since they're one-way, nothing waits for it.
+	for _, msg := range []llrp.Outgoing{&llrp.KeepAliveAck{}, &llrp.GetReport{}} {
+		if err := dev.TrySendNoWait(ctx, msg); err != nil {
+			t.Errorf("failed to send %v: %+v", msg.Type(), err)
+		}
+	}
+
+	// Messages are written in order, so once this round trip completes,
+	// the Reader has seen the one-way messages, and their replies
+	// didn't get mistaken for this one's.
+	if err := dev.TrySend(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{}); err != nil {
+		t.Errorf("failed to send after one-way messages: %+v", err)
+	}
+}
+
+func TestGetDevice_concurrentCreation(t *testing.T) {
+	slowStarted := make(chan struct{})
+	releaseSlow := make(chan struct{})
+	var mu sync.Mutex
+	created := map[string]int{}
+
+	d := newLocalDriver(t)
+	d.newDevice = func(name string, address net.Addr, _ contract.OperatingState) *LLRPDevice {
+		mu.Lock()
+		created[name]++
+		mu.Unlock()
+
+		if name == "slow" {
+			close(slowStarted)
+			<-releaseSlow
+		}
+		return &LLRPDevice{name: name, address: address, lc: d.lc}
+	}
+
+	protocols := protocolMap{"tcp": {"host": "127.0.0.1", "port": "5084"}}
+
+	type result struct {
+		dev   *LLRPDevice
+		isNew bool
+		err   error
+	}
+	slowResults := make(chan result, 2)
+	getSlow := func() {
+		dev, isNew, err := d.getDevice("slow", protocols)
+		slowResults <- result{dev, isNew, err}
+	}
+
+	go getSlow()
+	<-slowStarted
+	go getSlow()
+
+	// Creating a device for another name doesn't wait for the slow one.
+	fastDone := make(chan result, 1)
+	go func() {
+		dev, isNew, err := d.getDevice("fast", protocols)
+		fastDone <- result{dev, isNew, err}
+	}()
+
+	select {
+	case r := <-fastDone:
+		if r.err != nil || !r.isNew || r.dev == nil || r.dev.name != "fast" {
+			t.Errorf("expected a new device named fast; got %+v", r)
+		}
+	case <-time.After(5 * time.Second):
+		close(releaseSlow)
+		t.Fatal("creating a device waited for another device's creation")
+	}
+
+	// Both requests for the slow device get the same one.
+	close(releaseSlow)
+	r1, r2 := <-slowResults, <-slowResults
+	if r1.err != nil || r2.err != nil {
+		t.Fatalf("unexpected errors: %v, %v", r1.err, r2.err)
+	}
+	if r1.dev == nil || r1.dev != r2.dev {
+		t.Errorf("expected the same device; got %p and %p", r1.dev, r2.dev)
+	}
+	if r1.isNew == r2.isNew {
+		t.Errorf("expected exactly one new device; got isNew %v and %v", r1.isNew, r2.isNew)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if created["slow"] != 1 || created["fast"] != 1 {
+		t.Errorf("expected each device to be created once; got %v", created)
+	}
+	if d.activeDevices["slow"] != r1.dev {
+		t.Error("expected the slow device to be active")
+	}
+}
+
+func TestGetDevice_removedDuringCreation(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	d := newLocalDriver(t)
+	d.newDevice = func(name string, address net.Addr, _ contract.OperatingState) *LLRPDevice {
+		close(started)
+		<-release
+		return &LLRPDevice{name: name, address: address, lc: d.lc}
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, _, err := d.getDevice("localReader", protocolMap{"tcp": {"host": "127.0.0.1", "port": "5084"}})
+		errs <- err
+	}()
+
+	<-started
+	if d.removeDevice(context.Background(), "localReader") {
+		t.Error("expected the device not to be active yet")
+	}
+	close(release)
+
+	if err := <-errs; err == nil || !strings.Contains(err.Error(), "removed") {
+		t.Errorf("expected an error about the removed device; got %v", err)
+	}
+	if _, ok := d.activeDevices["localReader"]; ok {
+		t.Error("expected the removed device not to be added")
+	}
+}
@@ -0,0 +1,190 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxEPCWords is the longest EPC a C1G2 tag's PC can describe, in 16-bit words.
+	maxEPCWords = 31
+	// epcStartBit is where the EPC begins in the EPC memory bank, after the CRC and PC.
+	epcStartBit = 0x20
+)
+
+// epcFields are the optional hex EPCs a request may include alongside an AccessSpec or ROSpec.
+type epcFields struct {
+	// TargetEPC sets an AccessSpec's C1G2TagSpec to match only the tag with this EPC,
+	// so its OpSpecs (e.g., writes and locks) only apply to that tag.
+	TargetEPC string
+	// FilterEPC adds a C1G2Filter to an ROSpec so that it only inventories the tag with this EPC.
+	FilterEPC string
+}
+
+// ParseEPC parses an EPC written as hex digits, optionally prefixed with "0x",
+// ignoring case and whitespace (e.g., "3008 33B2 DDD9 0140 0000 0000").
+//
+// C1G2 EPCs are a whole number of 16-bit words, up to 31 of them,
+// so it returns an error if the EPC is empty, has an odd number of digits,
+// has a partial word, is too long, or has characters that aren't hex digits.
+func ParseEPC(s string) ([]byte, error) {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	normalized = strings.TrimPrefix(strings.TrimPrefix(normalized, "0x"), "0X")
+
+	if normalized == "" {
+		return nil, errors.New("EPC is empty")
+	}
+
+	if i := strings.IndexFunc(normalized, func(r rune) bool {
+		return !strings.ContainsRune("0123456789abcdefABCDEF", r)
+	}); i >= 0 {
+		return nil, errors.Errorf("EPC %q has a character that isn't a hex digit: %q",
+			s, []rune(normalized[i:])[0])
+	}
+
+	switch n := len(normalized); {
+	case n%2 != 0:
+		return nil, errors.Errorf("EPC %q has an odd number of hex digits (%d)", s, n)
+	case n%4 != 0:
+		return nil, errors.Errorf("EPC %q has %d bits, but C1G2 EPCs are a whole number "+
+			"of 16-bit words (4 hex digits each)", s, n*4)
+	case n/4 > maxEPCWords:
+		return nil, errors.Errorf("EPC %q has %d bits, but C1G2 EPCs are at most %d bits",
+			s, n*4, maxEPCWords*16)
+	}
+
+	return hex.DecodeString(normalized)
+}
+
+// parseEPCFields returns the epcFields in the request's JSON data,
+// which is empty if the request was built by the driver rather than the user.
+func parseEPCFields(data []byte) (epcFields, error) {
+	fields := epcFields{}
+	if len(data) == 0 {
+		return fields, nil
+	}
+	err := errors.Wrap(json.Unmarshal(data, &fields), "failed to unmarshal EPC fields")
+	return fields, err
+}
+
+// applyTargetEPC sets the AccessCommand's first tag pattern to match
+// the TargetEPC in the request's JSON data, if it has one.
+// It returns an error if the EPC is invalid
+// or if the request also specifies a tag pattern of its own.
+func applyTargetEPC(cmd *llrp.AccessCommand, data []byte) error {
+	fields, err := parseEPCFields(data)
+	if err != nil || fields.TargetEPC == "" {
+		return err
+	}
+
+	epc, err := ParseEPC(fields.TargetEPC)
+	if err != nil {
+		return errors.WithMessage(err, "invalid TargetEPC")
+	}
+
+	if p := cmd.C1G2TagSpec.TagPattern1; p.TagMaskNumBits != 0 || p.TagDataNumBits != 0 {
+		return errors.New("the request has both a TargetEPC and a C1G2TagSpec TagPattern1; " +
+			"use one or the other")
+	}
+
+	cmd.C1G2TagSpec.TagPattern1 = epcTagPattern(epc)
+	return nil
+}
+
+// epcTagPattern returns a tag pattern that matches only the tag with the EPC.
+func epcTagPattern(epc []byte) llrp.C1G2TargetTag {
+	numBits := uint16(len(epc) * 8)
+	return llrp.C1G2TargetTag{
+		C1G2MemoryBank:     memoryBankEPC,
+		MatchFlag:          true,
+		MostSignificantBit: epcStartBit,
+		TagMaskNumBits:     numBits,
+		TagMask:            fullMask(len(epc)),
+		TagDataNumBits:     numBits,
+		TagData:            epc,
+	}
+}
+
+// applyFilterEPC adds a C1G2Filter for the FilterEPC in the request's JSON data,
+// if it has one, to every AntennaConfiguration in the ROSpec's InventoryParameterSpecs,
+// adding an AntennaConfiguration for all antennas to those that have none.
+// It returns an error if the EPC is invalid or if the ROSpec has no InventoryParameterSpecs.
+func applyFilterEPC(ros *llrp.ROSpec, data []byte) error {
+	fields, err := parseEPCFields(data)
+	if err != nil || fields.FilterEPC == "" {
+		return err
+	}
+
+	epc, err := ParseEPC(fields.FilterEPC)
+	if err != nil {
+		return errors.WithMessage(err, "invalid FilterEPC")
+	}
+
+	// Tags that match are selected, and the others are unselected.
+	action := llrp.C1G2TagInventoryStateUnawareFilterAction(llrp.UnawareSelectMSetUClear)
+	filter := llrp.C1G2Filter{
+		TruncateAction: llrp.FilterActionDoNotTruncate,
+		TagInventoryMask: llrp.C1G2TagInventoryMask{
+			MemoryBank:         memoryBankEPC,
+			MostSignificantBit: epcStartBit,
+			TagMaskNumBits:     uint16(len(epc) * 8),
+			TagMask:            epc,
+		},
+		UnawareFilterAction: &action,
+	}
+
+	// The ROSpec's AISpecs may share parameters (e.g., after SetAntennaDwell),
+	// so this copies what it changes to filter each one only once.
+	filtered := false
+	for i := range ros.AISpecs {
+		ai := &ros.AISpecs[i]
+		ai.InventoryParameterSpecs = append([]llrp.InventoryParameterSpec(nil), ai.InventoryParameterSpecs...)
+
+		for j := range ai.InventoryParameterSpecs {
+			ips := &ai.InventoryParameterSpecs[j]
+			ips.AntennaConfigurations = append([]llrp.AntennaConfiguration(nil), ips.AntennaConfigurations...)
+			if len(ips.AntennaConfigurations) == 0 {
+				ips.AntennaConfigurations = []llrp.AntennaConfiguration{{AntennaID: llrp.AllAntennas}}
+			}
+
+			for k := range ips.AntennaConfigurations {
+				ac := &ips.AntennaConfigurations[k]
+				inv := llrp.C1G2InventoryCommand{}
+				if ac.C1G2InventoryCommand != nil {
+					inv = *ac.C1G2InventoryCommand
+				}
+				inv.Filters = append(append([]llrp.C1G2Filter(nil), inv.Filters...), filter)
+				ac.C1G2InventoryCommand = &inv
+			}
+			filtered = true
+		}
+	}
+
+	if !filtered {
+		return errors.New("FilterEPC requires the ROSpec to have an AISpec with an InventoryParameterSpec")
+	}
+	return nil
+}
+
+// fullMask returns a mask of n bytes with every bit set.
+func fullMask(n int) []byte {
+	mask := make([]byte, n)
+	for i := range mask {
+		mask[i] = 0xff
+	}
+	return mask
+}
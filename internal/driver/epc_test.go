@@ -0,0 +1,136 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strings"
+	"testing"
+)
+
+func TestParseEPC(t *testing.T) {
+	for _, testCase := range []struct {
+		input    string
+		expected []byte
+		invalid  string // if set, the error must mention it
+	}{
+		{input: "300833B2DDD9014000000000", expected: []byte{
+			0x30, 0x08, 0x33, 0xb2, 0xdd, 0xd9, 0x01, 0x40, 0, 0, 0, 0}},
+		{input: "  3008 33b2\tdDd9\n ", expected: []byte{0x30, 0x08, 0x33, 0xb2, 0xdd, 0xd9}},
+		{input: "0xABCD", expected: []byte{0xab, 0xcd}},
+		{input: "0XABCD", expected: []byte{0xab, 0xcd}},
+		{input: strings.Repeat("ab", 62), expected: bytes.Repeat([]byte{0xab}, 62)},
+		{input: "", invalid: "empty"},
+		{input: " 0x ", invalid: "empty"},
+		{input: "ABC", invalid: "odd number"},
+		{input: "ABCDEF", invalid: "16-bit words"},
+		{input: "ABCG", invalid: "'G'"},
+		{input: "AB-CD", invalid: "'-'"},
+		{input: "ABCé", invalid: "'é'"},
+		{input: strings.Repeat("ab", 64), invalid: "at most 496 bits"},
+	} {
+		epc, err := ParseEPC(testCase.input)
+		if testCase.invalid != "" {
+			if err == nil || !strings.Contains(err.Error(), testCase.invalid) {
+				t.Errorf("%q: expected an error mentioning %s; got %v", testCase.input, testCase.invalid, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: %v", testCase.input, err)
+		} else if !bytes.Equal(epc, testCase.expected) {
+			t.Errorf("%q: expected %x; got %x", testCase.input, testCase.expected, epc)
+		}
+	}
+}
+
+func TestApplyTargetEPC(t *testing.T) {
+	cmd := llrp.AccessCommand{}
+	if err := applyTargetEPC(&cmd, []byte(`{"TargetEPC": "3008 33B2"}`)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	p := cmd.C1G2TagSpec.TagPattern1
+	if p.C1G2MemoryBank != memoryBankEPC || !p.MatchFlag || p.MostSignificantBit != epcStartBit ||
+		p.TagMaskNumBits != 32 || !bytes.Equal(p.TagMask, []byte{0xff, 0xff, 0xff, 0xff}) ||
+		p.TagDataNumBits != 32 || !bytes.Equal(p.TagData, []byte{0x30, 0x08, 0x33, 0xb2}) {
+		t.Errorf("expected a pattern matching the whole EPC; got %+v", p)
+	}
+
+	// A request with a pattern of its own is ambiguous.
+	if err := applyTargetEPC(&cmd, []byte(`{"TargetEPC": "ABCD"}`)); err == nil {
+		t.Error("expected an error for a request with both a TargetEPC and a TagPattern1")
+	}
+
+	if err := applyTargetEPC(&llrp.AccessCommand{}, []byte(`{"TargetEPC": "ABC"}`)); err == nil ||
+		!strings.Contains(err.Error(), "TargetEPC") {
+		t.Errorf("expected an error about the invalid TargetEPC; got %v", err)
+	}
+
+	// Requests without a TargetEPC are unchanged.
+	unchanged := llrp.AccessCommand{}
+	if err := applyTargetEPC(&unchanged, []byte(`{"AccessSpecID": 1}`)); err != nil {
+		t.Errorf("%+v", err)
+	}
+	if unchanged.C1G2TagSpec.TagPattern1.TagData != nil {
+		t.Errorf("expected no tag pattern; got %+v", unchanged.C1G2TagSpec.TagPattern1)
+	}
+}
+
+func TestApplyFilterEPC(t *testing.T) {
+	ros := llrp.ROSpec{ROSpecID: 1}
+	ros.SetAntennaDwell(llrp.AntennaDwell{AntennaID: 1}, llrp.AntennaDwell{AntennaID: 2})
+
+	if err := applyFilterEPC(&ros, []byte(`{"FilterEPC": "abcd"}`)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// The AISpecs share their InventoryParameterSpecs, but each is filtered once.
+	for i, ai := range ros.AISpecs {
+		acs := ai.InventoryParameterSpecs[0].AntennaConfigurations
+		if len(acs) != 1 || acs[0].AntennaID != llrp.AllAntennas || acs[0].C1G2InventoryCommand == nil {
+			t.Fatalf("AISpec %d: expected an AntennaConfiguration for all antennas; got %+v", i, acs)
+		}
+
+		filters := acs[0].C1G2InventoryCommand.Filters
+		if len(filters) != 1 {
+			t.Fatalf("AISpec %d: expected 1 filter; got %d", i, len(filters))
+		}
+
+		mask := filters[0].TagInventoryMask
+		if mask.MemoryBank != memoryBankEPC || mask.MostSignificantBit != epcStartBit ||
+			mask.TagMaskNumBits != 16 || !bytes.Equal(mask.TagMask, []byte{0xab, 0xcd}) {
+			t.Errorf("AISpec %d: expected a mask for the EPC; got %+v", i, mask)
+		}
+	}
+
+	// Existing filters are kept.
+	existing := llrp.ROSpec{AISpecs: []llrp.AISpec{{InventoryParameterSpecs: []llrp.InventoryParameterSpec{{
+		AntennaConfigurations: []llrp.AntennaConfiguration{{
+			AntennaID:            3,
+			C1G2InventoryCommand: &llrp.C1G2InventoryCommand{Filters: []llrp.C1G2Filter{{}}},
+		}},
+	}}}}}
+	if err := applyFilterEPC(&existing, []byte(`{"FilterEPC": "abcd"}`)); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if n := len(existing.AISpecs[0].InventoryParameterSpecs[0].AntennaConfigurations[0].C1G2InventoryCommand.Filters); n != 2 {
+		t.Errorf("expected the existing filter and the EPC filter; got %d filters", n)
+	}
+
+	if err := applyFilterEPC(&llrp.ROSpec{}, []byte(`{"FilterEPC": "abcd"}`)); err == nil {
+		t.Error("expected an error for an ROSpec without AISpecs")
+	}
+	if err := applyFilterEPC(&llrp.ROSpec{}, []byte(`{"FilterEPC": "ab cd ef"}`)); err == nil ||
+		!strings.Contains(err.Error(), "FilterEPC") {
+		t.Errorf("expected an error about the invalid FilterEPC; got %v", err)
+	}
+	if err := applyFilterEPC(&llrp.ROSpec{}, nil); err != nil {
+		t.Errorf("expected no error without request data; got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// ReaderLogEvent is a single entry in a Reader's internal event/error history.
+type ReaderLogEvent struct {
+	Timestamp time.Time
+	Severity  string `json:",omitempty"`
+	Code      uint32 `json:",omitempty"`
+	Message   string
+}
+
+// eventLogFormat returns the format of the Reader's event history,
+// based on the vendor in its capabilities,
+// or an error if the vendor isn't known to support one.
+func (l *LLRPDevice) eventLogFormat(ctx context.Context) (VendorIDType, eventLogFormat, error) {
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		return 0, eventLogFormat{}, errors.WithMessage(err, "unable to determine Reader vendor")
+	}
+
+	return eventLogFormatFor(caps)
+}
+
+// eventLogFormatFor returns the format of the event history of a Reader with the capabilities,
+// or an error if its vendor isn't known to support one.
+func eventLogFormatFor(caps *llrp.GetReaderCapabilitiesResponse) (VendorIDType, eventLogFormat, error) {
+	if caps.GeneralDeviceCapabilities == nil {
+		return 0, eventLogFormat{}, errors.New("Reader does not support event history: unknown vendor")
+	}
+
+	vendor := VendorIDType(caps.GeneralDeviceCapabilities.DeviceManufacturer)
+	format, ok := eventLogFormats[vendor]
+	if !ok {
+		return 0, eventLogFormat{}, errors.Errorf("Reader does not support event history: "+
+			"no event history format is known for vendor %v", vendor)
+	}
+
+	return vendor, format, nil
+}
+
+// EventHistory returns the Reader's recent event/error history,
+// which some vendors make available via CustomMessages.
+// This is useful for diagnosing intermittent faults
+// that happened while the service wasn't connected.
+func (l *LLRPDevice) EventHistory(ctx context.Context) ([]ReaderLogEvent, error) {
+	vendor, format, err := l.eventLogFormat(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &llrp.CustomMessage{}
+	if err := l.TrySend(ctx, &llrp.CustomMessage{
+		VendorID:       uint32(vendor),
+		MessageSubtype: format.getSubtype,
+	}, resp); err != nil {
+		return nil, errors.WithMessage(err, "failed to get event history")
+	}
+
+	events, err := format.decode(resp.Data)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to decode event history")
+	}
+
+	if events == nil {
+		events = []ReaderLogEvent{}
+	}
+	return events, nil
+}
+
+// ClearEventHistory clears the Reader's event/error history.
+func (l *LLRPDevice) ClearEventHistory(ctx context.Context) error {
+	vendor, format, err := l.eventLogFormat(ctx)
+	if err != nil {
+		return err
+	}
+
+	return errors.WithMessage(l.TrySend(ctx, &llrp.CustomMessage{
+		VendorID:       uint32(vendor),
+		MessageSubtype: format.clearSubtype,
+	}, &llrp.CustomMessage{}), "failed to clear event history")
+}
@@ -0,0 +1,107 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventHistory(t *testing.T) {
+	const testVendor = VendorIDType(12345)
+	eventTime := time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	// No vendor is built in yet, so register one that replies
+	// with the event message as the CustomMessage data.
+	eventLogFormats[testVendor] = eventLogFormat{
+		getSubtype:   1,
+		clearSubtype: 2,
+		decode: func(data []byte) ([]ReaderLogEvent, error) {
+			if len(data) == 0 {
+				return nil, nil
+			}
+			return []ReaderLogEvent{{Timestamp: eventTime, Message: string(data)}}, nil
+		},
+	}
+	defer delete(eventLogFormats, testVendor)
+
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgCustomMessage, &llrp.CustomMessage{
+		VendorID:       uint32(testVendor),
+		MessageSubtype: 1,
+		Data:           []byte("antenna 2 disconnected"),
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	dev := &LLRPDevice{
+		client: c,
+		caps: &llrp.GetReaderCapabilitiesResponse{
+			GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+				DeviceManufacturer: uint32(testVendor),
+			},
+		},
+	}
+	d := newLocalDriver(t, dev)
+
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceEventHistory,
+		Type:               dsModels.String,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cvs) != 1 || cvs[0] == nil {
+		t.Fatalf("expected exactly one command value; got %v", cvs)
+	}
+
+	s, err := cvs[0].StringValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []ReaderLogEvent
+	if err := json.Unmarshal([]byte(s), &events); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 1 || !events[0].Timestamp.Equal(eventTime) ||
+		events[0].Message != "antenna 2 disconnected" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+
+	if err := d.HandleWriteCommands("localReader", protocolMap{},
+		[]dsModels.CommandRequest{{DeviceResourceName: ResourceEventHistory, Type: dsModels.String}},
+		[]*dsModels.CommandValue{dsModels.NewStringValue(ResourceEventHistory, 0, ActionClear)},
+	); err != nil {
+		t.Errorf("failed to clear event history: %+v", err)
+	}
+
+	// Readers from other vendors don't support it.
+	dev.caps = &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			DeviceManufacturer: uint32(Alien),
+		},
+	}
+
+	_, err = d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceEventHistory,
+		Type:               dsModels.String,
+	}})
+	if err == nil || !strings.Contains(err.Error(), "does not support") {
+		t.Errorf("expected an unsupported error; got %v", err)
+	}
+}
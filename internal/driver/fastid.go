@@ -0,0 +1,193 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ResourceFastIDROSpec adds an ROSpec with Impinj's FastID enabled,
+	// so Impinj Readers report Monza tags' TIDs along with their EPCs.
+	ResourceFastIDROSpec = "FastIDROSpec"
+
+	// ResourceTagTID holds a tag read's TID as a hex string
+	// for devices that use ReportFormatFlat, if the Reader reported it.
+	ResourceTagTID = "TagTID"
+)
+
+// Impinj CustomMessage and Custom parameter subtypes used by FastID.
+const (
+	impinjEnableExtensions         = 21 // CustomMessage enabling Impinj's extensions
+	impinjEnableExtensionsResponse = 22
+	impinjTagReportContentSelector = 50 // ROReportSpec Custom parameter
+	impinjEnableSerializedTID      = 51 // nested in an impinjTagReportContentSelector
+	impinjSerializedTID            = 55 // TagReportData Custom parameter holding the TID
+
+	impinjSerializedTIDEnabled = 1
+)
+
+// impinjParam returns the binary encoding of an Impinj Custom parameter,
+// for nesting inside another Custom parameter's Data.
+func impinjParam(subtype uint32, data []byte) []byte {
+	const customParamType = 1023
+	b := make([]byte, 12, 12+len(data))
+	binary.BigEndian.PutUint16(b[0:], customParamType)
+	binary.BigEndian.PutUint16(b[2:], uint16(12+len(data)))
+	binary.BigEndian.PutUint32(b[4:], uint32(Impinj))
+	binary.BigEndian.PutUint32(b[8:], subtype)
+	return append(b, data...)
+}
+
+// enableFastID adds the Impinj Custom parameter enabling FastID
+// to the ROSpec's ROReportSpec, which must not be nil.
+func enableFastID(ros *llrp.ROSpec) error {
+	if ros.ROReportSpec == nil {
+		return errors.New("FastID requires the ROSpec to have an ROReportSpec")
+	}
+
+	mode := make([]byte, 2)
+	binary.BigEndian.PutUint16(mode, impinjSerializedTIDEnabled)
+	ros.ROReportSpec.Custom = append(ros.ROReportSpec.Custom, llrp.Custom{
+		VendorID: uint32(Impinj),
+		Subtype:  impinjTagReportContentSelector,
+		Data:     impinjParam(impinjEnableSerializedTID, mode),
+	})
+	return nil
+}
+
+// tagTID returns the TID an Impinj Reader reported with a tag read using FastID,
+// or nil if the read doesn't include one.
+func tagTID(tr *llrp.TagReportData) []byte {
+	for _, c := range tr.Custom {
+		if c.VendorID != uint32(Impinj) || c.Subtype != impinjSerializedTID || len(c.Data) < 2 {
+			continue
+		}
+
+		// The TID is a list of 16-bit words, prefixed by their count.
+		n := 2 * int(binary.BigEndian.Uint16(c.Data))
+		if len(c.Data) < 2+n {
+			return nil
+		}
+		return c.Data[2 : 2+n]
+	}
+	return nil
+}
+
+// tagTIDString returns the hex encoding of a tag read's TID,
+// or the empty string if it doesn't have one.
+func tagTIDString(tr *llrp.TagReportData) string {
+	return hex.EncodeToString(tagTID(tr))
+}
+
+// hasTIDs returns true if any of the tag reads include a FastID TID.
+func hasTIDs(reads []llrp.TagReportData) bool {
+	for i := range reads {
+		if tagTID(&reads[i]) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFastIDROSpec adds the ROSpec to the Reader with Impinj's FastID enabled,
+// first enabling Impinj's extensions, which the Reader requires to accept it.
+// Readers from other vendors don't support FastID.
+func (l *LLRPDevice) AddFastIDROSpec(ctx context.Context, ros llrp.ROSpec) error {
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "unable to determine Reader vendor")
+	}
+
+	if err := checkFastID(caps); err != nil {
+		return err
+	}
+
+	if err := enableFastID(&ros); err != nil {
+		return err
+	}
+
+	add := ros.Add()
+	if err := l.checkSupported(ctx, add); err != nil {
+		return err
+	}
+
+	if err := l.enableImpinjExtensions(ctx); err != nil {
+		return err
+	}
+
+	return errors.WithMessage(l.AddROSpec(ctx, add, &llrp.AddROSpecResponse{}),
+		"failed to add FastID ROSpec")
+}
+
+// enableImpinjExtensions enables Impinj's extensions,
+// which Impinj Readers require before accepting their Custom parameters.
+func (l *LLRPDevice) enableImpinjExtensions(ctx context.Context) error {
+	resp := &llrp.CustomMessage{}
+	if err := l.TrySend(ctx, &llrp.CustomMessage{
+		VendorID:       uint32(Impinj),
+		MessageSubtype: impinjEnableExtensions,
+		Data:           make([]byte, 4), // reserved
+	}, resp); err != nil {
+		return errors.WithMessage(err, "failed to enable Impinj extensions")
+	}
+
+	return errors.WithMessage(impinjExtensionsErr(resp), "failed to enable Impinj extensions")
+}
+
+// checkFastID returns an error unless the capabilities show the Reader is an Impinj Reader.
+func checkFastID(caps *llrp.GetReaderCapabilitiesResponse) error {
+	if caps.GeneralDeviceCapabilities == nil ||
+		VendorIDType(caps.GeneralDeviceCapabilities.DeviceManufacturer) != Impinj {
+		return errors.New("Reader does not support FastID: it's only available on Impinj Readers")
+	}
+	return nil
+}
+
+// usesImpinjExtensions returns true if the ROSpec's ROReportSpec
+// has Impinj Custom parameters, such as the one enableFastID adds.
+func usesImpinjExtensions(ros *llrp.ROSpec) bool {
+	if ros.ROReportSpec == nil {
+		return false
+	}
+
+	for _, c := range ros.ROReportSpec.Custom {
+		if c.VendorID == uint32(Impinj) {
+			return true
+		}
+	}
+	return false
+}
+
+// impinjExtensionsErr returns an error if the Reader's reply
+// to an impinjEnableExtensions message doesn't indicate success.
+func impinjExtensionsErr(resp *llrp.CustomMessage) error {
+	if resp.VendorID != uint32(Impinj) || resp.MessageSubtype != impinjEnableExtensionsResponse {
+		return errors.Errorf("unexpected reply: vendor %d, subtype %d",
+			resp.VendorID, resp.MessageSubtype)
+	}
+
+	// The reply's only parameter is an LLRPStatus.
+	if len(resp.Data) < 4 {
+		return errors.New("reply is missing its LLRPStatus")
+	}
+
+	paramLen := int(binary.BigEndian.Uint16(resp.Data[2:]))
+	if paramLen < 4 || paramLen > len(resp.Data) {
+		return errors.Errorf("reply's LLRPStatus has an invalid length: %d", paramLen)
+	}
+
+	status := llrp.LLRPStatus{}
+	if err := status.UnmarshalBinary(resp.Data[4:paramLen]); err != nil {
+		return errors.Wrap(err, "failed to decode reply's LLRPStatus")
+	}
+
+	return status.Err()
+}
@@ -0,0 +1,205 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fastIDRead returns a tag read with the TID an Impinj Reader reports using FastID.
+func fastIDRead(tid ...byte) llrp.TagReportData {
+	data := []byte{0, byte(len(tid) / 2)}
+	return llrp.TagReportData{
+		EPC96: llrp.EPC96{EPC: []byte{0x30, 0x00}},
+		Custom: []llrp.Custom{{
+			VendorID: uint32(Impinj),
+			Subtype:  impinjSerializedTID,
+			Data:     append(data, tid...),
+		}},
+	}
+}
+
+func TestEnableFastID(t *testing.T) {
+	ros := llrp.ROSpec{ROSpecID: 1}
+	if err := enableFastID(&ros); err == nil {
+		t.Error("expected an error for an ROSpec without an ROReportSpec")
+	}
+
+	ros.ROReportSpec = &llrp.ROReportSpec{Trigger: llrp.NTagsOrROEnd, N: 1}
+	if err := enableFastID(&ros); err != nil {
+		t.Fatal(err)
+	}
+
+	// The selector's Data is an ImpinjEnableSerializedTID parameter.
+	expected := llrp.Custom{
+		VendorID: uint32(Impinj),
+		Subtype:  impinjTagReportContentSelector,
+		Data: []byte{
+			0x03, 0xFF, 0x00, 0x0E, // Custom parameter, 14 bytes
+			0x00, 0x00, 0x65, 0x1A, // Impinj
+			0x00, 0x00, 0x00, 0x33, // ImpinjEnableSerializedTID
+			0x00, 0x01, // enabled
+		},
+	}
+
+	custom := ros.ROReportSpec.Custom
+	if len(custom) != 1 || custom[0].VendorID != expected.VendorID ||
+		custom[0].Subtype != expected.Subtype || !bytes.Equal(custom[0].Data, expected.Data) {
+		t.Errorf("expected %+v; got %+v", expected, custom)
+	}
+
+	// It survives encoding.
+	data, err := ros.Add().MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	add := llrp.AddROSpec{}
+	if err := add.UnmarshalBinary(data); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if add.ROSpec.ROReportSpec == nil || len(add.ROSpec.ROReportSpec.Custom) != 1 ||
+		!bytes.Equal(add.ROSpec.ROReportSpec.Custom[0].Data, expected.Data) {
+		t.Errorf("FastID selector didn't round trip: %+v", add.ROSpec.ROReportSpec)
+	}
+}
+
+func TestTagTID(t *testing.T) {
+	tr := fastIDRead(0xE2, 0x80, 0x11, 0x05)
+	if got := tagTIDString(&tr); got != "e2801105" {
+		t.Errorf("expected TID e2801105; got %q", got)
+	}
+
+	// Other vendors' parameters and truncated TIDs are ignored.
+	tr.Custom[0].Data = tr.Custom[0].Data[:3]
+	if got := tagTID(&tr); got != nil {
+		t.Errorf("expected no TID for a truncated parameter; got %x", got)
+	}
+
+	tr = fastIDRead(0xE2, 0x80)
+	tr.Custom[0].VendorID = uint32(Alien)
+	if got := tagTID(&tr); got != nil {
+		t.Errorf("expected no TID for another vendor's parameter; got %x", got)
+	}
+}
+
+func TestFastIDReports(t *testing.T) {
+	tr := fastIDRead(0xE2, 0x80, 0x11, 0x05)
+	report := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{tr}}
+
+	data, err := json.Marshal(withLocations(nil, nil, report))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"TID":"e2801105"`) {
+		t.Errorf("expected the JSON report to include the TID; got %s", data)
+	}
+	if strings.Contains(string(data), `"Location"`) {
+		t.Errorf("expected no locations without any configured; got %s", data)
+	}
+
+	cvs, err := flatReadValues(time.Now(), OriginHost, nil, &tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, cv := range cvs {
+		if cv.DeviceResourceName == ResourceTagTID {
+			found = cv.ValueToString() == "e2801105"
+		}
+	}
+	if !found {
+		t.Errorf("expected a %s value of e2801105; got %v", ResourceTagTID, cvs)
+	}
+}
+
+func TestAddFastIDROSpec(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgCustomMessage, &llrp.CustomMessage{
+		VendorID:       uint32(Impinj),
+		MessageSubtype: impinjEnableExtensionsResponse,
+		Data:           []byte{0x01, 0x1F, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00}, // LLRPStatus: Success
+	})
+	rfid.SetResponse(llrp.MsgAddROSpec, &llrp.AddROSpecResponse{})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	dev := &LLRPDevice{
+		client: c,
+		caps: &llrp.GetReaderCapabilitiesResponse{
+			GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+				DeviceManufacturer: uint32(Impinj),
+			},
+		},
+	}
+	d := newLocalDriver(t, dev)
+	d.asyncCh = make(chan *dsModels.AsyncValues, 1)
+
+	write := func(spec string) error {
+		return d.HandleWriteCommands("localReader", protocolMap{},
+			[]dsModels.CommandRequest{{DeviceResourceName: ResourceFastIDROSpec, Type: dsModels.String}},
+			[]*dsModels.CommandValue{dsModels.NewStringValue(ResourceFastIDROSpec, 0, spec)})
+	}
+
+	if err := write(`{"ROSpecID":1,"ROReportSpec":{"Trigger":1,"N":1}}`); err != nil {
+		t.Errorf("failed to add FastID ROSpec: %+v", err)
+	}
+
+	if err := write(`{"ROSpecID":1}`); err == nil || !strings.Contains(err.Error(), "ROReportSpec") {
+		t.Errorf("expected an error about the missing ROReportSpec; got %v", err)
+	}
+
+	// Readers from other vendors don't support it.
+	dev.caps = &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			DeviceManufacturer: uint32(Zebra),
+		},
+	}
+
+	err = write(`{"ROSpecID":1,"ROReportSpec":{"Trigger":1,"N":1}}`)
+	if err == nil || !strings.Contains(err.Error(), "does not support") {
+		t.Errorf("expected an unsupported error; got %v", err)
+	}
+}
+
+func TestImpinjExtensionsErr(t *testing.T) {
+	resp := &llrp.CustomMessage{
+		VendorID:       uint32(Impinj),
+		MessageSubtype: impinjEnableExtensionsResponse,
+		Data:           []byte{0x01, 0x1F, 0x00, 0x08, 0x00, 0x00, 0x00, 0x00},
+	}
+	if err := impinjExtensionsErr(resp); err != nil {
+		t.Errorf("expected success; got %v", err)
+	}
+
+	// StatusCode 100 is M_ParameterError.
+	resp.Data = []byte{0x01, 0x1F, 0x00, 0x08, 0x00, 0x64, 0x00, 0x00}
+	if err := impinjExtensionsErr(resp); err == nil {
+		t.Error("expected an error for a failing LLRPStatus")
+	}
+
+	resp.Data = nil
+	if err := impinjExtensionsErr(resp); err == nil {
+		t.Error("expected an error for a missing LLRPStatus")
+	}
+
+	resp.MessageSubtype = impinjEnableExtensions
+	if err := impinjExtensionsErr(resp); err == nil {
+		t.Error("expected an error for the wrong subtype")
+	}
+}
@@ -0,0 +1,50 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+const (
+	// ResourceGPIPort and ResourceGPIState are sent together as a reading
+	// when a Reader reports that one of its GPI ports changed state:
+	// the port's number and whether it's now high.
+	ResourceGPIPort  = "GPIPort"
+	ResourceGPIState = "GPIState"
+)
+
+// gpiEventValues returns typed values for a GPIEvent's port and new state.
+func gpiEventValues(ns int64, event *llrp.GPIEvent) ([]*dsModels.CommandValue, error) {
+	port, err := dsModels.NewUint16Value(ResourceGPIPort, ns, event.Port)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := dsModels.NewBoolValue(ResourceGPIState, ns, event.Event)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*dsModels.CommandValue{port, state}, nil
+}
+
+// sendGPIEvent sends a reading with the port and state of the GPIEvent in the notification data,
+// if it has one, so GPI-triggered automation needn't decode the whole notification.
+func (l *LLRPDevice) sendGPIEvent(ns int64, data *llrp.ReaderEventNotificationData) {
+	if data.GPIEvent == nil {
+		return
+	}
+
+	cvs, err := gpiEventValues(ns, data.GPIEvent)
+	if err != nil {
+		l.lc.Error("Failed to create GPI event values.", "device", l.name, "error", err.Error())
+		return
+	}
+
+	l.sendAsync(&dsModels.AsyncValues{DeviceName: l.name, CommandValues: cvs})
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_sendGPIEvent(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 10)
+	l := &LLRPDevice{
+		name: "localReader",
+		lc:   edgexCompatTestLogger{t},
+		ch:   ch,
+		clk:  newFakeClock(),
+	}
+	handler := l.newReaderEventHandler(nil)
+
+	for _, testCase := range []struct {
+		name  string
+		port  uint16
+		state bool
+		// payload is a ReaderEventNotification as a Reader sends it.
+		payload []byte
+	}{
+		{
+			name: "high", port: 2, state: true,
+			payload: []byte{
+				0x00, 0xf6, 0x00, 0x17, // ReaderEventNotificationData, 23 bytes
+				0x00, 0x80, 0x00, 0x0c, // UTCTimestamp, 12 bytes
+				0x00, 0x05, 0xaf, 0x31, 0x07, 0xa4, 0x00, 0x00,
+				0x00, 0xf8, 0x00, 0x07, // GPIEvent, 7 bytes
+				0x00, 0x02, // Port
+				0x80, // Event: high
+			},
+		},
+		{
+			name: "low", port: 513, state: false,
+			payload: []byte{
+				0x00, 0xf6, 0x00, 0x17,
+				0x00, 0x80, 0x00, 0x0c,
+				0x00, 0x05, 0xaf, 0x31, 0x07, 0xa4, 0x00, 0x00,
+				0x00, 0xf8, 0x00, 0x07,
+				0x02, 0x01,
+				0x00, // Event: low; the remaining bits are reserved
+			},
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			msg, err := llrp.NewByteMessage(llrp.MsgReaderEventNotification, testCase.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+			handler.HandleMessage(nil, msg)
+
+			for {
+				var av *dsModels.AsyncValues
+				select {
+				case av = <-ch:
+				case <-time.After(time.Second):
+					t.Fatal("expected a GPI event reading")
+				}
+
+				if av.CommandValues[0].DeviceResourceName != ResourceGPIPort {
+					continue
+				}
+				if len(av.CommandValues) != 2 {
+					t.Fatalf("expected a port and state; got %v", av.CommandValues)
+				}
+
+				port, err := av.CommandValues[0].Uint16Value()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if port != testCase.port {
+					t.Errorf("expected port %d; got %d", testCase.port, port)
+				}
+
+				stateCV := av.CommandValues[1]
+				if stateCV.DeviceResourceName != ResourceGPIState {
+					t.Fatalf("expected a %s value; got %s", ResourceGPIState, stateCV.DeviceResourceName)
+				}
+				state, err := stateCV.BoolValue()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if state != testCase.state {
+					t.Errorf("expected state %v; got %v", testCase.state, state)
+				}
+				return
+			}
+		})
+	}
+
+	// Notifications without a GPIEvent don't send one.
+	l.sendGPIEvent(0, &llrp.ReaderEventNotificationData{})
+	select {
+	case av := <-ch:
+		t.Errorf("expected no values; got %+v", av)
+	default:
+	}
+}
@@ -0,0 +1,122 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const (
+	// ResourceGPOPulse sets a GPO port, then clears it after a duration,
+	// e.g., to flash a light or sound a buzzer when a tag is read.
+	ResourceGPOPulse = "GPOPulse"
+
+	// maxGPOPulse is the longest pulse the service accepts.
+	// Longer states should be set and cleared with ReaderConfig's GPOWriteData.
+	maxGPOPulse = time.Minute
+
+	// gpoClearTimeout is how long the service keeps trying to end a pulse,
+	// which gives a Reader that disconnected mid-pulse time to reconnect.
+	gpoClearTimeout = 2 * time.Minute
+)
+
+// gpoPulse is the JSON request for a GPOPulse.
+type gpoPulse struct {
+	Port           uint16 // the GPO port, starting from 1
+	DurationMillis uint32 // how long to hold the port's state, from 1 to maxGPOPulse
+}
+
+// PulseGPO sets the GPO port, then clears it after the duration.
+//
+// It returns once the port is set; clearing it happens in the background,
+// and if that fails, it's reattempted until gpoClearTimeout,
+// e.g., while the Reader reconnects.
+// Pulsing a port that's already pulsing extends its pulse.
+func (l *LLRPDevice) PulseGPO(ctx context.Context, port uint16, d time.Duration) error {
+	if port == 0 {
+		return errors.New("GPO ports start at 1")
+	}
+	if d <= 0 || d > maxGPOPulse {
+		return errors.Errorf("GPO pulse duration must be from 1ms to %v, but is %v", maxGPOPulse, d)
+	}
+
+	set := gpoWrite(port, true)
+	if err := l.checkSupported(ctx, set); err != nil {
+		return err
+	}
+
+	l.gpoMu.Lock()
+	defer l.gpoMu.Unlock()
+
+	if err := l.TrySend(ctx, set, &llrp.SetReaderConfigResponse{}); err != nil {
+		return errors.WithMessagef(err, "failed to set GPO port %d", port)
+	}
+
+	if prev, ok := l.gpoPulses[port]; ok {
+		prev.Stop()
+	}
+	if l.gpoPulses == nil {
+		l.gpoPulses = make(map[uint16]timer)
+	}
+
+	// The timer can't be read until we unlock gpoMu, after it's assigned.
+	var t timer
+	t = l.clock().AfterFunc(d, func() {
+		l.gpoMu.Lock()
+		current := l.gpoPulses[port] == t
+		if current {
+			delete(l.gpoPulses, port)
+		}
+		l.gpoMu.Unlock()
+
+		if current {
+			l.endGPOPulse(port)
+		}
+	})
+	l.gpoPulses[port] = t
+
+	return nil
+}
+
+// endGPOPulse clears the GPO port, reattempting until it succeeds,
+// a new pulse starts on the port, or gpoClearTimeout passes.
+func (l *LLRPDevice) endGPOPulse(port uint16) {
+	ctx, cancel := context.WithTimeout(context.Background(), gpoClearTimeout)
+	defer cancel()
+
+	err := retry.Quick.RetryWithCtx(ctx, retry.Forever, func(ctx context.Context) (bool, error) {
+		l.gpoMu.Lock()
+		defer l.gpoMu.Unlock()
+
+		// If another pulse set the port, it's responsible for clearing it.
+		if _, ok := l.gpoPulses[port]; ok {
+			return false, nil
+		}
+
+		sendCtx, sendCancel := context.WithTimeout(ctx, sendTimeout)
+		defer sendCancel()
+
+		err := l.TrySend(sendCtx, gpoWrite(port, false), &llrp.SetReaderConfigResponse{})
+		return err != nil, err
+	})
+
+	if err != nil {
+		l.lc.Error("Failed to end GPO pulse; the port may still be set.",
+			"device", l.name, "port", strconv.Itoa(int(port)), "error", err.Error())
+	}
+}
+
+// gpoWrite returns a SetReaderConfig that sets the GPO port's state.
+func gpoWrite(port uint16, state bool) *llrp.SetReaderConfig {
+	return &llrp.SetReaderConfig{
+		GPOWriteData: []llrp.GPOWriteData{{Port: port, Data: state}},
+	}
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_PulseGPO(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader records the GPO writes it receives and fails the first clear,
+	// as if it disconnected mid-pulse.
+	writes := make(chan llrp.GPOWriteData, 10)
+	var clears int32
+	rfid.SetResponseFunc(llrp.MsgSetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		conf := &llrp.SetReaderConfig{}
+		if err := msg.UnmarshalTo(conf); err != nil || len(conf.GPOWriteData) != 1 {
+			t.Errorf("expected a single GPOWriteData; got %+v, %v", conf, err)
+			return &llrp.SetReaderConfigResponse{}
+		}
+
+		gpo := conf.GPOWriteData[0]
+		if !gpo.Data && atomic.AddInt32(&clears, 1) == 1 {
+			return &llrp.SetReaderConfigResponse{LLRPStatus: llrp.LLRPStatus{
+				Status:           llrp.StatusDeviceError,
+				ErrorDescription: "temporarily unavailable",
+			}}
+		}
+
+		writes <- gpo
+		return &llrp.SetReaderConfigResponse{}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	clk := newFakeClock()
+	dev := &LLRPDevice{
+		name:   "localReader",
+		client: c,
+		lc:     edgexCompatTestLogger{t},
+		clk:    clk,
+		caps: &llrp.GetReaderCapabilitiesResponse{
+			GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+				GPIOCapabilities: llrp.GPIOCapabilities{NumGPOs: 2},
+			},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	expectWrite := func(port uint16, state bool) {
+		t.Helper()
+		select {
+		case gpo := <-writes:
+			if gpo.Port != port || gpo.Data != state {
+				t.Errorf("expected GPO port %d set to %v; got %+v", port, state, gpo)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected GPO port %d set to %v", port, state)
+		}
+	}
+
+	expectNoWrite := func() {
+		t.Helper()
+		select {
+		case gpo := <-writes:
+			t.Errorf("expected no GPO writes; got %+v", gpo)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	if err := dev.PulseGPO(ctx, 1, 500*time.Millisecond); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectWrite(1, true)
+	expectNoWrite()
+
+	// The port is cleared once the pulse ends, despite the failed first attempt.
+	clk.Advance(500 * time.Millisecond)
+	expectWrite(1, false)
+
+	// Pulsing a port again before its pulse ends extends it.
+	if err := dev.PulseGPO(ctx, 2, 500*time.Millisecond); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectWrite(2, true)
+	clk.Advance(400 * time.Millisecond)
+
+	if err := dev.PulseGPO(ctx, 2, 500*time.Millisecond); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectWrite(2, true)
+
+	clk.Advance(200 * time.Millisecond)
+	expectNoWrite()
+	clk.Advance(300 * time.Millisecond)
+	expectWrite(2, false)
+
+	for _, testCase := range []struct {
+		port     uint16
+		duration time.Duration
+		err      string
+	}{
+		{port: 0, duration: time.Second, err: "start at 1"},
+		{port: 3, duration: time.Second, err: "only 2"},
+		{port: 1, duration: 0, err: "duration"},
+		{port: 1, duration: maxGPOPulse + time.Millisecond, err: "duration"},
+	} {
+		err := dev.PulseGPO(ctx, testCase.port, testCase.duration)
+		if err == nil || !strings.Contains(err.Error(), testCase.err) {
+			t.Errorf("port %d for %v: expected an error about %q; got %v",
+				testCase.port, testCase.duration, testCase.err, err)
+		}
+	}
+	expectNoWrite()
+
+	// It's available as a write resource.
+	d := newLocalDriver(t, dev)
+	if err := d.HandleWriteCommands(dev.name, protocolMap{},
+		[]dsModels.CommandRequest{{DeviceResourceName: ResourceGPOPulse, Type: dsModels.String}},
+		[]*dsModels.CommandValue{dsModels.NewStringValue(ResourceGPOPulse, 0,
+			`{"Port":1,"DurationMillis":250}`)}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expectWrite(1, true)
+	clk.Advance(250 * time.Millisecond)
+	expectWrite(1, false)
+}
@@ -0,0 +1,82 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+)
+
+// ResourceHoldEventsAndReports is whether the Reader holds events and reports
+// each time a client connects until it receives EnableEventsAndReports,
+// i.e., the EventsAndReports (HoldEventsAndReportsUponReconnect) flag in its ReaderConfig.
+const ResourceHoldEventsAndReports = "HoldEventsAndReports"
+
+// HoldEventsAndReports gets the Reader's EventsAndReports flag,
+// which is true if the Reader holds events and reports upon reconnect.
+func (l *LLRPDevice) HoldEventsAndReports(ctx context.Context) (bool, error) {
+	conf := llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		RequestedData: llrp.ReaderConfReqEventsAndReports,
+	}, &conf); err != nil {
+		return false, err
+	}
+
+	if conf.EventsAndReports == nil {
+		return false, errors.New("Reader's config is missing its EventsAndReports flag")
+	}
+
+	hold := bool(*conf.EventsAndReports)
+	l.setHoldsEventsAndReports(hold)
+	return hold, nil
+}
+
+// SetHoldEventsAndReports sets the Reader's EventsAndReports flag.
+// Setting it fails without contacting the Reader if its capabilities
+// show it doesn't support holding events and reports.
+//
+// While it's set, the service sends EnableEventsAndReports each time it reconnects,
+// after restoring the Reader's KeepAlives and reprovisioning its specs,
+// so the Reader's held events and reports arrive once the Reader is ready for them.
+func (l *LLRPDevice) SetHoldEventsAndReports(ctx context.Context, hold bool) error {
+	flag := llrp.EventsAndReports(hold)
+	conf := &llrp.SetReaderConfig{EventsAndReports: &flag}
+	if err := l.checkSupported(ctx, conf); err != nil {
+		return err
+	}
+
+	if err := l.TrySend(ctx, conf, &llrp.SetReaderConfigResponse{}); err != nil {
+		return err
+	}
+
+	l.setHoldsEventsAndReports(hold)
+	return nil
+}
+
+// setHoldsEventsAndReports notes whether the Reader holds events and reports upon reconnect.
+func (l *LLRPDevice) setHoldsEventsAndReports(hold bool) {
+	l.deviceMu.Lock()
+	l.holdsEventsAndReports = hold
+	l.deviceMu.Unlock()
+}
+
+// releaseHeldEventsAndReports sends EnableEventsAndReports
+// if the Reader was last known to hold events and reports upon reconnect.
+func (l *LLRPDevice) releaseHeldEventsAndReports(ctx context.Context) {
+	l.deviceMu.RLock()
+	hold := l.holdsEventsAndReports
+	l.deviceMu.RUnlock()
+
+	if !hold {
+		return
+	}
+
+	l.lc.Debug("Releasing events and reports held upon reconnect.", "device", l.name)
+	if err := l.TrySendNoWait(ctx, &llrp.EnableEventsAndReports{}); err != nil {
+		l.lc.Error("Failed to release held events and reports.", "device", l.name, "error", err.Error())
+	}
+}
@@ -0,0 +1,129 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHandleReadWrite_HoldEventsAndReports(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader keeps whatever flag it's last sent.
+	var flagMu sync.Mutex
+	flag := llrp.EventsAndReports(false)
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		conf := &llrp.GetReaderConfig{}
+		if err := msg.UnmarshalTo(conf); err != nil {
+			t.Errorf("expected a GetReaderConfig; got %v", err)
+		}
+		if conf.RequestedData != llrp.ReaderConfReqEventsAndReports {
+			t.Errorf("expected a request for EventsAndReports; got %v", conf.RequestedData)
+		}
+
+		flagMu.Lock()
+		defer flagMu.Unlock()
+		current := flag
+		return &llrp.GetReaderConfigResponse{EventsAndReports: &current}
+	})
+	rfid.SetResponseFunc(llrp.MsgSetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		conf := &llrp.SetReaderConfig{}
+		if err := msg.UnmarshalTo(conf); err != nil {
+			t.Errorf("expected a SetReaderConfig; got %v", err)
+		}
+		if conf.EventsAndReports == nil {
+			t.Error("expected the SetReaderConfig to include EventsAndReports")
+			return &llrp.SetReaderConfigResponse{}
+		}
+
+		flagMu.Lock()
+		flag = *conf.EventsAndReports
+		flagMu.Unlock()
+		return &llrp.SetReaderConfigResponse{}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	dev := &LLRPDevice{client: c}
+	d := newLocalDriver(t, dev)
+
+	read := func() bool {
+		t.Helper()
+		cvs, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+			DeviceResourceName: ResourceHoldEventsAndReports,
+			Type:               dsModels.Bool,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cvs) != 1 || cvs[0] == nil {
+			t.Fatalf("expected exactly one command value; got %v", cvs)
+		}
+		hold, err := cvs[0].BoolValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hold
+	}
+
+	write := func(hold bool) {
+		t.Helper()
+		cv, err := dsModels.NewBoolValue(ResourceHoldEventsAndReports, 0, hold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := d.HandleWriteCommands("localReader", protocolMap{},
+			[]dsModels.CommandRequest{{
+				DeviceResourceName: ResourceHoldEventsAndReports,
+				Type:               dsModels.Bool,
+			}},
+			[]*dsModels.CommandValue{cv}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	holds := func() bool {
+		dev.deviceMu.RLock()
+		defer dev.deviceMu.RUnlock()
+		return dev.holdsEventsAndReports
+	}
+
+	if read() || holds() {
+		t.Error("expected the Reader to start out not holding events and reports")
+	}
+
+	write(true)
+	if !holds() {
+		t.Error("expected the device to note the Reader holds events and reports")
+	}
+	if !read() {
+		t.Error("expected the Reader to hold events and reports")
+	}
+
+	write(false)
+	if holds() {
+		t.Error("expected the device to note the Reader no longer holds events and reports")
+	}
+	if read() {
+		t.Error("expected the Reader to no longer hold events and reports")
+	}
+
+	// A value read from the Reader is noted, too.
+	flagMu.Lock()
+	flag = true
+	flagMu.Unlock()
+	if !read() || !holds() {
+		t.Error("expected the read value to be noted")
+	}
+}
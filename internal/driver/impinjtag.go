@@ -0,0 +1,111 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/binary"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+)
+
+const (
+	// ResourceTagPeakRSSI, ResourceTagPhaseAngle, and ResourceTagDopplerFrequency
+	// hold the Impinj-specific fields of a tag read
+	// for devices that use ReportFormatFlat, if the Reader reported them.
+	ResourceTagPeakRSSI         = "TagPeakRSSI"
+	ResourceTagPhaseAngle       = "TagPhaseAngle"
+	ResourceTagDopplerFrequency = "TagDopplerFrequency"
+)
+
+// Impinj TagReportData Custom parameter subtypes.
+const (
+	impinjRFPhaseAngle       = 56 // uint16; 4096ths of a full rotation
+	impinjPeakRSSI           = 57 // int16; hundredths of a dBm
+	impinjRFDopplerFrequency = 68 // int16; 16ths of a Hz
+)
+
+// ImpinjTagData holds the fields Impinj Readers can add to tag reads
+// for location and motion analytics, converted to conventional units.
+// Each is nil if the Reader didn't report it.
+type ImpinjTagData struct {
+	PeakRSSI           *float64 `json:",omitempty"` // in dBm, to the hundredth
+	RFPhaseAngle       *float64 `json:",omitempty"` // in degrees, from 0 up to 360
+	RFDopplerFrequency *float64 `json:",omitempty"` // in Hz
+}
+
+// impinjTagData returns the Impinj fields in a tag read's Custom parameters,
+// or nil if it doesn't have any,
+// e.g. because the Reader isn't an Impinj Reader or didn't enable them.
+func impinjTagData(tr *llrp.TagReportData) *ImpinjTagData {
+	data := ImpinjTagData{}
+	found := false
+	for _, c := range tr.Custom {
+		if c.VendorID != uint32(Impinj) || len(c.Data) < 2 {
+			continue
+		}
+
+		raw := binary.BigEndian.Uint16(c.Data)
+		var v float64
+		switch c.Subtype {
+		case impinjPeakRSSI:
+			v = float64(int16(raw)) / 100
+			data.PeakRSSI = &v
+		case impinjRFPhaseAngle:
+			v = float64(raw%4096) * 360 / 4096
+			data.RFPhaseAngle = &v
+		case impinjRFDopplerFrequency:
+			v = float64(int16(raw)) / 16
+			data.RFDopplerFrequency = &v
+		default:
+			continue
+		}
+		found = true
+	}
+
+	if !found {
+		return nil
+	}
+	return &data
+}
+
+// hasImpinjTagData returns true if any of the tag reads include Impinj fields.
+func hasImpinjTagData(reads []llrp.TagReportData) bool {
+	for i := range reads {
+		if impinjTagData(&reads[i]) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// impinjTagValues returns CommandValues for the Impinj fields of a tag read, if any.
+func impinjTagValues(ns int64, tr *llrp.TagReportData) ([]*dsModels.CommandValue, error) {
+	data := impinjTagData(tr)
+	if data == nil {
+		return nil, nil
+	}
+
+	var cvs []*dsModels.CommandValue
+	for _, f := range []struct {
+		resource string
+		value    *float64
+	}{
+		{ResourceTagPeakRSSI, data.PeakRSSI},
+		{ResourceTagPhaseAngle, data.RFPhaseAngle},
+		{ResourceTagDopplerFrequency, data.RFDopplerFrequency},
+	} {
+		if f.value == nil {
+			continue
+		}
+
+		cv, err := dsModels.NewFloat64Value(f.resource, ns, *f.value)
+		if err != nil {
+			return nil, err
+		}
+		cvs = append(cvs, cv)
+	}
+	return cvs, nil
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// impinjCustom returns an Impinj Custom parameter holding a 16-bit value.
+func impinjCustom(subtype uint32, value uint16) llrp.Custom {
+	return llrp.Custom{
+		VendorID: uint32(Impinj),
+		Subtype:  subtype,
+		Data:     []byte{byte(value >> 8), byte(value)},
+	}
+}
+
+func TestImpinjTagData(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	for _, testCase := range []struct {
+		name     string
+		customs  []llrp.Custom
+		expected *ImpinjTagData
+	}{
+		{
+			name: "all fields",
+			customs: []llrp.Custom{
+				impinjCustom(impinjPeakRSSI, uint16(0x10000-6150)), // -61.5 dBm
+				impinjCustom(impinjRFPhaseAngle, 1024),             // a quarter turn
+				impinjCustom(impinjRFDopplerFrequency, uint16(0x10000-40)),
+			},
+			expected: &ImpinjTagData{PeakRSSI: f(-61.5), RFPhaseAngle: f(90), RFDopplerFrequency: f(-2.5)},
+		},
+		{
+			name:     "some fields",
+			customs:  []llrp.Custom{impinjCustom(impinjRFPhaseAngle, 2048)},
+			expected: &ImpinjTagData{RFPhaseAngle: f(180)},
+		},
+		{
+			name:    "other vendor",
+			customs: []llrp.Custom{{VendorID: 1, Subtype: impinjPeakRSSI, Data: []byte{0xE8, 0x0A}}},
+		},
+		{
+			name:    "other Impinj field",
+			customs: []llrp.Custom{impinjCustom(impinjSerializedTID, 0)},
+		},
+		{
+			name:    "truncated",
+			customs: []llrp.Custom{{VendorID: uint32(Impinj), Subtype: impinjPeakRSSI, Data: []byte{0xE8}}},
+		},
+		{
+			name: "no customs",
+		},
+	} {
+		got := impinjTagData(&llrp.TagReportData{Custom: testCase.customs})
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("%s: expected %+v; got %+v", testCase.name, testCase.expected, got)
+		}
+	}
+}
+
+func TestWithLocations_impinjTagData(t *testing.T) {
+	report := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{
+		{Custom: []llrp.Custom{impinjCustom(impinjPeakRSSI, uint16(0x10000-5525))}},
+		{}, // e.g., from a non-Impinj Reader
+	}}
+
+	data, err := json.Marshal(withLocations(nil, nil, report))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		TagReportData []struct{ Impinj *ImpinjTagData }
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.TagReportData) != 2 {
+		t.Fatalf("expected 2 tag reads; got %s", data)
+	}
+
+	rssi := -55.25
+	if got := decoded.TagReportData[0].Impinj; !reflect.DeepEqual(got, &ImpinjTagData{PeakRSSI: &rssi}) {
+		t.Errorf("expected a peak RSSI of %v; got %+v", rssi, got)
+	}
+	if got := decoded.TagReportData[1].Impinj; got != nil {
+		t.Errorf("expected no Impinj data; got %+v", got)
+	}
+}
+
+func TestFlatReadValues_impinjTagData(t *testing.T) {
+	tr := &llrp.TagReportData{
+		EPC96: llrp.EPC96{EPC: []byte{0x30, 0x00}},
+		Custom: []llrp.Custom{
+			impinjCustom(impinjRFPhaseAngle, 3072),
+			impinjCustom(impinjRFDopplerFrequency, 48),
+		},
+	}
+
+	cvs, err := flatReadValues(time.Now(), OriginHost, nil, tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]float64{}
+	for _, cv := range cvs {
+		switch cv.DeviceResourceName {
+		case ResourceTagPeakRSSI, ResourceTagPhaseAngle, ResourceTagDopplerFrequency:
+			v, err := cv.Float64Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[cv.DeviceResourceName] = v
+		}
+	}
+
+	expected := map[string]float64{ResourceTagPhaseAngle: 270, ResourceTagDopplerFrequency: 3}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v; got %v", expected, got)
+	}
+}
@@ -0,0 +1,46 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"math"
+	"time"
+)
+
+// maxKeepAliveSeconds is the longest KeepAlive interval LLRP can express,
+// as the KeepAliveSpec's Interval is a uint32 number of milliseconds.
+const maxKeepAliveSeconds = math.MaxUint32 / 1000
+
+// checkKeepAlive returns an error if the KeepAlive interval is negative
+// or too long for a KeepAliveSpec.
+func checkKeepAlive(secs int) error {
+	if secs < 0 {
+		return errors.Errorf("KeepAlive interval must be at least 0; got %d seconds", secs)
+	}
+	if secs > maxKeepAliveSeconds {
+		return errors.Errorf("KeepAlive interval must be at most %d seconds; got %d seconds",
+			maxKeepAliveSeconds, secs)
+	}
+	return nil
+}
+
+// connTimeout returns how long a connection to a Reader sending KeepAlives
+// at the interval may go without receiving a message before it's considered dead,
+// or 0 if the interval is 0, in which case connections never time out.
+func connTimeout(keepAlive time.Duration) time.Duration {
+	return keepAlive * maxMissedKAs
+}
+
+// keepAliveSpec returns the KeepAliveSpec asking a Reader
+// to send KeepAlives at the interval.
+func keepAliveSpec(keepAlive time.Duration) *llrp.KeepAliveSpec {
+	return &llrp.KeepAliveSpec{
+		Trigger:  llrp.KATriggerPeriodic,
+		Interval: llrp.Millisecs32(keepAlive.Milliseconds()),
+	}
+}
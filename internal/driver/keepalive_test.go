@@ -0,0 +1,82 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_keepAliveSpec(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*10, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader passes along the KeepAliveSpec of each SetReaderConfig it receives.
+	specs := make(chan *llrp.KeepAliveSpec, 1)
+	rfid.SetResponseFunc(llrp.MsgSetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		conf := &llrp.SetReaderConfig{}
+		if err := msg.UnmarshalTo(conf); err != nil {
+			t.Errorf("expected a SetReaderConfig; got %v", err)
+		}
+		specs <- conf.KeepAliveSpec
+		return &llrp.SetReaderConfigResponse{}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	for _, testCase := range []struct {
+		name      string
+		keepAlive time.Duration
+		requested *llrp.KeepAliveSpec
+		expected  *llrp.KeepAliveSpec
+	}{
+		{
+			name:      "added",
+			keepAlive: 10 * time.Second,
+			expected:  &llrp.KeepAliveSpec{Trigger: llrp.KATriggerPeriodic, Interval: 10000},
+		},
+		{
+			name:      "overridden",
+			keepAlive: 10 * time.Second,
+			requested: &llrp.KeepAliveSpec{Trigger: llrp.KATriggerPeriodic, Interval: 5000},
+			expected:  &llrp.KeepAliveSpec{Trigger: llrp.KATriggerPeriodic, Interval: 10000},
+		},
+		{
+			name:      "disabled, left alone",
+			requested: &llrp.KeepAliveSpec{Trigger: llrp.KATriggerPeriodic, Interval: 5000},
+			expected:  &llrp.KeepAliveSpec{Trigger: llrp.KATriggerPeriodic, Interval: 5000},
+		},
+		{
+			name: "disabled, not added",
+		},
+	} {
+		dev := &LLRPDevice{
+			name:      "localReader",
+			client:    c,
+			lc:        edgexCompatTestLogger{t},
+			keepAlive: testCase.keepAlive,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		err := dev.TrySend(ctx, &llrp.SetReaderConfig{KeepAliveSpec: testCase.requested},
+			&llrp.SetReaderConfigResponse{})
+		cancel()
+		if err != nil {
+			t.Fatalf("%s: %+v", testCase.name, err)
+		}
+
+		got := <-specs
+		if (got == nil) != (testCase.expected == nil) ||
+			(got != nil && *got != *testCase.expected) {
+			t.Errorf("%s: expected KeepAliveSpec %+v; got %+v", testCase.name, testCase.expected, got)
+		}
+	}
+}
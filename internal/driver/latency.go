@@ -0,0 +1,151 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ResourceSlowCommand is sent as an event when a command's round trip to the Reader
+	// takes longer than the device's slowThreshold.
+	ResourceSlowCommand = "SlowCommand"
+
+	// ResourceCommandLatency is a read-only resource with the device's
+	// command round-trip latency statistics, by message type.
+	ResourceCommandLatency = "CommandLatency"
+)
+
+// slowCommandEvent is the value of ResourceSlowCommand events.
+type slowCommandEvent struct {
+	Message         string // the type of the command's message, e.g. "AddROSpec"
+	LatencyMillis   int64  // how long the Reader took to reply
+	ThresholdMillis int64  // the device's slowThreshold
+}
+
+// LatencyStats summarizes the round-trip latency of a type of command
+// since the service started managing the device.
+type LatencyStats struct {
+	Count      uint64  // commands measured
+	Slow       uint64  // commands that exceeded the slowThreshold
+	LastMillis float64 // latency of the most recent command
+	MeanMillis float64
+	MaxMillis  float64
+}
+
+// latencyTracker accumulates command latency by message type.
+// Its zero value is ready to use.
+type latencyTracker struct {
+	mu    sync.Mutex
+	stats map[string]*latencyTotals
+}
+
+// latencyTotals are the running totals behind LatencyStats.
+type latencyTotals struct {
+	count, slow uint64
+	last, max   time.Duration
+	total       time.Duration
+}
+
+// record adds a command's latency to the totals for its message type.
+func (lt *latencyTracker) record(msgType string, latency time.Duration, slow bool) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	if lt.stats == nil {
+		lt.stats = make(map[string]*latencyTotals)
+	}
+	t, ok := lt.stats[msgType]
+	if !ok {
+		t = &latencyTotals{}
+		lt.stats[msgType] = t
+	}
+
+	t.count++
+	if slow {
+		t.slow++
+	}
+	t.last = latency
+	t.total += latency
+	if latency > t.max {
+		t.max = latency
+	}
+}
+
+// snapshot returns the current LatencyStats by message type.
+func (lt *latencyTracker) snapshot() map[string]LatencyStats {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	snap := make(map[string]LatencyStats, len(lt.stats))
+	for msgType, t := range lt.stats {
+		snap[msgType] = LatencyStats{
+			Count:      t.count,
+			Slow:       t.slow,
+			LastMillis: millis(t.last),
+			MeanMillis: millis(t.total / time.Duration(t.count)),
+			MaxMillis:  millis(t.max),
+		}
+	}
+	return snap
+}
+
+// millis returns d in fractional milliseconds.
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// CommandLatency returns the device's command latency statistics by message type.
+func (l *LLRPDevice) CommandLatency() map[string]LatencyStats {
+	return l.latency.snapshot()
+}
+
+// recordLatency adds a command's round-trip latency to the device's statistics,
+// and if it exceeds the device's slowThreshold, logs it and sends a ResourceSlowCommand event.
+func (l *LLRPDevice) recordLatency(typ llrp.MessageType, latency time.Duration) {
+	l.deviceMu.RLock()
+	threshold := l.slowThreshold
+	l.deviceMu.RUnlock()
+
+	msgType := strings.TrimPrefix(typ.String(), "Msg")
+	slow := threshold > 0 && latency > threshold
+	l.latency.record(msgType, latency, slow)
+	if !slow {
+		return
+	}
+
+	l.lc.Warn("Reader was slow to reply.", "device", l.name, "message", msgType,
+		"latency", latency.String(), "threshold", threshold.String())
+
+	ns := l.clock().Now().UnixNano()
+	l.goSend(func() {
+		l.sendEdgeXEvent(ResourceSlowCommand, ns, slowCommandEvent{
+			Message:         msgType,
+			LatencyMillis:   latency.Milliseconds(),
+			ThresholdMillis: threshold.Milliseconds(),
+		})
+	})
+}
+
+// getSlowThreshold returns the commands protocol's slowThreshold property,
+// or 0 if it's missing, which disables slow command events.
+func getSlowThreshold(protocols protocolMap) (time.Duration, error) {
+	threshold := protocols[ProtocolCommands]["slowThreshold"]
+	if threshold == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(threshold)
+	if err != nil || d <= 0 {
+		return 0, errors.Errorf("%s slowThreshold must be a positive duration, e.g. \"2s\", but is %q",
+			ProtocolCommands, threshold)
+	}
+	return d, nil
+}
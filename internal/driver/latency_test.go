@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_recordLatency(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader "takes" however long the test says to reply.
+	clk := newFakeClock()
+	delays := make(chan time.Duration, 1)
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(llrp.Message) llrp.Outgoing {
+		clk.Advance(<-delays)
+		return &llrp.GetReaderConfigResponse{}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	ch := make(chan *dsModels.AsyncValues, 1)
+	dev := &LLRPDevice{
+		name:          "localReader",
+		client:        c,
+		lc:            edgexCompatTestLogger{t},
+		ch:            ch,
+		clk:           clk,
+		slowThreshold: 2 * time.Second,
+	}
+
+	send := func(delay time.Duration) {
+		t.Helper()
+		delays <- delay
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		if err := dev.TrySend(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{}); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	send(3 * time.Second)
+
+	var av *dsModels.AsyncValues
+	select {
+	case av = <-ch:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a %s event", ResourceSlowCommand)
+	}
+
+	if len(av.CommandValues) != 1 || av.CommandValues[0].DeviceResourceName != ResourceSlowCommand {
+		t.Fatalf("expected a %s event; got %+v", ResourceSlowCommand, av)
+	}
+
+	event := slowCommandEvent{}
+	if err := json.Unmarshal([]byte(av.CommandValues[0].ValueToString()), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := slowCommandEvent{Message: "GetReaderConfig", LatencyMillis: 3000, ThresholdMillis: 2000}
+	if event != expected {
+		t.Errorf("expected %+v; got %+v", expected, event)
+	}
+
+	// Commands within the threshold are measured, but don't send events.
+	send(1 * time.Second)
+	select {
+	case av := <-ch:
+		t.Errorf("expected no event for a fast command; got %+v", av)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	stats := dev.CommandLatency()
+	expectedStats := LatencyStats{Count: 2, Slow: 1, LastMillis: 1000, MeanMillis: 2000, MaxMillis: 3000}
+	if len(stats) != 1 || stats["GetReaderConfig"] != expectedStats {
+		t.Errorf("expected GetReaderConfig stats %+v; got %+v", expectedStats, stats)
+	}
+}
+
+func TestGetSlowThreshold(t *testing.T) {
+	for _, testCase := range []struct {
+		threshold string
+		expected  time.Duration
+		invalid   bool
+	}{
+		{threshold: "", expected: 0},
+		{threshold: "2s", expected: 2 * time.Second},
+		{threshold: "250ms", expected: 250 * time.Millisecond},
+		{threshold: "0s", invalid: true},
+		{threshold: "-1s", invalid: true},
+		{threshold: "2", invalid: true},
+	} {
+		protocols := protocolMap{ProtocolCommands: contract.ProtocolProperties{"slowThreshold": testCase.threshold}}
+		got, err := getSlowThreshold(protocols)
+		if testCase.invalid != (err != nil) {
+			t.Errorf("%q: expected invalid: %v; got %v", testCase.threshold, testCase.invalid, err)
+		}
+		if got != testCase.expected {
+			t.Errorf("%q: expected %v; got %v", testCase.threshold, testCase.expected, got)
+		}
+	}
+}
@@ -33,12 +33,6 @@ func (l *edgexLLRPClientLogger) ReceivedMsg(h llrp.Header, ver llrp.VersionNum)
 	default:
 		l.lc.Info("Incoming LLRP message", "type", h.Type().String(), "device", l.devName)
 	}
-
-	if ver != h.Version() {
-		l.lc.Warn("LLRP incoming message version mismatch",
-			"message-version", h.Version().String(),
-			"client-version", ver.String())
-	}
 }
 
 func (l *edgexLLRPClientLogger) MsgHandled(h llrp.Header) {
@@ -49,6 +43,11 @@ func (l *edgexLLRPClientLogger) MsgUnhandled(h llrp.Header) {
 	l.lc.Debug("Ignored LLRP message.", "type", h.Type().String(), "device", l.devName)
 }
 
+func (l *edgexLLRPClientLogger) MsgUnmatched(h llrp.Header) {
+	l.lc.Warn("Reader sent a reply that doesn't match an outstanding request.",
+		"type", h.Type().String(), "device", l.devName, "header", h.String())
+}
+
 func (l *edgexLLRPClientLogger) HandlerPanic(h llrp.Header, err error) {
 	l.lc.Error("LLRP message handler panic'd (recovered).",
 		"type", h.Type().String(), "device", l.devName, "error", err.Error())
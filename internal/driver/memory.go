@@ -0,0 +1,99 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strconv"
+)
+
+// C1G2 memory banks.
+const (
+	memoryBankReserved = llrp.C1G2MemoryBankType(0)
+	memoryBankEPC      = llrp.C1G2MemoryBankType(1)
+	memoryBankTID      = llrp.C1G2MemoryBankType(2)
+	memoryBankUser     = llrp.C1G2MemoryBankType(3)
+)
+
+// TagMemoryRead is the result of an AccessSpec's C1G2Read in a tag read,
+// labeled with the memory bank and word address the C1G2Read asked for,
+// since the Reader's C1G2ReadOpSpecResult only includes the data.
+type TagMemoryRead struct {
+	MemoryBank  string // Reserved, EPC, TID, or User
+	WordAddress uint16 // the first word read
+	Result      string // Success, or why the read failed
+	Data        string `json:",omitempty"` // the words read, as hex; empty if the read failed
+}
+
+// memoryBankName returns the name of a C1G2 memory bank,
+// or its number if it isn't one C1G2 defines.
+func memoryBankName(bank llrp.C1G2MemoryBankType) string {
+	switch bank {
+	case memoryBankReserved:
+		return "Reserved"
+	case memoryBankEPC:
+		return "EPC"
+	case memoryBankTID:
+		return "TID"
+	case memoryBankUser:
+		return "User"
+	}
+	return "Unknown(" + strconv.Itoa(int(bank)) + ")"
+}
+
+// readResultName returns the name of a C1G2Read's result,
+// or its number if it isn't one LLRP defines.
+func readResultName(result llrp.C1G2ReadOpSpecResultType) string {
+	if result <= llrp.C1G2ReadIncorrectPasswordError {
+		return result.String()
+	}
+	return "Unknown(" + strconv.Itoa(int(result)) + ")"
+}
+
+// hasReadResults returns true if any of the tag reads
+// include the result of an AccessSpec's C1G2Read.
+func hasReadResults(reads []llrp.TagReportData) bool {
+	for i := range reads {
+		if reads[i].AccessSpecID != nil && reads[i].C1G2ReadOpSpecResult != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// tagMemoryRead returns the C1G2Read result in the tag read,
+// labeled using the C1G2Read with the same OpSpecID in readOps,
+// which holds the C1G2Reads of the AccessSpecs the service added, by AccessSpecID.
+// It returns nil if the tag read doesn't have a C1G2Read result,
+// or if its AccessSpec isn't in readOps, e.g. because another client added it.
+func tagMemoryRead(readOps map[uint32]llrp.C1G2Read, tr *llrp.TagReportData) *TagMemoryRead {
+	result := tr.C1G2ReadOpSpecResult
+	if tr.AccessSpecID == nil || result == nil {
+		return nil
+	}
+
+	op, ok := readOps[uint32(*tr.AccessSpecID)]
+	if !ok || op.OpSpecID != result.OpSpecID {
+		return nil
+	}
+
+	mr := &TagMemoryRead{
+		MemoryBank:  memoryBankName(op.C1G2MemoryBank),
+		WordAddress: op.WordAddress,
+		Result:      readResultName(result.C1G2ReadOpSpecResultType),
+	}
+
+	if result.C1G2ReadOpSpecResultType == readSuccess {
+		data := make([]byte, 2*len(result.Data))
+		for i, w := range result.Data {
+			binary.BigEndian.PutUint16(data[2*i:], w)
+		}
+		mr.Data = hex.EncodeToString(data)
+	}
+	return mr
+}
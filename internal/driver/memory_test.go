@@ -0,0 +1,156 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"reflect"
+	"testing"
+)
+
+func TestTagMemoryRead(t *testing.T) {
+	readOps := map[uint32]llrp.C1G2Read{
+		7: {OpSpecID: 1, C1G2MemoryBank: memoryBankUser, WordAddress: 4, WordCount: 2},
+	}
+	asID := func(id uint32) *llrp.AccessSpecID {
+		asID := llrp.AccessSpecID(id)
+		return &asID
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		tr       llrp.TagReportData
+		expected *TagMemoryRead
+	}{
+		{
+			name: "success",
+			tr: llrp.TagReportData{AccessSpecID: asID(7), C1G2ReadOpSpecResult: &llrp.C1G2ReadOpSpecResult{
+				OpSpecID: 1, Data: []uint16{0xCAFE, 0x0102},
+			}},
+			expected: &TagMemoryRead{MemoryBank: "User", WordAddress: 4, Result: "Success", Data: "cafe0102"},
+		},
+		{
+			name: "failure",
+			tr: llrp.TagReportData{AccessSpecID: asID(7), C1G2ReadOpSpecResult: &llrp.C1G2ReadOpSpecResult{
+				C1G2ReadOpSpecResultType: 5, OpSpecID: 1,
+			}},
+			expected: &TagMemoryRead{MemoryBank: "User", WordAddress: 4, Result: "MemoryLockedError"},
+		},
+		{
+			name: "unknown result",
+			tr: llrp.TagReportData{AccessSpecID: asID(7), C1G2ReadOpSpecResult: &llrp.C1G2ReadOpSpecResult{
+				C1G2ReadOpSpecResultType: 99, OpSpecID: 1,
+			}},
+			expected: &TagMemoryRead{MemoryBank: "User", WordAddress: 4, Result: "Unknown(99)"},
+		},
+		{
+			name: "untracked AccessSpec",
+			tr: llrp.TagReportData{AccessSpecID: asID(8), C1G2ReadOpSpecResult: &llrp.C1G2ReadOpSpecResult{
+				OpSpecID: 1, Data: []uint16{0xCAFE},
+			}},
+		},
+		{
+			name: "other OpSpec",
+			tr: llrp.TagReportData{AccessSpecID: asID(7), C1G2ReadOpSpecResult: &llrp.C1G2ReadOpSpecResult{
+				OpSpecID: 2, Data: []uint16{0xCAFE},
+			}},
+		},
+		{
+			name: "no read",
+			tr:   llrp.TagReportData{AccessSpecID: asID(7)},
+		},
+	} {
+		if got := tagMemoryRead(readOps, &testCase.tr); !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("%s: expected %+v; got %+v", testCase.name, testCase.expected, got)
+		}
+	}
+}
+
+func TestWithLocations_memoryReads(t *testing.T) {
+	asID := llrp.AccessSpecID(7)
+	report := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{
+		{
+			AccessSpecID: &asID,
+			C1G2ReadOpSpecResult: &llrp.C1G2ReadOpSpecResult{
+				OpSpecID: 1, Data: []uint16{0xE280, 0x1160},
+			},
+		},
+		{
+			AccessSpecID: &asID,
+			C1G2ReadOpSpecResult: &llrp.C1G2ReadOpSpecResult{
+				C1G2ReadOpSpecResultType: 2, OpSpecID: 1,
+			},
+		},
+		{}, // a read without an AccessSpec
+	}}
+
+	readOps := map[uint32]llrp.C1G2Read{
+		7: {OpSpecID: 1, C1G2MemoryBank: memoryBankTID, WordCount: 2},
+	}
+
+	data, err := json.Marshal(withLocations(nil, readOps, report))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		TagReportData []struct {
+			TID        string
+			MemoryRead *TagMemoryRead
+		}
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.TagReportData) != 3 {
+		t.Fatalf("expected 3 tag reads; got %s", data)
+	}
+
+	// Reading the TID bank from its start provides the TID.
+	if tr := decoded.TagReportData[0]; tr.TID != "e2801160" || !reflect.DeepEqual(tr.MemoryRead,
+		&TagMemoryRead{MemoryBank: "TID", Result: "Success", Data: "e2801160"}) {
+		t.Errorf("expected the TID read; got %+v", tr)
+	}
+
+	if tr := decoded.TagReportData[1]; tr.TID != "" || !reflect.DeepEqual(tr.MemoryRead,
+		&TagMemoryRead{MemoryBank: "TID", Result: "NoResponseFromTag"}) {
+		t.Errorf("expected the failed TID read; got %+v", tr)
+	}
+
+	if tr := decoded.TagReportData[2]; tr.TID != "" || tr.MemoryRead != nil {
+		t.Errorf("expected no memory read; got %+v", tr)
+	}
+
+	// Without OpSpec results or tracked C1G2Reads, the report is unchanged.
+	unlabeled := &llrp.ROAccessReport{TagReportData: report.TagReportData[2:]}
+	if got := withLocations(nil, nil, unlabeled); got != unlabeled {
+		t.Errorf("expected the report unchanged; got %+v", got)
+	}
+}
+
+func TestSpecTracker_readOps(t *testing.T) {
+	read := llrp.C1G2Read{OpSpecID: 1, C1G2MemoryBank: memoryBankTID, WordCount: 6}
+
+	st := specTracker{}
+	for _, msg := range []llrp.Outgoing{
+		&llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{AccessSpecID: 10,
+			AccessCommand: llrp.AccessCommand{C1G2Read: &read}}},
+		&llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{AccessSpecID: 11,
+			AccessCommand: llrp.AccessCommand{C1G2Write: &llrp.C1G2Write{OpSpecID: 1}}}},
+		&llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{AccessSpecID: 12,
+			AccessCommand: llrp.AccessCommand{C1G2Read: &read}}},
+		&llrp.DeleteAccessSpec{AccessSpecID: 12},
+	} {
+		st.record(msg)
+	}
+
+	expected := map[uint32]llrp.C1G2Read{10: read}
+	if got := st.readOps(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v; got %+v", expected, got)
+	}
+}
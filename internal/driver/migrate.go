@@ -0,0 +1,198 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"net"
+	"time"
+)
+
+const (
+	// ResourceMigrateAddress moves a device to a new address,
+	// but only once it confirms the Reader there is the one it's connected to now.
+	ResourceMigrateAddress = "MigrateAddress"
+
+	// ResourceAddressMigration is sent as an event with the outcome of each MigrateAddress.
+	ResourceAddressMigration = "AddressMigration"
+
+	// migrationProbeTimeout limits how long a migration waits to identify the Reader
+	// at the device's current address and at the new one.
+	migrationProbeTimeout = 5 * time.Second
+)
+
+// Outcomes of address migrations.
+const (
+	// MigrationMigrated means the device now uses the new address.
+	MigrationMigrated = "Migrated"
+	// MigrationUnverifiable means the service couldn't tell which Reader the device uses,
+	// because it couldn't reach it and its protocols don't have a readerID,
+	// so it left the device alone.
+	MigrationUnverifiable = "Unverifiable"
+	// MigrationUnreachable means the service couldn't identify a Reader at the new address.
+	MigrationUnreachable = "Unreachable"
+	// MigrationMismatch means a different Reader is at the new address.
+	MigrationMismatch = "Mismatch"
+	// MigrationRolledBack means the device switched to the new address,
+	// but couldn't confirm the Reader or save the address, so it switched back.
+	MigrationRolledBack = "RolledBack"
+)
+
+// migrateAddressRequest is the value written to ResourceMigrateAddress.
+type migrateAddressRequest struct {
+	Host string
+	Port string
+}
+
+// addressMigration is the value of ResourceAddressMigration events.
+type addressMigration struct {
+	OldAddress string
+	NewAddress string
+	ReaderID   string // the Reader the device uses, if known
+	// FoundReaderID is the Reader at the new address, if it's a different one.
+	FoundReaderID string `json:",omitempty"`
+	Outcome       string // e.g., Migrated or Mismatch
+	Reason        string `json:",omitempty"` // why the migration failed, if it did
+}
+
+// migrateAddress moves the device to the host and port,
+// but only if the Reader there is the one the device uses now,
+// either as it reports itself or, if it's unreachable, per its protocols' readerID.
+// It probes the new address before switching the device to it,
+// then confirms the Reader over the device's own connection
+// and saves the new address in the device's protocols,
+// switching back to the old address if either fails.
+//
+// It sends a ResourceAddressMigration event with the outcome
+// and returns an error if the device didn't migrate.
+func (d *Driver) migrateAddress(ctx context.Context, dev *LLRPDevice, protocols protocolMap,
+	req migrateAddressRequest) error {
+	newProtocols := protocolMap{"tcp": contract.ProtocolProperties{"host": req.Host, "port": req.Port}}
+	newAddr, err := getAddr(newProtocols)
+	if err != nil {
+		return errors.WithMessage(err, "invalid address to migrate to")
+	}
+
+	dev.deviceMu.RLock()
+	oldAddr := dev.address
+	dev.deviceMu.RUnlock()
+
+	if sameAddr(oldAddr, newAddr) {
+		return errors.Errorf("device %q already uses %v", dev.name, newAddr)
+	}
+
+	result := addressMigration{
+		OldAddress: addrString(oldAddr),
+		NewAddress: newAddr.String(),
+	}
+
+	// fail sends the event for a failed migration and returns an error describing it.
+	fail := func(outcome string, err error) error {
+		result.Outcome = outcome
+		result.Reason = err.Error()
+		dev.sendEdgeXEvent(ResourceAddressMigration, d.clock().Now().UnixNano(), result)
+		return errors.WithMessagef(err, "did not migrate device %q to %v (%s)", dev.name, newAddr, outcome)
+	}
+
+	// A device being migrated often can't reach its Reader, so don't wait long to find out.
+	idCtx, cancel := context.WithTimeout(ctx, migrationProbeTimeout)
+	id, err := dev.ReaderID(idCtx)
+	cancel()
+	if err == nil {
+		result.ReaderID = id.String()
+	} else {
+		result.ReaderID = protocols["tcp"][PropertyReaderID]
+		if result.ReaderID == "" {
+			return fail(MigrationUnverifiable, errors.WithMessage(err,
+				"the Reader is unreachable and the device's protocols don't have a "+PropertyReaderID))
+		}
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, migrationProbeTimeout)
+	info, err := probe(probeCtx, req.Host, req.Port, migrationProbeTimeout)
+	cancel()
+	if err != nil {
+		return fail(MigrationUnreachable, errors.WithMessage(err, "failed to identify a Reader at the new address"))
+	}
+	if info.readerID != result.ReaderID {
+		result.FoundReaderID = info.readerID
+		return fail(MigrationMismatch, errors.Errorf("the Reader at the new address is %s, not %s",
+			info.readerID, result.ReaderID))
+	}
+
+	// The probe closed its connection, so the device can connect now.
+	rollback := func(err error) error {
+		if rbErr := dev.UpdateAddr(ctx, oldAddr); rbErr != nil {
+			err = errors.Errorf("%v; switching back also failed: %v", err, rbErr)
+		}
+		return fail(MigrationRolledBack, err)
+	}
+
+	if err := dev.UpdateAddr(ctx, newAddr); err != nil {
+		return rollback(errors.WithMessage(err, "failed to switch to the new address"))
+	}
+
+	// The device reconnects in the background, so wait for it rather than fail fast.
+	// The mode is a known one, so this can't fail.
+	waitCtx, _ := withReconnectMode(ctx, map[string]string{AttribReconnect: ReconnectWait})
+	confirmCtx, cancel := context.WithTimeout(waitCtx, migrationProbeTimeout)
+	id, err = dev.ReaderID(confirmCtx)
+	cancel()
+	if err != nil {
+		return rollback(errors.WithMessage(err, "failed to confirm the Reader at the new address"))
+	}
+	if id.String() != result.ReaderID {
+		result.FoundReaderID = id.String()
+		return rollback(errors.Errorf("the device reached Reader %s at the new address, not %s",
+			id.String(), result.ReaderID))
+	}
+
+	if err := d.saveAddress(dev.name, req.Host, req.Port, result.ReaderID); err != nil {
+		return rollback(err)
+	}
+
+	result.Outcome = MigrationMigrated
+	dev.sendEdgeXEvent(ResourceAddressMigration, d.clock().Now().UnixNano(), result)
+	d.lc.Info("Migrated device to a new address.", "device", dev.name,
+		"oldAddress", result.OldAddress, "newAddress", result.NewAddress)
+	return nil
+}
+
+// saveAddress updates the device's tcp protocol with the host, port, and readerID in EdgeX,
+// keeping its other properties.
+func (d *Driver) saveAddress(name, host, port, readerID string) error {
+	device, err := d.svc.GetDeviceByName(name)
+	if err != nil {
+		return errors.Wrap(err, "failed to get the device to save its new address")
+	}
+
+	updated := contract.ProtocolProperties{}
+	for k, v := range device.Protocols["tcp"] {
+		updated[k] = v
+	}
+	updated["host"] = host
+	updated["port"] = port
+	updated[PropertyReaderID] = readerID
+
+	protocols := make(map[string]contract.ProtocolProperties, len(device.Protocols))
+	for k, v := range device.Protocols {
+		protocols[k] = v
+	}
+	protocols["tcp"] = updated
+	device.Protocols = protocols
+
+	return errors.Wrap(d.svc.UpdateDevice(device), "failed to save the device's new address")
+}
+
+// addrString returns the address as a string, or an empty string if it's nil.
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}
@@ -0,0 +1,140 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDriver_migrateAddress(t *testing.T) {
+	// The Reader the device uses answers at two addresses, and a different Reader at a third.
+	readerIDs := []byte{1, 1, 2}
+	ports := make([]int, len(readerIDs))
+	for i, readerID := range readerIDs {
+		var emu *llrp.TestEmulator
+		emu, ports[i] = startEmulator(t, readerID)
+		emu.SetResponse(llrp.MsgGetReaderCapabilities, &llrp.GetReaderCapabilitiesResponse{})
+	}
+	unusedPort := freePort(t)
+
+	migrations := make(chan addressMigration, 10)
+	d := newTestDriver(func(av *dsModels.AsyncValues) {
+		for _, cv := range av.CommandValues {
+			if cv.DeviceResourceName != ResourceAddressMigration {
+				continue
+			}
+			m := addressMigration{}
+			if err := json.Unmarshal([]byte(cv.ValueToString()), &m); err != nil {
+				t.Errorf("failed to unmarshal %s: %+v", ResourceAddressMigration, err)
+			}
+			migrations <- m
+		}
+	})
+
+	svc.clearDevices()
+	defer svc.clearDevices()
+
+	const name = "migratingReader"
+	protocols := protocolMap{"tcp": {"host": "127.0.0.1", "port": strconv.Itoa(ports[0])}}
+	if _, err := svc.AddDevice(contract.Device{Name: name, Protocols: protocols}); err != nil {
+		t.Fatal(err)
+	}
+
+	dev := d.NewLLRPDevice(name, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: ports[0]}, contract.Enabled)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		_ = dev.Stop(ctx)
+	}()
+
+	expectOutcome := func(t *testing.T, outcome string) addressMigration {
+		t.Helper()
+		select {
+		case m := <-migrations:
+			if m.Outcome != outcome {
+				t.Errorf("expected a %s migration; got %+v", outcome, m)
+			}
+			return m
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for a %s migration event", outcome)
+			return addressMigration{}
+		}
+	}
+
+	expectAddr := func(t *testing.T, port int) {
+		t.Helper()
+		dev.deviceMu.RLock()
+		addr := dev.address
+		dev.deviceMu.RUnlock()
+		if !sameAddr(addr, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}) {
+			t.Errorf("expected the device at port %d; got %v", port, addr)
+		}
+	}
+
+	migrate := func(port int) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		return d.migrateAddress(ctx, dev, protocols,
+			migrateAddressRequest{Host: "127.0.0.1", Port: strconv.Itoa(port)})
+	}
+
+	t.Run("mismatch", func(t *testing.T) {
+		if err := migrate(ports[2]); err == nil {
+			t.Fatal("expected an error migrating to a different Reader")
+		}
+		m := expectOutcome(t, MigrationMismatch)
+		if m.ReaderID == "" || m.FoundReaderID == "" || m.ReaderID == m.FoundReaderID {
+			t.Errorf("expected different Reader IDs; got %+v", m)
+		}
+		expectAddr(t, ports[0])
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		if err := migrate(unusedPort); err == nil {
+			t.Fatal("expected an error migrating to an address without a Reader")
+		}
+		expectOutcome(t, MigrationUnreachable)
+		expectAddr(t, ports[0])
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		if err := migrate(ports[0]); err == nil {
+			t.Fatal("expected an error migrating to the device's current address")
+		}
+	})
+
+	t.Run("migrated", func(t *testing.T) {
+		if err := migrate(ports[1]); err != nil {
+			t.Fatalf("%+v", err)
+		}
+		m := expectOutcome(t, MigrationMigrated)
+		if m.ReaderID == "" || m.FoundReaderID != "" {
+			t.Errorf("expected only the device's Reader ID; got %+v", m)
+		}
+		expectAddr(t, ports[1])
+
+		saved, err := svc.GetDeviceByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tcp := saved.Protocols["tcp"]
+		if tcp["port"] != strconv.Itoa(ports[1]) || tcp[PropertyReaderID] != m.ReaderID {
+			t.Errorf("expected the new address and Reader ID to be saved; got %+v", tcp)
+		}
+		// The device's original protocols aren't modified in place.
+		if protocols["tcp"]["port"] != strconv.Itoa(ports[0]) {
+			t.Errorf("expected the original protocols to be unchanged; got %+v", protocols["tcp"])
+		}
+	})
+}
@@ -0,0 +1,85 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strconv"
+)
+
+// OpSpecResult is the outcome of one of an AccessSpec's OpSpecs on a tag,
+// labeled with the name of its operation and result,
+// since the Reader's OpSpec results only include their numeric codes.
+type OpSpecResult struct {
+	OpSpecID  uint16
+	Operation string // Read, Write, Kill, Lock, BlockErase, BlockWrite, etc.
+	Result    string // Success, or why the operation failed
+}
+
+// opSpecResult returns an OpSpecResult with the name of the result,
+// or its number if it's greater than the last one LLRP defines for the operation.
+func opSpecResult(op string, opSpecID uint16, result fmt.Stringer, code, last uint8) OpSpecResult {
+	name := "Unknown(" + strconv.Itoa(int(code)) + ")"
+	if code <= last {
+		name = result.String()
+	}
+	return OpSpecResult{OpSpecID: opSpecID, Operation: op, Result: name}
+}
+
+// tagOpSpecResults returns the results of the OpSpecs in the tag read,
+// in the order LLRP lists their parameters, or nil if it has none.
+func tagOpSpecResults(tr *llrp.TagReportData) []OpSpecResult {
+	var results []OpSpecResult
+	if r := tr.C1G2ReadOpSpecResult; r != nil {
+		results = append(results, opSpecResult("Read", r.OpSpecID, r.C1G2ReadOpSpecResultType,
+			uint8(r.C1G2ReadOpSpecResultType), uint8(llrp.C1G2ReadIncorrectPasswordError)))
+	}
+	if r := tr.C1G2WriteOpSpecResult; r != nil {
+		results = append(results, opSpecResult("Write", r.OpSpecID, r.C1G2WriteOpSpecResultType,
+			uint8(r.C1G2WriteOpSpecResultType), uint8(llrp.C1G2WriteIncorrectPasswordError)))
+	}
+	if r := tr.C1G2KillOpSpecResult; r != nil {
+		results = append(results, opSpecResult("Kill", r.OpSpecID, r.C1G2KillResult,
+			uint8(r.C1G2KillResult), uint8(llrp.C1G2KillIncorrectPasswordError)))
+	}
+	if r := tr.C1G2LockOpSpecResult; r != nil {
+		results = append(results, opSpecResult("Lock", r.OpSpecID, r.C1G2LockResult,
+			uint8(r.C1G2LockResult), uint8(llrp.C1G2LockMemoryLockedError)))
+	}
+	if r := tr.C1G2BlockEraseOpSpecResult; r != nil {
+		results = append(results, opSpecResult("BlockErase", r.OpSpecID, r.C1G2BlockEraseResult,
+			uint8(r.C1G2BlockEraseResult), uint8(llrp.C1G2BlockEraseIncorrectPasswordError)))
+	}
+	if r := tr.C1G2BlockWriteOpSpecResult; r != nil {
+		results = append(results, opSpecResult("BlockWrite", r.OpSpecID, r.C1G2BlockWriteResult,
+			uint8(r.C1G2BlockWriteResult), uint8(llrp.C1G2BlockWriteIncorrectPasswordError)))
+	}
+	if r := tr.C1G2RecommissionOpSpecResult; r != nil {
+		results = append(results, opSpecResult("Recommission", r.OpSpecID, r.C1G2RecommissionResult,
+			uint8(r.C1G2RecommissionResult), uint8(llrp.C1G2RecommissionIncorrectPasswordError)))
+	}
+	if r := tr.C1G2BlockPermalockOpSpecResult; r != nil {
+		results = append(results, opSpecResult("BlockPermalock", r.OpSpecID, r.C1G2BlockPermalockResult,
+			uint8(r.C1G2BlockPermalockResult), uint8(llrp.C1G2BPLockMemoryOverrun)))
+	}
+	if r := tr.C1G2GetBlockPermalockStatusOpSpecResult; r != nil {
+		results = append(results, opSpecResult("GetBlockPermalockStatus", r.OpSpecID,
+			r.C1G2GetBlockPermalockStatusResult, uint8(r.C1G2GetBlockPermalockStatusResult),
+			uint8(llrp.C1G2GetBPLockStatusMemoryOverrunError)))
+	}
+	return results
+}
+
+// hasOpSpecResults returns true if any of the tag reads include an OpSpec's result.
+func hasOpSpecResults(reads []llrp.TagReportData) bool {
+	for i := range reads {
+		if tagOpSpecResults(&reads[i]) != nil {
+			return true
+		}
+	}
+	return false
+}
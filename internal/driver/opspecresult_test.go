@@ -0,0 +1,104 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"reflect"
+	"testing"
+)
+
+func TestTagOpSpecResults(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		tr       llrp.TagReportData
+		expected []OpSpecResult
+	}{
+		{name: "none"},
+		{
+			name: "write",
+			tr: llrp.TagReportData{C1G2WriteOpSpecResult: &llrp.C1G2WriteOpSpecResult{
+				C1G2WriteOpSpecResultType: llrp.C1G2WriteNoResponseFromTag, OpSpecID: 3,
+			}},
+			expected: []OpSpecResult{{OpSpecID: 3, Operation: "Write", Result: "NoResponseFromTag"}},
+		},
+		{
+			name: "several",
+			tr: llrp.TagReportData{
+				C1G2ReadOpSpecResult: &llrp.C1G2ReadOpSpecResult{OpSpecID: 1},
+				C1G2LockOpSpecResult: &llrp.C1G2LockOpSpecResult{
+					C1G2LockResult: llrp.C1G2LockIncorrectPasswordError, OpSpecID: 2,
+				},
+				C1G2BlockPermalockOpSpecResult: &llrp.C1G2BlockPermalockOpSpecResult{
+					C1G2BlockPermalockResult: llrp.C1G2BPLockMemoryOverrun, OpSpecID: 4,
+				},
+			},
+			expected: []OpSpecResult{
+				{OpSpecID: 1, Operation: "Read", Result: "Success"},
+				{OpSpecID: 2, Operation: "Lock", Result: "IncorrectPasswordError"},
+				{OpSpecID: 4, Operation: "BlockPermalock", Result: "MemoryOverrunError"},
+			},
+		},
+		{
+			name: "unknown",
+			tr: llrp.TagReportData{C1G2KillOpSpecResult: &llrp.C1G2KillOpSpecResult{
+				C1G2KillResult: 7, OpSpecID: 5,
+			}},
+			expected: []OpSpecResult{{OpSpecID: 5, Operation: "Kill", Result: "Unknown(7)"}},
+		},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := tagOpSpecResults(&testCase.tr); !reflect.DeepEqual(got, testCase.expected) {
+				t.Errorf("expected %+v; got %+v", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestWithLocations_opSpecResults(t *testing.T) {
+	report := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{
+		{C1G2BlockWriteOpSpecResult: &llrp.C1G2BlockWriteOpSpecResult{
+			C1G2BlockWriteResult: llrp.C1G2BlockWriteMemoryLockedError, OpSpecID: 2,
+		}},
+		{}, // a read without an AccessSpec
+	}}
+
+	data, err := json.Marshal(withLocations(nil, nil, report))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded struct {
+		TagReportData []struct {
+			C1G2BlockWriteOpSpecResult *llrp.C1G2BlockWriteOpSpecResult
+			OpSpecResults              []OpSpecResult
+		}
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded.TagReportData) != 2 {
+		t.Fatalf("expected 2 tag reads; got %s", data)
+	}
+
+	// The numeric result is still there, along with its name.
+	tr := decoded.TagReportData[0]
+	if tr.C1G2BlockWriteOpSpecResult == nil ||
+		tr.C1G2BlockWriteOpSpecResult.C1G2BlockWriteResult != llrp.C1G2BlockWriteMemoryLockedError {
+		t.Errorf("expected the numeric result; got %s", data)
+	}
+	expected := []OpSpecResult{{OpSpecID: 2, Operation: "BlockWrite", Result: "MemoryLockedError"}}
+	if !reflect.DeepEqual(tr.OpSpecResults, expected) {
+		t.Errorf("expected %+v; got %+v", expected, tr.OpSpecResults)
+	}
+
+	if tr := decoded.TagReportData[1]; tr.OpSpecResults != nil {
+		t.Errorf("expected no OpSpec results; got %+v", tr)
+	}
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// Sources for the Origin of tag read readings,
+// selected by the ReadingOrigin driver configuration.
+//
+// Reader timestamps are in UTC; for Readers without a UTC clock,
+// the service converts their Uptime timestamps before using them.
+const (
+	OriginHost      = "host"      // when the service received the read
+	OriginFirstSeen = "firstSeen" // the read's FirstSeenUTC, or LastSeenUTC if it lacks one
+	OriginLastSeen  = "lastSeen"  // the read's LastSeenUTC, or FirstSeenUTC if it lacks one
+)
+
+// checkReadingOrigin returns an error if source isn't a known Origin source.
+func checkReadingOrigin(source string) error {
+	switch source {
+	case OriginHost, OriginFirstSeen, OriginLastSeen:
+		return nil
+	default:
+		return errors.Errorf("unknown reading origin %q; origins are %s, %s, or %s",
+			source, OriginHost, OriginFirstSeen, OriginLastSeen)
+	}
+}
+
+// readOrigin returns the Origin, in Unix nanoseconds, for readings of a tag read.
+// If the source is OriginHost or the Reader didn't report when it saw the tag,
+// it returns now.
+func readOrigin(source string, now time.Time, tr *llrp.TagReportData) int64 {
+	if ns, ok := seenUTC(source, tr); ok {
+		return ns
+	}
+	return now.UnixNano()
+}
+
+// reportOrigin returns the Origin, in Unix nanoseconds, for a reading of several tag reads:
+// the earliest of their timestamps for OriginFirstSeen, or the latest for OriginLastSeen.
+// If the source is OriginHost or none of the reads have timestamps, it returns now.
+func reportOrigin(source string, now time.Time, reads []llrp.TagReportData) int64 {
+	var origin int64
+	var found bool
+	for i := range reads {
+		ns, ok := seenUTC(source, &reads[i])
+		if !ok {
+			continue
+		}
+
+		if !found || (source == OriginFirstSeen && ns < origin) ||
+			(source == OriginLastSeen && ns > origin) {
+			origin = ns
+			found = true
+		}
+	}
+
+	if !found {
+		return now.UnixNano()
+	}
+	return origin
+}
+
+// seenUTC returns the tag read's timestamp for the source in Unix nanoseconds,
+// or false if the source is OriginHost or the read has no timestamps.
+func seenUTC(source string, tr *llrp.TagReportData) (int64, bool) {
+	var first, last *uint64
+	if tr.FirstSeenUTC != nil {
+		first = (*uint64)(tr.FirstSeenUTC)
+	}
+	if tr.LastSeenUTC != nil {
+		last = (*uint64)(tr.LastSeenUTC)
+	}
+
+	var seen *uint64
+	switch source {
+	case OriginFirstSeen:
+		seen = first
+		if seen == nil {
+			seen = last
+		}
+	case OriginLastSeen:
+		seen = last
+		if seen == nil {
+			seen = first
+		}
+	}
+
+	if seen == nil {
+		return 0, false
+	}
+	return int64(*seen) * 1000, true
+}
+
+// seenPair returns when the Reader first and last saw a tag, in Unix nanoseconds,
+// or false if the read has neither timestamp.
+// If it has only one, both are that time, as for a tag seen once,
+// so the pair is consistent whichever timestamps the Reader reports.
+func seenPair(tr *llrp.TagReportData) (first, last int64, ok bool) {
+	first, hasFirst := seenUTC(OriginFirstSeen, tr)
+	last, hasLast := seenUTC(OriginLastSeen, tr)
+	return first, last, hasFirst && hasLast
+}
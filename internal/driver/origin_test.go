@@ -0,0 +1,213 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestReadOrigin(t *testing.T) {
+	now := time.Now()
+	first := llrp.FirstSeenUTC(1600000000000000)
+	last := llrp.LastSeenUTC(1600000005000000)
+
+	both := llrp.TagReportData{FirstSeenUTC: &first, LastSeenUTC: &last}
+	onlyFirst := llrp.TagReportData{FirstSeenUTC: &first}
+	neither := llrp.TagReportData{}
+
+	for _, testCase := range []struct {
+		name   string
+		source string
+		read   llrp.TagReportData
+		origin int64
+	}{
+		{name: "host", source: OriginHost, read: both, origin: now.UnixNano()},
+		{name: "firstSeen", source: OriginFirstSeen, read: both, origin: 1600000000000000000},
+		{name: "lastSeen", source: OriginLastSeen, read: both, origin: 1600000005000000000},
+		{name: "lastSeenFallback", source: OriginLastSeen, read: onlyFirst, origin: 1600000000000000000},
+		{name: "noTimestamps", source: OriginFirstSeen, read: neither, origin: now.UnixNano()},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			if origin := readOrigin(testCase.source, now, &testCase.read); origin != testCase.origin {
+				t.Errorf("expected origin %d; got %d", testCase.origin, origin)
+			}
+		})
+	}
+
+	// Reports use the earliest or latest of their reads' timestamps,
+	// ignoring reads without any.
+	reads := []llrp.TagReportData{neither, onlyFirst, both}
+	if origin := reportOrigin(OriginFirstSeen, now, reads); origin != 1600000000000000000 {
+		t.Errorf("expected the earliest FirstSeenUTC; got %d", origin)
+	}
+	if origin := reportOrigin(OriginLastSeen, now, reads); origin != 1600000005000000000 {
+		t.Errorf("expected the latest LastSeenUTC; got %d", origin)
+	}
+	if origin := reportOrigin(OriginLastSeen, now, []llrp.TagReportData{neither}); origin != now.UnixNano() {
+		t.Errorf("expected the current time without timestamps; got %d", origin)
+	}
+	if origin := reportOrigin(OriginHost, now, reads); origin != now.UnixNano() {
+		t.Errorf("expected the current time for host origins; got %d", origin)
+	}
+
+	// Flat read values use the source for their Origin,
+	// but TagTimestamp is always the Reader's timestamp.
+	for source, origin := range map[string]int64{
+		OriginHost:      now.UnixNano(),
+		OriginFirstSeen: 1600000000000000000,
+	} {
+		cvs, err := flatReadValues(now, source, nil, &both)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, cv := range cvs {
+			if cv.Origin != origin {
+				t.Errorf("%s: expected %s Origin %d; got %d", source, cv.DeviceResourceName, origin, cv.Origin)
+			}
+			if cv.DeviceResourceName != ResourceTagTimestamp {
+				continue
+			}
+			if ts, err := cv.Int64Value(); err != nil || ts != 1600000005000000000 {
+				t.Errorf("%s: expected TagTimestamp to be LastSeenUTC; got %d, %v", source, ts, err)
+			}
+		}
+	}
+}
+
+func TestSeenPair(t *testing.T) {
+	first := llrp.FirstSeenUTC(1600000000000000)
+	last := llrp.LastSeenUTC(1600000005000000)
+
+	for _, testCase := range []struct {
+		name        string
+		read        llrp.TagReportData
+		first, last int64
+		ok          bool
+	}{
+		{name: "both", read: llrp.TagReportData{FirstSeenUTC: &first, LastSeenUTC: &last},
+			first: 1600000000000000000, last: 1600000005000000000, ok: true},
+		{name: "onlyFirst", read: llrp.TagReportData{FirstSeenUTC: &first},
+			first: 1600000000000000000, last: 1600000000000000000, ok: true},
+		{name: "onlyLast", read: llrp.TagReportData{LastSeenUTC: &last},
+			first: 1600000005000000000, last: 1600000005000000000, ok: true},
+		{name: "neither"},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			first, last, ok := seenPair(&testCase.read)
+			if ok != testCase.ok || first != testCase.first || last != testCase.last {
+				t.Errorf("expected %d, %d, %v; got %d, %d, %v",
+					testCase.first, testCase.last, testCase.ok, first, last, ok)
+			}
+		})
+	}
+
+	// Flat reads include the pair.
+	cvs, err := flatReadValues(time.Now(), OriginHost, nil, &llrp.TagReportData{LastSeenUTC: &last})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]int64{}
+	for _, cv := range cvs {
+		if cv.DeviceResourceName == ResourceTagFirstSeen || cv.DeviceResourceName == ResourceTagLastSeen {
+			if got[cv.DeviceResourceName], err = cv.Int64Value(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if got[ResourceTagFirstSeen] != 1600000005000000000 || got[ResourceTagLastSeen] != 1600000005000000000 {
+		t.Errorf("expected both TagFirstSeen and TagLastSeen to be LastSeenUTC; got %v", got)
+	}
+}
+
+func TestProcessReport(t *testing.T) {
+	readerStart := time.Unix(1600000000, 0)
+	firstUptime := llrp.FirstSeenUptime(2000000) // 2s after the Reader started
+	lastUptime := llrp.LastSeenUptime(5000000)
+	lastUTC := llrp.LastSeenUTC(1700000000000000)
+
+	report := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{
+		{FirstSeenUptime: &firstUptime, LastSeenUptime: &lastUptime},
+		{FirstSeenUptime: &firstUptime, LastSeenUTC: &lastUTC},
+		{},
+	}}
+	processReport(readerStart, report)
+
+	uptimeOnly := report.TagReportData[0]
+	if uptimeOnly.FirstSeenUTC == nil || *uptimeOnly.FirstSeenUTC != 1600000002000000 ||
+		uptimeOnly.LastSeenUTC == nil || *uptimeOnly.LastSeenUTC != 1600000005000000 {
+		t.Errorf("expected the Uptimes converted to UTC; got %+v", uptimeOnly)
+	}
+	if uptimeOnly.FirstSeenUptime == nil || uptimeOnly.LastSeenUptime == nil {
+		t.Errorf("expected the Uptimes kept; got %+v", uptimeOnly)
+	}
+
+	mixed := report.TagReportData[1]
+	if mixed.FirstSeenUTC == nil || *mixed.FirstSeenUTC != 1600000002000000 ||
+		mixed.LastSeenUTC == nil || *mixed.LastSeenUTC != lastUTC {
+		t.Errorf("expected the Uptime converted and the UTC timestamp kept; got %+v", mixed)
+	}
+
+	if neither := report.TagReportData[2]; neither.FirstSeenUTC != nil || neither.LastSeenUTC != nil {
+		t.Errorf("expected no timestamps; got %+v", neither)
+	}
+}
+
+func TestLLRPDevice_uptimeReader(t *testing.T) {
+	clk := newFakeClock()
+	l := &LLRPDevice{
+		name:   "uptimeReader",
+		lc:     edgexCompatTestLogger{t},
+		ch:     make(chan *dsModels.AsyncValues, 10),
+		clk:    clk,
+		reads:  newTagReadCache(10),
+		counts: newTagCounter(time.Minute),
+	}
+
+	send := func(handler llrp.MessageHandler, mt llrp.MessageType, m llrp.Outgoing) {
+		t.Helper()
+		payload, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := llrp.NewByteMessage(mt, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler.HandleMessage(nil, msg)
+	}
+
+	// The Reader has been up for 10s, so it started 10s before the host's now.
+	send(l.newReaderEventHandler(nil), llrp.MsgReaderEventNotification, &llrp.ReaderEventNotification{
+		ReaderEventNotificationData: llrp.ReaderEventNotificationData{
+			Uptime:       10000000,
+			AntennaEvent: &llrp.AntennaEvent{Event: llrp.AntennaConnected, AntennaID: 1},
+		},
+	})
+	readerStart := clk.Now().Add(-10 * time.Second)
+
+	// It saw a tag 12s after it started.
+	seen := llrp.LastSeenUptime(12000000)
+	send(l.newROHandler(), llrp.MsgROAccessReport, &llrp.ROAccessReport{
+		TagReportData: []llrp.TagReportData{{EPC96: llrp.EPC96{EPC: make([]byte, 12)}, LastSeenUptime: &seen}},
+	})
+
+	reads := l.reads.latest()
+	if len(reads) != 1 {
+		t.Fatalf("expected 1 cached read; got %+v", reads)
+	}
+
+	expected := readerStart.Add(12*time.Second).UnixNano() / 1000
+	if utc := reads[0].LastSeenUTC; utc == nil || int64(*utc) != expected {
+		t.Errorf("expected LastSeenUTC %d; got %v", expected, utc)
+	}
+}
@@ -0,0 +1,218 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ProtocolC1G2 is an optional protocol holding a device's default C1G2 tag passwords.
+	// Its "accessPassword" and "killPassword" properties are each two 16-bit words
+	// written as 8 hex digits, optionally prefixed with "0x".
+	// AccessSpecs added to the device use them for any OpSpec whose password is 0.
+	ProtocolC1G2           = "c1g2"
+	PropertyAccessPassword = "accessPassword"
+	PropertyKillPassword   = "killPassword"
+
+	// passwordWords is the length of C1G2 access and kill passwords, in 16-bit words.
+	passwordWords = 2
+	// passwordMask replaces passwords in diagnostic output.
+	passwordMask = "********"
+)
+
+// tagPasswords are a device's default C1G2 tag passwords.
+// Since 0 is the password of a tag without one, a zero password isn't a default.
+type tagPasswords struct {
+	access uint32
+	kill   uint32
+}
+
+// parseTagPassword parses a C1G2 access or kill password
+// written as passwordWords words of 4 hex digits each.
+//
+// Errors it returns never include the password.
+func parseTagPassword(s string) (uint32, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s) != passwordWords*4 {
+		return 0, errors.Errorf("must be %d words (%d hex digits), but has %d digits",
+			passwordWords, passwordWords*4, len(s))
+	}
+
+	pw, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, errors.New("must be hex digits")
+	}
+	return uint32(pw), nil
+}
+
+// getTagPasswords returns the device's default passwords from the c1g2 protocol.
+// If one is invalid, it still returns the other, along with an error.
+func getTagPasswords(protocols protocolMap) (tagPasswords, error) {
+	var pws tagPasswords
+	var errs []string
+	for prop, pw := range map[string]*uint32{
+		PropertyAccessPassword: &pws.access,
+		PropertyKillPassword:   &pws.kill,
+	} {
+		s := protocols[ProtocolC1G2][prop]
+		if s == "" {
+			continue
+		}
+
+		var err error
+		if *pw, err = parseTagPassword(s); err != nil {
+			errs = append(errs, prop+": "+err.Error())
+		}
+	}
+
+	if len(errs) != 0 {
+		return pws, errors.Errorf("invalid %s protocol: %s", ProtocolC1G2, strings.Join(errs, "; "))
+	}
+	return pws, nil
+}
+
+// applyTagPasswords sets the passwords of the OpSpecs in ac
+// that don't have one to the device's defaults, if it has them.
+func (l *LLRPDevice) applyTagPasswords(ac *llrp.AccessCommand) {
+	l.deviceMu.RLock()
+	pws := l.tagPasswords
+	l.deviceMu.RUnlock()
+
+	setDefault := func(pw *uint32, def uint32) {
+		if *pw == 0 {
+			*pw = def
+		}
+	}
+
+	forEachPassword(ac, func(pw *uint32, isKill bool) {
+		if isKill {
+			setDefault(pw, pws.kill)
+		} else {
+			setDefault(pw, pws.access)
+		}
+	})
+}
+
+// forEachPassword calls f with a pointer to the password of each OpSpec in ac;
+// isKill is true for kill passwords and false for access passwords.
+func forEachPassword(ac *llrp.AccessCommand, f func(pw *uint32, isKill bool)) {
+	if op := ac.C1G2Read; op != nil {
+		f(&op.AccessPassword, false)
+	}
+	if op := ac.C1G2Write; op != nil {
+		f(&op.AccessPassword, false)
+	}
+	if op := ac.C1G2Kill; op != nil {
+		f(&op.KillPassword, true)
+	}
+	if op := ac.C1G2Recommission; op != nil {
+		f(&op.KillPassword, true)
+	}
+	if op := ac.C1G2Lock; op != nil {
+		f(&op.AccessPassword, false)
+	}
+	if op := ac.C1G2BlockErase; op != nil {
+		f(&op.AccessPassword, false)
+	}
+	if op := ac.C1G2BlockWrite; op != nil {
+		f(&op.AccessPassword, false)
+	}
+	if op := ac.C1G2BlockPermalock; op != nil {
+		f(&op.AccessPassword, false)
+	}
+	if op := ac.C1G2GetBlockPermalockStatus; op != nil {
+		f(&op.AccessPassword, false)
+	}
+}
+
+// redactAccessCommand returns a copy of ac with its passwords set to 0,
+// leaving ac unchanged.
+func redactAccessCommand(ac llrp.AccessCommand) llrp.AccessCommand {
+	// Copy the OpSpecs so clearing their passwords doesn't modify the originals.
+	if op := ac.C1G2Read; op != nil {
+		cp := *op
+		ac.C1G2Read = &cp
+	}
+	if op := ac.C1G2Write; op != nil {
+		cp := *op
+		ac.C1G2Write = &cp
+	}
+	if op := ac.C1G2Kill; op != nil {
+		cp := *op
+		ac.C1G2Kill = &cp
+	}
+	if op := ac.C1G2Recommission; op != nil {
+		cp := *op
+		ac.C1G2Recommission = &cp
+	}
+	if op := ac.C1G2Lock; op != nil {
+		cp := *op
+		ac.C1G2Lock = &cp
+	}
+	if op := ac.C1G2BlockErase; op != nil {
+		cp := *op
+		ac.C1G2BlockErase = &cp
+	}
+	if op := ac.C1G2BlockWrite; op != nil {
+		cp := *op
+		ac.C1G2BlockWrite = &cp
+	}
+	if op := ac.C1G2BlockPermalock; op != nil {
+		cp := *op
+		ac.C1G2BlockPermalock = &cp
+	}
+	if op := ac.C1G2GetBlockPermalockStatus; op != nil {
+		cp := *op
+		ac.C1G2GetBlockPermalockStatus = &cp
+	}
+
+	forEachPassword(&ac, func(pw *uint32, _ bool) { *pw = 0 })
+	return ac
+}
+
+// redactAccessSpecs returns a copy of resp with the passwords
+// of its AccessSpecs set to 0, leaving resp unchanged.
+func redactAccessSpecs(resp *llrp.GetAccessSpecsResponse) *llrp.GetAccessSpecsResponse {
+	redacted := *resp
+	if resp.AccessSpecs == nil {
+		return &redacted
+	}
+
+	redacted.AccessSpecs = make([]llrp.AccessSpec, len(resp.AccessSpecs))
+	for i, spec := range resp.AccessSpecs {
+		spec.AccessCommand = redactAccessCommand(spec.AccessCommand)
+		redacted.AccessSpecs[i] = spec
+	}
+	return &redacted
+}
+
+// maskProtocols returns a copy of protocols suitable for logging,
+// with the c1g2 protocol's passwords replaced by passwordMask.
+func maskProtocols(protocols protocolMap) protocolMap {
+	c1g2, ok := protocols[ProtocolC1G2]
+	if !ok {
+		return protocols
+	}
+
+	masked := make(protocolMap, len(protocols))
+	for name, props := range protocols {
+		masked[name] = props
+	}
+
+	maskedC1G2 := make(map[string]string, len(c1g2))
+	for prop, val := range c1g2 {
+		if prop == PropertyAccessPassword || prop == PropertyKillPassword {
+			val = passwordMask
+		}
+		maskedC1G2[prop] = val
+	}
+	masked[ProtocolC1G2] = maskedC1G2
+	return masked
+}
@@ -0,0 +1,165 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"fmt"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTagPassword(t *testing.T) {
+	for _, testCase := range []struct {
+		password string
+		expected uint32
+		err      bool
+	}{
+		{password: "00000000", expected: 0},
+		{password: "12345678", expected: 0x12345678},
+		{password: "0xDEADBEEF", expected: 0xDEADBEEF},
+		{password: "0Xcafef00d", expected: 0xCAFEF00D},
+		{password: "1234", err: true},            // one word
+		{password: "123456789abc", err: true},    // three words
+		{password: "0x", err: true},              // no words
+		{password: "1234567g", err: true},        // not hex
+		{password: "+1234567", err: true},        // not hex
+		{password: "0x0x123456", err: true},      // doubled prefix
+		{password: "secret-password", err: true}, // not a password
+	} {
+		pw, err := parseTagPassword(testCase.password)
+		if (err != nil) != testCase.err || pw != testCase.expected {
+			t.Errorf("password %q: expected %#x (error: %v); got %#x, %v",
+				testCase.password, testCase.expected, testCase.err, pw, err)
+		}
+
+		if err != nil && strings.Contains(err.Error(), testCase.password) {
+			t.Errorf("error reveals the password %q: %v", testCase.password, err)
+		}
+	}
+}
+
+func TestGetTagPasswords(t *testing.T) {
+	pws, err := getTagPasswords(protocolMap{})
+	if err != nil || pws != (tagPasswords{}) {
+		t.Errorf("expected no passwords without the protocol; got %+v, %v", pws, err)
+	}
+
+	pws, err = getTagPasswords(protocolMap{ProtocolC1G2: contract.ProtocolProperties{
+		PropertyAccessPassword: "0x11112222",
+		PropertyKillPassword:   "33334444",
+	}})
+	expected := tagPasswords{access: 0x11112222, kill: 0x33334444}
+	if err != nil || pws != expected {
+		t.Errorf("expected %+v; got %+v, %v", expected, pws, err)
+	}
+
+	// The valid password is still used.
+	pws, err = getTagPasswords(protocolMap{ProtocolC1G2: contract.ProtocolProperties{
+		PropertyAccessPassword: "5555",
+		PropertyKillPassword:   "66667777",
+	}})
+	expected = tagPasswords{kill: 0x66667777}
+	if err == nil || pws != expected {
+		t.Errorf("expected %+v and an error; got %+v, %v", expected, pws, err)
+	}
+	if err != nil && strings.Contains(err.Error(), "5555") {
+		t.Errorf("error reveals the password: %v", err)
+	}
+}
+
+// testAccessCommand returns an AccessCommand with every OpSpec that has a password.
+func testAccessCommand(access, kill uint32) llrp.AccessCommand {
+	return llrp.AccessCommand{
+		C1G2Read:                    &llrp.C1G2Read{OpSpecID: 1, AccessPassword: access},
+		C1G2Write:                   &llrp.C1G2Write{OpSpecID: 2, AccessPassword: access, Data: []uint16{1}},
+		C1G2Kill:                    &llrp.C1G2Kill{OpSpecID: 3, KillPassword: kill},
+		C1G2Recommission:            &llrp.C1G2Recommission{OpSpecID: 4, KillPassword: kill},
+		C1G2Lock:                    &llrp.C1G2Lock{OpSpecID: 5, AccessPassword: access},
+		C1G2BlockErase:              &llrp.C1G2BlockErase{OpSpecID: 6, AccessPassword: access},
+		C1G2BlockWrite:              &llrp.C1G2BlockWrite{OpSpecID: 7, AccessPassword: access},
+		C1G2BlockPermalock:          &llrp.C1G2BlockPermalock{OpSpecID: 8, AccessPassword: access},
+		C1G2GetBlockPermalockStatus: &llrp.C1G2GetBlockPermalockStatus{OpSpecID: 9, AccessPassword: access},
+	}
+}
+
+func TestLLRPDevice_applyTagPasswords(t *testing.T) {
+	dev := &LLRPDevice{tagPasswords: tagPasswords{access: 0xAAAA0001, kill: 0xBBBB0002}}
+
+	// OpSpecs without passwords use the defaults.
+	ac := testAccessCommand(0, 0)
+	dev.applyTagPasswords(&ac)
+	if expected := testAccessCommand(0xAAAA0001, 0xBBBB0002); !reflect.DeepEqual(ac, expected) {
+		t.Errorf("expected %+v; got %+v", expected, ac)
+	}
+
+	// OpSpecs with passwords keep them.
+	ac = testAccessCommand(0x1, 0x2)
+	dev.applyTagPasswords(&ac)
+	if expected := testAccessCommand(0x1, 0x2); !reflect.DeepEqual(ac, expected) {
+		t.Errorf("expected %+v; got %+v", expected, ac)
+	}
+
+	// Without defaults, nothing changes.
+	dev = &LLRPDevice{}
+	ac = testAccessCommand(0, 0)
+	dev.applyTagPasswords(&ac)
+	if expected := testAccessCommand(0, 0); !reflect.DeepEqual(ac, expected) {
+		t.Errorf("expected %+v; got %+v", expected, ac)
+	}
+}
+
+func TestRedactAccessSpecs(t *testing.T) {
+	resp := &llrp.GetAccessSpecsResponse{AccessSpecs: []llrp.AccessSpec{
+		{AccessSpecID: 1, AccessCommand: testAccessCommand(0x12345678, 0x9ABCDEF0)},
+		{AccessSpecID: 2},
+	}}
+
+	redacted := redactAccessSpecs(resp)
+	expected := &llrp.GetAccessSpecsResponse{AccessSpecs: []llrp.AccessSpec{
+		{AccessSpecID: 1, AccessCommand: testAccessCommand(0, 0)},
+		{AccessSpecID: 2},
+	}}
+	if !reflect.DeepEqual(redacted, expected) {
+		t.Errorf("expected %+v; got %+v", expected, redacted)
+	}
+
+	// The original is unchanged.
+	if ac := resp.AccessSpecs[0].AccessCommand; !reflect.DeepEqual(ac, testAccessCommand(0x12345678, 0x9ABCDEF0)) {
+		t.Errorf("redacting changed the original AccessCommand: %+v", ac)
+	}
+
+	if redacted := redactAccessSpecs(&llrp.GetAccessSpecsResponse{}); redacted.AccessSpecs != nil {
+		t.Errorf("expected no AccessSpecs; got %+v", redacted.AccessSpecs)
+	}
+}
+
+func TestMaskProtocols(t *testing.T) {
+	protocols := protocolMap{
+		"tcp": {"host": "localhost", "port": "5084"},
+		ProtocolC1G2: {
+			PropertyAccessPassword: "12345678",
+			PropertyKillPassword:   "9abcdef0",
+		},
+	}
+
+	masked := fmt.Sprintf("%v", maskProtocols(protocols))
+	for _, secret := range []string{"12345678", "9abcdef0"} {
+		if strings.Contains(masked, secret) {
+			t.Errorf("masked protocols reveal the password %q: %s", secret, masked)
+		}
+	}
+	if !strings.Contains(masked, "localhost") || !strings.Contains(masked, passwordMask) {
+		t.Errorf("expected the masked protocols to keep the other properties; got %s", masked)
+	}
+
+	// The original is unchanged.
+	if pw := protocols[ProtocolC1G2][PropertyAccessPassword]; pw != "12345678" {
+		t.Errorf("masking changed the original protocols: %q", pw)
+	}
+}
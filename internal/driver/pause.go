@@ -0,0 +1,176 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"sort"
+	"strings"
+)
+
+const (
+	// ResourceReading pauses or resumes all of a Reader's ROSpecs
+	// with an Action of ActionPause or ActionResume.
+	ResourceReading = "Reading"
+
+	// ResourceReadingPause is sent as an event with the result of each pause or resume.
+	ResourceReadingPause = "ReadingPause"
+)
+
+// readingPause is the value of ResourceReadingPause events.
+type readingPause struct {
+	Action    string   // Pause or Resume
+	ROSpecIDs []uint32 // ROSpecs paused or resumed
+	Missing   []uint32 `json:",omitempty"` // paused ROSpecs the Reader no longer has
+	Errors    []string `json:",omitempty"` // failures pausing or resuming ROSpecs
+}
+
+// PauseReading disables each of the Reader's enabled or active ROSpecs
+// and remembers them, so ResumeReading can restore exactly that set.
+// Pausing again while paused adds any ROSpecs enabled since to the set.
+//
+// It sends a ResourceReadingPause event with the ROSpecs it paused
+// and returns an error if it couldn't get the ROSpecs or disable any of them.
+func (l *LLRPDevice) PauseReading(ctx context.Context) error {
+	l.pauseMu.Lock()
+	defer l.pauseMu.Unlock()
+
+	result := readingPause{Action: ActionPause}
+	specs := llrp.GetROSpecsResponse{}
+	if err := l.TrySend(ctx, &llrp.GetROSpecs{}, &specs); err != nil {
+		return l.finishPause(result, err)
+	}
+
+	paused := l.pausedSpecs()
+	var first error
+	for _, ros := range specs.ROSpecs {
+		if ros.ROSpecCurrentState == llrp.ROSpecStateDisabled {
+			continue
+		}
+
+		// Disabling an active ROSpec stops it first.
+		id := ros.ROSpecID
+		if err := l.TrySend(ctx, &llrp.DisableROSpec{ROSpecID: id},
+			&llrp.DisableROSpecResponse{}); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("ROSpec %d: %v", id, err))
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+
+		// ROSpecs with start triggers become active on their own once they're enabled.
+		paused[id] = ros.ROSpecCurrentState == llrp.ROSpecStateActive &&
+			ros.ROBoundarySpec.StartTrigger.Trigger == llrp.ROStartTriggerNone
+		result.ROSpecIDs = append(result.ROSpecIDs, id)
+	}
+
+	l.setPausedSpecs(paused)
+	return l.finishPause(result, first)
+}
+
+// ResumeReading enables the ROSpecs PauseReading disabled,
+// and starts the ones that were active and have no start trigger.
+//
+// ROSpecs added while paused are left as they are,
+// and paused ROSpecs the Reader no longer has are reported as missing and forgotten.
+// ROSpecs it fails to resume stay paused, so resuming again retries them.
+// It sends a ResourceReadingPause event with the ROSpecs it resumed
+// and returns an error if it couldn't get the ROSpecs or resume any of them.
+func (l *LLRPDevice) ResumeReading(ctx context.Context) error {
+	l.pauseMu.Lock()
+	defer l.pauseMu.Unlock()
+
+	result := readingPause{Action: ActionResume}
+	paused := l.pausedSpecs()
+	if len(paused) == 0 {
+		return l.finishPause(result, nil)
+	}
+
+	specs := llrp.GetROSpecsResponse{}
+	if err := l.TrySend(ctx, &llrp.GetROSpecs{}, &specs); err != nil {
+		return l.finishPause(result, err)
+	}
+
+	states := make(map[uint32]llrp.ROSpecCurrentStateType, len(specs.ROSpecs))
+	for _, ros := range specs.ROSpecs {
+		states[ros.ROSpecID] = ros.ROSpecCurrentState
+	}
+
+	ids := make([]uint32, 0, len(paused))
+	for id := range paused {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var first error
+	fail := func(id uint32, err error) {
+		result.Errors = append(result.Errors, fmt.Sprintf("ROSpec %d: %v", id, err))
+		if first == nil {
+			first = err
+		}
+	}
+
+	for _, id := range ids {
+		state, ok := states[id]
+		if !ok {
+			delete(paused, id)
+			result.Missing = append(result.Missing, id)
+			continue
+		}
+
+		if state == llrp.ROSpecStateDisabled {
+			if err := l.TrySend(ctx, &llrp.EnableROSpec{ROSpecID: id},
+				&llrp.EnableROSpecResponse{}); err != nil {
+				fail(id, err)
+				continue
+			}
+		}
+
+		if paused[id] && state != llrp.ROSpecStateActive {
+			if err := l.TrySend(ctx, &llrp.StartROSpec{ROSpecID: id},
+				&llrp.StartROSpecResponse{}); err != nil {
+				fail(id, err)
+				continue
+			}
+		}
+
+		delete(paused, id)
+		result.ROSpecIDs = append(result.ROSpecIDs, id)
+	}
+
+	l.setPausedSpecs(paused)
+	return l.finishPause(result, first)
+}
+
+// finishPause sends a ResourceReadingPause event with the result
+// and returns the error, if any, with the action that failed.
+func (l *LLRPDevice) finishPause(result readingPause, err error) error {
+	l.sendEdgeXEvent(ResourceReadingPause, l.clock().Now().UnixNano(), result)
+	return errors.WithMessagef(err, "failed to %s reading", strings.ToLower(result.Action))
+}
+
+// pausedSpecs returns a copy of the paused ROSpecs.
+func (l *LLRPDevice) pausedSpecs() map[uint32]bool {
+	l.deviceMu.RLock()
+	defer l.deviceMu.RUnlock()
+
+	paused := make(map[uint32]bool, len(l.pausedROSpecs))
+	for id, start := range l.pausedROSpecs {
+		paused[id] = start
+	}
+	return paused
+}
+
+// setPausedSpecs replaces the paused ROSpecs.
+func (l *LLRPDevice) setPausedSpecs(paused map[uint32]bool) {
+	l.deviceMu.Lock()
+	l.pausedROSpecs = paused
+	l.deviceMu.Unlock()
+}
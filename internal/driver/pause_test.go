@@ -0,0 +1,106 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_PauseReading(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	periodic := llrp.ROBoundarySpec{StartTrigger: llrp.ROSpecStartTrigger{Trigger: llrp.ROStartTriggerPeriodic}}
+	reader := newSpecReader(t, rfid,
+		llrp.ROSpec{ROSpecID: 1, ROSpecCurrentState: llrp.ROSpecStateActive},
+		llrp.ROSpec{ROSpecID: 2},
+		llrp.ROSpec{ROSpecID: 3, ROSpecCurrentState: llrp.ROSpecStateInactive},
+		llrp.ROSpec{ROSpecID: 4, ROSpecCurrentState: llrp.ROSpecStateActive, ROBoundarySpec: periodic},
+		llrp.ROSpec{ROSpecID: 5, ROSpecCurrentState: llrp.ROSpecStateInactive},
+	)
+
+	go rfid.ImpersonateReader()
+	ch := make(chan *dsModels.AsyncValues, 1)
+	dev := &LLRPDevice{
+		name:   "localReader",
+		client: rfid.ConnectClient(t),
+		lc:     edgexCompatTestLogger{t},
+		ch:     ch,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// nextPause returns the next ResourceReadingPause event.
+	nextPause := func() readingPause {
+		t.Helper()
+		var av *dsModels.AsyncValues
+		select {
+		case av = <-ch:
+		case <-time.After(time.Second):
+			t.Fatalf("expected a %s event", ResourceReadingPause)
+		}
+		p := readingPause{}
+		if err := json.Unmarshal([]byte(av.CommandValues[0].ValueToString()), &p); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	if err := dev.PauseReading(ctx); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if p := nextPause(); !reflect.DeepEqual(p, readingPause{Action: ActionPause, ROSpecIDs: []uint32{1, 3, 4, 5}}) {
+		t.Errorf("expected ROSpecs 1, 3, 4, and 5 paused; got %+v", p)
+	}
+	for id, state := range reader.states() {
+		if state != llrp.ROSpecStateDisabled {
+			t.Errorf("expected ROSpec %d to be disabled; got %v", id, state)
+		}
+	}
+
+	// While paused, one ROSpec is deleted, and another is added.
+	reader.mu.Lock()
+	delete(reader.specs, 5)
+	reader.specs[6] = llrp.ROSpec{ROSpecID: 6}
+	reader.mu.Unlock()
+
+	if err := dev.ResumeReading(ctx); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	expected := readingPause{Action: ActionResume, ROSpecIDs: []uint32{1, 3, 4}, Missing: []uint32{5}}
+	if p := nextPause(); !reflect.DeepEqual(p, expected) {
+		t.Errorf("expected %+v; got %+v", expected, p)
+	}
+
+	// The ROSpec with a start trigger is left for its trigger to start.
+	expectedStates := map[uint32]llrp.ROSpecCurrentStateType{
+		1: llrp.ROSpecStateActive,
+		2: llrp.ROSpecStateDisabled,
+		3: llrp.ROSpecStateInactive,
+		4: llrp.ROSpecStateInactive,
+		6: llrp.ROSpecStateDisabled,
+	}
+	if states := reader.states(); !reflect.DeepEqual(states, expectedStates) {
+		t.Errorf("expected ROSpec states %v; got %v", expectedStates, states)
+	}
+
+	// Resuming again has nothing left to resume.
+	if err := dev.ResumeReading(ctx); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if p := nextPause(); !reflect.DeepEqual(p, readingPause{Action: ActionResume}) {
+		t.Errorf("expected nothing resumed; got %+v", p)
+	}
+}
@@ -0,0 +1,49 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"strings"
+	"time"
+)
+
+// ResourcePendingRequests is a read-only resource with the device's requests
+// still awaiting the Reader's reply, for diagnosing commands that seem stuck.
+const ResourcePendingRequests = "PendingRequests"
+
+// PendingRequest is a request the device sent to the Reader that it hasn't yet answered.
+type PendingRequest struct {
+	MessageID uint32
+	Message   string // the type of the request's message, e.g. "AddROSpec"
+	AgeMillis int64  // how long ago the request was sent
+}
+
+// PendingRequests returns the requests on the device's current connection
+// still awaiting the Reader's reply, oldest first.
+//
+// It doesn't contact the Reader, so it works even if the Reader stopped responding,
+// and it doesn't wake a connection closed while idle.
+func (l *LLRPDevice) PendingRequests() []PendingRequest {
+	l.clientLock.RLock()
+	c := l.client
+	l.clientLock.RUnlock()
+
+	pending := []PendingRequest{}
+	if c == nil {
+		return pending
+	}
+
+	// The Client records when it sends requests with the system clock.
+	now := time.Now()
+	for _, p := range c.PendingRequests() {
+		pending = append(pending, PendingRequest{
+			MessageID: p.MessageID,
+			Message:   strings.TrimPrefix(p.Type.String(), "Msg"),
+			AgeMillis: now.Sub(p.Sent).Milliseconds(),
+		})
+	}
+	return pending
+}
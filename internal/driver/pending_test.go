@@ -0,0 +1,130 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_PendingRequests(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*2, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader doesn't reply to GetReaderConfig until it's released.
+	release := make(chan struct{})
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(llrp.Message) llrp.Outgoing {
+		<-release
+		return &llrp.GetReaderConfigResponse{}
+	})
+
+	dev := &LLRPDevice{name: "localReader", lc: edgexCompatTestLogger{t}}
+	if pending := dev.PendingRequests(); pending == nil || len(pending) != 0 {
+		t.Errorf("expected an empty list without a connection; got %+v", pending)
+	}
+
+	go rfid.ImpersonateReader()
+	dev.client = rfid.ConnectClient(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	configErr := make(chan error, 1)
+	go func() {
+		configErr <- dev.TrySend(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{})
+	}()
+
+	var pending []PendingRequest
+	for len(pending) == 0 && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+		pending = dev.PendingRequests()
+	}
+	if len(pending) != 1 || pending[0].Message != "GetReaderConfig" || pending[0].AgeMillis < 0 {
+		t.Errorf("expected the GetReaderConfig request to be pending; got %+v", pending)
+	}
+
+	close(release)
+	if err := <-configErr; err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if pending := dev.PendingRequests(); len(pending) != 0 {
+		t.Errorf("expected no pending requests after the reply; got %+v", pending)
+	}
+}
+
+func TestLLRPDevice_MessageIDStart(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// The Reader doesn't reply to GetReaderConfig until it's released.
+	release := make(chan struct{})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			rfid, err := llrp.NewReaderOnlyTestDevice(conn, !testing.Verbose())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			rfid.SetResponse(llrp.MsgSetReaderConfig, &llrp.SetReaderConfigResponse{})
+			rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(llrp.Message) llrp.Outgoing {
+				<-release
+				return &llrp.GetReaderConfigResponse{}
+			})
+			go rfid.ImpersonateReader()
+		}
+	}()
+
+	const start = 4000000000
+	d := newTestDriver(nil)
+	d.config = &driverConfiguration{KeepAliveSeconds: 30, ReportEncoding: ReportEncodingJSON,
+		MessageIDStart: start}
+	dev := d.NewLLRPDevice("numberedReader", ln.Addr(), contract.Enabled)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		_ = dev.Stop(ctx)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	configErr := make(chan error, 1)
+	go func() {
+		configErr <- dev.TrySend(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{})
+	}()
+
+	// Other requests may be pending, too, such as the one setting the KeepAlive.
+	var request *PendingRequest
+	for request == nil && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+		for _, p := range dev.PendingRequests() {
+			if p.Message == "GetReaderConfig" {
+				request = &p
+				break
+			}
+		}
+	}
+	close(release)
+
+	// Only a few messages precede it on the connection, e.g., for version negotiation.
+	if request == nil || request.MessageID < start || request.MessageID > start+10 {
+		t.Errorf("expected the GetReaderConfig request's ID to count up from %d; got %+v", uint32(start), request)
+	}
+	if err := <-configErr; err != nil {
+		t.Fatalf("%+v", err)
+	}
+}
@@ -0,0 +1,259 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// ResourceAsyncQueueUsage is a read-only resource with how much the device has queued
+// for EdgeX under the "queue" AsyncOverflow policy, and its use of the AsyncQueueBudgetKiB.
+const ResourceAsyncQueueUsage = "AsyncQueueUsage"
+
+// Approximate sizes of the structures holding queued values, in bytes,
+// in addition to the names and values they hold.
+const (
+	asyncValuesOverhead  = 64
+	commandValueOverhead = 120
+)
+
+// checkAsyncQueueBudgetKiB returns an error if the budget is negative.
+func checkAsyncQueueBudgetKiB(kib int) error {
+	if kib < 0 {
+		return errors.Errorf("async queue budget must be at least 0 KiB; got %d", kib)
+	}
+	return nil
+}
+
+// checkAsyncQueueBudgetOverflow returns an error if the policy isn't one the budget supports.
+func checkAsyncQueueBudgetOverflow(overflow string) error {
+	switch overflow {
+	case AsyncOverflowDrop, AsyncOverflowBlock:
+		return nil
+	default:
+		return errors.Errorf("unknown async queue budget overflow policy %q; policies are %s or %s",
+			overflow, AsyncOverflowDrop, AsyncOverflowBlock)
+	}
+}
+
+// asyncValuesSize estimates the bytes of memory the values use.
+func asyncValuesSize(av *dsModels.AsyncValues) int64 {
+	n := int64(asyncValuesOverhead + len(av.DeviceName))
+	for _, cv := range av.CommandValues {
+		if cv == nil {
+			continue
+		}
+		n += int64(commandValueOverhead + len(cv.DeviceResourceName) + len(cv.NumericValue) + len(cv.BinValue))
+		if cv.Type == dsModels.String {
+			n += int64(len(cv.ValueToString()))
+		}
+	}
+	return n
+}
+
+// AsyncQueueUsage is how much a device has queued for EdgeX,
+// and how much of the budget all devices share it's guaranteed.
+type AsyncQueueUsage struct {
+	Values  int    // values the device has queued
+	Bytes   int64  // their estimated size
+	Dropped uint64 // values the device dropped since it was created
+
+	// The rest are only set if devices share an AsyncQueueBudgetKiB.
+	BudgetBytes     int64 `json:",omitempty"` // the budget all devices share
+	GuaranteedBytes int64 `json:",omitempty"` // what the device may always use of it
+	TotalBytes      int64 `json:",omitempty"` // what all devices have queued
+	PeakTotalBytes  int64 `json:",omitempty"` // the most all devices have had queued at once
+}
+
+// queueBudget limits the memory all devices use for values queued for EdgeX.
+//
+// Each device sharing it is guaranteed half of an even share of the budget,
+// and the rest goes to whichever devices need it first.
+// A device can't use the parts of the other devices' guarantees they aren't using,
+// so a device with a chatty Reader can't starve the others,
+// but while they're quiet, it can use most of the budget.
+//
+// Methods on a nil *queueBudget behave as if the budget were unlimited.
+type queueBudget struct {
+	limit int64 // bytes all devices may queue
+	block bool  // if set, wait for room instead of dropping values that don't fit
+
+	mu       sync.Mutex
+	members  map[*budgetShare]bool
+	used     int64         // bytes queued by all devices, including ones that left
+	peak     int64         // the most bytes queued at once
+	reserved int64         // the parts of members' guarantees they aren't using
+	room     chan struct{} // closed when values leave the budget, then replaced
+}
+
+// budgetShare is a device's use of a queueBudget, guarded by the budget's lock.
+type budgetShare struct {
+	used int64
+}
+
+// newQueueBudget returns a queueBudget of the given KiB,
+// or nil if it's 0, meaning the memory isn't limited.
+func newQueueBudget(kib int, overflow string) *queueBudget {
+	if kib <= 0 {
+		return nil
+	}
+	return &queueBudget{
+		limit:   int64(kib) * 1024,
+		block:   overflow == AsyncOverflowBlock,
+		members: make(map[*budgetShare]bool),
+		room:    make(chan struct{}),
+	}
+}
+
+// join adds a device to the budget and returns its share.
+func (b *queueBudget) join() *budgetShare {
+	s := &budgetShare{}
+	if b == nil {
+		return s
+	}
+
+	b.mu.Lock()
+	b.members[s] = true
+	b.updateReservedLocked()
+	b.mu.Unlock()
+	return s
+}
+
+// leave removes a device from the budget, so its guarantee goes to the others.
+// Values it still has queued count against the budget until they're released.
+func (b *queueBudget) leave(s *budgetShare) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	delete(b.members, s)
+	b.updateReservedLocked()
+	b.signalRoomLocked()
+	b.mu.Unlock()
+}
+
+// guaranteeLocked returns the bytes each member may always use:
+// half of an even share of the budget.
+func (b *queueBudget) guaranteeLocked() int64 {
+	if len(b.members) == 0 {
+		return b.limit / 2
+	}
+	return b.limit / int64(2*len(b.members))
+}
+
+// unused returns the part of a guarantee a member isn't using.
+func unused(guarantee, used int64) int64 {
+	if used >= guarantee {
+		return 0
+	}
+	return guarantee - used
+}
+
+// updateReservedLocked recomputes the parts of members' guarantees they aren't using.
+func (b *queueBudget) updateReservedLocked() {
+	guarantee := b.guaranteeLocked()
+	b.reserved = 0
+	for s := range b.members {
+		b.reserved += unused(guarantee, s.used)
+	}
+}
+
+// tryAcquireLocked adds n bytes to the share and returns true if they fit in the budget.
+func (b *queueBudget) tryAcquireLocked(s *budgetShare, n int64) bool {
+	if b.used+n > b.limit {
+		return false
+	}
+
+	guarantee := b.guaranteeLocked()
+	reserved := b.reserved
+	if b.members[s] {
+		reserved += unused(guarantee, s.used+n) - unused(guarantee, s.used)
+	}
+	// Beyond its guarantee, a device can't use what's guaranteed to the others.
+	if s.used+n > guarantee && b.used+n+reserved > b.limit {
+		return false
+	}
+
+	b.reserved = reserved
+	b.used += n
+	s.used += n
+	if b.used > b.peak {
+		b.peak = b.used
+	}
+	return true
+}
+
+// acquire adds n bytes to the share and returns true if they fit in the budget.
+// If they don't and the budget blocks, it waits for room until done is closed.
+// Values larger than the whole budget never fit.
+func (b *queueBudget) acquire(s *budgetShare, n int64, done <-chan struct{}) bool {
+	if b == nil {
+		return true
+	}
+	if n > b.limit {
+		return false
+	}
+
+	for {
+		b.mu.Lock()
+		ok := b.tryAcquireLocked(s, n)
+		room := b.room
+		b.mu.Unlock()
+
+		if ok || !b.block {
+			return ok
+		}
+
+		select {
+		case <-room:
+		case <-done:
+			return false
+		}
+	}
+}
+
+// release removes n bytes from the share, e.g. once its values are sent to EdgeX.
+func (b *queueBudget) release(s *budgetShare, n int64) {
+	if b == nil || n == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	if b.members[s] {
+		guarantee := b.guaranteeLocked()
+		b.reserved += unused(guarantee, s.used-n) - unused(guarantee, s.used)
+	}
+	b.used -= n
+	s.used -= n
+	b.signalRoomLocked()
+	b.mu.Unlock()
+}
+
+// signalRoomLocked wakes devices waiting for room in the budget, if it blocks.
+func (b *queueBudget) signalRoomLocked() {
+	if !b.block {
+		return
+	}
+	close(b.room)
+	b.room = make(chan struct{})
+}
+
+// usage adds the budget's state to the device's queue usage.
+func (b *queueBudget) usage(u *AsyncQueueUsage) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	u.BudgetBytes = b.limit
+	u.GuaranteedBytes = b.guaranteeLocked()
+	u.TotalBytes = b.used
+	u.PeakTotalBytes = b.peak
+}
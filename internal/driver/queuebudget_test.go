@@ -0,0 +1,141 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestQueueBudget_guarantees(t *testing.T) {
+	b := newQueueBudget(4, AsyncOverflowDrop)
+	chatty, quiet := b.join(), b.join()
+
+	// Each is guaranteed 1KiB; the chatty one may use the 2KiB nobody is guaranteed,
+	// but not the part of the quiet one's guarantee it isn't using.
+	if !b.acquire(chatty, 3072, nil) {
+		t.Fatal("expected the chatty share to use all but the quiet one's guarantee")
+	}
+	if b.acquire(chatty, 1, nil) {
+		t.Error("expected the chatty share not to use the quiet one's guarantee")
+	}
+	if !b.acquire(quiet, 1024, nil) {
+		t.Fatal("expected the quiet share to use its guarantee")
+	}
+	if b.acquire(quiet, 1, nil) {
+		t.Error("expected a full budget to drop values")
+	}
+
+	// Once the chatty one sends values, the room goes to whoever needs it first.
+	b.release(chatty, 2048)
+	if !b.acquire(quiet, 2048, nil) {
+		t.Error("expected the quiet share to use the room the chatty one released")
+	}
+
+	// When a device leaves, its guarantee goes to the others.
+	b.release(quiet, 3072)
+	b.leave(quiet)
+	if !b.acquire(chatty, 3072, nil) {
+		t.Error("expected the remaining share to use the whole budget")
+	}
+
+	u := AsyncQueueUsage{}
+	b.usage(&u)
+	if u.BudgetBytes != 4096 || u.GuaranteedBytes != 2048 || u.TotalBytes != 4096 || u.PeakTotalBytes != 4096 {
+		t.Errorf("unexpected usage: %+v", u)
+	}
+}
+
+func TestQueueBudget_block(t *testing.T) {
+	b := newQueueBudget(1, AsyncOverflowBlock)
+	s := b.join()
+	if !b.acquire(s, 1024, nil) {
+		t.Fatal("expected the share to fill the budget")
+	}
+	if b.acquire(s, 2048, nil) {
+		t.Error("expected values larger than the budget never to fit")
+	}
+
+	acquired := make(chan bool, 1)
+	go func() { acquired <- b.acquire(s, 512, nil) }()
+	select {
+	case <-acquired:
+		t.Fatal("expected the budget to block until there's room")
+	case <-time.After(50 * time.Millisecond):
+	}
+	b.release(s, 512)
+	if !<-acquired {
+		t.Error("expected the values to fit once there was room")
+	}
+
+	done := make(chan struct{})
+	go func() { acquired <- b.acquire(s, 512, done) }()
+	close(done)
+	if <-acquired {
+		t.Error("expected values to be dropped once done is closed")
+	}
+}
+
+func TestQueueBudget_nil(t *testing.T) {
+	var b *queueBudget
+	if newQueueBudget(0, AsyncOverflowBlock) != nil {
+		t.Error("expected a 0KiB budget to be unlimited")
+	}
+	s := b.join()
+	if !b.acquire(s, 1<<40, nil) {
+		t.Error("expected an unlimited budget to accept anything")
+	}
+	b.release(s, 1<<40)
+	b.leave(s)
+
+	u := AsyncQueueUsage{}
+	b.usage(&u)
+	if u != (AsyncQueueUsage{}) {
+		t.Errorf("expected an unlimited budget not to report usage; got %+v", u)
+	}
+}
+
+func TestLLRPDevice_AsyncQueueUsage_budget(t *testing.T) {
+	budget := newQueueBudget(1, AsyncOverflowDrop)
+	newDevice := func(name string, ch chan *dsModels.AsyncValues) *LLRPDevice {
+		return &LLRPDevice{name: name, lc: edgexCompatTestLogger{t}, ch: ch,
+			asyncOverflow: AsyncOverflowQueue,
+			queue:         asyncQueue{size: 100, budget: budget, share: budget.join()}}
+	}
+	chattyCh, quietCh := make(chan *dsModels.AsyncValues), make(chan *dsModels.AsyncValues)
+	chatty, quiet := newDevice("chattyReader", chattyCh), newDevice("quietReader", quietCh)
+
+	// Nothing reads the channels, so the chatty device fills what the budget lets it,
+	// but it can't take the room guaranteed to the quiet one.
+	for i := 0; i < 100; i++ {
+		chatty.sendAsync(numberedValues(i))
+	}
+	quiet.sendAsync(numberedValues(0))
+
+	cu, qu := chatty.AsyncQueueUsage(), quiet.AsyncQueueUsage()
+	if cu.Dropped == 0 || cu.Bytes > 1024-qu.GuaranteedBytes {
+		t.Errorf("expected the chatty device to drop values beyond its part of the budget; got %+v", cu)
+	}
+	if qu.Dropped != 0 || qu.Bytes != asyncValuesSize(numberedValues(0)) {
+		t.Errorf("expected the quiet device's values to fit; got %+v", qu)
+	}
+	if qu.TotalBytes != cu.Bytes+qu.Bytes || qu.BudgetBytes != 1024 {
+		t.Errorf("unexpected budget usage: %+v", qu)
+	}
+
+	// Once sent, values no longer count against the budget.
+	if av := receiveAsync(t, quietCh); av.DeviceName != "0" {
+		t.Errorf("expected values 0; got %s", av.DeviceName)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for quiet.AsyncQueueUsage().Bytes != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if qu := quiet.AsyncQueueUsage(); qu.Bytes != 0 || qu.TotalBytes != cu.Bytes {
+		t.Errorf("expected the sent values to be released; got %+v", qu)
+	}
+}
@@ -0,0 +1,67 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	// AttribReconnect is an optional resource attribute selecting how its commands
+	// handle a Reader that's disconnected or whose connection closes mid-send:
+	// ReconnectFailFast fails them on the first attempt,
+	// while ReconnectWait keeps trying until the service reconnects
+	// or the command times out.
+	// Without it, commands make a few quick attempts before failing.
+	AttribReconnect   = "reconnect"
+	ReconnectFailFast = "failFast"
+	ReconnectWait     = "wait"
+)
+
+// reconnectWait paces the attempts of ReconnectWait commands.
+// Its short maximum wait keeps commands from giving up well before they time out
+// because the next wait would exceed their deadline.
+var reconnectWait = retry.ExpBackOff{
+	BackOff:  50 * time.Millisecond,
+	Max:      time.Second,
+	Jitter:   true,
+	KeepErrs: 10,
+}
+
+// reconnectModeKey is the context key for a command's AttribReconnect mode.
+type reconnectModeKey struct{}
+
+// withReconnectMode returns a context carrying the AttribReconnect mode
+// from the resource attributes, if they have one,
+// or an error if the mode isn't known.
+func withReconnectMode(ctx context.Context, attributes map[string]string) (context.Context, error) {
+	switch mode := attributes[AttribReconnect]; mode {
+	case "":
+		return ctx, nil
+	case ReconnectFailFast, ReconnectWait:
+		return context.WithValue(ctx, reconnectModeKey{}, mode), nil
+	default:
+		return ctx, errors.Errorf("unknown %s mode %q; modes are %s or %s",
+			AttribReconnect, mode, ReconnectFailFast, ReconnectWait)
+	}
+}
+
+// retrySend calls send until it succeeds or returns an error that isn't temporary,
+// making as many attempts as the context's AttribReconnect mode allows.
+func retrySend(ctx context.Context, send retry.Func) error {
+	mode, _ := ctx.Value(reconnectModeKey{}).(string)
+	switch mode {
+	case ReconnectFailFast:
+		return retry.Quick.RetryWithCtx(ctx, 1, send)
+	case ReconnectWait:
+		return reconnectWait.RetryWithCtx(ctx, retry.Forever, send)
+	default:
+		return retry.Quick.RetryWithCtx(ctx, maxSendAttempts, send)
+	}
+}
@@ -0,0 +1,115 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestWithReconnectMode(t *testing.T) {
+	for _, testCase := range []struct {
+		mode string
+		err  bool
+	}{
+		{mode: ""},
+		{mode: ReconnectFailFast},
+		{mode: ReconnectWait},
+		{mode: "eventually", err: true},
+	} {
+		attributes := map[string]string{}
+		if testCase.mode != "" {
+			attributes[AttribReconnect] = testCase.mode
+		}
+
+		ctx, err := withReconnectMode(context.Background(), attributes)
+		if (err != nil) != testCase.err {
+			t.Errorf("mode %q: expected error: %v; got %v", testCase.mode, testCase.err, err)
+			continue
+		}
+
+		if mode, _ := ctx.Value(reconnectModeKey{}).(string); mode != testCase.mode && !testCase.err {
+			t.Errorf("mode %q: expected the context to carry it; got %q", testCase.mode, mode)
+		}
+	}
+}
+
+func TestLLRPDevice_TrySend_failFast(t *testing.T) {
+	// The Reader is disconnected.
+	dev := &LLRPDevice{name: "disconnectedReader", lc: edgexCompatTestLogger{t}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ctx, err := withReconnectMode(ctx, map[string]string{AttribReconnect: ReconnectFailFast})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dev.TrySend(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{})
+	if !errors.Is(err, errNoClient) {
+		t.Fatalf("expected errNoClient; got %v", err)
+	}
+
+	// It made only the one attempt.
+	var fe *retry.FError
+	if !errors.As(err, &fe) || fe.Attempts != 0 {
+		t.Errorf("expected a single attempt; got %+v", err)
+	}
+}
+
+func TestLLRPDevice_TrySend_waitForReconnect(t *testing.T) {
+	// The test Reader doesn't send KeepAlives, so the connection's timeout
+	// must be long enough that it doesn't close while the device waits.
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*10, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rfid.SetResponse(llrp.MsgGetReaderConfig, &llrp.GetReaderConfigResponse{})
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	// The Reader is disconnected, but the service reconnects shortly.
+	dev := &LLRPDevice{name: "reconnectingReader", lc: edgexCompatTestLogger{t}}
+	reconnect := time.AfterFunc(500*time.Millisecond, func() {
+		dev.clientLock.Lock()
+		dev.client = c
+		dev.clientLock.Unlock()
+	})
+	defer reconnect.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// By default, the command only makes a few quick attempts.
+	if err := dev.TrySend(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{}); !errors.Is(err, errNoClient) {
+		t.Fatalf("expected errNoClient before the service reconnects; got %v", err)
+	}
+
+	waitCtx, err := withReconnectMode(ctx, map[string]string{AttribReconnect: ReconnectWait})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dev.TrySend(waitCtx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{}); err != nil {
+		t.Fatalf("expected the command to succeed after the service reconnects; got %+v", err)
+	}
+
+	// It still gives up when its context ends.
+	dev.clientLock.Lock()
+	dev.client = nil
+	dev.clientLock.Unlock()
+
+	shortCtx, cancelShort := context.WithTimeout(waitCtx, 300*time.Millisecond)
+	defer cancelShort()
+	if err := dev.TrySend(shortCtx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{}); err == nil {
+		t.Fatal("expected the command to fail once its context ended")
+	}
+}
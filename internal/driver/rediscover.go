@@ -0,0 +1,259 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"net"
+	"strings"
+)
+
+const (
+	// ResourceReaderPresence is sent as an event when a discovery adds a device for a new Reader,
+	// finds a Reader it previously reported missing, or doesn't find a disabled device's Reader.
+	ResourceReaderPresence = "ReaderPresence"
+
+	// MissingReaders policies, for disabled devices whose Readers a discovery didn't find.
+	MissingReadersFlag   = "flag"   // send a ReaderPresence event, but keep the device
+	MissingReadersRemove = "remove" // send a ReaderPresence event, then remove the device
+)
+
+// Presences in ReaderPresence events.
+const (
+	PresenceNew      = "New"      // discovery added a device for the Reader
+	PresenceReturned = "Returned" // discovery found a Reader it had reported missing
+	PresenceMissing  = "Missing"  // discovery didn't find the Reader of a disabled device
+	PresenceRemoved  = "Removed"  // like PresenceMissing, but the device was removed
+)
+
+// readerPresence is the value of ResourceReaderPresence events.
+type readerPresence struct {
+	Presence string
+	Host     string `json:",omitempty"`
+	Port     string `json:",omitempty"`
+	ReaderID string `json:",omitempty"`
+}
+
+// checkMissingReaders returns an error if policy isn't a known MissingReaders policy.
+func checkMissingReaders(policy string) error {
+	switch policy {
+	case MissingReadersFlag, MissingReadersRemove:
+		return nil
+	default:
+		return errors.Errorf("unknown missing readers policy %q; policies are %s or %s",
+			policy, MissingReadersFlag, MissingReadersRemove)
+	}
+}
+
+// newPresence returns a readerPresence with a device's address and ReaderID.
+func newPresence(presence string, tcpInfo contract.ProtocolProperties) readerPresence {
+	return readerPresence{
+		Presence: presence,
+		Host:     tcpInfo["host"],
+		Port:     tcpInfo["port"],
+		ReaderID: tcpInfo[PropertyReaderID],
+	}
+}
+
+// mergeDiscovery compares the devices registered before a discovery that scanned every address
+// with those registered after it and the existing devices whose Readers it found,
+// sends ResourceReaderPresence events for the changes, and returns true if there were any.
+//
+// Discovery doesn't scan the addresses of enabled devices that are connected to their Readers,
+// so it doesn't disturb their connections; those are present.
+// A device that was disabled or disconnected before the discovery,
+// whose Reader the discovery didn't find (and that wasn't enabled meanwhile),
+// and whose address the discovery scanned (or that has no address),
+// is missing: it's flagged once, or removed if the policy is MissingReadersRemove
+// and it's disabled. Devices that are merely disconnected are only flagged,
+// leaving them to their own reconnect attempts, which disable them after several failures.
+// A flagged device that's present again is reported as having returned.
+//
+// Only the discovery in progress calls this, so it needn't lock missingReaders.
+func (d *Driver) mergeDiscovery(before []contract.Device, added []dsModels.DiscoveredDevice,
+	found map[string]bool, params discoverParams, policy string) (changed bool) {
+	for _, discovered := range added {
+		d.sendPresence(discovered.Name, newPresence(PresenceNew, discovered.Protocols["tcp"]))
+	}
+	changed = len(added) != 0 || len(found) != 0
+
+	after := make(map[string]contract.Device)
+	for _, dev := range d.svc.Devices() {
+		after[dev.Name] = dev
+	}
+
+	if d.missingReaders == nil {
+		d.missingReaders = make(map[string]bool)
+	}
+	for name := range d.missingReaders {
+		if _, ok := after[name]; !ok {
+			delete(d.missingReaders, name)
+		}
+	}
+
+	for _, old := range before {
+		dev, ok := after[old.Name]
+		if !ok {
+			continue
+		}
+
+		wasDisabled := old.OperatingState == contract.Disabled
+		probed := wasDisabled || params.disconnected[old.Name]
+		present := !probed || found[dev.Name] || (wasDisabled && dev.OperatingState != contract.Disabled)
+
+		tcpInfo := dev.Protocols["tcp"]
+		flagged := d.missingReaders[dev.Name]
+		if present {
+			if flagged {
+				delete(d.missingReaders, dev.Name)
+				d.lc.Info("Discovery found a missing Reader.", "device", dev.Name)
+				d.sendPresence(dev.Name, newPresence(PresenceReturned, tcpInfo))
+				changed = true
+			}
+			continue
+		}
+
+		remove := policy == MissingReadersRemove && dev.OperatingState == contract.Disabled
+		if (flagged && !remove) || !params.scanned(tcpInfo) {
+			continue
+		}
+		changed = true
+
+		if !remove {
+			d.missingReaders[dev.Name] = true
+			d.lc.Warn("Discovery didn't find the Reader of a disabled or disconnected device.", "device", dev.Name)
+			d.sendPresence(dev.Name, newPresence(PresenceMissing, tcpInfo))
+			continue
+		}
+
+		d.lc.Warn("Removing device, since discovery didn't find its Reader.", "device", dev.Name)
+		d.sendPresence(dev.Name, newPresence(PresenceRemoved, tcpInfo))
+		if err := d.svc.RemoveDeviceByName(dev.Name); err != nil {
+			d.lc.Error("Failed to remove missing device.", "device", dev.Name, "error", err.Error())
+		}
+	}
+	return changed
+}
+
+// disconnectedDevices returns the names of active devices that aren't connected to their Readers,
+// not counting those that closed their connections because they were idle.
+func (d *Driver) disconnectedDevices() map[string]bool {
+	d.devicesMu.RLock()
+	defer d.devicesMu.RUnlock()
+
+	disconnected := make(map[string]bool)
+	for name, dev := range d.activeDevices {
+		dev.deviceMu.RLock()
+		if !dev.connected && !dev.idle {
+			disconnected[name] = true
+		}
+		dev.deviceMu.RUnlock()
+	}
+	return disconnected
+}
+
+// skipDiscovery returns true if the Driver should skip a discovery to back off
+// after recent ones changed nothing, counting it as skipped.
+func (d *Driver) skipDiscovery() bool {
+	d.discoverMu.Lock()
+	defer d.discoverMu.Unlock()
+
+	if d.discoverSkips == 0 {
+		return false
+	}
+	d.discoverSkips--
+	d.lc.Info("Skipping discovery to back off, since recent discoveries changed nothing.",
+		"remainingSkips", d.discoverSkips)
+	return true
+}
+
+// backOffDiscovery sets how many discoveries to skip after a complete one.
+// Each complete discovery in a row that changed nothing doubles the discoveries
+// between scans, up to maxBackoff; one that changed something resets it.
+// A maxBackoff of 1 or less scans at every discovery.
+func (d *Driver) backOffDiscovery(changed bool, maxBackoff int) {
+	d.discoverMu.Lock()
+	defer d.discoverMu.Unlock()
+
+	if changed || maxBackoff <= 1 {
+		d.quietDiscovers, d.discoverSkips = 0, 0
+		return
+	}
+
+	d.quietDiscovers++
+	between := 1
+	for i := 0; i < d.quietDiscovers && between < maxBackoff; i++ {
+		between *= 2
+	}
+	if between > maxBackoff {
+		between = maxBackoff
+	}
+	d.discoverSkips = between - 1
+}
+
+// resetDiscoveryBackoff makes the next discovery scan,
+// e.g., because a device lost its Reader.
+func (d *Driver) resetDiscoveryBackoff() {
+	d.discoverMu.Lock()
+	d.quietDiscovers, d.discoverSkips = 0, 0
+	d.discoverMu.Unlock()
+}
+
+// scanned returns true if a discovery with these parameters that scanned every address
+// would have found a Reader with this tcp protocol at its address:
+// either it's in one of the subnets at the scan port, or it's missing,
+// in which case the Reader could have been found at any address.
+func (params discoverParams) scanned(tcpInfo contract.ProtocolProperties) bool {
+	host, port := tcpInfo["host"], tcpInfo["port"]
+	if host == "" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || port != params.scanPort {
+		return false
+	}
+
+	for _, cidr := range params.subnets {
+		if _, ipnet, err := net.ParseCIDR(strings.TrimSpace(cidr)); err == nil && ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendPresence sends a ResourceReaderPresence event for the device.
+// Active devices send it per their AsyncOverflow policy, like their other events.
+// A device discovery just added may not be active yet,
+// so its event goes straight to EdgeX, unless the driver stops first.
+func (d *Driver) sendPresence(name string, presence readerPresence) {
+	now := d.clock().Now().UnixNano()
+
+	d.devicesMu.RLock()
+	dev := d.activeDevices[name]
+	d.devicesMu.RUnlock()
+	if dev != nil {
+		dev.goSend(func() {
+			dev.sendEdgeXEvent(ResourceReaderPresence, now, presence)
+		})
+		return
+	}
+
+	data, err := json.Marshal(presence)
+	if err != nil {
+		d.lc.Error("Failed to marshal reader presence.", "device", name, "error", err.Error())
+		return
+	}
+
+	cv := dsModels.NewStringValue(ResourceReaderPresence, now, string(data))
+	select {
+	case d.asyncCh <- &dsModels.AsyncValues{DeviceName: name, CommandValues: []*dsModels.CommandValue{cv}}:
+	case <-d.done:
+	}
+}
@@ -0,0 +1,216 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"testing"
+)
+
+func TestDriver_mergeDiscovery(t *testing.T) {
+	svc.clearDevices()
+	defer svc.clearDevices()
+
+	params := makeParams()
+	newDevice := func(name, host string, state contract.OperatingState) contract.Device {
+		return contract.Device{Name: name, OperatingState: state, Protocols: protocolMap{
+			"tcp": {"host": host, "port": params.scanPort, PropertyReaderID: name + "-id"},
+		}}
+	}
+
+	ch := make(chan *dsModels.AsyncValues, 10)
+	d := &Driver{
+		lc:      edgexCompatTestLogger{t},
+		asyncCh: ch,
+		done:    make(chan struct{}),
+		svc:     svc,
+		clk:     newFakeClock(),
+	}
+
+	// merge registers the devices, as a discovery might have left them,
+	// and merges a discovery that found the added ones,
+	// then returns the presence events it sent, by device name.
+	// It also returns whether the merge reported any changes.
+	var changed bool
+	merge := func(before []contract.Device, after []contract.Device,
+		added []dsModels.DiscoveredDevice, found map[string]bool, policy string) map[string]readerPresence {
+		t.Helper()
+		svc.clearDevices()
+		for _, dev := range after {
+			if _, err := svc.AddDevice(dev); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		changed = d.mergeDiscovery(before, added, found, params, policy)
+
+		presences := map[string]readerPresence{}
+		for len(ch) > 0 {
+			av := <-ch
+			if av.CommandValues[0].DeviceResourceName != ResourceReaderPresence {
+				t.Fatalf("expected a %s event; got %+v", ResourceReaderPresence, av)
+			}
+			p := readerPresence{}
+			if err := json.Unmarshal([]byte(av.CommandValues[0].ValueToString()), &p); err != nil {
+				t.Fatal(err)
+			}
+			presences[av.DeviceName] = p
+		}
+		return presences
+	}
+
+	expectPresences := func(presences map[string]readerPresence, expected map[string]string) {
+		t.Helper()
+		if len(presences) != len(expected) {
+			t.Errorf("expected presences %v; got %+v", expected, presences)
+		}
+		for name, presence := range expected {
+			if p, ok := presences[name]; !ok || p.Presence != presence {
+				t.Errorf("expected %s to be %s; got %+v", name, presence, p)
+			}
+		}
+	}
+
+	connected := newDevice("connected", "127.0.0.1", contract.Enabled)
+	gone := newDevice("gone", "127.0.0.1", contract.Disabled)
+	elsewhere := newDevice("elsewhere", "192.0.2.1", contract.Disabled)
+	added := dsModels.DiscoveredDevice{Name: "added", Protocols: protocolMap{
+		"tcp": {"host": "127.0.0.1", "port": params.scanPort},
+	}}
+	before := []contract.Device{connected, gone, elsewhere}
+
+	// The disabled device the discovery could have found is flagged;
+	// the one outside the scanned subnets and the connected one are left alone.
+	presences := merge(before, []contract.Device{connected, gone, elsewhere},
+		[]dsModels.DiscoveredDevice{added}, nil, MissingReadersFlag)
+	expectPresences(presences, map[string]string{"added": PresenceNew, "gone": PresenceMissing})
+	if p := presences["gone"]; p.ReaderID != "gone-id" || p.Host != "127.0.0.1" {
+		t.Errorf("expected the missing device's address and ReaderID; got %+v", p)
+	}
+	if !changed {
+		t.Error("expected the merge to report changes")
+	}
+
+	// It's only flagged once.
+	presences = merge(before, []contract.Device{connected, gone, elsewhere}, nil, nil, MissingReadersFlag)
+	expectPresences(presences, nil)
+	if changed {
+		t.Error("expected the merge to report no changes")
+	}
+
+	// Once discovery finds and enables it, it's returned.
+	enabled := newDevice("gone", "127.0.0.1", contract.Enabled)
+	presences = merge(before, []contract.Device{connected, enabled, elsewhere},
+		nil, map[string]bool{"gone": true}, MissingReadersFlag)
+	expectPresences(presences, map[string]string{"gone": PresenceReturned})
+
+	// A device whose address was taken by another Reader can be found anywhere,
+	// so it's missing, and with the remove policy, it's removed.
+	moved := newDevice("moved", "", contract.Disabled)
+	presences = merge([]contract.Device{moved}, []contract.Device{moved}, nil, nil, MissingReadersRemove)
+	expectPresences(presences, map[string]string{"moved": PresenceRemoved})
+	if _, err := svc.GetDeviceByName("moved"); err == nil {
+		t.Error("expected the missing device to be removed")
+	}
+
+	// Discovery scans the addresses of enabled devices that are disconnected, too.
+	// One the discovery found has no change in presence.
+	dropped := newDevice("dropped", "127.0.0.1", contract.Enabled)
+	params.disconnected = map[string]bool{"dropped": true}
+	presences = merge([]contract.Device{dropped}, []contract.Device{dropped},
+		nil, map[string]bool{"dropped": true}, MissingReadersRemove)
+	expectPresences(presences, nil)
+	if !changed {
+		t.Error("expected finding a disconnected device's Reader to be a change")
+	}
+
+	// One it didn't find is flagged, but not removed while it's enabled.
+	presences = merge([]contract.Device{dropped}, []contract.Device{dropped}, nil, nil, MissingReadersRemove)
+	expectPresences(presences, map[string]string{"dropped": PresenceMissing})
+	if _, err := svc.GetDeviceByName("dropped"); err != nil {
+		t.Error("expected the enabled device to be kept")
+	}
+
+	// Once its reconnect attempts disable it, it's removed.
+	disabled := newDevice("dropped", "127.0.0.1", contract.Disabled)
+	presences = merge([]contract.Device{disabled}, []contract.Device{disabled}, nil, nil, MissingReadersRemove)
+	expectPresences(presences, map[string]string{"dropped": PresenceRemoved})
+}
+
+func TestDriver_sendPresence(t *testing.T) {
+	driverCh, deviceCh := make(chan *dsModels.AsyncValues, 1), make(chan *dsModels.AsyncValues, 1)
+	dev := &LLRPDevice{name: "activeReader", ch: deviceCh}
+	d := newLocalDriver(t, dev)
+	d.asyncCh = driverCh
+	d.done = make(chan struct{})
+	d.clk = newFakeClock()
+
+	// Active devices send it like their other events.
+	d.sendPresence(dev.name, readerPresence{Presence: PresenceReturned})
+	if av := receiveAsync(t, deviceCh); av.DeviceName != dev.name ||
+		av.CommandValues[0].DeviceResourceName != ResourceReaderPresence {
+		t.Errorf("expected the device to send a %s event; got %+v", ResourceReaderPresence, av)
+	}
+
+	// Devices that aren't active yet send it straight to EdgeX.
+	d.sendPresence("newReader", readerPresence{Presence: PresenceNew})
+	if av := receiveAsync(t, driverCh); av.DeviceName != "newReader" {
+		t.Errorf("expected the driver to send the new device's event; got %+v", av)
+	}
+}
+
+func TestDriver_backOffDiscovery(t *testing.T) {
+	d := &Driver{lc: edgexCompatTestLogger{t}}
+
+	// skips returns how many discoveries in a row are skipped.
+	skips := func() int {
+		n := 0
+		for d.skipDiscovery() {
+			n++
+		}
+		return n
+	}
+
+	// Each quiet discovery doubles the intervals between scans, up to the max.
+	for i, expected := range []int{1, 3, 4, 4} {
+		d.backOffDiscovery(false, 5)
+		if n := skips(); n != expected {
+			t.Errorf("quiet discovery %d: expected %d skips; got %d", i+1, expected, n)
+		}
+	}
+
+	// A change resets it.
+	d.backOffDiscovery(true, 5)
+	if n := skips(); n != 0 {
+		t.Errorf("expected no skips after a change; got %d", n)
+	}
+	d.backOffDiscovery(false, 5)
+	d.resetDiscoveryBackoff()
+	if n := skips(); n != 0 {
+		t.Errorf("expected no skips after a reset; got %d", n)
+	}
+
+	// Without a max backoff, every discovery scans.
+	for _, maxBackoff := range []int{0, 1} {
+		d.backOffDiscovery(false, maxBackoff)
+		if n := skips(); n != 0 {
+			t.Errorf("max backoff %d: expected no skips; got %d", maxBackoff, n)
+		}
+	}
+}
+
+func TestCheckMissingReaders(t *testing.T) {
+	for _, policy := range []string{MissingReadersFlag, MissingReadersRemove} {
+		if err := checkMissingReaders(policy); err != nil {
+			t.Errorf("expected %q to be valid; got %v", policy, err)
+		}
+	}
+	if err := checkMissingReaders("ignore"); err == nil {
+		t.Error("expected an unknown policy to be invalid")
+	}
+}
@@ -0,0 +1,150 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	"github.com/pkg/errors"
+	"net"
+)
+
+// reportAddr returns the address of the device's report connection:
+// the Reader's host at the report port.
+// It returns nil if the device doesn't use a report connection.
+func (l *LLRPDevice) reportAddr() net.Addr {
+	l.deviceMu.RLock()
+	addr, port := l.address, l.reportPort
+	l.deviceMu.RUnlock()
+
+	if addr == nil || port == "" {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+
+	reportAddr, err := net.ResolveTCPAddr("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil
+	}
+	return reportAddr
+}
+
+// setReportPort sets the port of the device's report connection,
+// or disables it if the port is empty.
+//
+// If the port changes, it closes the current report connection, if any,
+// and the report connection is redialed using the new port.
+func (l *LLRPDevice) setReportPort(port string) {
+	l.deviceMu.Lock()
+	changed := l.reportPort != port
+	l.reportPort = port
+	l.deviceMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	l.clientLock.Lock()
+	l.closeReportConnLocked()
+	l.clientLock.Unlock()
+
+	select {
+	case l.reportWake <- struct{}{}:
+	default:
+	}
+}
+
+// closeReportConnLocked closes the current report connection, if any.
+// You must be holding the clientLock when you call this.
+func (l *LLRPDevice) closeReportConnLocked() {
+	if l.reportClient == nil {
+		return
+	}
+
+	// Reports are all that's sent on this connection,
+	// so there's nothing to gain by shutting it down gracefully.
+	_ = l.reportClient.Close()
+	l.reportClient = nil
+}
+
+// manageReportConn maintains the device's report connection
+// until the context is canceled.
+//
+// The report connection is a second LLRP connection to the Reader
+// on which the service only handles ROAccessReports,
+// so that bulk report traffic doesn't delay commands and their responses.
+// Reports it receives are handled just like those on the main connection,
+// so they're sent to EdgeX as the same device.
+//
+// While the device doesn't have a report port, this waits until it gets one.
+func (l *LLRPDevice) manageReportConn(ctx context.Context, opts []llrp.ClientOpt) {
+	dialer := net.Dialer{}
+
+	for ctx.Err() == nil {
+		if l.reportAddr() == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-l.reportWake:
+				continue
+			}
+		}
+
+		_ = retry.Slow.RetryWithCtx(ctx, retry.Forever, func(ctx context.Context) (bool, error) {
+			addr := l.reportAddr()
+			if addr == nil {
+				return false, nil // the report connection was disabled
+			}
+
+			l.lc.Debug("Attempting to dial Reader report connection.",
+				"address", addr.String(), "device", l.name)
+			dialCtx, dialCtxCancel := context.WithTimeout(ctx, dialTimeout)
+			defer dialCtxCancel()
+			conn, err := dialer.DialContext(dialCtx, addr.Network(), addr.String())
+			if err != nil {
+				l.lc.Error("Failed to dial Reader report connection.", "error", err.Error(),
+					"address", addr.String(), "device", l.name)
+				return true, err
+			}
+
+			defer conn.Close()
+
+			c := llrp.NewClient(opts...)
+			l.clientLock.Lock()
+			if ctx.Err() != nil { // the device stopped while we were dialing
+				l.clientLock.Unlock()
+				return false, ctx.Err()
+			}
+			l.reportClient = c
+			l.clientLock.Unlock()
+
+			// This blocks until the Client closes.
+			clientErr := c.Connect(conn)
+
+			l.clientLock.Lock()
+			if l.reportClient == c {
+				l.reportClient = nil
+			}
+			l.clientLock.Unlock()
+
+			if clientErr == nil || errors.Is(clientErr, llrp.ErrClientClosed) {
+				l.lc.Debug("Report connection closed normally.", "device", l.name)
+				return true, nil
+			}
+
+			// Most Readers only accept a single client connection,
+			// in which case every attempt fails, so it's worth a warning.
+			l.lc.Warn("Report connection closed unexpectedly.",
+				"error", clientErr.Error(), "device", l.name)
+			return true, clientErr
+		})
+	}
+}
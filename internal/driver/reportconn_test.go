@@ -0,0 +1,89 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestGetReportPort(t *testing.T) {
+	for _, testCase := range []struct {
+		protocols protocolMap
+		port      string
+		invalid   bool
+	}{
+		{protocols: protocolMap{}},
+		{protocols: protocolMap{ProtocolReport: {"format": "flat"}}},
+		{protocols: protocolMap{ProtocolReport: {"port": "5085"}}, port: "5085"},
+		{protocols: protocolMap{ProtocolReport: {"port": "0"}}, invalid: true},
+		{protocols: protocolMap{ProtocolReport: {"port": "65536"}}, invalid: true},
+		{protocols: protocolMap{ProtocolReport: {"port": "reports"}}, invalid: true},
+	} {
+		port, err := getReportPort(testCase.protocols)
+		if port != testCase.port || (err != nil) != testCase.invalid {
+			t.Errorf("%v: expected port=%q, invalid=%t; got %q, %v",
+				testCase.protocols, testCase.port, testCase.invalid, port, err)
+		}
+	}
+}
+
+func TestLLRPDevice_reportConn(t *testing.T) {
+	// The emulator accepts multiple connections, so it can serve as both.
+	_, port := startEmulator(t, 0)
+
+	d := newTestDriver(nil)
+
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}
+	dev := d.NewLLRPDevice("reportReader", addr, contract.Enabled)
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		_ = dev.Stop(ctx)
+	}()
+
+	if dev.reportAddr() != nil {
+		t.Fatal("expected no report connection without a report port")
+	}
+
+	dev.setReportPort(strconv.Itoa(port))
+	if ra := dev.reportAddr(); ra == nil || !sameAddr(ra, addr) {
+		t.Fatalf("expected report address %v; got %v", addr, ra)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The report connection opens asynchronously.
+	if err := retry.Quick.RetryWithCtx(ctx, retry.Forever, func(ctx context.Context) (bool, error) {
+		dev.clientLock.RLock()
+		c := dev.reportClient
+		dev.clientLock.RUnlock()
+		if c == nil {
+			return true, errors.New("report connection isn't open")
+		}
+
+		err := c.SendFor(ctx, &llrp.GetReaderConfig{}, &llrp.GetReaderConfigResponse{})
+		return err != nil, err
+	}); err != nil {
+		t.Fatalf("report connection didn't open: %+v", err)
+	}
+
+	dev.setReportPort("")
+	dev.clientLock.RLock()
+	c := dev.reportClient
+	dev.clientLock.RUnlock()
+	if c != nil {
+		t.Error("expected the report connection to close without a report port")
+	}
+}
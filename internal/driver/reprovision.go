@@ -0,0 +1,306 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ResourceReaderReprovisioned is sent as an event after the service
+	// restores the specs a Reader lost when it rebooted.
+	ResourceReaderReprovisioned = "ReaderReprovisioned"
+
+	// reprovisionTimeout limits how long the service spends restoring a Reader's specs.
+	reprovisionTimeout = 2 * time.Minute
+)
+
+// reprovisionEvent is the value of ResourceReaderReprovisioned events.
+type reprovisionEvent struct {
+	ROSpecIDs     []uint32 // ROSpecs the service restored
+	AccessSpecIDs []uint32 // AccessSpecs the service restored
+	Errors        []string `json:",omitempty"` // failures restoring specs or their states
+}
+
+// trackedROSpec is an ROSpec the service added and the state the service last set for it.
+type trackedROSpec struct {
+	spec    llrp.ROSpec
+	enabled bool
+	started bool
+	// stopping is true if the service stopped or disabled the ROSpec
+	// and the Reader hasn't yet reported that it ended.
+	stopping bool
+}
+
+// trackedAccessSpec is an AccessSpec the service added
+// and whether the service last enabled it.
+type trackedAccessSpec struct {
+	spec    llrp.AccessSpec
+	enabled bool
+}
+
+// specTracker tracks the ROSpecs and AccessSpecs the service added to a Reader
+// and their states, so they can be restored if the Reader reboots.
+// Its zero value is ready to use.
+type specTracker struct {
+	mu          sync.Mutex
+	roSpecs     map[uint32]*trackedROSpec
+	accessSpecs map[uint32]*trackedAccessSpec
+}
+
+// record updates the tracked specs after the Reader accepts a request.
+// Specs the service manages internally, for RF surveys and write verification,
+// aren't tracked since they're only useful while their operations are in progress.
+func (t *specTracker) record(request llrp.Outgoing) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.roSpecs == nil {
+		t.roSpecs = make(map[uint32]*trackedROSpec)
+		t.accessSpecs = make(map[uint32]*trackedAccessSpec)
+	}
+
+	// forROSpecs and forAccessSpecs call f for the specs matching an ID,
+	// or all of them if the ID is 0, as LLRP uses 0 for all specs.
+	forROSpecs := func(id uint32, f func(ros *trackedROSpec)) {
+		for roID, ros := range t.roSpecs {
+			if id == 0 || id == roID {
+				f(ros)
+			}
+		}
+	}
+	forAccessSpecs := func(id uint32, f func(as *trackedAccessSpec)) {
+		for asID, as := range t.accessSpecs {
+			if id == 0 || id == asID {
+				f(as)
+			}
+		}
+	}
+
+	switch m := request.(type) {
+	case *llrp.AddROSpec:
+		if m.ROSpec.ROSpecID != rfSurveyROSpecID {
+			t.roSpecs[m.ROSpec.ROSpecID] = &trackedROSpec{spec: m.ROSpec}
+		}
+	case *llrp.EnableROSpec:
+		forROSpecs(m.ROSpecID, func(ros *trackedROSpec) { ros.enabled = true })
+	case *llrp.StartROSpec:
+		forROSpecs(m.ROSpecID, func(ros *trackedROSpec) { ros.started, ros.stopping = true, false })
+	case *llrp.StopROSpec:
+		forROSpecs(m.ROSpecID, func(ros *trackedROSpec) { ros.started, ros.stopping = false, true })
+	case *llrp.DisableROSpec:
+		forROSpecs(m.ROSpecID, func(ros *trackedROSpec) {
+			ros.enabled, ros.started, ros.stopping = false, false, true
+		})
+	case *llrp.DeleteROSpec:
+		forROSpecs(m.ROSpecID, func(ros *trackedROSpec) { delete(t.roSpecs, ros.spec.ROSpecID) })
+
+	case *llrp.AddAccessSpec:
+		if m.AccessSpec.AccessSpecID <= firstReadbackID-numReadbackIDs {
+			t.accessSpecs[m.AccessSpec.AccessSpecID] = &trackedAccessSpec{spec: m.AccessSpec}
+		}
+	case *llrp.EnableAccessSpec:
+		forAccessSpecs(m.AccessSpecID, func(as *trackedAccessSpec) { as.enabled = true })
+	case *llrp.DisableAccessSpec:
+		forAccessSpecs(m.AccessSpecID, func(as *trackedAccessSpec) { as.enabled = false })
+	case *llrp.DeleteAccessSpec:
+		forAccessSpecs(m.AccessSpecID, func(as *trackedAccessSpec) { delete(t.accessSpecs, as.spec.AccessSpecID) })
+
+	case *llrp.SetReaderConfig:
+		// Resetting the Reader to its factory defaults deletes its specs.
+		if m.ResetToFactoryDefaults {
+			t.roSpecs = nil
+			t.accessSpecs = nil
+		}
+	}
+}
+
+// roSpecEnded returns a copy of the tracked ROSpec with the ID
+// and whether the service stopped it, for the Reader's report that it ended,
+// after which a later end is assumed to be the ROSpec's own.
+// If the service isn't tracking the ROSpec, it returns false.
+func (t *specTracker) roSpecEnded(id uint32) (ros trackedROSpec, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked, ok := t.roSpecs[id]
+	if !ok {
+		return trackedROSpec{}, false
+	}
+	ros = *tracked
+	tracked.stopping = false
+	return ros, true
+}
+
+// snapshot returns copies of the tracked specs, ordered by ID.
+func (t *specTracker) snapshot() ([]trackedROSpec, []trackedAccessSpec) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	roSpecs := make([]trackedROSpec, 0, len(t.roSpecs))
+	for _, ros := range t.roSpecs {
+		roSpecs = append(roSpecs, *ros)
+	}
+	sort.Slice(roSpecs, func(i, j int) bool { return roSpecs[i].spec.ROSpecID < roSpecs[j].spec.ROSpecID })
+
+	accessSpecs := make([]trackedAccessSpec, 0, len(t.accessSpecs))
+	for _, as := range t.accessSpecs {
+		accessSpecs = append(accessSpecs, *as)
+	}
+	sort.Slice(accessSpecs, func(i, j int) bool {
+		return accessSpecs[i].spec.AccessSpecID < accessSpecs[j].spec.AccessSpecID
+	})
+
+	return roSpecs, accessSpecs
+}
+
+// readOps returns the C1G2Reads of the tracked AccessSpecs, by AccessSpecID,
+// for labeling their results in tag reads.
+func (t *specTracker) readOps() map[uint32]llrp.C1G2Read {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ops := make(map[uint32]llrp.C1G2Read)
+	for id, as := range t.accessSpecs {
+		if op := as.spec.AccessCommand.C1G2Read; op != nil {
+			ops[id] = *op
+		}
+	}
+	return ops
+}
+
+// rebooted returns true if the Reader has none of the tracked specs,
+// which means it lost them, usually because it rebooted.
+// It returns false if there aren't any tracked specs.
+func (l *LLRPDevice) rebooted(ctx context.Context, roSpecs []trackedROSpec, accessSpecs []trackedAccessSpec) (bool, error) {
+	switch {
+	case len(roSpecs) != 0:
+		resp := &llrp.GetROSpecsResponse{}
+		if err := l.TrySend(ctx, &llrp.GetROSpecs{}, resp); err != nil {
+			return false, errors.WithMessage(err, "failed to get ROSpecs")
+		}
+
+		for _, ros := range resp.ROSpecs {
+			for _, tracked := range roSpecs {
+				if ros.ROSpecID == tracked.spec.ROSpecID {
+					return false, nil
+				}
+			}
+		}
+		return true, nil
+
+	case len(accessSpecs) != 0:
+		resp := &llrp.GetAccessSpecsResponse{}
+		if err := l.TrySend(ctx, &llrp.GetAccessSpecs{}, resp); err != nil {
+			return false, errors.WithMessage(err, "failed to get AccessSpecs")
+		}
+
+		for _, as := range resp.AccessSpecs {
+			for _, tracked := range accessSpecs {
+				if as.AccessSpecID == tracked.spec.AccessSpecID {
+					return false, nil
+				}
+			}
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// reprovision restores the specs the service added to the Reader, and their states,
+// if the Reader lost them, and sends a ResourceReaderReprovisioned event if it did.
+// It does nothing unless the device is configured to reprovision.
+func (l *LLRPDevice) reprovision(ctx context.Context) {
+	l.deviceMu.RLock()
+	enabled := l.reprovisionSpecs
+	l.deviceMu.RUnlock()
+
+	if !enabled {
+		return
+	}
+
+	roSpecs, accessSpecs := l.specs.snapshot()
+	rebooted, err := l.rebooted(ctx, roSpecs, accessSpecs)
+	if err != nil {
+		l.lc.Error("Failed to check whether the Reader lost its specs.", "device", l.name, "error", err.Error())
+		return
+	}
+	if !rebooted {
+		return
+	}
+
+	l.lc.Info("Reader lost its specs, probably due to a reboot; reprovisioning it.",
+		"device", l.name, "roSpecs", strconv.Itoa(len(roSpecs)), "accessSpecs", strconv.Itoa(len(accessSpecs)))
+
+	event := reprovisionEvent{ROSpecIDs: []uint32{}, AccessSpecIDs: []uint32{}}
+	send := func(request llrp.Outgoing, reply llrp.Incoming) bool {
+		if err := l.TrySend(ctx, request, reply); err != nil {
+			event.Errors = append(event.Errors,
+				strings.TrimPrefix(request.Type().String(), "Msg")+": "+err.Error())
+			return false
+		}
+		return true
+	}
+
+	// FastID ROSpecs need Impinj's extensions, which don't survive a reboot, either.
+	for _, ros := range roSpecs {
+		if usesImpinjExtensions(&ros.spec) {
+			if err := l.enableImpinjExtensions(ctx); err != nil {
+				event.Errors = append(event.Errors, err.Error())
+			}
+			break
+		}
+	}
+
+	// AccessSpecs refer to ROSpecs, so ROSpecs are added first,
+	// and ROSpecs are enabled last so their AccessSpecs are ready when they run.
+	var addedRO []trackedROSpec
+	for _, ros := range roSpecs {
+		if send(ros.spec.Add(), &llrp.AddROSpecResponse{}) {
+			addedRO = append(addedRO, ros)
+			event.ROSpecIDs = append(event.ROSpecIDs, ros.spec.ROSpecID)
+		}
+	}
+
+	for _, as := range accessSpecs {
+		if !send(&llrp.AddAccessSpec{AccessSpec: as.spec}, &llrp.AddAccessSpecResponse{}) {
+			continue
+		}
+		event.AccessSpecIDs = append(event.AccessSpecIDs, as.spec.AccessSpecID)
+
+		if as.enabled {
+			send(&llrp.EnableAccessSpec{AccessSpecID: as.spec.AccessSpecID}, &llrp.EnableAccessSpecResponse{})
+		}
+	}
+
+	for _, ros := range addedRO {
+		if !ros.enabled {
+			continue
+		}
+		if !send(&llrp.EnableROSpec{ROSpecID: ros.spec.ROSpecID}, &llrp.EnableROSpecResponse{}) {
+			continue
+		}
+		if ros.started {
+			send(&llrp.StartROSpec{ROSpecID: ros.spec.ROSpecID}, &llrp.StartROSpecResponse{})
+		}
+	}
+
+	if len(event.Errors) != 0 {
+		l.lc.Error("Failed to fully reprovision Reader.", "device", l.name,
+			"errors", strconv.Itoa(len(event.Errors)), "firstError", event.Errors[0])
+	}
+
+	ns := l.clock().Now().UnixNano()
+	l.goSend(func() { l.sendEdgeXEvent(ResourceReaderReprovisioned, ns, event) })
+}
@@ -0,0 +1,214 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetReprovision(t *testing.T) {
+	for _, testCase := range []struct {
+		reprovision string
+		expected    bool
+		err         bool
+	}{
+		{reprovision: "", expected: false},
+		{reprovision: "true", expected: true},
+		{reprovision: "false", expected: false},
+		{reprovision: "always", err: true},
+	} {
+		protocols := protocolMap{}
+		if testCase.reprovision != "" {
+			protocols[ProtocolCommands] = contract.ProtocolProperties{"reprovision": testCase.reprovision}
+		}
+
+		reprovision, err := getReprovision(protocols)
+		if (err != nil) != testCase.err || reprovision != testCase.expected {
+			t.Errorf("reprovision %q: expected %v (error: %v); got %v, %v",
+				testCase.reprovision, testCase.expected, testCase.err, reprovision, err)
+		}
+	}
+}
+
+func TestSpecTracker(t *testing.T) {
+	st := specTracker{}
+	for _, msg := range []llrp.Outgoing{
+		&llrp.AddROSpec{ROSpec: llrp.ROSpec{ROSpecID: 2}},
+		&llrp.AddROSpec{ROSpec: llrp.ROSpec{ROSpecID: 1}},
+		&llrp.AddROSpec{ROSpec: llrp.ROSpec{ROSpecID: 3}},
+		&llrp.AddROSpec{ROSpec: llrp.ROSpec{ROSpecID: rfSurveyROSpecID}},
+		&llrp.EnableROSpec{ROSpecID: 0},
+		&llrp.StartROSpec{ROSpecID: 1},
+		&llrp.StartROSpec{ROSpecID: 2},
+		&llrp.StopROSpec{ROSpecID: 2},
+		&llrp.DeleteROSpec{ROSpecID: 3},
+
+		&llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{AccessSpecID: 10, ROSpecID: 1}},
+		&llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{AccessSpecID: 11, ROSpecID: 1}},
+		&llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{AccessSpecID: firstReadbackID}},
+		&llrp.EnableAccessSpec{AccessSpecID: 10},
+	} {
+		st.record(msg)
+	}
+
+	roSpecs, accessSpecs := st.snapshot()
+
+	expectedRO := []trackedROSpec{
+		{spec: llrp.ROSpec{ROSpecID: 1}, enabled: true, started: true},
+		{spec: llrp.ROSpec{ROSpecID: 2}, enabled: true, stopping: true},
+	}
+	if !reflect.DeepEqual(roSpecs, expectedRO) {
+		t.Errorf("expected ROSpecs %+v; got %+v", expectedRO, roSpecs)
+	}
+
+	expectedAccess := []trackedAccessSpec{
+		{spec: llrp.AccessSpec{AccessSpecID: 10, ROSpecID: 1}, enabled: true},
+		{spec: llrp.AccessSpec{AccessSpecID: 11, ROSpecID: 1}},
+	}
+	if !reflect.DeepEqual(accessSpecs, expectedAccess) {
+		t.Errorf("expected AccessSpecs %+v; got %+v", expectedAccess, accessSpecs)
+	}
+
+	// Resetting the Reader forgets everything.
+	st.record(&llrp.SetReaderConfig{ResetToFactoryDefaults: true})
+	if roSpecs, accessSpecs := st.snapshot(); len(roSpecs) != 0 || len(accessSpecs) != 0 {
+		t.Errorf("expected no specs after a reset; got %+v, %+v", roSpecs, accessSpecs)
+	}
+}
+
+func TestLLRPDevice_reprovision(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader records the messages it receives.
+	var mu sync.Mutex
+	var received []llrp.MessageType
+	var onReader []llrp.ROSpec // the ROSpecs GetROSpecs reports
+	respond := func(mt llrp.MessageType, resp llrp.Outgoing) {
+		rfid.SetResponseFunc(mt, func(msg llrp.Message) llrp.Outgoing {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, mt)
+			if mt == llrp.MsgGetROSpecs {
+				return &llrp.GetROSpecsResponse{ROSpecs: onReader}
+			}
+			return resp
+		})
+	}
+
+	respond(llrp.MsgGetROSpecs, nil)
+	respond(llrp.MsgAddROSpec, &llrp.AddROSpecResponse{})
+	respond(llrp.MsgEnableROSpec, &llrp.EnableROSpecResponse{})
+	respond(llrp.MsgStartROSpec, &llrp.StartROSpecResponse{})
+	respond(llrp.MsgAddAccessSpec, &llrp.AddAccessSpecResponse{})
+	respond(llrp.MsgEnableAccessSpec, &llrp.EnableAccessSpecResponse{})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	ch := make(chan *dsModels.AsyncValues, 1)
+	dev := &LLRPDevice{
+		name:   "localReader",
+		client: c,
+		lc:     edgexCompatTestLogger{t},
+		ch:     ch,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, msg := range []struct {
+		req  llrp.Outgoing
+		resp llrp.Incoming
+	}{
+		{&llrp.AddROSpec{ROSpec: llrp.ROSpec{ROSpecID: 1}}, &llrp.AddROSpecResponse{}},
+		{&llrp.AddAccessSpec{AccessSpec: llrp.AccessSpec{AccessSpecID: 10, ROSpecID: 1}}, &llrp.AddAccessSpecResponse{}},
+		{&llrp.EnableAccessSpec{AccessSpecID: 10}, &llrp.EnableAccessSpecResponse{}},
+		{&llrp.EnableROSpec{ROSpecID: 1}, &llrp.EnableROSpecResponse{}},
+		{&llrp.StartROSpec{ROSpecID: 1}, &llrp.StartROSpecResponse{}},
+	} {
+		if err := dev.TrySend(ctx, msg.req, msg.resp); err != nil {
+			t.Fatalf("%+v", err)
+		}
+	}
+
+	// reprovisionAndCheck runs reprovision and checks the messages the Reader received.
+	reprovisionAndCheck := func(expected ...llrp.MessageType) {
+		t.Helper()
+		mu.Lock()
+		received = nil
+		mu.Unlock()
+
+		dev.reprovision(ctx)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(received, expected) {
+			t.Errorf("expected the Reader to receive %v; got %v", expected, received)
+		}
+	}
+
+	// Without the option, the service doesn't check.
+	reprovisionAndCheck()
+
+	dev.reprovisionSpecs = true
+
+	// The Reader still has its specs, so it didn't reboot.
+	mu.Lock()
+	onReader = []llrp.ROSpec{{ROSpecID: 1}}
+	mu.Unlock()
+	reprovisionAndCheck(llrp.MsgGetROSpecs)
+
+	select {
+	case av := <-ch:
+		t.Fatalf("expected no event; got %+v", av)
+	default:
+	}
+
+	// After a reboot, the Reader has no specs, so they're restored in order.
+	mu.Lock()
+	onReader = nil
+	mu.Unlock()
+	reprovisionAndCheck(
+		llrp.MsgGetROSpecs,
+		llrp.MsgAddROSpec,
+		llrp.MsgAddAccessSpec,
+		llrp.MsgEnableAccessSpec,
+		llrp.MsgEnableROSpec,
+		llrp.MsgStartROSpec,
+	)
+
+	var av *dsModels.AsyncValues
+	select {
+	case av = <-ch:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a %s event", ResourceReaderReprovisioned)
+	}
+
+	if len(av.CommandValues) != 1 || av.CommandValues[0].DeviceResourceName != ResourceReaderReprovisioned {
+		t.Fatalf("expected a %s event; got %+v", ResourceReaderReprovisioned, av)
+	}
+
+	event := reprovisionEvent{}
+	if err := json.Unmarshal([]byte(av.CommandValues[0].ValueToString()), &event); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := reprovisionEvent{ROSpecIDs: []uint32{1}, AccessSpecIDs: []uint32{10}}
+	if !reflect.DeepEqual(event, expected) {
+		t.Errorf("expected %+v; got %+v", expected, event)
+	}
+}
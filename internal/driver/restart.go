@@ -0,0 +1,285 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"net"
+	"sync"
+)
+
+const (
+	// ResourceConnectionRestart is sent as an event by each device the Driver keeps
+	// when it's re-initialized without being stopped or its device settings change,
+	// saying whether the device kept its connection to the Reader or was rebuilt with a new one.
+	ResourceConnectionRestart = "ConnectionRestart"
+
+	// What a re-initialization did with a device's connection.
+	RestartPreserved = "Preserved" // the device kept its connection
+	RestartRebuilt   = "Rebuilt"   // the device was replaced with a new one
+
+	// Why a re-initialization rebuilt a device.
+	RebuildAddressChanged  = "AddressChanged"  // the device's address changed
+	RebuildSettingsChanged = "SettingsChanged" // the Driver settings the device uses changed
+	RebuildNotConnected    = "NotConnected"    // the device wasn't connected to its Reader
+)
+
+// connectionRestart is the value of ResourceConnectionRestart events.
+type connectionRestart struct {
+	Action string
+	Reason string `json:",omitempty"` // why the device was rebuilt
+}
+
+// deviceParams are the Driver's settings an LLRPDevice captures when it's created,
+// so a device must be rebuilt to use new values of any of them.
+type deviceParams struct {
+	IdleTimeoutMinutes            int
+	KeepAliveSeconds              int
+	ReportCacheSize               int
+	TagCountWindowSeconds         int
+	ReportEncoding                string
+	AbsentFields                  string
+	ReadingOrigin                 string
+	CircuitBreakerFailures        int
+	CircuitBreakerCooldownSeconds int
+	VersionMismatch               string
+	MaxConcurrentCommands         int
+	CommandOverflow               string
+	MaxDiscardKiB                 int
+	MessageIDStart                uint32
+	AsyncOverflow                 string
+	AsyncQueueSize                int
+	ConnectSequence               string
+
+	// Devices share the report sink and the queue budget, which use these.
+	shared sharedParams
+}
+
+// sharedParams are the settings of the resources all devices share.
+type sharedParams struct {
+	AsyncQueueBudgetKiB      int
+	AsyncQueueBudgetOverflow string
+	ReportSink               string
+	ReportSinkAddress        string
+	ReportSinkTopic          string
+	ReportSinkClientID       string
+	ReportSinkUsername       string
+	ReportSinkPassword       string
+	ReportSinkCAFile         string
+}
+
+// newDeviceParams returns the device settings in the config,
+// or the zero value if it's nil.
+func newDeviceParams(config *driverConfiguration) deviceParams {
+	if config == nil {
+		return deviceParams{}
+	}
+
+	return deviceParams{
+		IdleTimeoutMinutes:            config.IdleTimeoutMinutes,
+		KeepAliveSeconds:              config.KeepAliveSeconds,
+		ReportCacheSize:               config.ReportCacheSize,
+		TagCountWindowSeconds:         config.TagCountWindowSeconds,
+		ReportEncoding:                config.ReportEncoding,
+		AbsentFields:                  config.AbsentFields,
+		ReadingOrigin:                 config.ReadingOrigin,
+		CircuitBreakerFailures:        config.CircuitBreakerFailures,
+		CircuitBreakerCooldownSeconds: config.CircuitBreakerCooldownSeconds,
+		VersionMismatch:               config.VersionMismatch,
+		MaxConcurrentCommands:         config.MaxConcurrentCommands,
+		CommandOverflow:               config.CommandOverflow,
+		MaxDiscardKiB:                 config.MaxDiscardKiB,
+		MessageIDStart:                config.MessageIDStart,
+		AsyncOverflow:                 config.AsyncOverflow,
+		AsyncQueueSize:                config.AsyncQueueSize,
+		ConnectSequence:               config.ConnectSequence,
+		shared: sharedParams{
+			AsyncQueueBudgetKiB:      config.AsyncQueueBudgetKiB,
+			AsyncQueueBudgetOverflow: config.AsyncQueueBudgetOverflow,
+			ReportSink:               config.ReportSink,
+			ReportSinkAddress:        config.ReportSinkAddress,
+			ReportSinkTopic:          config.ReportSinkTopic,
+			ReportSinkClientID:       config.ReportSinkClientID,
+			ReportSinkUsername:       config.ReportSinkUsername,
+			ReportSinkPassword:       config.ReportSinkPassword,
+			ReportSinkCAFile:         config.ReportSinkCAFile,
+		},
+	}
+}
+
+// rebuildReason returns why the device must be rebuilt to use the address, settings, and channel,
+// or an empty string if it can keep its connection.
+//
+// Devices that aren't connected are rebuilt, too, so they start reconnecting right away,
+// unless they closed their connections because they were idle.
+func (l *LLRPDevice) rebuildReason(addr net.Addr, params deviceParams, ch chan<- *dsModels.AsyncValues) string {
+	l.deviceMu.RLock()
+	defer l.deviceMu.RUnlock()
+
+	switch {
+	case !sameAddr(l.address, addr):
+		return RebuildAddressChanged
+	case l.params != params || l.ch != ch:
+		return RebuildSettingsChanged
+	case !l.connected && !l.idle:
+		return RebuildNotConnected
+	}
+	return ""
+}
+
+// restartDevices brings the Driver's devices in line with its configuration
+// and the devices registered with EdgeX.
+//
+// When the Driver isn't running, e.g. when it's first initialized or after Stop,
+// it creates the sink and queue budget the devices share and a device for each one registered.
+//
+// Otherwise, it keeps each existing device whose address and settings haven't changed
+// and that's connected (or closed its connection because it was idle),
+// so routine re-initializations don't interrupt its reports.
+// It stops and replaces the others, and it stops devices no longer registered.
+// Devices it keeps or replaces send a ResourceConnectionRestart event saying which.
+// If the settings of the shared sink or budget changed, it replaces them,
+// which rebuilds every device to use the new ones.
+func (d *Driver) restartDevices(registered []contract.Device) {
+	d.devicesMu.Lock()
+	defer d.devicesMu.Unlock()
+
+	// Devices from a previous Stop may still be closing their connections.
+	d.stopping.Wait()
+
+	d.configMu.RLock()
+	config := d.config
+	d.configMu.RUnlock()
+	params := newDeviceParams(config)
+
+	oldSink, running := d.sink, d.params != nil
+	if !running || d.params.shared != params.shared {
+		// Devices share the sink, so it's created before them.
+		sink, err := d.newReportSink(config)
+		if err != nil {
+			d.lc.Error("Failed to create report sink; sending reports to EdgeX.", "error", err.Error())
+		}
+		d.sink = sink
+
+		// Devices share the budget for their queues, too.
+		d.queueBudget = newQueueBudget(params.shared.AsyncQueueBudgetKiB, params.shared.AsyncQueueBudgetOverflow)
+	}
+	d.params = &params
+
+	// Decide which devices to keep, then stop the rest,
+	// so they close their connections before their replacements open new ones.
+	type rebuild struct {
+		device *contract.Device
+		reason string // empty for devices that weren't running
+	}
+	var rebuilds []rebuild
+	var stops []*LLRPDevice
+	kept := make(map[string]bool, len(registered))
+	for i := range registered {
+		device := &registered[i] // the Device struct is nearly 1kb, so this avoids copying it
+		kept[device.Name] = true
+
+		dev, ok := d.activeDevices[device.Name]
+		if !ok {
+			rebuilds = append(rebuilds, rebuild{device: device})
+			continue
+		}
+
+		addr, err := getAddr(device.Protocols)
+		if err != nil {
+			// It's logged below, and it's stopped without being replaced.
+			rebuilds = append(rebuilds, rebuild{device: device})
+			stops = append(stops, dev)
+			delete(d.activeDevices, device.Name)
+			continue
+		}
+
+		reason := dev.rebuildReason(addr, params, d.asyncCh)
+		if reason == "" {
+			d.lc.Info("Keeping the Reader connection.", "deviceName", device.Name)
+			d.sendRestartEvent(dev, connectionRestart{Action: RestartPreserved})
+			continue
+		}
+
+		d.lc.Info("Rebuilding the Reader connection.", "deviceName", device.Name, "reason", reason)
+		rebuilds = append(rebuilds, rebuild{device: device, reason: reason})
+		stops = append(stops, dev)
+		delete(d.activeDevices, device.Name)
+	}
+
+	for name, dev := range d.activeDevices {
+		if !kept[name] {
+			d.lc.Info("Stopping connection for device no longer registered.", "deviceName", name)
+			stops = append(stops, dev)
+			delete(d.activeDevices, name)
+		}
+	}
+
+	d.stopReplaced(stops)
+	if oldSink != nil && oldSink != d.sink {
+		// The old sink publishes the stopped devices' final reports before it disconnects.
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		oldSink.close(ctx)
+		cancel()
+	}
+
+	for _, r := range rebuilds {
+		addr, err := getAddr(r.device.Protocols)
+		if err != nil {
+			d.lc.Error("Unsupported protocol mapping.",
+				"error", err,
+				"protocols", fmt.Sprintf("%v", maskProtocols(r.device.Protocols)),
+				"deviceName", r.device.Name)
+			continue
+		}
+
+		d.lc.Info("Creating a new Reader connection.", "deviceName", r.device.Name)
+		dev := d.NewLLRPDevice(r.device.Name, addr, r.device.OperatingState)
+		d.activeDevices[r.device.Name] = dev
+		if r.reason != "" {
+			d.sendRestartEvent(dev, connectionRestart{Action: RestartRebuilt, Reason: r.reason})
+		}
+	}
+}
+
+// stopReplaced stops the devices, giving each the shutdownGrace period
+// to close its connection and send its pending reports to EdgeX.
+func (d *Driver) stopReplaced(devices []*LLRPDevice) {
+	if len(devices) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(len(devices))
+	for _, dev := range devices {
+		go func(dev *LLRPDevice) {
+			defer wg.Done()
+
+			if err := dev.Stop(ctx); err != nil {
+				d.lc.Error("Error attempting client shutdown.", "error", err.Error())
+			}
+			if err := dev.drainReports(ctx); err != nil {
+				d.lc.Warn("Dropping reports not sent to EdgeX before rebuilding the device.",
+					"device", dev.name, "error", err.Error())
+			}
+		}(dev)
+	}
+	wg.Wait()
+}
+
+// sendRestartEvent sends a ResourceConnectionRestart event from the device.
+func (d *Driver) sendRestartEvent(dev *LLRPDevice, restart connectionRestart) {
+	now := d.clock().Now().UnixNano()
+	dev.goSend(func() {
+		dev.sendEdgeXEvent(ResourceConnectionRestart, now, restart)
+	})
+}
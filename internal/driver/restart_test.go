@@ -0,0 +1,158 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"github.com/pkg/errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDriver_restartDevices(t *testing.T) {
+	_, port := startEmulator(t, 0)
+
+	restarts := make(chan connectionRestart, 10)
+	d := newTestDriver(func(av *dsModels.AsyncValues) {
+		for _, cv := range av.CommandValues {
+			if cv.DeviceResourceName != ResourceConnectionRestart {
+				continue
+			}
+			r := connectionRestart{}
+			if err := json.Unmarshal([]byte(cv.ValueToString()), &r); err != nil {
+				t.Errorf("failed to unmarshal %s: %+v", ResourceConnectionRestart, err)
+			}
+			restarts <- r
+		}
+	})
+	d.config = &driverConfiguration{KeepAliveSeconds: 30, ReportEncoding: ReportEncodingJSON}
+
+	const name = "restartingReader"
+	registered := []contract.Device{{
+		Name:           name,
+		OperatingState: contract.Enabled,
+		Protocols: protocolMap{
+			"tcp": {"host": "127.0.0.1", "port": strconv.Itoa(port)},
+		},
+	}}
+	defer d.restartDevices(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// waitForDevice waits for the registered device to connect and returns it.
+	waitForDevice := func() *LLRPDevice {
+		t.Helper()
+		d.devicesMu.RLock()
+		dev := d.activeDevices[name]
+		d.devicesMu.RUnlock()
+		if dev == nil {
+			t.Fatal("expected the device to be active")
+		}
+
+		if err := retry.Quick.RetryWithCtx(ctx, retry.Forever, func(context.Context) (bool, error) {
+			dev.deviceMu.RLock()
+			connected := dev.connected
+			dev.deviceMu.RUnlock()
+			if !connected {
+				return true, errors.New("device isn't connected")
+			}
+			return false, nil
+		}); err != nil {
+			t.Fatalf("device didn't connect: %+v", err)
+		}
+		return dev
+	}
+
+	expectRestart := func(expected connectionRestart) {
+		t.Helper()
+		select {
+		case r := <-restarts:
+			if r != expected {
+				t.Errorf("expected %+v; got %+v", expected, r)
+			}
+		case <-ctx.Done():
+			t.Fatalf("expected a %s event", ResourceConnectionRestart)
+		}
+	}
+
+	// Initially, it creates the device without sending an event.
+	d.restartDevices(registered)
+	first := waitForDevice()
+
+	// Re-initializing keeps the connected device.
+	d.restartDevices(registered)
+	expectRestart(connectionRestart{Action: RestartPreserved})
+	if dev := waitForDevice(); dev != first {
+		t.Error("expected the device to be preserved")
+	}
+
+	// Changing a setting the device uses rebuilds it.
+	d.configMu.Lock()
+	d.config = &driverConfiguration{KeepAliveSeconds: 30, ReportEncoding: ReportEncodingJSON, ReportCacheSize: 10}
+	d.configMu.Unlock()
+	d.restartDevices(registered)
+	expectRestart(connectionRestart{Action: RestartRebuilt, Reason: RebuildSettingsChanged})
+	if dev := waitForDevice(); dev == first {
+		t.Error("expected the device to be rebuilt")
+	}
+	first.clientLock.RLock()
+	stopped := first.cancel == nil
+	first.clientLock.RUnlock()
+	if !stopped {
+		t.Error("expected the replaced device to be stopped")
+	}
+
+	// Devices no longer registered are stopped.
+	d.restartDevices(nil)
+	d.devicesMu.RLock()
+	active := len(d.activeDevices)
+	d.devicesMu.RUnlock()
+	if active != 0 {
+		t.Errorf("expected no active devices; got %d", active)
+	}
+}
+
+func TestLLRPDevice_rebuildReason(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues)
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5084}
+	params := newDeviceParams(&driverConfiguration{KeepAliveSeconds: 30})
+
+	for _, testCase := range []struct {
+		name      string
+		addr      net.Addr
+		params    deviceParams
+		ch        chan<- *dsModels.AsyncValues
+		connected bool
+		idle      bool
+		expected  string
+	}{
+		{name: "unchanged", addr: addr, params: params, ch: ch, connected: true},
+		{name: "idle", addr: addr, params: params, ch: ch, idle: true},
+		{name: "notConnected", addr: addr, params: params, ch: ch, expected: RebuildNotConnected},
+		{name: "address", addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5085}, params: params, ch: ch,
+			connected: true, expected: RebuildAddressChanged},
+		{name: "settings", addr: addr, params: deviceParams{KeepAliveSeconds: 10}, ch: ch,
+			connected: true, expected: RebuildSettingsChanged},
+		{name: "sharedSettings", addr: addr, params: newDeviceParams(&driverConfiguration{
+			KeepAliveSeconds: 30, AsyncQueueBudgetKiB: 1024}), ch: ch,
+			connected: true, expected: RebuildSettingsChanged},
+		{name: "channel", addr: addr, params: params, ch: make(chan *dsModels.AsyncValues),
+			connected: true, expected: RebuildSettingsChanged},
+	} {
+		l := &LLRPDevice{address: addr, params: params, ch: ch,
+			connected: testCase.connected, idle: testCase.idle}
+		if reason := l.rebuildReason(testCase.addr, testCase.params, testCase.ch); reason != testCase.expected {
+			t.Errorf("%s: expected %q; got %q", testCase.name, testCase.expected, reason)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strconv"
+)
+
+// roReportDefault is the Trigger of an roReporting for an ROSpec without an ROReportSpec.
+const roReportDefault = "ReaderDefault"
+
+// describedROSpec is an ROSpec with its state's name and its reporting spelled out.
+type describedROSpec struct {
+	llrp.ROSpec
+	// StateName is the name of the ROSpecCurrentState: Disabled, Inactive, or Active.
+	StateName string
+	Reporting roReporting
+}
+
+// describedROSpecs is a GetROSpecsResponse with its ROSpecs' states and reporting spelled out.
+type describedROSpecs struct {
+	LLRPStatus llrp.LLRPStatus
+	ROSpecs    []describedROSpec
+}
+
+// roReporting describes when an ROSpec reports its tags and what its reports include.
+type roReporting struct {
+	// Trigger is the name of the ROReportSpec's trigger, e.g., NSecondsOrROEnd,
+	// or ReaderDefault if the ROSpec doesn't have an ROReportSpec.
+	Trigger string
+	// N is the ROReportSpec's N: a number of tags, seconds, or milliseconds,
+	// depending on the Trigger; 0 means it only reports at the end of the AISpec or ROSpec.
+	N *uint16 `json:",omitempty"`
+	// Summary says when the ROSpec reports in words, e.g., "every 5 seconds and when the ROSpec ends".
+	Summary string
+	// Fields are the tag fields the TagReportContentSelector enables, e.g., AntennaID.
+	Fields []string `json:",omitempty"`
+}
+
+// roReportTriggerName returns the name of an ROReportTriggerType,
+// or its number if it isn't one LLRP defines.
+func roReportTriggerName(t llrp.ROReportTriggerType) string {
+	switch t {
+	case llrp.None:
+		return "None"
+	case llrp.NTagsOrAIEnd:
+		return "NTagsOrAIEnd"
+	case llrp.NTagsOrROEnd:
+		return "NTagsOrROEnd"
+	case llrp.NSecondsOrAIEnd:
+		return "NSecondsOrAIEnd"
+	case llrp.NSecondsOrROEnd:
+		return "NSecondsOrROEnd"
+	case llrp.NMillisOrAIEnd:
+		return "NMillisOrAIEnd"
+	case llrp.NMillisOrROEnd:
+		return "NMillisOrROEnd"
+	}
+	return "Unknown(" + strconv.Itoa(int(t)) + ")"
+}
+
+// describeROSpecs returns the response with each ROSpec's state and reporting spelled out.
+func describeROSpecs(resp *llrp.GetROSpecsResponse) *describedROSpecs {
+	described := &describedROSpecs{
+		LLRPStatus: resp.LLRPStatus,
+		ROSpecs:    make([]describedROSpec, len(resp.ROSpecs)),
+	}
+
+	for i, ros := range resp.ROSpecs {
+		described.ROSpecs[i] = describedROSpec{
+			ROSpec:    ros,
+			StateName: ros.ROSpecCurrentState.String(),
+			Reporting: describeROReportSpec(ros.ROReportSpec),
+		}
+	}
+	return described
+}
+
+// describeROReportSpec returns when an ROSpec with the ROReportSpec reports and what it includes.
+// A nil ROReportSpec means the ROSpec uses the one in the Reader's ReaderConfig.
+func describeROReportSpec(spec *llrp.ROReportSpec) roReporting {
+	if spec == nil {
+		return roReporting{
+			Trigger: roReportDefault,
+			Summary: "as the ROReportSpec in the Reader's ReaderConfig says",
+		}
+	}
+
+	n := spec.N
+	reporting := roReporting{
+		Trigger: roReportTriggerName(spec.Trigger),
+		N:       &n,
+		Fields:  tagReportFields(spec.TagReportContentSelector),
+	}
+
+	var every, end string
+	switch spec.Trigger {
+	case llrp.None:
+		reporting.N = nil
+		reporting.Summary = "only when requested"
+		return reporting
+	case llrp.NTagsOrAIEnd, llrp.NTagsOrROEnd:
+		every = "tag"
+	case llrp.NSecondsOrAIEnd, llrp.NSecondsOrROEnd:
+		every = "second"
+	case llrp.NMillisOrAIEnd, llrp.NMillisOrROEnd:
+		every = "millisecond"
+	default:
+		reporting.Summary = "unknown trigger"
+		return reporting
+	}
+
+	switch spec.Trigger {
+	case llrp.NTagsOrAIEnd, llrp.NSecondsOrAIEnd, llrp.NMillisOrAIEnd:
+		end = "when each AISpec ends"
+	default:
+		end = "when the ROSpec ends"
+	}
+
+	switch n {
+	case 0:
+		reporting.Summary = end
+	case 1:
+		reporting.Summary = "every " + every + " and " + end
+	default:
+		reporting.Summary = "every " + strconv.Itoa(int(n)) + " " + every + "s and " + end
+	}
+	return reporting
+}
+
+// tagReportFields returns the names of the fields the selector enables.
+func tagReportFields(sel llrp.TagReportContentSelector) []string {
+	var fields []string
+	for _, f := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"ROSpecID", sel.EnableROSpecID},
+		{"SpecIndex", sel.EnableSpecIndex},
+		{"InventoryParamSpecID", sel.EnableInventoryParamSpecID},
+		{"AntennaID", sel.EnableAntennaID},
+		{"ChannelIndex", sel.EnableChannelIndex},
+		{"PeakRSSI", sel.EnablePeakRSSI},
+		{"FirstSeenTimestamp", sel.EnableFirstSeenTimestamp},
+		{"LastSeenTimestamp", sel.EnableLastSeenTimestamp},
+		{"TagSeenCount", sel.EnableTagSeenCount},
+		{"AccessSpecID", sel.EnableAccessSpecID},
+	} {
+		if f.enabled {
+			fields = append(fields, f.name)
+		}
+	}
+
+	if mem := sel.C1G2EPCMemorySelector; mem != nil {
+		if mem.CRCEnabled {
+			fields = append(fields, "C1G2CRC")
+		}
+		if mem.PCBitsEnabled {
+			fields = append(fields, "C1G2PC")
+		}
+		if mem.XPCBitsEnabled {
+			fields = append(fields, "C1G2XPC")
+		}
+	}
+	return fields
+}
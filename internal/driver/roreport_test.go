@@ -0,0 +1,103 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"reflect"
+	"testing"
+)
+
+func TestDescribeROReportSpec(t *testing.T) {
+	for _, testCase := range []struct {
+		name     string
+		spec     *llrp.ROReportSpec
+		expected roReporting
+	}{
+		{name: "readerDefault", expected: roReporting{Trigger: roReportDefault,
+			Summary: "as the ROReportSpec in the Reader's ReaderConfig says"}},
+		{name: "none", spec: &llrp.ROReportSpec{Trigger: llrp.None, N: 3},
+			expected: roReporting{Trigger: "None", Summary: "only when requested"}},
+		{name: "seconds", spec: &llrp.ROReportSpec{Trigger: llrp.NSecondsOrROEnd, N: 5},
+			expected: roReporting{Trigger: "NSecondsOrROEnd", N: uint16Ptr(5),
+				Summary: "every 5 seconds and when the ROSpec ends"}},
+		{name: "tags", spec: &llrp.ROReportSpec{Trigger: llrp.NTagsOrAIEnd, N: 1},
+			expected: roReporting{Trigger: "NTagsOrAIEnd", N: uint16Ptr(1),
+				Summary: "every tag and when each AISpec ends"}},
+		{name: "endOnly", spec: &llrp.ROReportSpec{Trigger: llrp.NMillisOrAIEnd},
+			expected: roReporting{Trigger: "NMillisOrAIEnd", N: uint16Ptr(0),
+				Summary: "when each AISpec ends"}},
+		{name: "unknown", spec: &llrp.ROReportSpec{Trigger: 9, N: 2},
+			expected: roReporting{Trigger: "Unknown(9)", N: uint16Ptr(2), Summary: "unknown trigger"}},
+		{name: "fields", spec: &llrp.ROReportSpec{Trigger: llrp.NTagsOrROEnd,
+			TagReportContentSelector: llrp.TagReportContentSelector{
+				EnableAntennaID:       true,
+				EnablePeakRSSI:        true,
+				C1G2EPCMemorySelector: &llrp.C1G2EPCMemorySelector{PCBitsEnabled: true},
+			}},
+			expected: roReporting{Trigger: "NTagsOrROEnd", N: uint16Ptr(0), Summary: "when the ROSpec ends",
+				Fields: []string{"AntennaID", "PeakRSSI", "C1G2PC"}}},
+	} {
+		if got := describeROReportSpec(testCase.spec); !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("%s: expected %+v; got %+v", testCase.name, testCase.expected, got)
+		}
+	}
+}
+
+func TestDescribeROSpecs(t *testing.T) {
+	described := describeROSpecs(&llrp.GetROSpecsResponse{ROSpecs: []llrp.ROSpec{
+		{ROSpecID: 1},
+		{ROSpecID: 2, ROSpecCurrentState: llrp.ROSpecStateActive,
+			ROReportSpec: &llrp.ROReportSpec{Trigger: llrp.NSecondsOrAIEnd, N: 10}},
+		{ROSpecID: 3, ROSpecCurrentState: 7},
+	}})
+
+	data, err := json.Marshal(described)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// The described specs still unmarshal as a GetROSpecsResponse.
+	resp := llrp.GetROSpecsResponse{}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(resp.ROSpecs) != 3 || resp.ROSpecs[0].ROReportSpec != nil ||
+		resp.ROSpecs[1].ROReportSpec == nil || resp.ROSpecs[1].ROReportSpec.N != 10 {
+		t.Errorf("expected the original ROSpecs; got %+v", resp.ROSpecs)
+	}
+
+	// States are both numbers and names.
+	var states struct {
+		ROSpecs []struct {
+			ROSpecCurrentState int
+			StateName          string
+		}
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	for i, expected := range []struct {
+		state int
+		name  string
+	}{{0, "Disabled"}, {2, "Active"}, {7, "ROSpecCurrentStateType(7)"}} {
+		if s := states.ROSpecs[i]; s.ROSpecCurrentState != expected.state || s.StateName != expected.name {
+			t.Errorf("ROSpec %d: expected state %d (%s); got %+v", i+1, expected.state, expected.name, s)
+		}
+	}
+
+	if r := described.ROSpecs[0].Reporting; r.Trigger != roReportDefault {
+		t.Errorf("expected the Reader's default reporting; got %+v", r)
+	}
+	if r := described.ROSpecs[1].Reporting; r.Summary != "every 10 seconds and when each AISpec ends" {
+		t.Errorf("expected periodic reporting; got %+v", r)
+	}
+}
+
+func uint16Ptr(n uint16) *uint16 {
+	return &n
+}
@@ -0,0 +1,152 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+)
+
+// SelfTestReport is the result of an LLRPDevice's SelfTest.
+type SelfTestReport struct {
+	Passed        bool  // true if every check passed
+	ElapsedMillis int64 // how long all the checks took
+	Checks        []SelfTestCheck
+}
+
+// SelfTestCheck is the result of one of a SelfTest's checks.
+//
+// A check fails if the service can't communicate with the Reader,
+// if the Reader replies with an error, or if the service can't decode its reply.
+// Its Error says which, to help distinguish Reader issues from network issues.
+type SelfTestCheck struct {
+	Name          string
+	Passed        bool
+	ElapsedMillis int64
+	Details       string   `json:",omitempty"` // a summary of the Reader's reply
+	Warnings      []string `json:",omitempty"` // problems that don't fail the check
+	Error         string   `json:",omitempty"` // why the check failed
+}
+
+// SelfTest checks that the service can communicate with the Reader
+// and round-trip its replies to a few safe queries:
+// its supported LLRP versions, its capabilities, and its configuration.
+// For each, it decodes the Reader's reply, then re-encodes it,
+// and warns if the result differs from what the Reader sent,
+// since that suggests some of the reply wasn't decoded correctly.
+// It also reports the Reader's ClockSkew.
+//
+// SelfTest doesn't change the Reader's state.
+func (l *LLRPDevice) SelfTest(ctx context.Context) SelfTestReport {
+	start := l.clock().Now()
+	report := SelfTestReport{Checks: []SelfTestCheck{
+		l.selfTestCheck(ctx, "Version", &llrp.GetSupportedVersion{}, func(resp llrp.Incoming) string {
+			sv := resp.(*llrp.GetSupportedVersionResponse)
+			return fmt.Sprintf("current version %v; max supported version %v",
+				sv.CurrentVersion, sv.MaxSupportedVersion)
+		}),
+		l.selfTestCheck(ctx, "Capabilities", &llrp.GetReaderCapabilities{}, func(resp llrp.Incoming) string {
+			gdc := resp.(*llrp.GetReaderCapabilitiesResponse).GeneralDeviceCapabilities
+			if gdc == nil {
+				return "no general device capabilities"
+			}
+			return fmt.Sprintf("vendor %v; model %d; firmware %q; %d antennas",
+				VendorIDType(gdc.DeviceManufacturer), gdc.Model, gdc.FirmwareVersion, gdc.MaxSupportedAntennas)
+		}),
+		l.selfTestCheck(ctx, "Config", &llrp.GetReaderConfig{}, func(resp llrp.Incoming) string {
+			id := resp.(*llrp.GetReaderConfigResponse).Identification
+			if id == nil {
+				return "no identification"
+			}
+			return "reader ID " + id.String()
+		}),
+		l.clockSelfTestCheck(),
+	}}
+
+	report.Passed = true
+	for _, check := range report.Checks {
+		report.Passed = report.Passed && check.Passed
+	}
+	report.ElapsedMillis = l.clock().Now().Sub(start).Milliseconds()
+	return report
+}
+
+// selfTestCheck sends the request, checks and round-trips the Reader's reply,
+// and returns the result, using details to summarize a successful reply.
+func (l *LLRPDevice) selfTestCheck(ctx context.Context, name string, request llrp.Outgoing,
+	details func(resp llrp.Incoming) string) SelfTestCheck {
+	check := SelfTestCheck{Name: name}
+	start := l.clock().Now()
+
+	fail := func(err error, msg string) SelfTestCheck {
+		check.Error = errors.WithMessage(err, msg).Error()
+		check.ElapsedMillis = l.clock().Now().Sub(start).Milliseconds()
+		return check
+	}
+
+	data, err := request.MarshalBinary()
+	if err != nil {
+		return fail(err, "unable to encode request")
+	}
+
+	respType, respData, err := l.TrySendRaw(ctx, request.Type(), data)
+	if err != nil {
+		return fail(err, "unable to communicate with the Reader")
+	}
+
+	if respType == llrp.MsgErrorMessage {
+		errMsg := &llrp.ErrorMessage{}
+		if err := errMsg.UnmarshalBinary(respData); err != nil {
+			return fail(err, "unable to decode ErrorMessage")
+		}
+
+		// Readers that only support LLRP 1.0.1 reject version queries.
+		if request.Type() == llrp.MsgGetSupportedVersion &&
+			errMsg.LLRPStatus.Status == llrp.StatusMsgVerUnsupported {
+			check.Passed = true
+			check.Details = fmt.Sprintf("max supported version %v", llrp.Version1_0_1)
+			check.ElapsedMillis = l.clock().Now().Sub(start).Milliseconds()
+			return check
+		}
+		return fail(errMsg.LLRPStatus.Err(), "Reader returned an error")
+	}
+
+	expected, _ := request.Type().ResponseType()
+	if respType != expected {
+		return fail(errors.Errorf("expected %v, but got %v", expected, respType),
+			"Reader sent an unexpected reply")
+	}
+
+	resp := respType.NewInstance()
+	if err := resp.UnmarshalBinary(respData); err != nil {
+		return fail(err, fmt.Sprintf("unable to decode %v", respType))
+	}
+
+	if s, ok := resp.(llrp.Statusable); ok {
+		status := s.Status()
+		if err := status.Err(); err != nil {
+			return fail(err, "Reader returned an error")
+		}
+	}
+
+	if reencoded, err := resp.MarshalBinary(); err != nil {
+		check.Warnings = append(check.Warnings,
+			fmt.Sprintf("unable to re-encode %v: %v", respType, err))
+	} else if !bytes.Equal(reencoded, respData) {
+		check.Warnings = append(check.Warnings, fmt.Sprintf(
+			"re-encoding %v yields %d bytes that differ from the Reader's %d bytes; "+
+				"some of its data may not have been decoded correctly",
+			respType, len(reencoded), len(respData)))
+	}
+
+	check.Passed = true
+	check.Details = details(resp)
+	check.ElapsedMillis = l.clock().Now().Sub(start).Milliseconds()
+	return check
+}
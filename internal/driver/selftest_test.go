@@ -0,0 +1,124 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleRead_SelfTest(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgGetReaderCapabilities, &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			DeviceManufacturer:   uint32(Impinj),
+			Model:                uint32(SpeedwayR420),
+			FirmwareVersion:      "5.14.0.240",
+			MaxSupportedAntennas: 4,
+			GPIOCapabilities:     llrp.GPIOCapabilities{NumGPIs: 4, NumGPOs: 4},
+			PerAntennaAirProtocols: []llrp.PerAntennaAirProtocol{{
+				AntennaID:      1,
+				AirProtocolIDs: []llrp.AirProtocolIDType{llrp.AirProtoEPCGlobalClass1Gen2},
+			}},
+		},
+	})
+	rfid.SetResponse(llrp.MsgGetReaderConfig, &llrp.GetReaderConfigResponse{
+		LLRPStatus: llrp.LLRPStatus{
+			Status:           llrp.StatusFieldInvalid,
+			ErrorDescription: "bad config request",
+		},
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	d := newLocalDriver(t, &LLRPDevice{client: c})
+
+	cvs, err := d.HandleReadCommands("localReader", protocolMap{}, []dsModels.CommandRequest{{
+		DeviceResourceName: ResourceSelfTest,
+		Type:               dsModels.String,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cvs) != 1 || cvs[0] == nil {
+		t.Fatalf("expected exactly one command value; got %v", cvs)
+	}
+
+	var report SelfTestReport
+	s, err := cvs[0].StringValue()
+	if err == nil {
+		err = json.Unmarshal([]byte(s), &report)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Passed {
+		t.Error("expected the report to fail when a check fails")
+	}
+	if len(report.Checks) != 4 {
+		t.Fatalf("expected 4 checks; got %+v", report.Checks)
+	}
+
+	for i, name := range []string{"Version", "Capabilities"} {
+		check := report.Checks[i]
+		if check.Name != name || !check.Passed || check.Error != "" || check.Details == "" {
+			t.Errorf("expected the %s check to pass; got %+v", name, check)
+		}
+		if len(check.Warnings) != 0 {
+			t.Errorf("expected %s to round-trip; got %v", name, check.Warnings)
+		}
+	}
+
+	if d := report.Checks[1].Details; !strings.Contains(d, "5.14.0.240") {
+		t.Errorf("expected the capability details to include the firmware; got %q", d)
+	}
+
+	if check := report.Checks[2]; check.Passed || !strings.Contains(check.Error, "Reader returned an error") {
+		t.Errorf("expected the Config check to report the Reader's error; got %+v", check)
+	}
+
+	// The Reader never sent a notification, so its clock can't be compared.
+	if check := report.Checks[3]; check.Name != "Clock" || !check.Passed || len(check.Warnings) != 1 {
+		t.Errorf("expected the Clock check to pass with a warning; got %+v", check)
+	}
+}
+
+func TestLLRPDevice_SelfTest_noClient(t *testing.T) {
+	// The breaker opens after the first check,
+	// so the rest fail fast rather than waiting out their retries.
+	dev := &LLRPDevice{
+		name:    "deadReader",
+		lc:      edgexCompatTestLogger{t},
+		ch:      make(chan *dsModels.AsyncValues, 1),
+		breaker: newCircuitBreaker(1, time.Hour),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	report := dev.SelfTest(ctx)
+	if report.Passed || len(report.Checks) != 4 {
+		t.Fatalf("expected 4 checks; got %+v", report)
+	}
+
+	for _, check := range report.Checks[:3] {
+		if check.Passed || !strings.Contains(check.Error, "unable to communicate with the Reader") {
+			t.Errorf("expected the %s check to fail to communicate; got %+v", check.Name, check)
+		}
+	}
+}
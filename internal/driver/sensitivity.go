@@ -0,0 +1,262 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"sort"
+)
+
+// ResourceReceiverSensitivity reads each antenna's gain and receiver sensitivity,
+// and writing it sets the receiver sensitivity of one or all antennas.
+const ResourceReceiverSensitivity = "ReceiverSensitivity"
+
+// AntennaSensitivity is the gain and receiver sensitivity of one of a Reader's antennas.
+type AntennaSensitivity struct {
+	AntennaID llrp.AntennaID
+	GainDBi   float64 // composite forward gain, including cable loss, in dBi
+	// SensitivityIndex is the antenna's entry in the Reader's receive sensitivity table,
+	// or nil if the Reader didn't include an RFReceiver in its configuration.
+	SensitivityIndex *uint16 `json:",omitempty"`
+	// SensitivityDB is the table entry's sensitivity, in dB relative to the Reader's maximum,
+	// or nil if the index isn't in the Reader's table.
+	SensitivityDB *llrp.Decibel `json:",omitempty"`
+}
+
+// ResourceReceiverSensitivityRange reads the Reader's receive sensitivity table
+// and each antenna's range of it and current setting,
+// so operators can see which values they can write to ResourceReceiverSensitivity.
+const ResourceReceiverSensitivityRange = "ReceiverSensitivityRange"
+
+// SensitivityRange is the receive sensitivity a Reader supports and its antennas' current settings.
+type SensitivityRange struct {
+	// Supported is false if the Reader's capabilities have no receive sensitivity table,
+	// in which case the service rejects writes to ResourceReceiverSensitivity.
+	Supported bool
+	// MaxSensitivityDBm is the Reader's maximum sensitivity, which the table is relative to,
+	// or nil if the Reader didn't report it (it's only reported since LLRP 1.1).
+	MaxSensitivityDBm *llrp.DecibelMilliwatt16  `json:",omitempty"`
+	Table             []SensitivityEntry        // the Reader's table, ordered by Index
+	Antennas          []AntennaSensitivityRange // ordered by AntennaID
+}
+
+// SensitivityEntry is an entry in a Reader's receive sensitivity table.
+type SensitivityEntry struct {
+	Index         uint16
+	SensitivityDB llrp.Decibel // relative to the Reader's maximum
+	// SensitivityDBm is the absolute sensitivity,
+	// or nil if the Reader didn't report its maximum.
+	SensitivityDBm *int `json:",omitempty"`
+}
+
+// AntennaSensitivityRange is an antenna's current receiver sensitivity
+// and the range of table indexes it supports.
+type AntennaSensitivityRange struct {
+	AntennaSensitivity
+	// SensitivityDBm is the absolute sensitivity of the current setting,
+	// or nil if it or the Reader's maximum isn't known.
+	SensitivityDBm *int `json:",omitempty"`
+	// MinIndex and MaxIndex bound the indexes the antenna supports,
+	// or are nil if the Reader didn't report a range for it, so it supports the whole table.
+	MinIndex *uint16 `json:",omitempty"`
+	MaxIndex *uint16 `json:",omitempty"`
+}
+
+// receiverSensitivity is the JSON request to set receiver sensitivity.
+type receiverSensitivity struct {
+	AntennaID llrp.AntennaID // the antenna to set, or 0 for all of them
+	Index     uint16         // the entry in the Reader's receive sensitivity table
+}
+
+// ReceiverSensitivity returns the gain and receiver sensitivity of each of the Reader's antennas,
+// ordered by AntennaID, using the AntennaProperties and AntennaConfigurations in its ReaderConfig.
+func (l *LLRPDevice) ReceiverSensitivity(ctx context.Context) ([]AntennaSensitivity, error) {
+	props := llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		RequestedData: llrp.ReaderConfReqAntennaProperties,
+	}, &props); err != nil {
+		return nil, errors.WithMessage(err, "failed to get AntennaProperties")
+	}
+
+	confs := llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		RequestedData: llrp.ReaderConfReqAntennaConfig,
+	}, &confs); err != nil {
+		return nil, errors.WithMessage(err, "failed to get AntennaConfigurations")
+	}
+
+	var table []llrp.ReceiveSensitivityTableEntry
+	if caps, err := l.capabilities(ctx); err == nil && caps.GeneralDeviceCapabilities != nil {
+		table = caps.GeneralDeviceCapabilities.ReceiveSensitivities
+	}
+
+	byID := make(map[llrp.AntennaID]*AntennaSensitivity, len(props.AntennaProperties))
+	antennas := make([]AntennaSensitivity, len(props.AntennaProperties))
+	for i, ap := range props.AntennaProperties {
+		antennas[i] = AntennaSensitivity{
+			AntennaID: ap.AntennaID,
+			GainDBi:   float64(ap.AntennaGain) / 100,
+		}
+		byID[ap.AntennaID] = &antennas[i]
+	}
+
+	for _, ac := range confs.AntennaConfigurations {
+		a, ok := byID[ac.AntennaID]
+		if !ok || ac.RFReceiver == nil {
+			continue
+		}
+
+		index := uint16(*ac.RFReceiver)
+		a.SensitivityIndex = &index
+		for j := range table {
+			if table[j].Index == index {
+				a.SensitivityDB = &table[j].ReceiveSensitivity
+				break
+			}
+		}
+	}
+
+	sort.Slice(antennas, func(i, j int) bool { return antennas[i].AntennaID < antennas[j].AntennaID })
+	return antennas, nil
+}
+
+// ReceiverSensitivityRange returns the receive sensitivity table from the Reader's capabilities,
+// with each antenna's range of it and current setting.
+//
+// Readers without a table return an empty one with Supported false,
+// along with whatever their antennas' configurations report.
+func (l *LLRPDevice) ReceiverSensitivityRange(ctx context.Context) (*SensitivityRange, error) {
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get Reader capabilities")
+	}
+
+	antennas, err := l.ReceiverSensitivity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sr := &SensitivityRange{
+		Table:    []SensitivityEntry{},
+		Antennas: make([]AntennaSensitivityRange, len(antennas)),
+	}
+	gen := caps.GeneralDeviceCapabilities
+	if gen == nil {
+		gen = &llrp.GeneralDeviceCapabilities{}
+	}
+	sr.Supported = len(gen.ReceiveSensitivities) != 0
+	if gen.MaximumReceiveSensitivity != nil {
+		maxDBm := llrp.DecibelMilliwatt16(*gen.MaximumReceiveSensitivity)
+		sr.MaxSensitivityDBm = &maxDBm
+	}
+
+	// The table's values are dB below the maximum, so add them to it for dBm.
+	toDBm := func(db llrp.Decibel) *int {
+		if sr.MaxSensitivityDBm == nil {
+			return nil
+		}
+		dBm := int(*sr.MaxSensitivityDBm) + int(db)
+		return &dBm
+	}
+
+	for _, entry := range gen.ReceiveSensitivities {
+		sr.Table = append(sr.Table, SensitivityEntry{
+			Index:          entry.Index,
+			SensitivityDB:  entry.ReceiveSensitivity,
+			SensitivityDBm: toDBm(entry.ReceiveSensitivity),
+		})
+	}
+	sort.Slice(sr.Table, func(i, j int) bool { return sr.Table[i].Index < sr.Table[j].Index })
+
+	ranges := make(map[llrp.AntennaID]llrp.PerAntennaReceiveSensitivityRange,
+		len(gen.PerAntennaReceiveSensitivityRanges))
+	for _, r := range gen.PerAntennaReceiveSensitivityRanges {
+		ranges[r.AntennaID] = r
+	}
+
+	for i, a := range antennas {
+		ar := AntennaSensitivityRange{AntennaSensitivity: a}
+		if a.SensitivityDB != nil {
+			ar.SensitivityDBm = toDBm(*a.SensitivityDB)
+		}
+		if r, ok := ranges[a.AntennaID]; ok {
+			lo, hi := r.ReceiveSensitivityIndexMin, r.ReceiveSensitivityIndexMax
+			ar.MinIndex, ar.MaxIndex = &lo, &hi
+		}
+		sr.Antennas[i] = ar
+	}
+
+	return sr, nil
+}
+
+// SetReceiverSensitivity sets the receiver sensitivity of the antenna,
+// or of all of them if the antennaID is 0, to the entry in the Reader's
+// receive sensitivity table with the given index.
+//
+// It returns an error if the Reader's capabilities don't include a receive sensitivity table,
+// or if the index isn't in the table or the antenna's range.
+func (l *LLRPDevice) SetReceiverSensitivity(ctx context.Context, antennaID llrp.AntennaID, index uint16) error {
+	if caps, err := l.capabilities(ctx); err == nil {
+		if err := checkReceiverSensitivity(caps); err != nil {
+			return err
+		}
+	}
+
+	rx := llrp.RFReceiver(index)
+	set := &llrp.SetReaderConfig{
+		AntennaConfigurations: []llrp.AntennaConfiguration{{AntennaID: antennaID, RFReceiver: &rx}},
+	}
+	if err := l.checkSupported(ctx, set); err != nil {
+		return err
+	}
+
+	return l.TrySend(ctx, set, &llrp.SetReaderConfigResponse{})
+}
+
+// checkReceiverSensitivity returns an error if the capabilities show
+// the Reader has no receive sensitivity table, so its sensitivity can't be set.
+func checkReceiverSensitivity(caps *llrp.GetReaderCapabilitiesResponse) error {
+	if gen := caps.GeneralDeviceCapabilities; gen != nil && len(gen.ReceiveSensitivities) == 0 {
+		return errors.New("Reader does not support setting receiver sensitivity: " +
+			"its capabilities have no receive sensitivity table")
+	}
+	return nil
+}
+
+// checkSensitivityIndex returns an error if the Reader's receive sensitivity table
+// doesn't have the index, or if it's outside the antenna's range.
+// An antennaID of 0 is checked against the range of every antenna.
+// Readers without a table or per-antenna ranges are left to decide for themselves.
+func checkSensitivityIndex(gen *llrp.GeneralDeviceCapabilities, antennaID llrp.AntennaID, index uint16) error {
+	if len(gen.ReceiveSensitivities) != 0 {
+		found := false
+		for _, entry := range gen.ReceiveSensitivities {
+			if entry.Index == index {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("Reader does not support receive sensitivity index %d: "+
+				"it's not in the Reader's receive sensitivity table", index)
+		}
+	}
+
+	for _, r := range gen.PerAntennaReceiveSensitivityRanges {
+		if antennaID != llrp.AllAntennas && r.AntennaID != antennaID {
+			continue
+		}
+		if index < r.ReceiveSensitivityIndexMin || index > r.ReceiveSensitivityIndexMax {
+			return errors.Errorf("Reader does not support receive sensitivity index %d on antenna %d: "+
+				"its range is %d to %d", index, r.AntennaID,
+				r.ReceiveSensitivityIndexMin, r.ReceiveSensitivityIndexMax)
+		}
+	}
+
+	return nil
+}
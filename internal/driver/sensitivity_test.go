@@ -0,0 +1,273 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckSupported_receiverSensitivity(t *testing.T) {
+	caps := &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			MaxSupportedAntennas: 2,
+			ReceiveSensitivities: []llrp.ReceiveSensitivityTableEntry{
+				{Index: 1, ReceiveSensitivity: 0},
+				{Index: 2, ReceiveSensitivity: 10},
+				{Index: 3, ReceiveSensitivity: 20},
+			},
+			PerAntennaReceiveSensitivityRanges: []llrp.PerAntennaReceiveSensitivityRange{
+				{AntennaID: 1, ReceiveSensitivityIndexMin: 1, ReceiveSensitivityIndexMax: 3},
+				{AntennaID: 2, ReceiveSensitivityIndexMin: 1, ReceiveSensitivityIndexMax: 2},
+			},
+		},
+	}
+
+	for _, testCase := range []struct {
+		name        string
+		antenna     llrp.AntennaID
+		index       uint16
+		unsupported string // if set, the error must mention it
+	}{
+		{name: "inRange", antenna: 1, index: 3},
+		{name: "allInRange", antenna: llrp.AllAntennas, index: 2},
+		{name: "notInTable", antenna: 1, index: 4, unsupported: "index 4"},
+		{name: "outsideAntennaRange", antenna: 2, index: 3, unsupported: "antenna 2"},
+		{name: "outsideSomeAntennaRange", antenna: llrp.AllAntennas, index: 3, unsupported: "antenna 2"},
+		{name: "noSuchAntenna", antenna: 3, index: 1, unsupported: "antenna 3"},
+	} {
+		rx := llrp.RFReceiver(testCase.index)
+		err := checkSupported(caps, &llrp.SetReaderConfig{
+			AntennaConfigurations: []llrp.AntennaConfiguration{{AntennaID: testCase.antenna, RFReceiver: &rx}},
+		})
+
+		switch {
+		case testCase.unsupported == "" && err != nil:
+			t.Errorf("%s: expected no error; got %v", testCase.name, err)
+		case testCase.unsupported != "" && (err == nil || !strings.Contains(err.Error(), testCase.unsupported)):
+			t.Errorf("%s: expected an error mentioning %q; got %v", testCase.name, testCase.unsupported, err)
+		}
+	}
+
+	// Without a table or ranges, the Reader decides.
+	rx := llrp.RFReceiver(7)
+	if err := checkSupported(&llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{},
+	}, &llrp.SetReaderConfig{
+		AntennaConfigurations: []llrp.AntennaConfiguration{{AntennaID: 1, RFReceiver: &rx}},
+	}); err != nil {
+		t.Errorf("expected no error without a sensitivity table; got %v", err)
+	}
+}
+
+func TestLLRPDevice_ReceiverSensitivity(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgGetReaderCapabilities, &llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			MaxSupportedAntennas: 2,
+			ReceiveSensitivities: []llrp.ReceiveSensitivityTableEntry{
+				{Index: 1, ReceiveSensitivity: 0},
+				{Index: 2, ReceiveSensitivity: 10},
+			},
+		},
+	})
+
+	rx := llrp.RFReceiver(2)
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		req := &llrp.GetReaderConfig{}
+		if err := msg.UnmarshalTo(req); err != nil {
+			t.Errorf("expected a GetReaderConfig; got %v", err)
+		}
+
+		switch req.RequestedData {
+		case llrp.ReaderConfReqAntennaProperties:
+			return &llrp.GetReaderConfigResponse{AntennaProperties: []llrp.AntennaProperties{
+				{AntennaID: 2, AntennaConnected: true, AntennaGain: 600},
+				{AntennaID: 1, AntennaConnected: true, AntennaGain: 850},
+			}}
+		case llrp.ReaderConfReqAntennaConfig:
+			// Antenna 2 has no RFReceiver.
+			return &llrp.GetReaderConfigResponse{AntennaConfigurations: []llrp.AntennaConfiguration{
+				{AntennaID: 1, RFReceiver: &rx},
+				{AntennaID: 2},
+			}}
+		}
+
+		t.Errorf("unexpected RequestedData %v", req.RequestedData)
+		return &llrp.GetReaderConfigResponse{}
+	})
+
+	// The Reader passes along the AntennaConfigurations of each SetReaderConfig it receives.
+	sets := make(chan []llrp.AntennaConfiguration, 1)
+	rfid.SetResponseFunc(llrp.MsgSetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		conf := &llrp.SetReaderConfig{}
+		if err := msg.UnmarshalTo(conf); err != nil {
+			t.Errorf("expected a SetReaderConfig; got %v", err)
+		}
+		sets <- conf.AntennaConfigurations
+		return &llrp.SetReaderConfigResponse{}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	dev := &LLRPDevice{name: "localReader", client: c, lc: edgexCompatTestLogger{t}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	antennas, err := dev.ReceiverSensitivity(ctx)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	index, db := uint16(2), llrp.Decibel(10)
+	expected := []AntennaSensitivity{
+		{AntennaID: 1, GainDBi: 8.5, SensitivityIndex: &index, SensitivityDB: &db},
+		{AntennaID: 2, GainDBi: 6},
+	}
+	if !reflect.DeepEqual(antennas, expected) {
+		t.Errorf("expected %+v; got %+v", expected, antennas)
+	}
+
+	if err := dev.SetReceiverSensitivity(ctx, 2, 1); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	got := <-sets
+	if len(got) != 1 || got[0].AntennaID != 2 || got[0].RFReceiver == nil || *got[0].RFReceiver != 1 {
+		t.Errorf("expected antenna 2's RFReceiver to be set to 1; got %+v", got)
+	}
+
+	// Indexes that aren't in the table aren't sent.
+	if err := dev.SetReceiverSensitivity(ctx, 1, 3); err == nil {
+		t.Error("expected an error for an index that isn't in the table")
+	}
+	select {
+	case got := <-sets:
+		t.Errorf("expected no SetReaderConfig; got %+v", got)
+	default:
+	}
+}
+
+func TestCheckReceiverSensitivity(t *testing.T) {
+	// LLRP requires Readers to send a table, but the check doesn't rely on it.
+	err := checkReceiverSensitivity(&llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{MaxSupportedAntennas: 2},
+	})
+	if err == nil || !strings.Contains(err.Error(), "receive sensitivity table") {
+		t.Errorf("expected an error about the missing sensitivity table; got %v", err)
+	}
+
+	if err := checkReceiverSensitivity(&llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			ReceiveSensitivities: []llrp.ReceiveSensitivityTableEntry{{Index: 1}},
+		},
+	}); err != nil {
+		t.Errorf("expected no error with a sensitivity table; got %v", err)
+	}
+
+	// If the Reader didn't report its general capabilities, it decides.
+	if err := checkReceiverSensitivity(&llrp.GetReaderCapabilitiesResponse{}); err != nil {
+		t.Errorf("expected no error without general capabilities; got %v", err)
+	}
+}
+
+func TestLLRPDevice_ReceiverSensitivityRange(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rx := llrp.RFReceiver(2)
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		req := &llrp.GetReaderConfig{}
+		if err := msg.UnmarshalTo(req); err != nil {
+			t.Errorf("expected a GetReaderConfig; got %v", err)
+		}
+
+		if req.RequestedData == llrp.ReaderConfReqAntennaProperties {
+			return &llrp.GetReaderConfigResponse{AntennaProperties: []llrp.AntennaProperties{
+				{AntennaID: 2, AntennaConnected: true},
+				{AntennaID: 1, AntennaConnected: true},
+			}}
+		}
+		return &llrp.GetReaderConfigResponse{AntennaConfigurations: []llrp.AntennaConfiguration{
+			{AntennaID: 1, RFReceiver: &rx},
+			{AntennaID: 2},
+		}}
+	})
+
+	go rfid.ImpersonateReader()
+	dev := &LLRPDevice{name: "localReader", client: rfid.ConnectClient(t), lc: edgexCompatTestLogger{t}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	maxDBm := llrp.MaximumReceiveSensitivity(-80)
+	dev.setCapabilities(&llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{
+			MaxSupportedAntennas: 2,
+			ReceiveSensitivities: []llrp.ReceiveSensitivityTableEntry{
+				{Index: 2, ReceiveSensitivity: 10},
+				{Index: 1, ReceiveSensitivity: 0},
+			},
+			PerAntennaReceiveSensitivityRanges: []llrp.PerAntennaReceiveSensitivityRange{
+				{AntennaID: 1, ReceiveSensitivityIndexMin: 1, ReceiveSensitivityIndexMax: 2},
+			},
+			MaximumReceiveSensitivity: &maxDBm,
+		},
+	})
+
+	sr, err := dev.ReceiverSensitivityRange(ctx)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	maxSens, dBm80, dBm70 := llrp.DecibelMilliwatt16(-80), -80, -70
+	index, db, lo, hi := uint16(2), llrp.Decibel(10), uint16(1), uint16(2)
+	expected := &SensitivityRange{
+		Supported:         true,
+		MaxSensitivityDBm: &maxSens,
+		Table: []SensitivityEntry{
+			{Index: 1, SensitivityDB: 0, SensitivityDBm: &dBm80},
+			{Index: 2, SensitivityDB: 10, SensitivityDBm: &dBm70},
+		},
+		Antennas: []AntennaSensitivityRange{
+			{
+				AntennaSensitivity: AntennaSensitivity{AntennaID: 1, SensitivityIndex: &index, SensitivityDB: &db},
+				SensitivityDBm:     &dBm70, MinIndex: &lo, MaxIndex: &hi,
+			},
+			{AntennaSensitivity: AntennaSensitivity{AntennaID: 2}},
+		},
+	}
+	if !reflect.DeepEqual(sr, expected) {
+		t.Errorf("expected %+v; got %+v", expected, sr)
+	}
+
+	// Readers without a table still report their antennas' settings.
+	dev.setCapabilities(&llrp.GetReaderCapabilitiesResponse{
+		GeneralDeviceCapabilities: &llrp.GeneralDeviceCapabilities{MaxSupportedAntennas: 2},
+	})
+	sr, err = dev.ReceiverSensitivityRange(ctx)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if sr.Supported || sr.Table == nil || len(sr.Table) != 0 || sr.MaxSensitivityDBm != nil {
+		t.Errorf("expected an empty, unsupported table; got %+v", sr)
+	}
+	if len(sr.Antennas) != 2 || sr.Antennas[0].SensitivityIndex == nil || *sr.Antennas[0].SensitivityIndex != 2 ||
+		sr.Antennas[0].SensitivityDB != nil || sr.Antennas[0].SensitivityDBm != nil {
+		t.Errorf("expected antenna 1's index without its sensitivity; got %+v", sr.Antennas)
+	}
+}
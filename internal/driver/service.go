@@ -22,6 +22,7 @@ type ServiceWrapper interface {
 	GetProvisionWatcherByName(name string) (contract.ProvisionWatcher, error)
 	AddProvisionWatcher(watcher contract.ProvisionWatcher) (id string, err error)
 	AddDevice(device contract.Device) (id string, err error)
+	RemoveDeviceByName(name string) error
 
 	// Pass-through
 	DriverConfigs() map[string]string
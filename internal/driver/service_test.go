@@ -63,6 +63,14 @@ func (s *MockSDKService) AddDevice(device contract.Device) (id string, err error
 	return device.Id, nil
 }
 
+func (s *MockSDKService) RemoveDeviceByName(name string) error {
+	if _, ok := s.devices[name]; !ok {
+		return fmt.Errorf("device %s was not found", name)
+	}
+	delete(s.devices, name)
+	return nil
+}
+
 func (s *MockSDKService) DriverConfigs() map[string]string {
 	return s.Config
 }
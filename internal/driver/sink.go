@@ -0,0 +1,378 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/retry"
+	"github.com/edgexfoundry/go-mod-core-contracts/clients/logger"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Destinations for decoded ROAccessReports,
+// selected by the ReportSink driver configuration.
+const (
+	ReportSinkEdgeX = "edgex" // send reports to EdgeX as readings
+	ReportSinkMQTT  = "mqtt"  // publish reports as JSON directly to an MQTT broker
+
+	// ReportSinkTopicDevice is replaced in the ReportSinkTopic
+	// by the name of the device that sent the report.
+	ReportSinkTopicDevice = "{device}"
+)
+
+const (
+	reportSinkQueueSize = 1000                   // reports waiting for the sink before it drops new ones
+	mqttKeepAlive       = time.Second * 30       // how often we ping the broker
+	mqttQoS             = 1                      // at least once, so the client resends reports after reconnecting
+	mqttQuiesce         = 250                    // milliseconds to wait for the broker after DISCONNECT
+	mqttTokenPoll       = time.Millisecond * 100 // how often to check on the client's progress
+)
+
+// reportSink publishes reports outside of EdgeX.
+//
+// A reportSink manages its own connection, independent of the Readers':
+// publish never blocks on the sink's destination,
+// and reports published while it's disconnected wait in a queue
+// until it reconnects or the queue fills.
+type reportSink interface {
+	// publish queues a JSON-encoded report from the named device.
+	publish(device string, report []byte)
+	// close publishes the reports in the sink's queue, then disconnects it.
+	// If ctx is done first, it gives up, and the reports it hasn't published are lost.
+	close(ctx context.Context)
+}
+
+// checkReportSink returns an error if sink isn't a known ReportSink.
+func checkReportSink(sink string) error {
+	switch sink {
+	case ReportSinkEdgeX, ReportSinkMQTT:
+		return nil
+	default:
+		return errors.Errorf("unknown report sink %q; sinks are %s or %s",
+			sink, ReportSinkEdgeX, ReportSinkMQTT)
+	}
+}
+
+// newReportSink returns the reportSink selected by the configuration,
+// or nil if reports should be sent to EdgeX.
+func (d *Driver) newReportSink(config *driverConfiguration) (reportSink, error) {
+	if config == nil || config.ReportSink == "" || config.ReportSink == ReportSinkEdgeX {
+		return nil, nil
+	}
+
+	if err := checkReportSink(config.ReportSink); err != nil {
+		return nil, err
+	}
+
+	opts, err := newMQTTOptions(config)
+	if err != nil {
+		return nil, err
+	}
+	return newMQTTSink(d.lc, opts), nil
+}
+
+// mqttOptions are how an mqttSink connects and publishes to its broker.
+type mqttOptions struct {
+	broker    string      // the broker's URL
+	tlsConfig *tls.Config // nil to trust the host's certificate authorities
+	topic     string      // the topic template
+	clientID  string
+	username  string
+	password  string
+}
+
+// newMQTTOptions returns the mqttOptions in the configuration,
+// or an error if they're incomplete or invalid.
+func newMQTTOptions(config *driverConfiguration) (mqttOptions, error) {
+	if config.ReportSinkAddress == "" {
+		return mqttOptions{}, errors.New("the MQTT report sink requires a ReportSinkAddress")
+	}
+	if config.ReportSinkTopic == "" || strings.ContainsAny(config.ReportSinkTopic, "+#") {
+		return mqttOptions{}, errors.Errorf("invalid MQTT report topic %q: "+
+			"it must be non-empty and may not contain wildcards", config.ReportSinkTopic)
+	}
+	if config.ReportSinkPassword != "" && config.ReportSinkUsername == "" {
+		return mqttOptions{}, errors.New("the MQTT report sink requires a ReportSinkUsername " +
+			"to use a ReportSinkPassword")
+	}
+
+	opts := mqttOptions{
+		broker:   config.ReportSinkAddress,
+		topic:    config.ReportSinkTopic,
+		clientID: config.ReportSinkClientID,
+		username: config.ReportSinkUsername,
+		password: config.ReportSinkPassword,
+	}
+
+	hostPort := opts.broker
+	if i := strings.Index(opts.broker, "://"); i >= 0 {
+		switch scheme := opts.broker[:i]; scheme {
+		case "tcp", "tls", "ssl":
+		default:
+			return mqttOptions{}, errors.Errorf("unknown MQTT report sink scheme %q; "+
+				"schemes are tcp, tls, or ssl", scheme)
+		}
+		hostPort = opts.broker[i+3:]
+	} else {
+		opts.broker = "tcp://" + opts.broker
+	}
+
+	if _, _, err := net.SplitHostPort(hostPort); err != nil {
+		return mqttOptions{}, errors.Wrap(err, "invalid ReportSinkAddress")
+	}
+
+	if config.ReportSinkCAFile != "" {
+		pem, err := ioutil.ReadFile(config.ReportSinkCAFile)
+		if err != nil {
+			return mqttOptions{}, errors.Wrap(err, "failed to read ReportSinkCAFile")
+		}
+		opts.tlsConfig = &tls.Config{RootCAs: x509.NewCertPool()}
+		if !opts.tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return mqttOptions{}, errors.Errorf("ReportSinkCAFile %q has no PEM certificates",
+				config.ReportSinkCAFile)
+		}
+	}
+
+	if opts.clientID == "" {
+		suffix := make([]byte, 4)
+		if _, err := rand.Read(suffix); err != nil {
+			return mqttOptions{}, errors.Wrap(err, "failed to generate an MQTT client ID")
+		}
+		opts.clientID = ServiceName + "-" + hex.EncodeToString(suffix)
+	}
+
+	return opts, nil
+}
+
+// sinkReport is a report waiting in an mqttSink's queue.
+type sinkReport struct {
+	topic   string
+	payload []byte
+}
+
+// mqttSink publishes reports to an MQTT broker using the Paho MQTT client.
+//
+// Once it first connects, the client reconnects on its own if the connection is lost,
+// and resends reports the broker hadn't acknowledged.
+// Meanwhile, the sink holds new reports in its queue, so they don't pile up in the client.
+type mqttSink struct {
+	mqttOptions
+	lc logger.LoggingClient
+
+	queue   chan sinkReport
+	closing chan struct{} // closed when the sink should publish what's queued and stop
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	dropMu  sync.Mutex
+	dropped int // reports dropped since the last successful publish
+}
+
+// newMQTTSink returns an mqttSink and starts connecting it to the broker.
+func newMQTTSink(lc logger.LoggingClient, opts mqttOptions) *mqttSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &mqttSink{
+		mqttOptions: opts,
+		lc:          lc,
+		queue:       make(chan sinkReport, reportSinkQueueSize),
+		closing:     make(chan struct{}),
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go s.run(ctx)
+	return s
+}
+
+func (s *mqttSink) publish(device string, report []byte) {
+	select {
+	case s.queue <- sinkReport{topic: strings.ReplaceAll(s.topic, ReportSinkTopicDevice, device), payload: report}:
+		return
+	default:
+	}
+
+	s.dropMu.Lock()
+	s.dropped++
+	first := s.dropped == 1
+	s.dropMu.Unlock()
+
+	if first {
+		s.lc.Warn("MQTT report queue is full; dropping reports until it drains.",
+			"broker", s.broker, "device", device)
+	}
+}
+
+func (s *mqttSink) close(ctx context.Context) {
+	close(s.closing)
+	select {
+	case <-s.done:
+		return
+	case <-ctx.Done():
+	}
+
+	s.cancel()
+	if n := len(s.queue); n > 0 {
+		s.lc.Warn("Closed MQTT report sink before it published every report.",
+			"broker", s.broker, "dropped", n)
+	}
+}
+
+// clientOptions returns the options for the sink's Paho client.
+func (s *mqttSink) clientOptions() *mqtt.ClientOptions {
+	opts := mqtt.NewClientOptions().
+		AddBroker(s.broker).
+		SetClientID(s.clientID).
+		SetUsername(s.username).
+		SetPassword(s.password).
+		SetCleanSession(true).
+		SetKeepAlive(mqttKeepAlive).
+		SetConnectTimeout(dialTimeout).
+		SetWriteTimeout(sendTimeout).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(func(mqtt.Client) {
+			s.lc.Info("Connected to MQTT broker.", "broker", s.broker, "clientID", s.clientID)
+		}).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			s.lc.Warn("Lost connection to MQTT broker; reconnecting.",
+				"broker", s.broker, "error", err.Error())
+		})
+	if s.tlsConfig != nil {
+		opts.SetTLSConfig(s.tlsConfig)
+	}
+	return opts
+}
+
+// run connects to the broker and publishes queued reports until the sink is closed.
+// Once it's closing, it publishes the reports left in the queue, then disconnects.
+func (s *mqttSink) run(ctx context.Context) {
+	defer close(s.done)
+
+	client := mqtt.NewClient(s.clientOptions())
+	if err := s.connect(ctx, client); err != nil {
+		return // the sink was closed
+	}
+	defer client.Disconnect(mqttQuiesce)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case r := <-s.queue:
+			s.publishReport(ctx, client, r)
+		case <-s.closing:
+			for ctx.Err() == nil {
+				select {
+				case r := <-s.queue:
+					s.publishReport(ctx, client, r)
+				default:
+					return
+				}
+			}
+			return
+		}
+	}
+}
+
+// publishReport sends the report and logs the outcome.
+func (s *mqttSink) publishReport(ctx context.Context, client mqtt.Client, r sinkReport) {
+	if err := s.send(ctx, client, r); err != nil {
+		if ctx.Err() == nil {
+			s.lc.Error("Failed to publish report to MQTT broker.",
+				"broker", s.broker, "topic", r.topic, "error", err.Error())
+		}
+		return
+	}
+	s.resumed()
+}
+
+// connect connects the client to the broker, retrying until it succeeds, ctx is canceled,
+// or the sink closes with nothing queued to publish.
+// After that, the client reconnects on its own.
+func (s *mqttSink) connect(ctx context.Context, client mqtt.Client) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-s.closing:
+			if len(s.queue) == 0 {
+				cancel()
+			}
+		case <-ctx.Done():
+		}
+	}()
+
+	return retry.Quick.RetryWithCtx(ctx, retry.Forever, func(ctx context.Context) (bool, error) {
+		token := client.Connect()
+		if !waitToken(ctx, token, dialTimeout) {
+			return true, errors.New("timed out connecting to MQTT broker")
+		}
+		if err := token.Error(); err != nil {
+			s.lc.Debug("Failed to connect to MQTT broker.", "broker", s.broker, "error", err.Error())
+			return true, err
+		}
+		return false, nil
+	})
+}
+
+// errBrokerDisconnected is returned while the client is reconnecting to the broker.
+var errBrokerDisconnected = errors.New("not connected to MQTT broker")
+
+// send waits for the client to be connected, then publishes the report.
+// If the broker doesn't acknowledge it within the sendTimeout,
+// the connection was lost, and the client resends the report once it reconnects.
+func (s *mqttSink) send(ctx context.Context, client mqtt.Client, r sinkReport) error {
+	if err := retry.Quick.RetryWithCtx(ctx, retry.Forever, func(context.Context) (bool, error) {
+		if !client.IsConnectionOpen() {
+			return true, errBrokerDisconnected
+		}
+		return false, nil
+	}); err != nil {
+		return err
+	}
+
+	token := client.Publish(r.topic, mqttQoS, false, r.payload)
+	if !waitToken(ctx, token, sendTimeout) {
+		return ctx.Err()
+	}
+	return token.Error()
+}
+
+// waitToken waits up to the timeout for the token to complete, or until ctx is done,
+// and reports whether it completed.
+//
+// It waits in short intervals because the token's WaitTimeout holds a lock
+// the client needs to complete the token with an error,
+// so a single long wait wouldn't return an error until it timed out.
+func waitToken(ctx context.Context, token mqtt.Token, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for !token.WaitTimeout(mqttTokenPoll) {
+		if ctx.Err() != nil || time.Now().After(deadline) {
+			return false
+		}
+	}
+	return true
+}
+
+// resumed logs the number of reports dropped while the queue was full, if any.
+func (s *mqttSink) resumed() {
+	s.dropMu.Lock()
+	dropped := s.dropped
+	s.dropped = 0
+	s.dropMu.Unlock()
+
+	if dropped > 0 {
+		s.lc.Info("MQTT report queue drained.", "broker", s.broker, "dropped", dropped)
+	}
+}
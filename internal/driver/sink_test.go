@@ -0,0 +1,316 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mqttConnectFields are the fields of a CONNECT packet the sink sets.
+type mqttConnectFields struct {
+	cleanSession                 bool
+	clientID, username, password string
+}
+
+// acceptMQTT accepts a connection to a fake broker and accepts its CONNECT.
+func acceptMQTT(t *testing.T, ln net.Listener) (net.Conn, mqttConnectFields) {
+	t.Helper()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	pkt, err := packets.ReadPacket(conn)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	connect, ok := pkt.(*packets.ConnectPacket)
+	if !ok {
+		t.Fatalf("expected CONNECT; got %v", pkt)
+	}
+	if connect.ProtocolName != "MQTT" || connect.ProtocolVersion != 4 {
+		t.Fatalf("expected an MQTT 3.1.1 CONNECT; got %v", connect)
+	}
+
+	ack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+	ack.ReturnCode = packets.Accepted
+	if err := ack.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+
+	return conn, mqttConnectFields{
+		cleanSession: connect.CleanSession,
+		clientID:     connect.ClientIdentifier,
+		username:     connect.Username,
+		password:     string(connect.Password),
+	}
+}
+
+// expectPublish reads a PUBLISH packet, checks its topic and payload,
+// and acknowledges it.
+func expectPublish(t *testing.T, conn net.Conn, topic, payload string) {
+	t.Helper()
+	for {
+		pkt, err := packets.ReadPacket(conn)
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+		if _, ok := pkt.(*packets.PingreqPacket); ok {
+			continue
+		}
+		pub, ok := pkt.(*packets.PublishPacket)
+		if !ok {
+			t.Fatalf("expected PUBLISH; got %v", pkt)
+		}
+
+		if pub.TopicName != topic {
+			t.Errorf("expected topic %q; got %q", topic, pub.TopicName)
+		}
+		if string(pub.Payload) != payload {
+			t.Errorf("expected payload %q; got %q", payload, pub.Payload)
+		}
+		if pub.Qos != mqttQoS {
+			t.Errorf("expected QoS %d; got %d", mqttQoS, pub.Qos)
+		}
+
+		ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+		ack.MessageID = pub.MessageID
+		if err := ack.Write(conn); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+}
+
+func TestMQTTSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	s := newMQTTSink(edgexCompatTestLogger{t}, mqttOptions{
+		broker:   "tcp://" + ln.Addr().String(),
+		topic:    "llrp/{device}/reports",
+		clientID: "llrp-east",
+		username: "reports",
+		password: "secret",
+	})
+
+	conn, fields := acceptMQTT(t, ln)
+	expected := mqttConnectFields{
+		cleanSession: true,
+		clientID:     "llrp-east",
+		username:     "reports",
+		password:     "secret",
+	}
+	if fields != expected {
+		t.Errorf("expected CONNECT %+v; got %+v", expected, fields)
+	}
+	s.publish("reader1", []byte(`{"first":true}`))
+	expectPublish(t, conn, "llrp/reader1/reports", `{"first":true}`)
+
+	// The sink reconnects on its own when the broker goes away.
+	conn.Close()
+	conn, _ = acceptMQTT(t, ln)
+	defer conn.Close()
+	defer s.close(context.Background())
+
+	s.publish("reader2", []byte(`{"first":false}`))
+	expectPublish(t, conn, "llrp/reader2/reports", `{"first":false}`)
+}
+
+func TestMQTTSink_TLS(t *testing.T) {
+	cert, caFile := testCertificate(t)
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	d := &Driver{lc: edgexCompatTestLogger{t}}
+	sink, err := d.newReportSink(&driverConfiguration{
+		ReportSink:        ReportSinkMQTT,
+		ReportSinkAddress: "tls://" + ln.Addr().String(),
+		ReportSinkTopic:   "llrp/{device}/reports",
+		ReportSinkCAFile:  caFile,
+	})
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// Without a configured client ID, each sink gets its own.
+	conn, fields := acceptMQTT(t, ln)
+	defer conn.Close()
+	defer sink.close(context.Background()) // before the broker hangs up, so the sink doesn't try to reconnect
+	if !strings.HasPrefix(fields.clientID, ServiceName+"-") || fields.clientID == ServiceName+"-" {
+		t.Errorf("expected a client ID with a random suffix; got %q", fields.clientID)
+	}
+	if fields.username != "" || fields.password != "" {
+		t.Errorf("expected a CONNECT without credentials; got %+v", fields)
+	}
+
+	sink.publish("reader1", []byte(`{"secure":true}`))
+	expectPublish(t, conn, "llrp/reader1/reports", `{"secure":true}`)
+}
+
+func TestDriver_Stop_flushesSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	s := newMQTTSink(edgexCompatTestLogger{t}, mqttOptions{
+		broker:   "tcp://" + ln.Addr().String(),
+		topic:    "llrp/{device}/reports",
+		clientID: "llrp-east",
+	})
+	d := newLocalDriver(t)
+	d.done = make(chan struct{})
+	d.sink = s
+
+	// The broker doesn't accept the sink until it's closing,
+	// so these reports are still queued when Stop is called.
+	for i := 0; i < 3; i++ {
+		s.publish("reader1", []byte(`{"report":`+strconv.Itoa(i)+`}`))
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- d.Stop(false) }()
+	<-s.closing
+
+	conn, _ := acceptMQTT(t, ln)
+	defer conn.Close()
+	for i := 0; i < 3; i++ {
+		expectPublish(t, conn, "llrp/reader1/reports", `{"report":`+strconv.Itoa(i)+`}`)
+	}
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Stop to return once the sink published its queue")
+	}
+}
+
+func TestMQTTSink_closeTimeout(t *testing.T) {
+	// Nothing listens at the address, so the sink never connects.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker := "tcp://" + ln.Addr().String()
+	ln.Close()
+
+	// With nothing queued, there's nothing to wait for.
+	s := newMQTTSink(edgexCompatTestLogger{t}, mqttOptions{broker: broker, topic: "reports"})
+	start := time.Now()
+	s.close(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected an idle sink to close immediately; took %v", elapsed)
+	}
+
+	// With reports queued, it waits for the deadline, then drops them.
+	s = newMQTTSink(edgexCompatTestLogger{t}, mqttOptions{broker: broker, topic: "reports"})
+	s.publish("reader1", []byte(`{}`))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	s.close(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected close to give up at its deadline; took %v", elapsed)
+	}
+}
+
+// testCertificate returns a self-signed certificate for 127.0.0.1
+// and the path to a PEM file with it, which is removed when the test ends.
+func testCertificate(t *testing.T) (tls.Certificate, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test broker"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := ioutil.TempFile("", "llrp-test-ca-*.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Remove(f.Name()) })
+	if err := pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, f.Name()
+}
+
+func TestDriver_newReportSink(t *testing.T) {
+	d := &Driver{lc: edgexCompatTestLogger{t}}
+
+	sink, err := d.newReportSink(&driverConfiguration{ReportSink: ReportSinkEdgeX})
+	if err != nil || sink != nil {
+		t.Errorf("expected no sink for %s; got %v, %v", ReportSinkEdgeX, sink, err)
+	}
+
+	for _, cfg := range []driverConfiguration{
+		{ReportSink: "kafka", ReportSinkAddress: "broker:9092", ReportSinkTopic: "reports"},
+		{ReportSink: ReportSinkMQTT, ReportSinkTopic: "reports"},
+		{ReportSink: ReportSinkMQTT, ReportSinkAddress: "broker:1883"},
+		{ReportSink: ReportSinkMQTT, ReportSinkAddress: "broker:1883", ReportSinkTopic: "llrp/+/reports"},
+		{ReportSink: ReportSinkMQTT, ReportSinkAddress: "broker", ReportSinkTopic: "reports"},
+		{ReportSink: ReportSinkMQTT, ReportSinkAddress: "ws://broker:1883", ReportSinkTopic: "reports"},
+		{ReportSink: ReportSinkMQTT, ReportSinkAddress: "broker:1883", ReportSinkTopic: "reports",
+			ReportSinkPassword: "secret"},
+		{ReportSink: ReportSinkMQTT, ReportSinkAddress: "tls://broker:8883", ReportSinkTopic: "reports",
+			ReportSinkCAFile: "/nonexistent/ca.pem"},
+	} {
+		cfg := cfg
+		if sink, err := d.newReportSink(&cfg); err == nil {
+			sink.close(context.Background())
+			t.Errorf("expected an error for %+v", cfg)
+		}
+	}
+}
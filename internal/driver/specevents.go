@@ -0,0 +1,172 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strconv"
+)
+
+const (
+	// ResourceROSpecEvent is sent as an event when a Reader reports
+	// that an ROSpec started, ended, or was preempted by another.
+	ResourceROSpecEvent = "ROSpecEvent"
+
+	// ResourceAISpecEvent is sent as an event when a Reader reports
+	// that an AISpec ended, with the air protocol's singulation details, if present.
+	ResourceAISpecEvent = "AISpecEvent"
+
+	// ResourceROSpecCompleted is sent as an event when a Reader reports that an ROSpec ended,
+	// with the reason it ended, so apps know a scan cycle finished.
+	ResourceROSpecCompleted = "ROSpecCompleted"
+)
+
+// Reasons an ROSpec ended, for ResourceROSpecCompleted events.
+const (
+	// EndedStopped means the service stopped or disabled the ROSpec.
+	EndedStopped = "Stopped"
+	// EndedDuration means the ROSpec's, or each of its AISpecs', duration passed.
+	EndedDuration = "Duration"
+	// EndedGPI means a GPI event, or the timeout waiting for one, ended the ROSpec or its AISpecs.
+	EndedGPI = "GPI"
+	// EndedTagObservation means the ROSpec's AISpecs saw the tags they were waiting for,
+	// or stopped seeing new ones, according to their TagObservationTriggers.
+	EndedTagObservation = "TagObservation"
+	// EndedSpecsDone means the ROSpec's specs ended for different reasons.
+	EndedSpecsDone = "SpecsDone"
+	// EndedUnknown means the service didn't add the ROSpec, so it doesn't know its triggers.
+	EndedUnknown = "Unknown"
+)
+
+// roSpecEvent is the value of ResourceROSpecEvent events.
+type roSpecEvent struct {
+	UTCTimestamp llrp.UTCTimestamp // when the Reader reported the event
+	Event        string            // Started, Ended, or Preempted
+	ROSpecID     uint32
+	// PreemptingROSpecID is the ROSpec that preempted this one, if its Event is Preempted.
+	PreemptingROSpecID uint32 `json:",omitempty"`
+}
+
+// aiSpecEvent is the value of ResourceAISpecEvent events.
+type aiSpecEvent struct {
+	UTCTimestamp       llrp.UTCTimestamp            // when the Reader reported the event
+	Event              string                       // Ended
+	ROSpecID           uint32                       // the ROSpec containing the AISpec
+	SpecIndex          uint16                       // the AISpec's 1-based index in its ROSpec's specs
+	SingulationDetails *llrp.C1G2SingulationDetails `json:",omitempty"`
+}
+
+// roSpecCompleted is the value of ResourceROSpecCompleted events.
+type roSpecCompleted struct {
+	UTCTimestamp llrp.UTCTimestamp // when the Reader reported the event
+	ROSpecID     uint32
+	Reason       string // e.g., Duration or Stopped
+}
+
+// roSpecEndReason returns why the tracked ROSpec ended:
+// because the service stopped it, or because of its stop trigger or,
+// if it doesn't have one, those of its specs.
+func roSpecEndReason(ros trackedROSpec) string {
+	if ros.stopping {
+		return EndedStopped
+	}
+
+	switch ros.spec.ROBoundarySpec.StopTrigger.Trigger {
+	case llrp.ROStopTriggerDuration:
+		return EndedDuration
+	case llrp.ROStopTriggerGPI:
+		return EndedGPI
+	}
+
+	// Without a stop trigger of its own, the ROSpec ends when its specs do.
+	reason := ""
+	for _, ai := range ros.spec.AISpecs {
+		var aiReason string
+		switch ai.StopTrigger.Trigger {
+		case llrp.AIStopTriggerDuration:
+			aiReason = EndedDuration
+		case llrp.AIStopTriggerGPI:
+			aiReason = EndedGPI
+		case llrp.AIStopTriggerTagObservation:
+			aiReason = EndedTagObservation
+		default:
+			return EndedSpecsDone
+		}
+
+		if reason != "" && reason != aiReason {
+			return EndedSpecsDone
+		}
+		reason = aiReason
+	}
+
+	if reason == "" || len(ros.spec.RFSurveySpecs) != 0 {
+		return EndedSpecsDone
+	}
+	return reason
+}
+
+// roSpecEventName returns the name of an ROSpecEvent's type,
+// or its number if it isn't one LLRP defines.
+func roSpecEventName(typ llrp.ROSpecEventType) string {
+	switch typ {
+	case llrp.ROSpecStarted:
+		return "Started"
+	case llrp.ROSpecEnded:
+		return "Ended"
+	case llrp.ROSpecPreempted:
+		return "Preempted"
+	}
+	return "Unknown(" + strconv.Itoa(int(typ)) + ")"
+}
+
+// aiSpecEventName returns the name of an AISpecEvent's type,
+// or its number if it isn't one LLRP defines.
+func aiSpecEventName(typ llrp.AISpecEventType) string {
+	if typ == llrp.AISpecEnded {
+		return "Ended"
+	}
+	return "Unknown(" + strconv.Itoa(int(typ)) + ")"
+}
+
+// sendSpecEvents sends ResourceROSpecEvent and ResourceAISpecEvent events
+// for the ROSpecEvent and AISpecEvent in the notification data, if it has them.
+// These are sent in addition to the ResourceReaderNotification event,
+// so consumers interested only in spec state needn't decode the whole notification.
+func (l *LLRPDevice) sendSpecEvents(ns int64, data *llrp.ReaderEventNotificationData) {
+	if ros := data.ROSpecEvent; ros != nil {
+		event := roSpecEvent{
+			UTCTimestamp: data.UTCTimestamp,
+			Event:        roSpecEventName(ros.Event),
+			ROSpecID:     ros.ROSpecID,
+		}
+		if ros.Event == llrp.ROSpecPreempted {
+			event.PreemptingROSpecID = ros.PreemptingROSpecID
+		}
+		l.sendEdgeXEvent(ResourceROSpecEvent, ns, event)
+
+		if ros.Event == llrp.ROSpecEnded && ros.ROSpecID != rfSurveyROSpecID {
+			reason := EndedUnknown
+			if tracked, ok := l.specs.roSpecEnded(ros.ROSpecID); ok {
+				reason = roSpecEndReason(tracked)
+			}
+			l.sendEdgeXEvent(ResourceROSpecCompleted, ns, roSpecCompleted{
+				UTCTimestamp: data.UTCTimestamp,
+				ROSpecID:     ros.ROSpecID,
+				Reason:       reason,
+			})
+		}
+	}
+
+	if ais := data.AISpecEvent; ais != nil {
+		l.sendEdgeXEvent(ResourceAISpecEvent, ns, aiSpecEvent{
+			UTCTimestamp:       data.UTCTimestamp,
+			Event:              aiSpecEventName(ais.Event),
+			ROSpecID:           ais.ROSpecID,
+			SpecIndex:          ais.SpecIndex,
+			SingulationDetails: ais.SingulationDetails,
+		})
+	}
+}
@@ -0,0 +1,179 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_sendSpecEvents(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 10)
+	l := &LLRPDevice{
+		name: "localReader",
+		lc:   edgexCompatTestLogger{t},
+		ch:   ch,
+		clk:  newFakeClock(),
+	}
+	handler := l.newReaderEventHandler(nil)
+
+	// notify sends the notification data to the handler
+	// and returns the JSON of the event sent for the resource.
+	notify := func(data llrp.ReaderEventNotificationData, resource string) string {
+		t.Helper()
+		payload, err := (&llrp.ReaderEventNotification{ReaderEventNotificationData: data}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := llrp.NewByteMessage(llrp.MsgReaderEventNotification, payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handler.HandleMessage(nil, msg)
+
+		for {
+			select {
+			case av := <-ch:
+				cv := av.CommandValues[0]
+				if cv.DeviceResourceName == resource {
+					return cv.ValueToString()
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("expected a %s event", resource)
+			}
+		}
+	}
+
+	const ts = llrp.UTCTimestamp(1600000000000000)
+
+	l.specs.record(&llrp.AddROSpec{ROSpec: llrp.ROSpec{ROSpecID: 5, ROBoundarySpec: llrp.ROBoundarySpec{
+		StopTrigger: llrp.ROSpecStopTrigger{Trigger: llrp.ROStopTriggerDuration, DurationTriggerValue: 1000}}}})
+	l.specs.record(&llrp.AddROSpec{ROSpec: llrp.ROSpec{ROSpecID: 6}})
+	l.specs.record(&llrp.StartROSpec{ROSpecID: 6})
+	l.specs.record(&llrp.StopROSpec{ROSpecID: 6})
+
+	for _, testCase := range []struct {
+		name     string
+		data     llrp.ReaderEventNotificationData
+		resource string
+		expected string
+	}{
+		{
+			name: "started",
+			data: llrp.ReaderEventNotificationData{UTCTimestamp: ts,
+				ROSpecEvent: &llrp.ROSpecEvent{Event: llrp.ROSpecStarted, ROSpecID: 3}},
+			resource: ResourceROSpecEvent,
+			expected: `{"UTCTimestamp":1600000000000000,"Event":"Started","ROSpecID":3}`,
+		},
+		{
+			name: "preempted",
+			data: llrp.ReaderEventNotificationData{UTCTimestamp: ts,
+				ROSpecEvent: &llrp.ROSpecEvent{Event: llrp.ROSpecPreempted, ROSpecID: 3, PreemptingROSpecID: 7}},
+			resource: ResourceROSpecEvent,
+			expected: `{"UTCTimestamp":1600000000000000,"Event":"Preempted","ROSpecID":3,"PreemptingROSpecID":7}`,
+		},
+		{
+			name: "completedDuration",
+			data: llrp.ReaderEventNotificationData{UTCTimestamp: ts,
+				ROSpecEvent: &llrp.ROSpecEvent{Event: llrp.ROSpecEnded, ROSpecID: 5}},
+			resource: ResourceROSpecCompleted,
+			expected: `{"UTCTimestamp":1600000000000000,"ROSpecID":5,"Reason":"Duration"}`,
+		},
+		{
+			name: "completedStopped",
+			data: llrp.ReaderEventNotificationData{UTCTimestamp: ts,
+				ROSpecEvent: &llrp.ROSpecEvent{Event: llrp.ROSpecEnded, ROSpecID: 6}},
+			resource: ResourceROSpecCompleted,
+			expected: `{"UTCTimestamp":1600000000000000,"ROSpecID":6,"Reason":"Stopped"}`,
+		},
+		{
+			name: "completedUntracked",
+			data: llrp.ReaderEventNotificationData{UTCTimestamp: ts,
+				ROSpecEvent: &llrp.ROSpecEvent{Event: llrp.ROSpecEnded, ROSpecID: 3}},
+			resource: ResourceROSpecCompleted,
+			expected: `{"UTCTimestamp":1600000000000000,"ROSpecID":3,"Reason":"Unknown"}`,
+		},
+		{
+			name: "aiSpecEnded",
+			data: llrp.ReaderEventNotificationData{UTCTimestamp: ts,
+				AISpecEvent: &llrp.AISpecEvent{Event: llrp.AISpecEnded, ROSpecID: 3, SpecIndex: 1}},
+			resource: ResourceAISpecEvent,
+			expected: `{"UTCTimestamp":1600000000000000,"Event":"Ended","ROSpecID":3,"SpecIndex":1}`,
+		},
+		{
+			name: "aiSpecSingulation",
+			data: llrp.ReaderEventNotificationData{UTCTimestamp: ts,
+				AISpecEvent: &llrp.AISpecEvent{Event: llrp.AISpecEnded, ROSpecID: 3, SpecIndex: 2,
+					SingulationDetails: &llrp.C1G2SingulationDetails{NumCollisionSlots: 4, NumEmptySlots: 9}}},
+			resource: ResourceAISpecEvent,
+			expected: `{"UTCTimestamp":1600000000000000,"Event":"Ended","ROSpecID":3,"SpecIndex":2,` +
+				`"SingulationDetails":{"NumCollisionSlots":4,"NumEmptySlots":9}}`,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := notify(testCase.data, testCase.resource)
+
+			// Compare decoded values so the test doesn't depend on field order.
+			var expected, actual map[string]interface{}
+			if err := json.Unmarshal([]byte(testCase.expected), &expected); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(got), &actual); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected %s; got %s", testCase.expected, got)
+			}
+		})
+	}
+}
+
+func TestSpecEventNames(t *testing.T) {
+	if name := roSpecEventName(llrp.ROSpecEventType(9)); name != "Unknown(9)" {
+		t.Errorf("expected Unknown(9); got %s", name)
+	}
+	if name := aiSpecEventName(llrp.AISpecEventType(1)); name != "Unknown(1)" {
+		t.Errorf("expected Unknown(1); got %s", name)
+	}
+}
+
+func TestROSpecEndReason(t *testing.T) {
+	aiSpec := func(trigger llrp.AISpecStopTriggerType) llrp.AISpec {
+		return llrp.AISpec{StopTrigger: llrp.AISpecStopTrigger{Trigger: trigger}}
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		ros      trackedROSpec
+		expected string
+	}{
+		{name: "stopped", expected: EndedStopped, ros: trackedROSpec{stopping: true, spec: llrp.ROSpec{
+			ROBoundarySpec: llrp.ROBoundarySpec{StopTrigger: llrp.ROSpecStopTrigger{Trigger: llrp.ROStopTriggerDuration}}}}},
+		{name: "duration", expected: EndedDuration, ros: trackedROSpec{spec: llrp.ROSpec{
+			ROBoundarySpec: llrp.ROBoundarySpec{StopTrigger: llrp.ROSpecStopTrigger{Trigger: llrp.ROStopTriggerDuration}}}}},
+		{name: "gpi", expected: EndedGPI, ros: trackedROSpec{spec: llrp.ROSpec{
+			ROBoundarySpec: llrp.ROBoundarySpec{StopTrigger: llrp.ROSpecStopTrigger{Trigger: llrp.ROStopTriggerGPI}}}}},
+		{name: "aiSpecDurations", expected: EndedDuration, ros: trackedROSpec{spec: llrp.ROSpec{
+			AISpecs: []llrp.AISpec{aiSpec(llrp.AIStopTriggerDuration), aiSpec(llrp.AIStopTriggerDuration)}}}},
+		{name: "tagObservation", expected: EndedTagObservation, ros: trackedROSpec{spec: llrp.ROSpec{
+			AISpecs: []llrp.AISpec{aiSpec(llrp.AIStopTriggerTagObservation)}}}},
+		{name: "mixed", expected: EndedSpecsDone, ros: trackedROSpec{spec: llrp.ROSpec{
+			AISpecs: []llrp.AISpec{aiSpec(llrp.AIStopTriggerDuration), aiSpec(llrp.AIStopTriggerGPI)}}}},
+		{name: "untriggeredAISpec", expected: EndedSpecsDone, ros: trackedROSpec{spec: llrp.ROSpec{
+			AISpecs: []llrp.AISpec{aiSpec(llrp.AIStopTriggerNone)}}}},
+		{name: "rfSurvey", expected: EndedSpecsDone, ros: trackedROSpec{spec: llrp.ROSpec{
+			AISpecs:       []llrp.AISpec{aiSpec(llrp.AIStopTriggerDuration)},
+			RFSurveySpecs: []llrp.RFSurveySpec{{AntennaID: 1}}}}},
+	} {
+		if reason := roSpecEndReason(testCase.ros); reason != testCase.expected {
+			t.Errorf("%s: expected %s; got %s", testCase.name, testCase.expected, reason)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"math"
+)
+
+// rfSurveyROSpecID is the ROSpecID of the ROSpecs the service creates to run RF surveys.
+// It's at the top of the ID range to make conflicts with users' ROSpecs unlikely.
+const rfSurveyROSpecID = math.MaxUint32
+
+// RunRFSurvey starts an RF survey on the Reader using the given RFSurveySpec.
+//
+// It replaces the ROSpec used for any previous survey with one containing the spec,
+// then enables and starts it. The Reader reports the survey's results
+// in an ROAccessReport when the spec's stop trigger fires,
+// after which they're available from RFSurveyResults.
+// The spec must have a stop trigger, since it'd otherwise run indefinitely.
+func (l *LLRPDevice) RunRFSurvey(ctx context.Context, spec llrp.RFSurveySpec) error {
+	if spec.Trigger.Trigger == llrp.RFSurveyStopTriggerNone {
+		return errors.New("RFSurveySpec must have a Duration or NIteration stop trigger")
+	}
+
+	add := &llrp.AddROSpec{ROSpec: llrp.ROSpec{
+		ROSpecID:           rfSurveyROSpecID,
+		ROSpecCurrentState: llrp.ROSpecStateDisabled,
+		ROBoundarySpec: llrp.ROBoundarySpec{
+			StartTrigger: llrp.ROSpecStartTrigger{Trigger: llrp.ROStartTriggerNone},
+			StopTrigger:  llrp.ROSpecStopTrigger{Trigger: llrp.ROStopTriggerNone},
+		},
+		RFSurveySpecs: []llrp.RFSurveySpec{spec},
+		// Report once, when the survey finishes.
+		ROReportSpec: &llrp.ROReportSpec{Trigger: llrp.NTagsOrROEnd},
+	}}
+
+	if err := l.checkSupported(ctx, add); err != nil {
+		return err
+	}
+
+	// The previous survey's ROSpec usually exists, but it's not an error if it doesn't.
+	if err := l.TrySend(ctx, &llrp.DeleteROSpec{ROSpecID: rfSurveyROSpecID},
+		&llrp.DeleteROSpecResponse{}); err != nil {
+		l.lc.Debug("Failed to delete previous RF survey ROSpec.",
+			"device", l.name, "error", err.Error())
+	}
+
+	if err := l.TrySend(ctx, add, &llrp.AddROSpecResponse{}); err != nil {
+		return errors.WithMessage(err, "failed to add RF survey ROSpec")
+	}
+
+	if err := l.TrySend(ctx, &llrp.EnableROSpec{ROSpecID: rfSurveyROSpecID},
+		&llrp.EnableROSpecResponse{}); err != nil {
+		return errors.WithMessage(err, "failed to enable RF survey ROSpec")
+	}
+
+	if err := l.TrySend(ctx, &llrp.StartROSpec{ROSpecID: rfSurveyROSpecID},
+		&llrp.StartROSpecResponse{}); err != nil {
+		return errors.WithMessage(err, "failed to start RF survey ROSpec")
+	}
+
+	return nil
+}
+
+// RFSurveyResults returns the RFSurveyReportData from the most recent
+// ROAccessReport that had any, or an empty list if there hasn't been one.
+// If the Reader's capabilities show it can't perform RF surveys, it returns an error.
+func (l *LLRPDevice) RFSurveyResults(ctx context.Context) ([]llrp.RFSurveyReportData, error) {
+	if caps, err := l.capabilities(ctx); err == nil {
+		if err := checkRFSurvey(caps); err != nil {
+			return nil, err
+		}
+	}
+
+	l.deviceMu.RLock()
+	defer l.deviceMu.RUnlock()
+	return append([]llrp.RFSurveyReportData{}, l.survey...), nil
+}
+
+// checkRFSurvey returns an error if the capabilities show the Reader can't perform RF surveys.
+func checkRFSurvey(caps *llrp.GetReaderCapabilitiesResponse) error {
+	if lacksCapability(caps, ResourceCanDoRFSurvey) {
+		return errors.New("Reader does not support RFSurveySpecs")
+	}
+	return nil
+}
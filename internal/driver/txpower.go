@@ -0,0 +1,207 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// ResourceTransmitPower reads each antenna's transmit power in dBm,
+// and writing it sets the transmit power of one or all antennas in dBm,
+// using the nearest level in the Reader's transmit power table.
+const ResourceTransmitPower = "TransmitPower"
+
+// errNoPowerTable is returned when setting the transmit power of a Reader
+// whose capabilities have no transmit power table.
+var errNoPowerTable = errors.New("Reader does not support setting transmit power: " +
+	"its capabilities have no transmit power table")
+
+// AntennaTransmitPower is the transmit power of one of a Reader's antennas.
+type AntennaTransmitPower struct {
+	AntennaID  llrp.AntennaID
+	PowerIndex uint16 // the antenna's entry in the Reader's transmit power table
+	// PowerDBm is the entry's power, or nil if the index isn't in the Reader's table.
+	PowerDBm *float64 `json:",omitempty"`
+}
+
+// transmitPower is the JSON request to set transmit power.
+type transmitPower struct {
+	AntennaID llrp.AntennaID // the antenna to set, or 0 for all of them
+	PowerDBm  float64
+}
+
+// TransmitPower returns the transmit power of each of the Reader's antennas
+// that has an RFTransmitter in its AntennaConfiguration, ordered by AntennaID.
+func (l *LLRPDevice) TransmitPower(ctx context.Context) ([]AntennaTransmitPower, error) {
+	confs := llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		RequestedData: llrp.ReaderConfReqAntennaConfig,
+	}, &confs); err != nil {
+		return nil, errors.WithMessage(err, "failed to get AntennaConfigurations")
+	}
+
+	var table []llrp.TransmitPowerLevelTableEntry
+	if caps, err := l.capabilities(ctx); err == nil {
+		table = powerTable(caps)
+	}
+
+	var antennas []AntennaTransmitPower
+	for _, ac := range confs.AntennaConfigurations {
+		if ac.RFTransmitter == nil {
+			continue
+		}
+
+		a := AntennaTransmitPower{AntennaID: ac.AntennaID, PowerIndex: ac.RFTransmitter.TransmitPowerIndex}
+		for _, entry := range table {
+			if entry.Index == a.PowerIndex {
+				dBm := powerDBm(entry)
+				a.PowerDBm = &dBm
+				break
+			}
+		}
+		antennas = append(antennas, a)
+	}
+
+	sort.Slice(antennas, func(i, j int) bool { return antennas[i].AntennaID < antennas[j].AntennaID })
+	return antennas, nil
+}
+
+// SetTransmitPower sets the transmit power of the antenna, or of all of them if the antennaID is 0,
+// to the level in the Reader's transmit power table nearest the given dBm,
+// and returns the level's actual power in dBm.
+//
+// LLRP sets transmit power along with the antenna's frequency hop table and channel,
+// so it keeps the ones in each antenna's current RFTransmitter.
+// It returns an error if the Reader's capabilities aren't available or have no table,
+// if the power is outside the table's range, or if the antenna has no RFTransmitter.
+func (l *LLRPDevice) SetTransmitPower(ctx context.Context, antennaID llrp.AntennaID, dBm float64) (float64, error) {
+	caps, err := l.capabilities(ctx)
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get the Reader's transmit power table")
+	}
+
+	entry, err := nearestPower(powerTable(caps), dBm)
+	if err != nil {
+		return 0, err
+	}
+
+	current := llrp.GetReaderConfigResponse{}
+	if err := l.TrySend(ctx, &llrp.GetReaderConfig{
+		AntennaID:     antennaID,
+		RequestedData: llrp.ReaderConfReqAntennaConfig,
+	}, &current); err != nil {
+		return 0, errors.WithMessage(err, "failed to get AntennaConfigurations")
+	}
+
+	set := &llrp.SetReaderConfig{}
+	for _, ac := range current.AntennaConfigurations {
+		if ac.RFTransmitter == nil || (antennaID != llrp.AllAntennas && ac.AntennaID != antennaID) {
+			continue
+		}
+
+		tx := *ac.RFTransmitter
+		tx.TransmitPowerIndex = entry.Index
+		set.AntennaConfigurations = append(set.AntennaConfigurations,
+			llrp.AntennaConfiguration{AntennaID: ac.AntennaID, RFTransmitter: &tx})
+	}
+
+	if len(set.AntennaConfigurations) == 0 {
+		return 0, errors.Errorf("Reader's configuration has no RFTransmitter for antenna %d, "+
+			"so its frequency hop table and channel are unknown", antennaID)
+	}
+
+	if err := l.checkSupported(ctx, set); err != nil {
+		return 0, err
+	}
+
+	if err := l.TrySend(ctx, set, &llrp.SetReaderConfigResponse{}); err != nil {
+		return 0, err
+	}
+
+	applied := powerDBm(entry)
+	l.lc.Info("Set transmit power.", "device", l.name, "antenna", strconv.Itoa(int(antennaID)),
+		"requestedDBm", strconv.FormatFloat(dBm, 'f', 2, 64),
+		"appliedDBm", strconv.FormatFloat(applied, 'f', 2, 64),
+		"powerIndex", strconv.Itoa(int(entry.Index)))
+	return applied, nil
+}
+
+// powerTable returns the transmit power table from the capabilities, if they have one.
+func powerTable(caps *llrp.GetReaderCapabilitiesResponse) []llrp.TransmitPowerLevelTableEntry {
+	if caps.RegulatoryCapabilities == nil || caps.RegulatoryCapabilities.UHFBandCapabilities == nil {
+		return nil
+	}
+	return caps.RegulatoryCapabilities.UHFBandCapabilities.TransmitPowerLevels
+}
+
+// powerDBm returns the table entry's power in dBm.
+func powerDBm(entry llrp.TransmitPowerLevelTableEntry) float64 {
+	return float64(entry.TransmitPowerValue) / 100
+}
+
+// nearestPower returns the table entry with the power nearest the given dBm,
+// preferring the lower power in a tie.
+// It returns an error if the table is empty or the power is outside its range.
+func nearestPower(table []llrp.TransmitPowerLevelTableEntry, dBm float64) (llrp.TransmitPowerLevelTableEntry, error) {
+	if len(table) == 0 {
+		return llrp.TransmitPowerLevelTableEntry{}, errNoPowerTable
+	}
+
+	min, max := table[0], table[0]
+	for _, entry := range table[1:] {
+		if entry.TransmitPowerValue < min.TransmitPowerValue {
+			min = entry
+		}
+		if entry.TransmitPowerValue > max.TransmitPowerValue {
+			max = entry
+		}
+	}
+
+	if dBm < powerDBm(min) || dBm > powerDBm(max) {
+		return llrp.TransmitPowerLevelTableEntry{}, errors.Errorf("transmit power %.2f dBm is outside "+
+			"the Reader's range of %.2f to %.2f dBm", dBm, powerDBm(min), powerDBm(max))
+	}
+
+	nearest := min
+	for _, entry := range table {
+		diff, best := math.Abs(powerDBm(entry)-dBm), math.Abs(powerDBm(nearest)-dBm)
+		if diff < best || (diff == best && entry.TransmitPowerValue < nearest.TransmitPowerValue) {
+			nearest = entry
+		}
+	}
+	return nearest, nil
+}
+
+// checkTransmitPower returns an error if the capabilities
+// have no transmit power table, so the Reader's power can't be set in dBm.
+func checkTransmitPower(caps *llrp.GetReaderCapabilitiesResponse) error {
+	if len(powerTable(caps)) == 0 {
+		return errNoPowerTable
+	}
+	return nil
+}
+
+// checkPowerIndex returns an error if the Reader's transmit power table
+// doesn't have the index. Readers without a table are left to decide for themselves.
+func checkPowerIndex(caps *llrp.GetReaderCapabilitiesResponse, index uint16) error {
+	table := powerTable(caps)
+	if len(table) == 0 {
+		return nil
+	}
+
+	for _, entry := range table {
+		if entry.Index == index {
+			return nil
+		}
+	}
+	return errors.Errorf("Reader does not support transmit power index %d: "+
+		"it's not in the Reader's transmit power table", index)
+}
@@ -0,0 +1,196 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPowerTable has levels from 10 to 30 dBm in 5 dBm steps, out of order.
+var testPowerTable = []llrp.TransmitPowerLevelTableEntry{
+	{Index: 3, TransmitPowerValue: 2000},
+	{Index: 1, TransmitPowerValue: 1000},
+	{Index: 2, TransmitPowerValue: 1500},
+	{Index: 4, TransmitPowerValue: 2500},
+	{Index: 5, TransmitPowerValue: 3000},
+}
+
+func TestNearestPower(t *testing.T) {
+	for _, testCase := range []struct {
+		dBm      float64
+		expected uint16
+		invalid  bool
+	}{
+		{dBm: 10, expected: 1},
+		{dBm: 30, expected: 5},
+		{dBm: 21, expected: 3},
+		{dBm: 24, expected: 4},
+		{dBm: 22.5, expected: 3}, // ties go to the lower power
+		{dBm: 9.99, invalid: true},
+		{dBm: 30.01, invalid: true},
+	} {
+		entry, err := nearestPower(testPowerTable, testCase.dBm)
+		if testCase.invalid {
+			if err == nil || !strings.Contains(err.Error(), "10.00 to 30.00 dBm") {
+				t.Errorf("%v dBm: expected an error with the table's range; got %v", testCase.dBm, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%v dBm: %v", testCase.dBm, err)
+		} else if entry.Index != testCase.expected {
+			t.Errorf("%v dBm: expected index %d; got %d", testCase.dBm, testCase.expected, entry.Index)
+		}
+	}
+
+	if _, err := nearestPower(nil, 20); err != errNoPowerTable {
+		t.Errorf("expected %v; got %v", errNoPowerTable, err)
+	}
+}
+
+func TestCheckSupported_transmitPower(t *testing.T) {
+	caps := &llrp.GetReaderCapabilitiesResponse{
+		RegulatoryCapabilities: &llrp.RegulatoryCapabilities{
+			UHFBandCapabilities: &llrp.UHFBandCapabilities{TransmitPowerLevels: testPowerTable},
+		},
+	}
+
+	set := func(index uint16) *llrp.SetReaderConfig {
+		return &llrp.SetReaderConfig{AntennaConfigurations: []llrp.AntennaConfiguration{{
+			AntennaID:     1,
+			RFTransmitter: &llrp.RFTransmitter{HopTableID: 1, TransmitPowerIndex: index},
+		}}}
+	}
+
+	if err := checkSupported(caps, set(5)); err != nil {
+		t.Errorf("expected no error for an index in the table; got %v", err)
+	}
+	if err := checkSupported(caps, set(6)); err == nil || !strings.Contains(err.Error(), "index 6") {
+		t.Errorf("expected an error for an index not in the table; got %v", err)
+	}
+	if err := checkSupported(&llrp.GetReaderCapabilitiesResponse{}, set(6)); err != nil {
+		t.Errorf("expected no error without a table; got %v", err)
+	}
+}
+
+func TestLLRPDevice_TransmitPower(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgGetReaderCapabilities, &llrp.GetReaderCapabilitiesResponse{
+		RegulatoryCapabilities: &llrp.RegulatoryCapabilities{
+			UHFBandCapabilities: &llrp.UHFBandCapabilities{
+				TransmitPowerLevels: testPowerTable,
+				FrequencyInformation: llrp.FrequencyInformation{
+					Hopping: true,
+					FrequencyHopTables: []llrp.FrequencyHopTable{
+						{HopTableID: 1, Frequencies: []llrp.Kilohertz{902750}},
+					},
+				},
+				C1G2RFModes: llrp.UHFC1G2RFModeTable{
+					UHFC1G2RFModeTableEntries: []llrp.UHFC1G2RFModeTableEntry{{ModeID: 1}},
+				},
+			},
+		},
+	})
+
+	// Antenna 3 has no RFTransmitter, and antenna 2 uses a different channel.
+	rfid.SetResponseFunc(llrp.MsgGetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		req := &llrp.GetReaderConfig{}
+		if err := msg.UnmarshalTo(req); err != nil {
+			t.Errorf("expected a GetReaderConfig; got %v", err)
+		}
+		if req.RequestedData != llrp.ReaderConfReqAntennaConfig {
+			t.Errorf("expected a request for AntennaConfigurations; got %v", req.RequestedData)
+		}
+
+		all := []llrp.AntennaConfiguration{
+			{AntennaID: 2, RFTransmitter: &llrp.RFTransmitter{HopTableID: 1, ChannelIndex: 2, TransmitPowerIndex: 5}},
+			{AntennaID: 1, RFTransmitter: &llrp.RFTransmitter{HopTableID: 1, ChannelIndex: 1, TransmitPowerIndex: 7}},
+			{AntennaID: 3},
+		}
+
+		resp := &llrp.GetReaderConfigResponse{}
+		for _, ac := range all {
+			if req.AntennaID == llrp.AllAntennas || ac.AntennaID == req.AntennaID {
+				resp.AntennaConfigurations = append(resp.AntennaConfigurations, ac)
+			}
+		}
+		return resp
+	})
+
+	// The Reader passes along the AntennaConfigurations of each SetReaderConfig it receives.
+	sets := make(chan []llrp.AntennaConfiguration, 1)
+	rfid.SetResponseFunc(llrp.MsgSetReaderConfig, func(msg llrp.Message) llrp.Outgoing {
+		conf := &llrp.SetReaderConfig{}
+		if err := msg.UnmarshalTo(conf); err != nil {
+			t.Errorf("expected a SetReaderConfig; got %v", err)
+		}
+		sets <- conf.AntennaConfigurations
+		return &llrp.SetReaderConfigResponse{}
+	})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	dev := &LLRPDevice{name: "localReader", client: c, lc: edgexCompatTestLogger{t}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	antennas, err := dev.TransmitPower(ctx)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// Antenna 1's index isn't in the table, so its power is unknown.
+	dBm := 30.0
+	expected := []AntennaTransmitPower{
+		{AntennaID: 1, PowerIndex: 7},
+		{AntennaID: 2, PowerIndex: 5, PowerDBm: &dBm},
+	}
+	if !reflect.DeepEqual(antennas, expected) {
+		t.Errorf("expected %+v; got %+v", expected, antennas)
+	}
+
+	applied, err := dev.SetTransmitPower(ctx, llrp.AllAntennas, 16)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if applied != 15 {
+		t.Errorf("expected the nearest level, 15 dBm, to be applied; got %v", applied)
+	}
+
+	// Each antenna keeps its own hop table and channel.
+	expectedSet := []llrp.AntennaConfiguration{
+		{AntennaID: 2, RFTransmitter: &llrp.RFTransmitter{HopTableID: 1, ChannelIndex: 2, TransmitPowerIndex: 2}},
+		{AntennaID: 1, RFTransmitter: &llrp.RFTransmitter{HopTableID: 1, ChannelIndex: 1, TransmitPowerIndex: 2}},
+	}
+	if got := <-sets; !reflect.DeepEqual(got, expectedSet) {
+		t.Errorf("expected %+v; got %+v", expectedSet, got)
+	}
+
+	// Neither out of range powers nor antennas without an RFTransmitter are sent.
+	if _, err := dev.SetTransmitPower(ctx, 1, 31); err == nil {
+		t.Error("expected an error for a power outside the table's range")
+	}
+	if _, err := dev.SetTransmitPower(ctx, 3, 20); err == nil || !strings.Contains(err.Error(), "RFTransmitter") {
+		t.Errorf("expected an error for an antenna without an RFTransmitter; got %v", err)
+	}
+	select {
+	case got := <-sets:
+		t.Errorf("expected no SetReaderConfig; got %+v", got)
+	default:
+	}
+}
@@ -42,3 +42,16 @@ func (imt ImpinjModelType) HostnamePrefix() string {
 		return DefaultDevicePrefix
 	}
 }
+
+// eventLogFormat describes the CustomMessages a vendor's Readers use
+// to report and clear their internal event/error history.
+type eventLogFormat struct {
+	getSubtype   uint8 // subtype of the CustomMessage requesting the history
+	clearSubtype uint8 // subtype of the CustomMessage clearing the history
+	// decode parses the Data of the Reader's reply to the get message.
+	decode func(data []byte) ([]ReaderLogEvent, error)
+}
+
+// eventLogFormats maps vendors to the format of their Readers' event history.
+// Readers from vendors not in this map can't report their history.
+var eventLogFormats = map[VendorIDType]eventLogFormat{}
@@ -0,0 +1,150 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding"
+	"encoding/binary"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	contract "github.com/edgexfoundry/go-mod-core-contracts/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetUnknownParams(t *testing.T) {
+	for _, testCase := range []struct {
+		policy   string
+		expected string
+		err      bool
+	}{
+		{policy: "", expected: UnknownParamsStrict},
+		{policy: UnknownParamsStrict, expected: UnknownParamsStrict},
+		{policy: UnknownParamsLenient, expected: UnknownParamsLenient},
+		{policy: UnknownParamsPreserve, expected: UnknownParamsPreserve},
+		{policy: "ignore", expected: UnknownParamsStrict, err: true},
+	} {
+		protocols := protocolMap{}
+		if testCase.policy != "" {
+			protocols[ProtocolDecode] = contract.ProtocolProperties{"unknownParams": testCase.policy}
+		}
+
+		policy, err := getUnknownParams(protocols)
+		if (err != nil) != testCase.err || policy != testCase.expected {
+			t.Errorf("unknownParams %q: expected %q (error: %v); got %q, %v",
+				testCase.policy, testCase.expected, testCase.err, policy, err)
+		}
+	}
+}
+
+// withUnknownParam encodes a message whose first parameter is a list of parameters,
+// such as an ROAccessReport's TagReportData or a ReaderEventNotification's data,
+// and appends a parameter of an unknown type to that first parameter.
+func withUnknownParam(t *testing.T, typ llrp.MessageType, m encoding.BinaryMarshaler) llrp.Message {
+	t.Helper()
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	unknown := []byte{0x03, 0xE8, 0x00, 0x06, 0xAB, 0xCD} // type 1000, 6 bytes
+	n := binary.BigEndian.Uint16(data[2:])
+	binary.BigEndian.PutUint16(data[2:], n+uint16(len(unknown)))
+
+	payload := append([]byte{}, data[:n]...)
+	payload = append(payload, unknown...)
+	payload = append(payload, data[n:]...)
+
+	msg, err := llrp.NewByteMessage(typ, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestLLRPDevice_unknownParams(t *testing.T) {
+	antenna := llrp.AntennaID(1)
+	report := &llrp.ROAccessReport{TagReportData: []llrp.TagReportData{{
+		EPC96:     llrp.EPC96{EPC: []byte{0x30, 0x08, 0x33, 0xB2, 0xDD, 0xD9, 0x01, 0x40, 0, 0, 0, 1}},
+		AntennaID: &antenna,
+	}}}
+
+	event := &llrp.ReaderEventNotification{ReaderEventNotificationData: llrp.ReaderEventNotificationData{
+		UTCTimestamp: 1600000000000000,
+		AntennaEvent: &llrp.AntennaEvent{Event: llrp.AntennaConnected, AntennaID: 1},
+	}}
+
+	for _, testCase := range []struct {
+		policy    string
+		delivered bool // whether the message reaches EdgeX
+		preserved bool // whether the unknown parameter is included with it
+	}{
+		{policy: "", delivered: false},
+		{policy: UnknownParamsStrict, delivered: false},
+		{policy: UnknownParamsLenient, delivered: true},
+		{policy: UnknownParamsPreserve, delivered: true, preserved: true},
+	} {
+		t.Run("policy "+testCase.policy, func(t *testing.T) {
+			ch := make(chan *dsModels.AsyncValues, 2)
+			l := &LLRPDevice{
+				name:          "localReader",
+				lc:            edgexCompatTestLogger{t},
+				ch:            ch,
+				reads:         newTagReadCache(10),
+				counts:        newTagCounter(time.Minute),
+				unknownParams: testCase.policy,
+			}
+
+			l.newROHandler().HandleMessage(nil, withUnknownParam(t, llrp.MsgROAccessReport, report))
+			l.newReaderEventHandler(nil).HandleMessage(nil,
+				withUnknownParam(t, llrp.MsgReaderEventNotification, event))
+
+			if !testCase.delivered {
+				select {
+				case av := <-ch:
+					t.Errorf("expected the messages to be discarded; got %+v", av)
+				case <-time.After(100 * time.Millisecond):
+				}
+				return
+			}
+
+			// The values are sent concurrently, so they may arrive in either order.
+			received := map[string]string{}
+			for len(received) < 2 {
+				select {
+				case av := <-ch:
+					// The event's AntennaEvent is also sent as its own reading.
+					if av.CommandValues[0].DeviceResourceName == ResourceAntennaEventPort {
+						continue
+					}
+					if len(av.CommandValues) != 1 {
+						t.Fatalf("expected a single value; got %+v", av)
+					}
+					cv := av.CommandValues[0]
+					received[cv.DeviceResourceName] = cv.ValueToString()
+				case <-time.After(time.Second):
+					t.Fatalf("expected a report and an event; got %v", received)
+				}
+			}
+
+			for _, name := range []string{ResourceROAccessReport, ResourceReaderNotification} {
+				js, ok := received[name]
+				if !ok {
+					t.Fatalf("expected a %s value; got %v", name, received)
+				}
+				if preserved := strings.Contains(js, `"UnknownParams"`); preserved != testCase.preserved {
+					t.Errorf("expected the %s's unknown parameters preserved: %v; got %s",
+						name, testCase.preserved, js)
+				}
+				if testCase.preserved && !strings.Contains(js, `"Type":1000`) {
+					t.Errorf("expected the %s to include the unknown parameter's type; got %s", name, js)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"math"
+	"time"
+)
+
+const (
+	// writeVerifyTimeout is how long to wait for the read-back of a verified write
+	// before reporting that it couldn't be verified, e.g., because the tag left the field.
+	writeVerifyTimeout = 10 * time.Second
+
+	// Read-back AccessSpecIDs count down from the top of the ID range
+	// to make conflicts with users' AccessSpecs unlikely.
+	firstReadbackID = math.MaxUint32
+	numReadbackIDs  = 1 << 16
+
+	// LLRP uses 0 to indicate an OpSpec succeeded.
+	writeSuccess = llrp.C1G2WriteSuccess
+	readSuccess  = llrp.C1G2ReadSuccess
+)
+
+// Outcomes of verifying a tag write.
+const (
+	WriteVerified       = "Verified"
+	WriteMismatch       = "Mismatch"
+	WriteCouldNotVerify = "CouldNotVerify"
+)
+
+// TagWriteVerification is the result of reading back the memory a C1G2Write wrote,
+// sent to EdgeX as a TagWriteVerification event.
+type TagWriteVerification struct {
+	AccessSpecID uint32 // the AccessSpec that performed the write
+	EPC          []byte // the EPC reported with the write's result
+	Outcome      string
+	Reason       string   `json:",omitempty"` // why a write couldn't be verified
+	Expected     []uint16 // the data written
+	Actual       []uint16 `json:",omitempty"` // the data read back, if it could be
+}
+
+// readback tracks a read-back AccessSpec awaiting its result.
+type readback struct {
+	write llrp.AccessSpec // the AccessSpec that performed the write
+	epc   []byte
+	timer timer
+}
+
+// AddVerifiedAccessSpec adds an AccessSpec with a C1G2Write
+// and tracks the results of its writes.
+//
+// Each time the Reader reports a successful write,
+// it adds a follow-up AccessSpec that reads back the same memory region,
+// compares the result to the data written, and sends the outcome to EdgeX.
+// If the read-back result doesn't arrive within writeVerifyTimeout,
+// e.g., because the tag left the field, the outcome is WriteCouldNotVerify.
+func (l *LLRPDevice) AddVerifiedAccessSpec(ctx context.Context, add *llrp.AddAccessSpec) error {
+	if add.AccessSpec.AccessCommand.C1G2Write == nil {
+		return errors.New("verified AccessSpecs must have a C1G2Write")
+	}
+
+	if err := l.checkSupported(ctx, add); err != nil {
+		return err
+	}
+
+	if err := l.TrySend(ctx, add, &llrp.AddAccessSpecResponse{}); err != nil {
+		return err
+	}
+
+	l.verifyMu.Lock()
+	if l.verifiedWrites == nil {
+		l.verifiedWrites = make(map[uint32]llrp.AccessSpec)
+	}
+	l.verifiedWrites[add.AccessSpec.AccessSpecID] = add.AccessSpec
+	l.verifyMu.Unlock()
+	return nil
+}
+
+// forgetVerifiedAccessSpec stops verifying writes from the AccessSpec,
+// e.g., because it's being deleted.
+func (l *LLRPDevice) forgetVerifiedAccessSpec(id uint32) {
+	l.verifyMu.Lock()
+	delete(l.verifiedWrites, id)
+	l.verifyMu.Unlock()
+}
+
+// verifyWrites starts read-backs for successful verified writes
+// and checks the results of read-backs in the reads.
+func (l *LLRPDevice) verifyWrites(reads []llrp.TagReportData) {
+	for i := range reads {
+		tr := &reads[i]
+		if tr.AccessSpecID == nil {
+			continue
+		}
+		id := uint32(*tr.AccessSpecID)
+
+		l.verifyMu.Lock()
+		write, isWrite := l.verifiedWrites[id]
+		_, isReadback := l.readbacks[id]
+		l.verifyMu.Unlock()
+
+		switch {
+		case isWrite && tr.C1G2WriteOpSpecResult != nil:
+			l.startReadback(write, tr)
+		case isReadback && tr.C1G2ReadOpSpecResult != nil:
+			l.finishReadback(id, tr.C1G2ReadOpSpecResult, "")
+		}
+	}
+}
+
+// startReadback adds an AccessSpec to read back the memory
+// written by the write AccessSpec for the tag in tr.
+func (l *LLRPDevice) startReadback(write llrp.AccessSpec, tr *llrp.TagReportData) {
+	epc := tr.EPC()
+
+	wr := tr.C1G2WriteOpSpecResult
+	if wr.C1G2WriteOpSpecResultType != writeSuccess {
+		l.sendVerification(TagWriteVerification{
+			AccessSpecID: write.AccessSpecID,
+			EPC:          epc,
+			Outcome:      WriteCouldNotVerify,
+			Reason:       fmt.Sprintf("write failed with result %d", wr.C1G2WriteOpSpecResultType),
+			Expected:     write.AccessCommand.C1G2Write.Data,
+		})
+		return
+	}
+
+	l.verifyMu.Lock()
+	if l.readbacks == nil {
+		l.readbacks = make(map[uint32]*readback)
+	}
+	id := firstReadbackID - l.nextReadback%numReadbackIDs
+	l.nextReadback++
+	rb := &readback{write: write, epc: epc}
+	rb.timer = l.clock().AfterFunc(writeVerifyTimeout, func() {
+		l.finishReadback(id, nil, "timed out waiting for the read-back; the tag may have left the field")
+	})
+	l.readbacks[id] = rb
+	l.verifyMu.Unlock()
+
+	w := write.AccessCommand.C1G2Write
+	read := write
+	read.AccessSpecID = id
+	read.IsActive = false
+	read.Trigger = llrp.AccessSpecStopTrigger{
+		Trigger:             llrp.AccessSpecStopTriggerOperationCount,
+		OperationCountValue: 1,
+	}
+	read.AccessCommand = llrp.AccessCommand{
+		C1G2TagSpec: readbackTagSpec(write, epc),
+		C1G2Read: &llrp.C1G2Read{
+			OpSpecID:       w.OpSpecID,
+			AccessPassword: w.AccessPassword,
+			C1G2MemoryBank: w.C1G2MemoryBank,
+			WordAddress:    w.WordAddress,
+			WordCount:      uint16(len(w.Data)),
+		},
+	}
+
+	// Don't block the report handler on the Reader's responses.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		if err := l.TrySend(ctx, &llrp.AddAccessSpec{AccessSpec: read},
+			&llrp.AddAccessSpecResponse{}); err != nil {
+			l.finishReadback(id, nil, "failed to add read-back AccessSpec: "+err.Error())
+			return
+		}
+
+		if err := l.TrySend(ctx, &llrp.EnableAccessSpec{AccessSpecID: id},
+			&llrp.EnableAccessSpecResponse{}); err != nil {
+			l.finishReadback(id, nil, "failed to enable read-back AccessSpec: "+err.Error())
+		}
+	}()
+}
+
+// readbackTagSpec returns a C1G2TagSpec matching only the tag with the EPC,
+// as it is after the write, so the read-back reads the tag that was written
+// rather than whichever one next matches the write's own tag spec.
+// If the report didn't include the EPC, it falls back to the write's tag spec.
+func readbackTagSpec(write llrp.AccessSpec, epc []byte) llrp.C1G2TagSpec {
+	if len(epc) == 0 {
+		return write.AccessCommand.C1G2TagSpec
+	}
+
+	// If the write changed the EPC, the tag now answers to the new one.
+	w := write.AccessCommand.C1G2Write
+	if w.C1G2MemoryBank == memoryBankEPC {
+		written := make([]byte, len(epc))
+		copy(written, epc)
+		for i, word := range w.Data {
+			b := (int(w.WordAddress)+i)*2 - epcStartBit/8
+			if b >= 0 && b+1 < len(written) {
+				written[b], written[b+1] = byte(word>>8), byte(word)
+			}
+		}
+		epc = written
+	}
+
+	return llrp.C1G2TagSpec{TagPattern1: epcTagPattern(epc)}
+}
+
+// finishReadback compares a read-back's result to the data written
+// and sends the outcome to EdgeX.
+// If result is nil, the outcome is WriteCouldNotVerify for the given reason.
+// It does nothing if the read-back already finished.
+func (l *LLRPDevice) finishReadback(id uint32, result *llrp.C1G2ReadOpSpecResult, reason string) {
+	l.verifyMu.Lock()
+	rb, ok := l.readbacks[id]
+	delete(l.readbacks, id)
+	l.verifyMu.Unlock()
+
+	if !ok {
+		return
+	}
+	rb.timer.Stop()
+
+	v := TagWriteVerification{
+		AccessSpecID: rb.write.AccessSpecID,
+		EPC:          rb.epc,
+		Outcome:      WriteCouldNotVerify,
+		Reason:       reason,
+		Expected:     rb.write.AccessCommand.C1G2Write.Data,
+	}
+
+	switch {
+	case result == nil:
+	case result.C1G2ReadOpSpecResultType != readSuccess:
+		v.Reason = fmt.Sprintf("read-back failed with result %d", result.C1G2ReadOpSpecResultType)
+	default:
+		v.Actual = result.Data
+		v.Outcome = WriteVerified
+		if !equalWords(v.Expected, v.Actual) {
+			v.Outcome = WriteMismatch
+		}
+	}
+
+	l.sendVerification(v)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		if err := l.TrySend(ctx, &llrp.DeleteAccessSpec{AccessSpecID: id},
+			&llrp.DeleteAccessSpecResponse{}); err != nil {
+			l.lc.Debug("Failed to delete read-back AccessSpec.",
+				"device", l.name, "accessSpecID", id, "error", err.Error())
+		}
+	}()
+}
+
+// sendVerification sends the outcome of a write verification to EdgeX.
+func (l *LLRPDevice) sendVerification(v TagWriteVerification) {
+	if v.Outcome != WriteVerified {
+		l.lc.Warn("Tag write not verified.", "device", l.name,
+			"accessSpecID", v.AccessSpecID, "outcome", v.Outcome, "reason", v.Reason)
+	}
+	go l.sendEdgeXEvent(ResourceTagWriteVerification, l.clock().Now().UnixNano(), v)
+}
+
+// equalWords returns true if a and b have the same words.
+func equalWords(a, b []uint16) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
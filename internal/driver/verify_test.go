@@ -0,0 +1,192 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestVerifiedAccessSpec(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rfid.SetResponse(llrp.MsgAddAccessSpec, &llrp.AddAccessSpecResponse{})
+	rfid.SetResponse(llrp.MsgEnableAccessSpec, &llrp.EnableAccessSpecResponse{})
+	rfid.SetResponse(llrp.MsgDeleteAccessSpec, &llrp.DeleteAccessSpecResponse{})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	events := make(chan *dsModels.AsyncValues, 10)
+	clk := newFakeClock()
+	dev := &LLRPDevice{
+		client: c,
+		ch:     events,
+		caps:   &llrp.GetReaderCapabilitiesResponse{},
+		clk:    clk,
+	}
+	d := newLocalDriver(t, dev)
+
+	written := []uint16{0x1234, 0x5678}
+	if err := d.HandleWriteCommands("localReader", protocolMap{},
+		[]dsModels.CommandRequest{{DeviceResourceName: ResourceVerifiedAccessSpec, Type: dsModels.String}},
+		[]*dsModels.CommandValue{dsModels.NewStringValue(ResourceVerifiedAccessSpec, 0,
+			`{"AccessSpecID":7,"ROSpecID":1,"AccessCommand":{"C1G2Write":`+
+				`{"C1G2MemoryBank":3,"WordAddress":0,"Data":[4660,22136]}}}`)},
+	); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	writeID := llrp.AccessSpecID(7)
+	epc := []byte{0xE2, 0x00, 0x01}
+
+	// nextVerification reads back a successful write
+	// with the given result and returns the reported outcome.
+	nextVerification := func(t *testing.T, result *llrp.C1G2ReadOpSpecResult) TagWriteVerification {
+		t.Helper()
+
+		dev.verifyWrites([]llrp.TagReportData{{
+			EPC96:                 llrp.EPC96{EPC: epc},
+			AccessSpecID:          &writeID,
+			C1G2WriteOpSpecResult: &llrp.C1G2WriteOpSpecResult{WordsWritten: 2},
+		}})
+
+		dev.verifyMu.Lock()
+		var readbackID uint32
+		for id := range dev.readbacks {
+			readbackID = id
+		}
+		dev.verifyMu.Unlock()
+
+		if readbackID == 0 {
+			t.Fatal("expected a read-back after a successful write")
+		}
+
+		if result == nil {
+			clk.Advance(writeVerifyTimeout)
+		} else {
+			rbID := llrp.AccessSpecID(readbackID)
+			dev.verifyWrites([]llrp.TagReportData{{
+				EPC96:                llrp.EPC96{EPC: epc},
+				AccessSpecID:         &rbID,
+				C1G2ReadOpSpecResult: result,
+			}})
+		}
+
+		var v TagWriteVerification
+		select {
+		case av := <-events:
+			s, err := av.CommandValues[0].StringValue()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(s), &v); err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for a TagWriteVerification event")
+		}
+
+		return v
+	}
+
+	for _, testCase := range []struct {
+		name    string
+		result  *llrp.C1G2ReadOpSpecResult
+		outcome string
+	}{
+		{name: "verified", result: &llrp.C1G2ReadOpSpecResult{Data: written}, outcome: WriteVerified},
+		{name: "mismatch", result: &llrp.C1G2ReadOpSpecResult{Data: []uint16{0x1234, 0}}, outcome: WriteMismatch},
+		{name: "readFailed", result: &llrp.C1G2ReadOpSpecResult{C1G2ReadOpSpecResultType: 1},
+			outcome: WriteCouldNotVerify},
+		{name: "tagLeft", outcome: WriteCouldNotVerify},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			v := nextVerification(t, testCase.result)
+
+			if v.Outcome != testCase.outcome {
+				t.Errorf("expected outcome %s; got %s (%s)", testCase.outcome, v.Outcome, v.Reason)
+			}
+
+			if v.AccessSpecID != uint32(writeID) || !equalWords(v.Expected, written) {
+				t.Errorf("verification doesn't match the write: %+v", v)
+			}
+		})
+	}
+
+	// Once the AccessSpec is deleted, its writes are no longer verified.
+	dev.forgetVerifiedAccessSpec(uint32(writeID))
+	dev.verifyWrites([]llrp.TagReportData{{
+		AccessSpecID:          &writeID,
+		C1G2WriteOpSpecResult: &llrp.C1G2WriteOpSpecResult{WordsWritten: 2},
+	}})
+
+	dev.verifyMu.Lock()
+	n := len(dev.readbacks)
+	dev.verifyMu.Unlock()
+	if n != 0 {
+		t.Errorf("expected no read-backs for a forgotten AccessSpec; got %d", n)
+	}
+}
+
+func TestReadbackTagSpec(t *testing.T) {
+	epc := []byte{0xE2, 0x00, 0x01, 0x02}
+	writeSpec := llrp.C1G2TagSpec{TagPattern1: llrp.C1G2TargetTag{
+		C1G2MemoryBank: memoryBankTID, MatchFlag: true, TagMaskNumBits: 8, TagMask: []byte{0xFF},
+		TagDataNumBits: 8, TagData: []byte{0xE2}}}
+
+	for _, testCase := range []struct {
+		name     string
+		bank     llrp.C1G2MemoryBankType
+		address  uint16
+		data     []uint16
+		epc      []byte
+		expected []byte // nil if the write's tag spec is expected
+	}{
+		{name: "user", bank: memoryBankUser, data: []uint16{0x1234}, epc: epc, expected: epc},
+		{name: "noEPC", bank: memoryBankUser, data: []uint16{0x1234}},
+		{name: "pc", bank: memoryBankEPC, address: 1, data: []uint16{0x1000}, epc: epc, expected: epc},
+		{name: "epc", bank: memoryBankEPC, address: 2, data: []uint16{0x3000, 0xABCD}, epc: epc,
+			expected: []byte{0x30, 0x00, 0xAB, 0xCD}},
+		{name: "pcAndEPC", bank: memoryBankEPC, address: 1, data: []uint16{0x1000, 0x3000}, epc: epc,
+			expected: []byte{0x30, 0x00, 0x01, 0x02}},
+		{name: "epcTail", bank: memoryBankEPC, address: 3, data: []uint16{0xABCD, 0xFFFF}, epc: epc,
+			expected: []byte{0xE2, 0x00, 0xAB, 0xCD}},
+	} {
+		write := llrp.AccessSpec{AccessCommand: llrp.AccessCommand{
+			C1G2TagSpec: writeSpec,
+			C1G2Write: &llrp.C1G2Write{C1G2MemoryBank: testCase.bank,
+				WordAddress: testCase.address, Data: testCase.data},
+		}}
+
+		spec := readbackTagSpec(write, testCase.epc)
+		if testCase.expected == nil {
+			if spec.TagPattern1.C1G2MemoryBank != memoryBankTID || spec.TagPattern2 != nil {
+				t.Errorf("%s: expected the write's tag spec; got %+v", testCase.name, spec)
+			}
+			continue
+		}
+
+		if p := spec.TagPattern1; spec.TagPattern2 != nil || p.C1G2MemoryBank != memoryBankEPC ||
+			!p.MatchFlag || p.MostSignificantBit != epcStartBit ||
+			p.TagDataNumBits != uint16(len(epc)*8) || !bytes.Equal(p.TagData, testCase.expected) {
+			t.Errorf("%s: expected a tag spec for EPC %X; got %+v", testCase.name, testCase.expected, spec)
+		}
+	}
+
+	// The report's EPC is left as-is.
+	if !bytes.Equal(epc, []byte{0xE2, 0x00, 0x01, 0x02}) {
+		t.Errorf("expected the EPC not to change; got %X", epc)
+	}
+}
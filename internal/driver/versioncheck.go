@@ -0,0 +1,77 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"github.com/pkg/errors"
+	"sync"
+)
+
+// ResourceVersionMismatch is sent as an event when a Reader sends a message
+// with a different LLRP version than the one negotiated for its connection.
+const ResourceVersionMismatch = "VersionMismatch"
+
+// Handling of messages whose version differs from their connection's,
+// selected by the VersionMismatch driver configuration.
+const (
+	VersionMismatchWarn   = "warn"   // warn, but process the message as usual
+	VersionMismatchReject = "reject" // warn, then close the connection
+)
+
+// versionMismatchEvent is the value of ResourceVersionMismatch events.
+type versionMismatchEvent struct {
+	MessageType       string // the type of the mismatched message
+	MessageVersion    string // the version in its header
+	NegotiatedVersion string // the version negotiated for the connection
+	Rejected          bool   // true if the service closed the connection
+}
+
+// checkVersionMismatch returns an error if policy isn't a known VersionMismatch policy.
+func checkVersionMismatch(policy string) error {
+	switch policy {
+	case VersionMismatchWarn, VersionMismatchReject:
+		return nil
+	default:
+		return errors.Errorf("unknown version mismatch policy %q; policies are %s or %s",
+			policy, VersionMismatchWarn, VersionMismatchReject)
+	}
+}
+
+// newVersionMismatchFunc returns an llrp.VersionMismatchFunc
+// that warns and sends a ResourceVersionMismatch event
+// for the first mismatched message on each connection.
+// Readers that ignore SetProtocolVersion usually do so for every message,
+// so later mismatches are only logged at the debug level.
+func (l *LLRPDevice) newVersionMismatchFunc(reject bool) llrp.VersionMismatchFunc {
+	var mu sync.Mutex
+	var reported *llrp.Client
+
+	return func(c *llrp.Client, hdr llrp.Header, negotiated llrp.VersionNum) {
+		mu.Lock()
+		first := reported != c
+		reported = c
+		mu.Unlock()
+
+		if !first {
+			l.lc.Debug("LLRP incoming message version mismatch.", "device", l.name,
+				"type", hdr.Type().String(), "message-version", hdr.Version().String(),
+				"negotiated-version", negotiated.String())
+			return
+		}
+
+		l.lc.Warn("Reader sent a message with a different version than it negotiated.",
+			"device", l.name, "type", hdr.Type().String(),
+			"message-version", hdr.Version().String(),
+			"negotiated-version", negotiated.String(), "rejected", reject)
+		go l.sendEdgeXEvent(ResourceVersionMismatch, l.clock().Now().UnixNano(), versionMismatchEvent{
+			MessageType:       hdr.Type().String(),
+			MessageVersion:    hdr.Version().String(),
+			NegotiatedVersion: negotiated.String(),
+			Rejected:          reject,
+		})
+	}
+}
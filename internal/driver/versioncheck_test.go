@@ -0,0 +1,78 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestLLRPDevice_versionMismatch(t *testing.T) {
+	ch := make(chan *dsModels.AsyncValues, 3)
+	dev := &LLRPDevice{
+		name: "staleReader",
+		lc:   edgexCompatTestLogger{t},
+		ch:   ch,
+	}
+
+	// A v1.1 ROAccessReport header.
+	data := make([]byte, 10)
+	binary.BigEndian.PutUint16(data[0:2], uint16(llrp.Version1_1)<<10|uint16(llrp.MsgROAccessReport))
+	binary.BigEndian.PutUint32(data[2:6], 10)
+	binary.BigEndian.PutUint32(data[6:10], 1)
+	var hdr llrp.Header
+	if err := hdr.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the first mismatch on each connection sends an event.
+	onMismatch := dev.newVersionMismatchFunc(true)
+	first, second := llrp.NewClient(llrp.WithLogger(nil)), llrp.NewClient(llrp.WithLogger(nil))
+	for _, c := range []*llrp.Client{first, first, second, second} {
+		onMismatch(c, hdr, llrp.Version1_0_1)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for i := 0; i < 2; i++ {
+		select {
+		case av := <-ch:
+			if len(av.CommandValues) != 1 || av.CommandValues[0].DeviceResourceName != ResourceVersionMismatch {
+				t.Fatalf("expected a %s event; got %+v", ResourceVersionMismatch, av)
+			}
+
+			var event versionMismatchEvent
+			s, err := av.CommandValues[0].StringValue()
+			if err == nil {
+				err = json.Unmarshal([]byte(s), &event)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := versionMismatchEvent{
+				MessageType:       llrp.MsgROAccessReport.String(),
+				MessageVersion:    llrp.Version1_1.String(),
+				NegotiatedVersion: llrp.Version1_0_1.String(),
+				Rejected:          true,
+			}
+			if event != expected {
+				t.Errorf("expected %+v; got %+v", expected, event)
+			}
+		case <-timeout:
+			t.Fatalf("expected 2 %s events; got %d", ResourceVersionMismatch, i)
+		}
+	}
+
+	select {
+	case av := <-ch:
+		t.Errorf("expected only one event per connection; got %+v", av)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,46 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	"strings"
+)
+
+// ResourceCommandWarning is sent as an event when a Reader accepts a command,
+// but includes details with its successful LLRPStatus, such as an antenna it couldn't activate.
+const ResourceCommandWarning = "CommandWarning"
+
+// commandWarningEvent is the value of ResourceCommandWarning events.
+type commandWarningEvent struct {
+	Message string // the type of the command's message, e.g. "EnableROSpec"
+	Warning string // the details the Reader included with its status
+}
+
+// warnStatus logs and sends a ResourceCommandWarning event
+// if the reply to a successful request has a status with a warning.
+// Warnings don't fail the command, so this is the only way they're visible.
+func (l *LLRPDevice) warnStatus(request llrp.Outgoing, reply llrp.Incoming) {
+	st, ok := reply.(llrp.Statusable)
+	if !ok {
+		return
+	}
+
+	status := st.Status()
+	warning := status.Warning()
+	if warning == "" {
+		return
+	}
+
+	msgType := strings.TrimPrefix(request.Type().String(), "Msg")
+	l.lc.Warn("Reader succeeded with a warning.",
+		"device", l.name, "message", msgType, "warning", warning)
+
+	ns := l.clock().Now().UnixNano()
+	l.goSend(func() {
+		l.sendEdgeXEvent(ResourceCommandWarning, ns, commandWarningEvent{Message: msgType, Warning: warning})
+	})
+}
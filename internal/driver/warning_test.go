@@ -0,0 +1,100 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"github.com/edgexfoundry-holding/device-rfid-llrp-go/internal/llrp"
+	dsModels "github.com/edgexfoundry/device-sdk-go/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestHandleWrite_statusWarnings(t *testing.T) {
+	rfid, err := llrp.NewTestDevice(llrp.Version1_0_1, llrp.Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader succeeds, but warns about an antenna it couldn't activate.
+	warning := llrp.LLRPStatus{
+		Status:           llrp.StatusSuccess,
+		ErrorDescription: "antenna 2 not connected",
+	}
+	rfid.SetResponse(llrp.MsgEnableROSpec, &llrp.EnableROSpecResponse{LLRPStatus: warning})
+	rfid.SetResponse(llrp.MsgStartROSpec, &llrp.StartROSpecResponse{LLRPStatus: warning})
+	rfid.SetResponse(llrp.MsgStopROSpec, &llrp.StopROSpecResponse{})
+
+	go rfid.ImpersonateReader()
+	c := rfid.ConnectClient(t)
+
+	ch := make(chan *dsModels.AsyncValues, 1)
+	dev := &LLRPDevice{client: c, ch: ch}
+	d := newLocalDriver(t, dev)
+
+	write := func(action string) error {
+		return d.HandleWriteCommands(dev.name, protocolMap{},
+			[]dsModels.CommandRequest{
+				{DeviceResourceName: ResourceROSpecID, Type: dsModels.Uint32},
+				{DeviceResourceName: ResourceAction, Type: dsModels.String},
+			},
+			[]*dsModels.CommandValue{
+				func() *dsModels.CommandValue {
+					cv, err := dsModels.NewUint32Value(ResourceROSpecID, 0, 1)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return cv
+				}(),
+				dsModels.NewStringValue(ResourceAction, 0, action),
+			})
+	}
+
+	// nextWarning returns the next CommandWarning event, skipping the commands' responses,
+	// or nil if none arrives before the timeout.
+	nextWarning := func(timeout time.Duration) *dsModels.AsyncValues {
+		deadline := time.After(timeout)
+		for {
+			select {
+			case av := <-ch:
+				if len(av.CommandValues) == 1 && av.CommandValues[0].DeviceResourceName == ResourceCommandWarning {
+					return av
+				}
+			case <-deadline:
+				return nil
+			}
+		}
+	}
+
+	for _, action := range []string{ActionEnable, ActionStart} {
+		if err := write(action); err != nil {
+			t.Fatalf("%s: expected warnings not to fail the command; got %+v", action, err)
+		}
+
+		av := nextWarning(time.Second)
+		if av == nil {
+			t.Fatalf("%s: expected a %s event", action, ResourceCommandWarning)
+		}
+
+		event := commandWarningEvent{}
+		if err := json.Unmarshal([]byte(av.CommandValues[0].ValueToString()), &event); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := commandWarningEvent{Message: action + "ROSpec", Warning: warning.ErrorDescription}
+		if event != expected {
+			t.Errorf("expected %+v; got %+v", expected, event)
+		}
+	}
+
+	// Plain successes don't warn.
+	if err := write(ActionStop); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if av := nextWarning(100 * time.Millisecond); av != nil {
+		t.Errorf("expected no warning for a plain success; got %+v", av)
+	}
+}
@@ -0,0 +1,139 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package llrp
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// These benchmarks establish a baseline for encoding and decoding throughput;
+// see the README's Benchmarks section for comparing runs.
+
+// reportSizes are the numbers of tags in the benchmarked ROAccessReports.
+// The largest stresses the report decode path with a busy Reader's buffered reads.
+var reportSizes = []int{1, 100, 1000, 50000}
+
+// benchReport returns an ROAccessReport with nTags TagReportData
+// with the parameters Readers typically include with each tag.
+func benchReport(nTags int) *ROAccessReport {
+	ro := &ROAccessReport{TagReportData: make([]TagReportData, nTags)}
+	for i := range ro.TagReportData {
+		antenna := AntennaID(i%4 + 1)
+		rssi := PeakRSSI(-50)
+		seen := FirstSeenUTC(1600000000000000 + uint64(i))
+		count := TagSeenCount(1)
+
+		ro.TagReportData[i] = TagReportData{
+			EPC96: EPC96{
+				EPC: []byte{0x30, 0x08, 0x33, 0xB2, 0xDD, 0xD9, 0x01, 0x40, 0, 0, byte(i >> 8), byte(i)},
+			},
+			AntennaID:    &antenna,
+			PeakRSSI:     &rssi,
+			FirstSeenUTC: &seen,
+			TagSeenCount: &count,
+		}
+	}
+	return ro
+}
+
+func BenchmarkHeader(b *testing.B) {
+	h := Header{version: Version1_0_1, typ: MsgROAccessReport, payloadLen: 1024, id: 42}
+	data, err := h.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(HeaderSz)
+		for i := 0; i < b.N; i++ {
+			if _, err := h.MarshalBinary(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(HeaderSz)
+		var h2 Header
+		for i := 0; i < b.N; i++ {
+			if err := h2.UnmarshalBinary(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkROAccessReport_MarshalBinary(b *testing.B) {
+	for _, nTags := range reportSizes {
+		ro := benchReport(nTags)
+		b.Run(strconv.Itoa(nTags)+"Tags", func(b *testing.B) {
+			data, err := ro.MarshalBinary()
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := ro.MarshalBinary(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkROAccessReport_UnmarshalBinary(b *testing.B) {
+	for _, nTags := range reportSizes {
+		data, err := benchReport(nTags).MarshalBinary()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(strconv.Itoa(nTags)+"Tags", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				ro := &ROAccessReport{}
+				if err := ro.UnmarshalBinary(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkClient_roundTrip measures a full command round trip:
+// encoding the request, writing it, the Reader's reply, and decoding the response.
+func BenchmarkClient_roundTrip(b *testing.B) {
+	td, err := NewTestDevice(Version1_0_1, Version1_1, time.Second, true)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	rspec := ROReportSpec{Trigger: NTagsOrROEnd, N: 1}
+	td.SetResponse(MsgGetReaderConfig, &GetReaderConfigResponse{ROReportSpec: &rspec})
+	go td.ImpersonateReader()
+	c := td.ConnectClient(b)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp := &GetReaderConfigResponse{}
+		if err := c.SendFor(ctx, &GetReaderConfig{}, resp); err != nil {
+			b.Fatalf("%+v", err)
+		}
+	}
+}
@@ -2208,7 +2208,7 @@ func TestTagReportContentSelector_roundTrip(t *testing.T) {
 
 // Test Parameter 239, AccessReportSpec.
 func TestAccessReportSpec_roundTrip(t *testing.T) {
-	p := AccessReportSpec(0)
+	p := AccessReportSpec(AccessReportEndOfAccessSpec)
 	b, err := p.MarshalBinary()
 	if err != nil {
 		t.Fatalf("%+v", err)
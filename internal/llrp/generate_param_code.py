@@ -831,7 +831,11 @@ class ParamSpec:
 
     def tlv_len_check(self, w: GoWriter):
         w.write('subLen := binary.BigEndian.Uint16(data[2:])')
-        with w.condition('int(subLen) > len(data)'):
+        with w.condition('subLen < 4'):
+            w.reterr(f'Param{self.param_name} '
+                     'says it has %d bytes, which is less than its 4 byte header',
+                     ['subLen'])
+            w.ifelse('int(subLen) > len(data)')
             w.reterr(f'Param{self.param_name} '
                      'says it has %d bytes, but only %d bytes remain',
                      ['subLen', 'len(data)'])
@@ -1343,7 +1347,10 @@ class Container:
                     if not mut_excl:
                         w.write('subLen := binary.BigEndian.Uint16(data[2:])')
                         has_sub_len = True
-                        with w.condition('int(subLen) > len(data)'):
+                        with w.condition('subLen < 4'):
+                            w.reterr(f'%v says it has %d bytes, which is less than its 4 byte header',
+                                     ['pt', 'subLen'])
+                            w.ifelse('int(subLen) > len(data)')
                             w.reterr(f'%v says it has %d bytes, but only %d bytes remain',
                                      ['pt', 'subLen', 'len(data)'])
 
@@ -1372,8 +1379,10 @@ class Container:
             if not p.optional:
                 w.reterr(f'expected Param{sub.name}, but found %v', ['subType'])
                 w.ifelse()
+            sub.sublen_check(w)
             self.alloc(w, p)
             self.write_unmarshal_sub(w, p, False)
+            sub.len_adv(w)
 
     def unmarshal_tlv(self, w, p, has_sub_len):
         sub = p.p_def
@@ -1456,21 +1465,23 @@ class Container:
         return known_len
 
     def sublen_check(self, w: GoWriter) -> bool:
-        """Write a check that ensures there's at least as many bytes as the header claims.
-        Only applies to TLVs; TVs simply return without writing anything."""
-
-        '''
-        if self.fixed_size:
+        """Write a check that ensures there's at least as many bytes as the header claims,
+        and that the claim includes the header itself.
+        TVs have no length in their header, so for them, this checks that there's enough data
+        for their fixed size, then returns False, since there's no subLen."""
+        if self.header_size == 1:
+            assert self.fixed_size
             with w.condition(f'len(data) < {self.min_size}'):
                 w.reterr(f'{self.const_name} '
                          f'needs {self.min_size} bytes, but only %d bytes remain',
                          ['len(data)'])
             return False
-        '''
-        if self.header_size == 1:
-            return False
         w.write('subLen := binary.BigEndian.Uint16(data[2:])')
-        with w.condition('int(subLen) > len(data)'):
+        with w.condition(f'subLen < {self.header_size}'):
+            w.reterr(f'{self.const_name} '
+                     f'says it has %d bytes, which is less than its {self.header_size} byte header',
+                     ['subLen'])
+            w.ifelse('int(subLen) > len(data)')
             w.reterr(f'{self.const_name} '
                      'says it has %d bytes, but only %d bytes remain',
                      ['subLen', 'len(data)'])
@@ -2613,10 +2613,10 @@ func (p *FieldError) EncodeFields(w io.Writer) error {
 // EncodeFields for Parameter 289, ParameterError.
 func (p *ParameterError) getHeader() paramHeader {
 	nParams := 0
-	if p.ParameterError != nil {
+	if p.FieldError != nil {
 		nParams++
 	}
-	if p.FieldError != nil {
+	if p.ParameterError != nil {
 		nParams++
 	}
 	ph := paramHeader{
@@ -2625,13 +2625,13 @@ func (p *ParameterError) getHeader() paramHeader {
 		sz:        8,
 		subs:      make([]paramHeader, 0, nParams),
 	}
-	if p.ParameterError != nil {
-		sh := p.ParameterError.getHeader()
+	if p.FieldError != nil {
+		sh := p.FieldError.getHeader()
 		ph.sz += sh.sz
 		ph.subs = append(ph.subs, sh)
 	}
-	if p.FieldError != nil {
-		sh := p.FieldError.getHeader()
+	if p.ParameterError != nil {
+		sh := p.ParameterError.getHeader()
 		ph.sz += sh.sz
 		ph.subs = append(ph.subs, sh)
 	}
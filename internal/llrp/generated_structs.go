@@ -160,6 +160,12 @@ const (
 )
 
 type AccessReportTriggerType uint8
+
+const (
+	AccessReportWithROReport    = AccessReportTriggerType(0)
+	AccessReportEndOfAccessSpec = AccessReportTriggerType(1)
+)
+
 type ROReportTriggerType uint8
 
 const (
@@ -1794,8 +1800,8 @@ type FieldError struct {
 type ParameterError struct {
 	ParameterType  ParamType
 	ErrorCode      StatusCode
-	ParameterError *ParameterError
 	FieldError     *FieldError
+	ParameterError *ParameterError
 }
 
 // C1G2LLRPCapabilities is Parameter 327, C1G2LLRPCapabilities.
@@ -53,7 +53,10 @@ func (m *GetSupportedVersionResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -91,7 +94,10 @@ func (m *SetProtocolVersionResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -124,7 +130,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -159,7 +168,10 @@ func (m *GetReaderCapabilitiesResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -176,7 +188,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -214,7 +229,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -249,7 +267,10 @@ func (m *AddROSpec) UnmarshalBinary(data []byte) error {
 		return errors.Errorf("expected ParamROSpec, but found %v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamROSpec says it has %d bytes, which is "+
+				"less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamROSpec says it has %d bytes, but only %d "+
 				"bytes remain", subLen, len(data))
 		}
@@ -277,7 +298,10 @@ func (m *AddROSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -315,7 +339,10 @@ func (m *DeleteROSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -353,7 +380,10 @@ func (m *StartROSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -391,7 +421,10 @@ func (m *StopROSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -429,7 +462,10 @@ func (m *EnableROSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -467,7 +503,10 @@ func (m *DisableROSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -505,7 +544,10 @@ func (m *GetROSpecsResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -522,7 +564,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -557,7 +602,10 @@ func (m *AddAccessSpec) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamAccessSpec says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamAccessSpec says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -585,7 +633,10 @@ func (m *AddAccessSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -623,7 +674,10 @@ func (m *DeleteAccessSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -661,7 +715,10 @@ func (m *EnableAccessSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -699,7 +756,10 @@ func (m *DisableAccessSpecResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -737,7 +797,10 @@ func (m *GetAccessSpecsResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -754,7 +817,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -789,7 +855,10 @@ func (m *ClientRequestOp) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamTagReportData says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamTagReportData says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -817,7 +886,10 @@ func (m *ClientRequestOpResponse) UnmarshalBinary(data []byte) error {
 			"%v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamClientRequestResponse says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamClientRequestResponse says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -858,7 +930,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -925,7 +1000,10 @@ func (m *ReaderEventNotification) UnmarshalBinary(data []byte) error {
 			"found %v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamReaderEventNotificationData says it has "+
+				"%d bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamReaderEventNotificationData says it has "+
 				"%d bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -963,7 +1041,10 @@ func (m *ErrorMessage) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -999,7 +1080,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1034,7 +1118,10 @@ func (m *GetReaderConfigResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -1048,7 +1135,10 @@ func (m *GetReaderConfigResponse) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamIdentification {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamIdentification says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamIdentification says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -1066,7 +1156,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1096,7 +1189,10 @@ paramGroup3:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1135,7 +1231,10 @@ paramGroup4:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1162,7 +1261,10 @@ paramGroup4:
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamEventsAndReports {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamEventsAndReports says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamEventsAndReports says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -1178,7 +1280,10 @@ paramGroup6:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1215,7 +1320,10 @@ func (m *SetReaderConfig) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamReaderEventNotificationSpec {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamReaderEventNotificationSpec says it has "+
+				"%d bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamReaderEventNotificationSpec says it has "+
 				"%d bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -1233,7 +1341,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1263,7 +1374,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1294,7 +1408,10 @@ paramGroup3:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1321,7 +1438,10 @@ paramGroup3:
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamEventsAndReports {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamEventsAndReports says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamEventsAndReports says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -1337,7 +1457,10 @@ paramGroup5:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1372,7 +1495,10 @@ func (m *SetReaderConfigResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -1410,7 +1536,10 @@ func (m *CloseConnectionResponse) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamLLRPStatus says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamLLRPStatus says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -1672,7 +1801,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1693,7 +1825,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1717,7 +1852,10 @@ paramGroup1:
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamGPIOCapabilities says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamGPIOCapabilities says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -1731,7 +1869,10 @@ paramGroup3:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1752,7 +1893,10 @@ paramGroup3:
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamMaximumReceiveSensitivity {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamMaximumReceiveSensitivity says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamMaximumReceiveSensitivity says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -1847,7 +1991,10 @@ func (p *RegulatoryCapabilities) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamUHFBandCapabilities {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamUHFBandCapabilities says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamUHFBandCapabilities says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -1865,7 +2012,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1899,7 +2049,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -1923,7 +2076,10 @@ paramGroup0:
 			"%v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamFrequencyInformation says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamFrequencyInformation says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -1940,7 +2096,10 @@ paramGroup0:
 			"%v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamUHFC1G2RFModeTable says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamUHFC1G2RFModeTable says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -1954,7 +2113,10 @@ paramGroup0:
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamRFSurveyFrequencyCapabilities {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamRFSurveyFrequencyCapabilities says it "+
+				"has %d bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamRFSurveyFrequencyCapabilities says it "+
 				"has %d bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -1997,7 +2159,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2018,7 +2183,10 @@ paramGroup0:
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamFixedFrequencyTable {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamFixedFrequencyTable says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamFixedFrequencyTable says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -2112,7 +2280,10 @@ func (p *ROSpec) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamROBoundarySpec says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamROBoundarySpec says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -2129,7 +2300,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2165,7 +2339,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2201,7 +2378,10 @@ func (p *ROBoundarySpec) UnmarshalBinary(data []byte) error {
 			"%v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamROSpecStartTrigger says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamROSpecStartTrigger says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -2218,7 +2398,10 @@ func (p *ROBoundarySpec) UnmarshalBinary(data []byte) error {
 			"%v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamROSpecStopTrigger says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamROSpecStopTrigger says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -2250,7 +2433,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2291,7 +2477,10 @@ func (p *PeriodicTriggerValue) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamUTCTimestamp {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamUTCTimestamp says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamUTCTimestamp says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -2331,7 +2520,10 @@ func (p *ROSpecStopTrigger) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamGPITriggerValue {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamGPITriggerValue says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamGPITriggerValue says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -2375,7 +2567,10 @@ func (p *AISpec) UnmarshalBinary(data []byte) error {
 			"%v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamAISpecStopTrigger says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamAISpecStopTrigger says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -2389,7 +2584,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2413,7 +2611,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2453,7 +2654,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2510,7 +2714,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2554,7 +2761,10 @@ func (p *RFSurveySpec) UnmarshalBinary(data []byte) error {
 			"found %v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamRFSurveySpecStopTrigger says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamRFSurveySpecStopTrigger says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -2571,7 +2781,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2622,7 +2835,10 @@ func (p *AccessSpec) UnmarshalBinary(data []byte) error {
 			"%v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamAccessSpecStopTrigger says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamAccessSpecStopTrigger says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -2639,7 +2855,10 @@ func (p *AccessSpec) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamAccessCommand says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamAccessCommand says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -2653,7 +2872,10 @@ func (p *AccessSpec) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamAccessReportSpec {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamAccessReportSpec says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamAccessReportSpec says it has %d bytes, "+
 				"but only %d bytes remain", subLen, len(data))
 		}
@@ -2669,7 +2891,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2713,7 +2938,10 @@ func (p *AccessCommand) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamC1G2TagSpec says it has %d bytes, which "+
+				"is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamC1G2TagSpec says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -2730,7 +2958,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2796,7 +3027,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -2840,7 +3074,10 @@ func (p *ClientRequestResponse) UnmarshalBinary(data []byte) error {
 		return errors.Errorf("expected ParamEPCData, but found %v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamEPCData says it has %d bytes, which is "+
+				"less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamEPCData says it has %d bytes, but only "+
 				"%d bytes remain", subLen, len(data))
 		}
@@ -2857,7 +3094,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3006,7 +3246,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3037,7 +3280,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3114,7 +3360,10 @@ func (p *ROReportSpec) UnmarshalBinary(data []byte) error {
 			"found %v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamTagReportContentSelector says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamTagReportContentSelector says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -3131,7 +3380,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3176,7 +3428,10 @@ func (p *TagReportContentSelector) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamC1G2EPCMemorySelector {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamC1G2EPCMemorySelector says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamC1G2EPCMemorySelector says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -3194,7 +3449,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3246,7 +3504,10 @@ func (p *TagReportData) UnmarshalBinary(data []byte) error {
 		switch pt {
 		case ParamEPCData:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamEPCData says it has %d bytes, which is "+
+					"less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamEPCData says it has %d bytes, but only "+
 					"%d bytes remain", subLen, len(data))
 			}
@@ -3255,6 +3516,10 @@ func (p *TagReportData) UnmarshalBinary(data []byte) error {
 			}
 			data = data[subLen:]
 		case ParamEPC96:
+			if len(data) < 13 {
+				return errors.Errorf("ParamEPC96 needs 13 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			if err := p.EPC96.UnmarshalBinary(data[1:13]); err != nil {
 				return err
 			}
@@ -3282,74 +3547,141 @@ paramGroup1:
 		}
 		switch pt {
 		case ParamROSpecID:
+			if len(data) < 5 {
+				return errors.Errorf("ParamROSpecID needs 5 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.ROSpecID = new(ROSpecID)
 			*p.ROSpecID = ROSpecID(binary.BigEndian.Uint32(data[1:]))
 			data = data[5:]
 		case ParamSpecIndex:
+			if len(data) < 3 {
+				return errors.Errorf("ParamSpecIndex needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.SpecIndex = new(SpecIndex)
 			*p.SpecIndex = SpecIndex(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamInventoryParameterSpecID:
+			if len(data) < 3 {
+				return errors.Errorf("ParamInventoryParameterSpecID needs 3 "+
+					"bytes, but only %d bytes remain", len(data))
+			}
 			p.InventoryParameterSpecID = new(InventoryParameterSpecID)
 			*p.InventoryParameterSpecID = InventoryParameterSpecID(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamAntennaID:
+			if len(data) < 3 {
+				return errors.Errorf("ParamAntennaID needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.AntennaID = new(AntennaID)
 			*p.AntennaID = AntennaID(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamPeakRSSI:
+			if len(data) < 2 {
+				return errors.Errorf("ParamPeakRSSI needs 2 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.PeakRSSI = new(PeakRSSI)
 			*p.PeakRSSI = PeakRSSI(DecibelMilliwatt8(data[1]))
 			data = data[2:]
 		case ParamChannelIndex:
+			if len(data) < 3 {
+				return errors.Errorf("ParamChannelIndex needs 3 bytes, but only "+
+					"%d bytes remain", len(data))
+			}
 			p.ChannelIndex = new(ChannelIndex)
 			*p.ChannelIndex = ChannelIndex(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamFirstSeenUTC:
+			if len(data) < 9 {
+				return errors.Errorf("ParamFirstSeenUTC needs 9 bytes, but only "+
+					"%d bytes remain", len(data))
+			}
 			p.FirstSeenUTC = new(FirstSeenUTC)
 			*p.FirstSeenUTC = FirstSeenUTC(binary.BigEndian.Uint64(data[1:]))
 			data = data[9:]
 		case ParamFirstSeenUptime:
+			if len(data) < 9 {
+				return errors.Errorf("ParamFirstSeenUptime needs 9 bytes, but "+
+					"only %d bytes remain", len(data))
+			}
 			p.FirstSeenUptime = new(FirstSeenUptime)
 			*p.FirstSeenUptime = FirstSeenUptime(binary.BigEndian.Uint64(data[1:]))
 			data = data[9:]
 		case ParamLastSeenUTC:
+			if len(data) < 9 {
+				return errors.Errorf("ParamLastSeenUTC needs 9 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.LastSeenUTC = new(LastSeenUTC)
 			*p.LastSeenUTC = LastSeenUTC(binary.BigEndian.Uint64(data[1:]))
 			data = data[9:]
 		case ParamLastSeenUptime:
+			if len(data) < 9 {
+				return errors.Errorf("ParamLastSeenUptime needs 9 bytes, but only "+
+					"%d bytes remain", len(data))
+			}
 			p.LastSeenUptime = new(LastSeenUptime)
 			*p.LastSeenUptime = LastSeenUptime(binary.BigEndian.Uint64(data[1:]))
 			data = data[9:]
 		case ParamTagSeenCount:
+			if len(data) < 3 {
+				return errors.Errorf("ParamTagSeenCount needs 3 bytes, but only "+
+					"%d bytes remain", len(data))
+			}
 			p.TagSeenCount = new(TagSeenCount)
 			*p.TagSeenCount = TagSeenCount(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamC1G2PC:
+			if len(data) < 3 {
+				return errors.Errorf("ParamC1G2PC needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.C1G2PC = new(C1G2PC)
 			if err := p.C1G2PC.UnmarshalBinary(data[1:3]); err != nil {
 				return err
 			}
 			data = data[3:]
 		case ParamC1G2XPCW1:
+			if len(data) < 3 {
+				return errors.Errorf("ParamC1G2XPCW1 needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.C1G2XPCW1 = new(C1G2XPCW1)
 			*p.C1G2XPCW1 = C1G2XPCW1(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamC1G2XPCW2:
+			if len(data) < 3 {
+				return errors.Errorf("ParamC1G2XPCW2 needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.C1G2XPCW2 = new(C1G2XPCW2)
 			*p.C1G2XPCW2 = C1G2XPCW2(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamC1G2CRC:
+			if len(data) < 3 {
+				return errors.Errorf("ParamC1G2CRC needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.C1G2CRC = new(C1G2CRC)
 			*p.C1G2CRC = C1G2CRC(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamAccessSpecID:
+			if len(data) < 5 {
+				return errors.Errorf("ParamAccessSpecID needs 5 bytes, but only "+
+					"%d bytes remain", len(data))
+			}
 			p.AccessSpecID = new(AccessSpecID)
 			*p.AccessSpecID = AccessSpecID(binary.BigEndian.Uint32(data[1:]))
 			data = data[5:]
 		case ParamC1G2ReadOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2ReadOpSpecResult says it has %d "+
+					"bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2ReadOpSpecResult says it has %d "+
 					"bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3360,7 +3692,10 @@ paramGroup1:
 			data = data[subLen:]
 		case ParamC1G2WriteOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2WriteOpSpecResult says it has %d "+
+					"bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2WriteOpSpecResult says it has %d "+
 					"bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3371,7 +3706,10 @@ paramGroup1:
 			data = data[subLen:]
 		case ParamC1G2KillOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2KillOpSpecResult says it has %d "+
+					"bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2KillOpSpecResult says it has %d "+
 					"bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3382,7 +3720,10 @@ paramGroup1:
 			data = data[subLen:]
 		case ParamC1G2LockOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2LockOpSpecResult says it has %d "+
+					"bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2LockOpSpecResult says it has %d "+
 					"bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3393,7 +3734,10 @@ paramGroup1:
 			data = data[subLen:]
 		case ParamC1G2BlockEraseOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2BlockEraseOpSpecResult says it has "+
+					"%d bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2BlockEraseOpSpecResult says it has "+
 					"%d bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3404,7 +3748,10 @@ paramGroup1:
 			data = data[subLen:]
 		case ParamC1G2BlockWriteOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2BlockWriteOpSpecResult says it has "+
+					"%d bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2BlockWriteOpSpecResult says it has "+
 					"%d bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3415,7 +3762,10 @@ paramGroup1:
 			data = data[subLen:]
 		case ParamC1G2RecommissionOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2RecommissionOpSpecResult says it "+
+					"has %d bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2RecommissionOpSpecResult says it "+
 					"has %d bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3426,7 +3776,10 @@ paramGroup1:
 			data = data[subLen:]
 		case ParamC1G2BlockPermalockOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2BlockPermalockOpSpecResult says it "+
+					"has %d bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2BlockPermalockOpSpecResult says it "+
 					"has %d bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3437,7 +3790,10 @@ paramGroup1:
 			data = data[subLen:]
 		case ParamC1G2GetBlockPermalockStatusOpSpecResult:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamC1G2GetBlockPermalockStatusOpSpecResult says "+
+					"it has %d bytes, which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamC1G2GetBlockPermalockStatusOpSpecResult says "+
 					"it has %d bytes, but only %d bytes remain", subLen, len(data))
 			}
@@ -3447,6 +3803,10 @@ paramGroup1:
 			}
 			data = data[subLen:]
 		case ParamClientRequestOpSpecResult:
+			if len(data) < 3 {
+				return errors.Errorf("ParamClientRequestOpSpecResult needs 3 "+
+					"bytes, but only %d bytes remain", len(data))
+			}
 			p.ClientRequestOpSpecResult = new(ClientRequestOpSpecResult)
 			*p.ClientRequestOpSpecResult = ClientRequestOpSpecResult(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
@@ -3462,7 +3822,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3522,10 +3885,18 @@ paramGroup0:
 		pt := ParamType(data[0] & 0x7F)
 		switch pt {
 		case ParamROSpecID:
+			if len(data) < 5 {
+				return errors.Errorf("ParamROSpecID needs 5 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.ROSpecID = new(ROSpecID)
 			*p.ROSpecID = ROSpecID(binary.BigEndian.Uint32(data[1:]))
 			data = data[5:]
 		case ParamSpecIndex:
+			if len(data) < 3 {
+				return errors.Errorf("ParamSpecIndex needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.SpecIndex = new(SpecIndex)
 			*p.SpecIndex = SpecIndex(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
@@ -3538,7 +3909,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3562,7 +3936,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3601,7 +3978,10 @@ func (p *FrequencyRSSILevelEntry) UnmarshalBinary(data []byte) error {
 		switch pt {
 		case ParamUTCTimestamp:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamUTCTimestamp says it has %d bytes, "+
+					"which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamUTCTimestamp says it has %d bytes, but "+
 					"only %d bytes remain", subLen, len(data))
 			}
@@ -3609,7 +3989,10 @@ func (p *FrequencyRSSILevelEntry) UnmarshalBinary(data []byte) error {
 			data = data[subLen:]
 		case ParamUptime:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamUptime says it has %d bytes, which is "+
+					"less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamUptime says it has %d bytes, but only "+
 					"%d bytes remain", subLen, len(data))
 			}
@@ -3638,7 +4021,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3682,7 +4068,10 @@ func (p *ReaderEventNotificationData) UnmarshalBinary(data []byte) error {
 		switch pt {
 		case ParamUTCTimestamp:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamUTCTimestamp says it has %d bytes, "+
+					"which is less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamUTCTimestamp says it has %d bytes, but "+
 					"only %d bytes remain", subLen, len(data))
 			}
@@ -3690,7 +4079,10 @@ func (p *ReaderEventNotificationData) UnmarshalBinary(data []byte) error {
 			data = data[subLen:]
 		case ParamUptime:
 			subLen := binary.BigEndian.Uint16(data[2:])
-			if int(subLen) > len(data) {
+			if subLen < 4 {
+				return errors.Errorf("ParamUptime says it has %d bytes, which is "+
+					"less than its 4 byte header", subLen)
+			} else if int(subLen) > len(data) {
 				return errors.Errorf("ParamUptime says it has %d bytes, but only "+
 					"%d bytes remain", subLen, len(data))
 			}
@@ -3709,7 +4101,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3781,7 +4176,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3875,26 +4273,50 @@ paramGroup0:
 		pt := ParamType(data[0] & 0x7F)
 		switch pt {
 		case ParamROSpecID:
+			if len(data) < 5 {
+				return errors.Errorf("ParamROSpecID needs 5 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.ROSpecID = new(ROSpecID)
 			*p.ROSpecID = ROSpecID(binary.BigEndian.Uint32(data[1:]))
 			data = data[5:]
 		case ParamSpecIndex:
+			if len(data) < 3 {
+				return errors.Errorf("ParamSpecIndex needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.SpecIndex = new(SpecIndex)
 			*p.SpecIndex = SpecIndex(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamInventoryParameterSpecID:
+			if len(data) < 3 {
+				return errors.Errorf("ParamInventoryParameterSpecID needs 3 "+
+					"bytes, but only %d bytes remain", len(data))
+			}
 			p.InventoryParameterSpecID = new(InventoryParameterSpecID)
 			*p.InventoryParameterSpecID = InventoryParameterSpecID(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamAntennaID:
+			if len(data) < 3 {
+				return errors.Errorf("ParamAntennaID needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.AntennaID = new(AntennaID)
 			*p.AntennaID = AntennaID(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
 		case ParamAccessSpecID:
+			if len(data) < 5 {
+				return errors.Errorf("ParamAccessSpecID needs 5 bytes, but only "+
+					"%d bytes remain", len(data))
+			}
 			p.AccessSpecID = new(AccessSpecID)
 			*p.AccessSpecID = AccessSpecID(binary.BigEndian.Uint32(data[1:]))
 			data = data[5:]
 		case ParamOpSpecID:
+			if len(data) < 3 {
+				return errors.Errorf("ParamOpSpecID needs 3 bytes, but only %d "+
+					"bytes remain", len(data))
+			}
 			p.OpSpecID = new(OpSpecID)
 			*p.OpSpecID = OpSpecID(binary.BigEndian.Uint16(data[1:]))
 			data = data[3:]
@@ -3910,7 +4332,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -3957,10 +4382,15 @@ func (p *AISpecEvent) UnmarshalBinary(data []byte) error {
 		return nil
 	}
 	if subType := ParamType(data[0] & 0x7F); subType == ParamC1G2SingulationDetails {
+		if len(data) < 5 {
+			return errors.Errorf("ParamC1G2SingulationDetails needs 5 bytes, "+
+				"but only %d bytes remain", len(data))
+		}
 		p.SingulationDetails = new(C1G2SingulationDetails)
 		if err := p.SingulationDetails.UnmarshalBinary(data[1:5]); err != nil {
 			return err
 		}
+		data = data[5:]
 	}
 	if len(data) > 0 {
 		return errors.Errorf("finished reading AISpecEvent, but an "+
@@ -4020,7 +4450,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -4074,21 +4507,24 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
 		switch pt {
-		case ParamParameterError:
-			p.ParameterError = new(ParameterError)
-			if err := p.ParameterError.UnmarshalBinary(data[4:subLen]); err != nil {
-				return err
-			}
 		case ParamFieldError:
 			p.FieldError = new(FieldError)
 			if err := p.FieldError.UnmarshalBinary(data[4:subLen]); err != nil {
 				return err
 			}
+		case ParamParameterError:
+			p.ParameterError = new(ParameterError)
+			if err := p.ParameterError.UnmarshalBinary(data[4:subLen]); err != nil {
+				return err
+			}
 		default:
 			break paramGroup0
 		}
@@ -4127,7 +4563,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -4185,7 +4624,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -4209,7 +4651,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -4237,7 +4682,10 @@ paramGroup2:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -4273,7 +4721,10 @@ func (p *C1G2Filter) UnmarshalBinary(data []byte) error {
 			"%v", subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamC1G2TagInventoryMask says it has %d "+
+				"bytes, which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamC1G2TagInventoryMask says it has %d "+
 				"bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -4290,7 +4741,10 @@ paramGroup1:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
@@ -4386,7 +4840,11 @@ func (p *C1G2SingulationControl) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamC1G2TagInventoryStateAwareSingulationAction {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamC1G2TagInventoryStateAwareSingulationAction "+
+				"says it has %d bytes, which is less than its 4 byte header",
+				subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamC1G2TagInventoryStateAwareSingulationAction "+
 				"says it has %d bytes, but only %d bytes remain", subLen, len(data))
 		}
@@ -4424,7 +4882,10 @@ func (p *C1G2TagSpec) UnmarshalBinary(data []byte) error {
 			subType)
 	} else {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamC1G2TargetTag says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamC1G2TargetTag says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -4438,7 +4899,10 @@ func (p *C1G2TagSpec) UnmarshalBinary(data []byte) error {
 	}
 	if subType := ParamType(binary.BigEndian.Uint16(data)); subType == ParamC1G2TargetTag {
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("ParamC1G2TargetTag says it has %d bytes, "+
+				"which is less than its 4 byte header", subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("ParamC1G2TargetTag says it has %d bytes, but "+
 				"only %d bytes remain", subLen, len(data))
 		}
@@ -4562,7 +5026,10 @@ paramGroup0:
 	for len(data) >= 4 {
 		pt := ParamType(binary.BigEndian.Uint16(data))
 		subLen := binary.BigEndian.Uint16(data[2:])
-		if int(subLen) > len(data) {
+		if subLen < 4 {
+			return errors.Errorf("%v says it has %d bytes, which is less than "+
+				"its 4 byte header", pt, subLen)
+		} else if int(subLen) > len(data) {
 			return errors.Errorf("%v says it has %d bytes, but only %d bytes "+
 				"remain", pt, subLen, len(data))
 		}
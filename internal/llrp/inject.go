@@ -0,0 +1,191 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build llrp_inject
+// +build llrp_inject
+
+package llrp
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// FrameInjector wraps a Client's net.Conn so tests can feed raw frames
+// into the Client's read loop as if they came from the socket.
+//
+// It's only available in builds with the llrp_inject tag,
+// so it stays out of production builds:
+//
+//	go test -tags llrp_inject ./...
+//
+// The injector reads whole frames from the underlying connection
+// and interleaves injected frames between them,
+// so an injected frame never splits one from the Reader.
+// Injected frames are passed along unchanged,
+// so a frame with a bad header or length is seen exactly as the socket would present it.
+type FrameInjector struct {
+	net.Conn
+
+	frames chan injectedFrame
+	done   chan struct{}
+	once   sync.Once
+
+	// Deadlines apply to waiting for the next frame,
+	// not to the underlying connection, which the injector reads on its own.
+	mu       sync.Mutex
+	deadline time.Time
+
+	pending []byte // remainder of the frame currently being read
+	err     error  // set once the underlying connection fails
+}
+
+// injectedFrame is the next frame for the Client, or the error that ended the connection.
+type injectedFrame struct {
+	data []byte
+	err  error
+}
+
+// NewFrameInjector returns a FrameInjector wrapping the connection.
+// Pass it to Client.Connect in place of the connection.
+func NewFrameInjector(conn net.Conn) *FrameInjector {
+	fi := &FrameInjector{
+		Conn:   conn,
+		frames: make(chan injectedFrame),
+		done:   make(chan struct{}),
+	}
+	go fi.pump()
+	return fi
+}
+
+// Inject feeds the raw frame to the Client's read loop,
+// blocking until the Client starts reading it.
+// It returns an error if the injector is closed first.
+func (fi *FrameInjector) Inject(frame []byte) error {
+	select {
+	case fi.frames <- injectedFrame{data: frame}:
+		return nil
+	case <-fi.done:
+		return errors.Wrap(io.ErrClosedPipe, "failed to inject frame")
+	}
+}
+
+// InjectMessage encodes the message with the given version and ID,
+// then injects it as a frame.
+func (fi *FrameInjector) InjectMessage(version VersionNum, mid uint32, out Outgoing) error {
+	buf := bytes.Buffer{}
+	if err := newMsgWriter(&buf, version).Write(messageID(mid), out); err != nil {
+		return err
+	}
+	return fi.Inject(buf.Bytes())
+}
+
+// Read fills p from the current frame, waiting for the next one if necessary.
+func (fi *FrameInjector) Read(p []byte) (int, error) {
+	if len(fi.pending) == 0 {
+		if fi.err != nil {
+			return 0, fi.err
+		}
+
+		fi.mu.Lock()
+		deadline := fi.deadline
+		fi.mu.Unlock()
+
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			timer := time.NewTimer(time.Until(deadline))
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case f := <-fi.frames:
+			if f.err != nil {
+				fi.err = f.err
+				return 0, f.err
+			}
+			fi.pending = f.data
+		case <-timeout:
+			return 0, os.ErrDeadlineExceeded
+		case <-fi.done:
+			return 0, io.ErrClosedPipe
+		}
+	}
+
+	n := copy(p, fi.pending)
+	fi.pending = fi.pending[n:]
+	return n, nil
+}
+
+// SetDeadline sets the read deadline on the injector
+// and the write deadline on the underlying connection.
+func (fi *FrameInjector) SetDeadline(t time.Time) error {
+	_ = fi.SetReadDeadline(t)
+	return fi.Conn.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for waiting on the next frame.
+func (fi *FrameInjector) SetReadDeadline(t time.Time) error {
+	fi.mu.Lock()
+	fi.deadline = t
+	fi.mu.Unlock()
+	return nil
+}
+
+// Close stops the injector and closes the underlying connection.
+func (fi *FrameInjector) Close() error {
+	fi.once.Do(func() { close(fi.done) })
+	return fi.Conn.Close()
+}
+
+// pump reads whole frames from the underlying connection and passes them to Read
+// until the connection fails or the injector is closed.
+func (fi *FrameInjector) pump() {
+	for {
+		frame := make([]byte, HeaderSz)
+		n, err := io.ReadFull(fi.Conn, frame)
+		frame = frame[:n]
+
+		// If the header is bad, the Client discovers it for itself.
+		h := Header{}
+		if err == nil && h.UnmarshalBinary(frame) == nil {
+			payload := make([]byte, h.payloadLen)
+			n, err = io.ReadFull(fi.Conn, payload)
+			frame = append(frame, payload[:n]...)
+		}
+
+		// Pass along whatever arrived; the Client sees any error on its next Read.
+		if len(frame) > 0 && !fi.send(injectedFrame{data: frame}) {
+			return
+		}
+		if err != nil {
+			fi.send(injectedFrame{err: err})
+			return
+		}
+	}
+}
+
+// send passes the frame to Read, returning false if the injector is closed first.
+func (fi *FrameInjector) send(f injectedFrame) bool {
+	select {
+	case fi.frames <- f:
+		return true
+	case <-fi.done:
+		return false
+	}
+}
+
+// InjectFrames wraps the client side of the TestDevice's connection
+// in a FrameInjector and returns it. Call it before ConnectClient.
+func (td *TestDevice) InjectFrames() *FrameInjector {
+	fi := NewFrameInjector(td.cConn)
+	td.cConn = fi
+	return fi
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build llrp_inject
+// +build llrp_inject
+
+package llrp
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newInjectTestDevice returns a TestDevice impersonating a Reader
+// and a FrameInjector for its Client, which isn't yet connected.
+func newInjectTestDevice(t *testing.T) (*TestDevice, *FrameInjector) {
+	td, err := NewTestDevice(Version1_0_1, Version1_1, time.Second*1, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fi := td.InjectFrames()
+	go td.ImpersonateReader()
+	return td, fi
+}
+
+// waitReady waits until the Client finishes negotiating its version,
+// so that injected frames aren't mistaken for part of the negotiation.
+func waitReady(t *testing.T, c *Client) {
+	select {
+	case <-c.ready:
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the Client to connect")
+	}
+}
+
+func TestFrameInjector_notification(t *testing.T) {
+	td, fi := newInjectTestDevice(t)
+
+	events := make(chan *ReaderEventNotification, 1)
+	td.Client.handlers[MsgReaderEventNotification] = MessageHandlerFunc(func(_ *Client, msg Message) {
+		ren := &ReaderEventNotification{}
+		if err := msg.UnmarshalTo(ren); err != nil {
+			t.Errorf("%+v", err)
+		}
+		if ren.ReaderEventNotificationData.ConnectionAttemptEvent == nil {
+			events <- ren
+		}
+	})
+
+	c := td.ConnectClient(t)
+	waitReady(t, c)
+
+	if err := fi.InjectMessage(c.version, 100, &ReaderEventNotification{
+		ReaderEventNotificationData: ReaderEventNotificationData{
+			UTCTimestamp: UTCTimestamp(time.Now().UnixNano() / 1000),
+			AntennaEvent: &AntennaEvent{Event: AntennaDisconnected, AntennaID: 2},
+		}}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case ren := <-events:
+		ae := ren.ReaderEventNotificationData.AntennaEvent
+		if ae == nil || ae.Event != AntennaDisconnected || ae.AntennaID != 2 {
+			t.Errorf("expected antenna 2 to be disconnected; got %+v", ren.ReaderEventNotificationData)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the injected notification")
+	}
+}
+
+func TestFrameInjector_keepAlive(t *testing.T) {
+	td, fi := newInjectTestDevice(t)
+
+	acks := make(chan messageID, 1)
+	td.reader.handlers[MsgKeepAliveAck] = MessageHandlerFunc(func(_ *Client, msg Message) {
+		acks <- msg.id
+	})
+
+	c := td.ConnectClient(t)
+	waitReady(t, c)
+
+	if err := fi.InjectMessage(c.version, 7, &KeepAlive{}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case id := <-acks:
+		if id != 7 {
+			t.Errorf("expected the KeepAliveAck to have ID 7; got %d", id)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the KeepAliveAck")
+	}
+}
+
+func TestFrameInjector_unexpectedErrorMessage(t *testing.T) {
+	td, fi := newInjectTestDevice(t)
+	td.SetResponse(MsgGetReaderConfig, &GetReaderConfigResponse{})
+	c := td.ConnectClient(t)
+	waitReady(t, c)
+
+	// An ErrorMessage no one's waiting for is dropped, and the connection stays up.
+	if err := fi.InjectMessage(c.version, 12345, &ErrorMessage{LLRPStatus: LLRPStatus{
+		Status: StatusMsgMsgUnexpected,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	if err := c.SendFor(ctx, &GetReaderConfig{}, &GetReaderConfigResponse{}); err != nil {
+		t.Errorf("%+v", err)
+	}
+}
+
+func TestFrameInjector_malformedFrame(t *testing.T) {
+	td, fi := newInjectTestDevice(t)
+	c := td.Client
+
+	connErrs := make(chan error, 1)
+	go func() { connErrs <- c.Connect(td.cConn) }()
+	waitReady(t, c)
+
+	// The length is smaller than a header, so the Client can't tell where the next message starts.
+	if err := fi.Inject([]byte{byte(c.version) << 2, byte(MsgKeepAlive), 0, 0, 0, 1, 0, 0, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-connErrs:
+		if err == nil || errors.Is(err, ErrClientClosed) || !strings.Contains(err.Error(), "message length") {
+			t.Errorf("expected the malformed header to end the connection; got %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for the Client to fail")
+	}
+}
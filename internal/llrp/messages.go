@@ -15,6 +15,8 @@ import (
 	"github.com/pkg/errors"
 	"io"
 	"io/ioutil"
+	"strings"
+	"time"
 )
 
 const (
@@ -58,6 +60,8 @@ var mirrorType = map[MessageType]MessageType{
 	MsgGetReaderCapabilitiesResponse: MsgGetReaderCapabilities,
 	MsgGetReaderConfig:               MsgGetReaderConfigResponse,
 	MsgGetReaderConfigResponse:       MsgGetReaderConfig,
+	MsgGetROSpecs:                    MsgGetROSpecsResponse,
+	MsgGetROSpecsResponse:            MsgGetROSpecs,
 	MsgGetSupportedVersion:           MsgGetSupportedVersionResponse,
 	MsgGetSupportedVersionResponse:   MsgGetSupportedVersion,
 	MsgKeepAlive:                     MsgKeepAliveAck,
@@ -87,10 +91,42 @@ func (mt MessageType) Converse() (MessageType, bool) {
 	return t, ok
 }
 
+// ResponseType returns the MessageType a Reader replies with when a Client sends mt,
+// or the zero value and false if the Reader doesn't reply to it.
+//
+// Unlike Converse, it's only true for requests a Client sends:
+// responses and KeepAliveAcks don't get a reply,
+// and KeepAlives are sent by the Reader, not the Client.
+func (mt MessageType) ResponseType() (MessageType, bool) {
+	if mt == MsgKeepAlive || mt == MsgKeepAliveAck || strings.HasSuffix(mt.String(), "Response") {
+		return msgTypeInvalid, false
+	}
+	return mt.Converse()
+}
+
 // messageID is just a uint32, but aliased to make its purpose clear
 type messageID uint32
 
-type awaitMap = map[messageID]chan<- Message
+type awaitMap = map[messageID]awaiter
+
+// awaiter is a sender awaiting the reply to a request.
+type awaiter struct {
+	replyChan chan<- Message
+	replyType MessageType // the type of reply the request expects; msgTypeInvalid if unknown
+	reqType   MessageType // the type of the request
+	sent      time.Time   // when the request was sent
+}
+
+// accepts returns true if a message of type mt can be the reply the awaiter expects.
+// Readers may reply to any request with an ErrorMessage.
+func (a awaiter) accepts(mt MessageType) bool {
+	return a.replyType == msgTypeInvalid || mt == a.replyType || mt == MsgErrorMessage
+}
+
+// isReply returns true if mt is a type Readers only send in reply to a request.
+func isReply(mt MessageType) bool {
+	return mt == MsgErrorMessage || strings.HasSuffix(mt.String(), "Response")
+}
 
 // Header holds information about an LLRP message header.
 //
@@ -211,16 +247,29 @@ func validateHeader(payloadLen uint32, typ MessageType) error {
 type Message struct {
 	payload io.Reader
 	Header
+
+	discardLimit uint32 // if non-zero, the most unread payload bytes Close discards
 }
 
 // Close the message by discarding any remaining payload.
 // This returns an error if discarding fails.
 // It's safe to call this multiple times.
+//
+// If the message came from a Client with a discard limit
+// and more than that much payload remains unread,
+// this returns an error wrapping ErrDiscardLimit without reading any of it;
+// the Client then closes the connection after the handler returns.
 func (m Message) Close() error {
 	if _, isBuffer := m.payload.(byteProvider); m.payload == nil || isBuffer {
 		return nil
 	}
 
+	if lr, ok := m.payload.(*io.LimitedReader); ok {
+		if err := checkDiscardLimit(lr.N, m.discardLimit); err != nil {
+			return err
+		}
+	}
+
 	_, err := io.Copy(ioutil.Discard, m.payload)
 	if err != nil {
 		return errors.Wrap(err, "failed to discard payload")
@@ -234,19 +283,32 @@ func (m Message) Close() error {
 }
 
 // isResponseTo returns nil if reqType's expected response type matches m's type.
-// If it doesn't it returns an error with information about the mismatch.
+// If m is an ErrorMessage, it returns the Reader's error status instead.
+// It returns an error if reqType is not a request with a known response type.
 func (m Message) isResponseTo(reqType MessageType) error {
-	expectedRespType, ok := reqType.Converse()
+	expectedRespType, ok := reqType.ResponseType()
 	if !ok {
-		return errors.Errorf("unknown request type %d", reqType)
+		return errors.Errorf("%v has no known response type", reqType)
 	}
 
-	if m.typ != expectedRespType {
-		return errors.Errorf("response message type (%d) "+
-			"does not match request's expected response type (%d -> %d)",
-			m.typ, reqType, expectedRespType)
+	switch m.typ {
+	case expectedRespType:
+		return nil
+	case MsgErrorMessage:
+		em := ErrorMessage{}
+		if err := m.UnmarshalTo(&em); err != nil {
+			return errors.WithMessagef(err,
+				"expected %v in response to %v, but got an error message; "+
+					"however, it failed to unmarshal properly", expectedRespType, reqType)
+		}
+		if err := em.LLRPStatus.Err(); err != nil {
+			return errors.Wrapf(err,
+				"expected %v in response to %v, but got an error message", expectedRespType, reqType)
+		}
 	}
-	return nil
+
+	return errors.Errorf("expected %v in response to %v, but got %v",
+		expectedRespType, reqType, m.typ)
 }
 
 // newMessage prepares a message for sending.
@@ -308,16 +370,25 @@ func NewHdrOnlyMsg(typ MessageType) Message {
 
 // NewByteMessage uses a []byte payload to create a message.
 // The caller should not modify the slice until the message is sent.
+//
+// Unlike NewHdrOnlyMsg, this returns an error rather than panicking
+// if the type is not valid for an LLRP header,
+// so it's safe to use with message types supplied by a user.
 func NewByteMessage(typ MessageType, payload []byte) (m Message, err error) {
-	if len(payload) == 0 {
-		return NewHdrOnlyMsg(typ), nil
-	}
-
 	// check this here, since len(data) could overflow a uint32
 	if int64(len(payload)) > int64(maxPayloadSz) {
 		return Message{}, errors.New("LLRP messages are limited to 4GiB (minus a 10 byte header)")
 	}
 	n := uint32(len(payload))
+
+	if err := validateHeader(n, typ); err != nil {
+		return Message{}, err
+	}
+
+	if n == 0 {
+		return NewHdrOnlyMsg(typ), nil
+	}
+
 	return newMessage(bytes.NewReader(payload), n, typ), nil
 }
 
@@ -408,6 +479,107 @@ func (ros *ROSpec) Delete() *DeleteROSpec {
 	return &DeleteROSpec{ROSpecID: ros.ROSpecID}
 }
 
+// AntennaDwell configures how long a Reader inventories using one antenna
+// before moving on to the next.
+// If both DwellTime and InventoryRounds are set,
+// the Reader moves on when it reaches either limit.
+// If neither is set, the antenna's inventory only stops when the ROSpec does.
+type AntennaDwell struct {
+	AntennaID       AntennaID
+	DwellTime       Millisecs32 // milliseconds to inventory, if non-zero
+	InventoryRounds uint16      // number of inventory attempts, if non-zero
+}
+
+// StopTrigger returns an AISpecStopTrigger that implements the dwell.
+func (ad AntennaDwell) StopTrigger() AISpecStopTrigger {
+	switch {
+	case ad.InventoryRounds != 0:
+		// A Timeout of 0 means the attempts have no time limit.
+		return AISpecStopTrigger{
+			Trigger: AIStopTriggerTagObservation,
+			TagObservationTrigger: &TagObservationTrigger{
+				Trigger:          TagObsTriggerNAttempts,
+				NumberOfAttempts: ad.InventoryRounds,
+				Timeout:          ad.DwellTime,
+			},
+		}
+	case ad.DwellTime != 0:
+		return AISpecStopTrigger{
+			Trigger:              AIStopTriggerDuration,
+			DurationTriggerValue: ad.DwellTime,
+		}
+	default:
+		return AISpecStopTrigger{Trigger: AIStopTriggerNone}
+	}
+}
+
+// AccessSpecStopAfter returns an AccessSpecStopTrigger that stops an AccessSpec
+// once its OpSpecs have run the given number of times, after which the Reader deletes it.
+// Use 1 for a one-shot operation, such as writing a single tag.
+// The zero AccessSpecStopTrigger runs the AccessSpec until it's deleted.
+func AccessSpecStopAfter(count uint16) AccessSpecStopTrigger {
+	return AccessSpecStopTrigger{
+		Trigger:             AccessSpecStopTriggerOperationCount,
+		OperationCountValue: count,
+	}
+}
+
+// AllAntennas is the AntennaID meaning every antenna the Reader has.
+// In an AISpec's AntennaIDs, it must be the only ID.
+const AllAntennas = AntennaID(0)
+
+// inventoryParameterSpecs returns the InventoryParameterSpecs of the ROSpec's first AISpec,
+// or if it doesn't have one, a single C1G2 InventoryParameterSpec with ID 1.
+func (ros *ROSpec) inventoryParameterSpecs() []InventoryParameterSpec {
+	if len(ros.AISpecs) != 0 && len(ros.AISpecs[0].InventoryParameterSpecs) != 0 {
+		return ros.AISpecs[0].InventoryParameterSpecs
+	}
+	return []InventoryParameterSpec{{
+		InventoryParameterSpecID: 1,
+		AirProtocolID:            AirProtoEPCGlobalClass1Gen2,
+	}}
+}
+
+// SetAntennas sets the AntennaIDs of each of the ROSpec's AISpecs,
+// so the Reader inventories using those antennas,
+// or if called without any, using AllAntennas.
+// If the ROSpec has no AISpecs, it adds one that stops only when the ROSpec does,
+// with a single C1G2 InventoryParameterSpec with ID 1.
+func (ros *ROSpec) SetAntennas(ids ...AntennaID) {
+	if len(ids) == 0 {
+		ids = []AntennaID{AllAntennas}
+	}
+
+	if len(ros.AISpecs) == 0 {
+		ros.AISpecs = []AISpec{{
+			StopTrigger:             AISpecStopTrigger{Trigger: AIStopTriggerNone},
+			InventoryParameterSpecs: ros.inventoryParameterSpecs(),
+		}}
+	}
+
+	for i := range ros.AISpecs {
+		ros.AISpecs[i].AntennaIDs = append([]AntennaID(nil), ids...)
+	}
+}
+
+// SetAntennaDwell replaces the ROSpec's AISpecs with one per AntennaDwell, in order,
+// so the Reader inventories using each antenna in turn for its configured dwell.
+//
+// Each AISpec uses the InventoryParameterSpecs of the ROSpec's first AISpec,
+// or if it doesn't have one, a single C1G2 InventoryParameterSpec with ID 1.
+func (ros *ROSpec) SetAntennaDwell(dwells ...AntennaDwell) {
+	invSpecs := ros.inventoryParameterSpecs()
+
+	ros.AISpecs = make([]AISpec, len(dwells))
+	for i, ad := range dwells {
+		ros.AISpecs[i] = AISpec{
+			AntennaIDs:              []AntennaID{ad.AntennaID},
+			StopTrigger:             ad.StopTrigger(),
+			InventoryParameterSpecs: invSpecs,
+		}
+	}
+}
+
 type Encodable interface {
 	Incoming
 	Outgoing
@@ -473,7 +645,7 @@ func (mt MessageType) NewInstance() Encodable {
 	case MsgDisableAccessSpec:
 		u = &DisableAccessSpec{}
 	case MsgDisableAccessSpecResponse:
-		u = &DisableROSpecResponse{}
+		u = &DisableAccessSpecResponse{}
 	case MsgGetAccessSpecs:
 		u = &GetAccessSpecs{}
 	case MsgGetAccessSpecsResponse:
@@ -0,0 +1,258 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package llrp
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestAISpecStopTrigger_dwell checks the encoding of the stop triggers AntennaDwell generates.
+func TestAISpecStopTrigger_dwell(t *testing.T) {
+	for _, testCase := range []struct {
+		name    string
+		dwell   AntennaDwell
+		trigger AISpecStopTriggerType
+		encoded []byte // if set, the expected encoding, without the parameter header
+	}{
+		{name: "none", dwell: AntennaDwell{AntennaID: 1}, trigger: AIStopTriggerNone,
+			encoded: []byte{0x00, 0x00, 0x00, 0x00, 0x00}},
+		{name: "duration", dwell: AntennaDwell{AntennaID: 1, DwellTime: 500}, trigger: AIStopTriggerDuration,
+			encoded: []byte{0x01, 0x00, 0x00, 0x01, 0xf4}},
+		{name: "rounds", dwell: AntennaDwell{AntennaID: 2, InventoryRounds: 3},
+			trigger: AIStopTriggerTagObservation},
+		{name: "roundsAndDuration", dwell: AntennaDwell{AntennaID: 2, DwellTime: 250, InventoryRounds: 3},
+			trigger: AIStopTriggerTagObservation},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			p := testCase.dwell.StopTrigger()
+			if p.Trigger != testCase.trigger {
+				t.Errorf("expected trigger %v; got %v", testCase.trigger, p.Trigger)
+			}
+
+			if p.TagObservationTrigger != nil {
+				obs := p.TagObservationTrigger
+				if obs.Trigger != TagObsTriggerNAttempts ||
+					obs.NumberOfAttempts != testCase.dwell.InventoryRounds ||
+					obs.Timeout != testCase.dwell.DwellTime {
+					t.Errorf("TagObservationTrigger doesn't match dwell: %+v", obs)
+				}
+			}
+
+			b, err := p.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if testCase.encoded != nil && !bytes.Equal(b, testCase.encoded) {
+				t.Errorf("expected encoding\n%# 02x\ngot\n%# 02x", testCase.encoded, b)
+			}
+
+			var p2 AISpecStopTrigger
+			if err := p2.UnmarshalBinary(b); err != nil {
+				t.Errorf("%+v\n%# 02x\n%+v\n%+v", err, b, p, p2)
+			}
+			if !reflect.DeepEqual(p, p2) {
+				t.Errorf("mismatch:\n%# 02x\n%+v\n%+v", b, p, p2)
+			}
+		})
+	}
+}
+
+func TestROSpec_SetAntennaDwell(t *testing.T) {
+	ros := ROSpec{ROSpecID: 1}
+	ros.SetAntennaDwell(
+		AntennaDwell{AntennaID: 1, DwellTime: 500},
+		AntennaDwell{AntennaID: 2, InventoryRounds: 4},
+	)
+
+	if len(ros.AISpecs) != 2 {
+		t.Fatalf("expected 2 AISpecs; got %d", len(ros.AISpecs))
+	}
+
+	for i, ai := range ros.AISpecs {
+		if len(ai.AntennaIDs) != 1 || ai.AntennaIDs[0] != AntennaID(i+1) {
+			t.Errorf("AISpec %d has the wrong antennas: %v", i, ai.AntennaIDs)
+		}
+		if len(ai.InventoryParameterSpecs) != 1 ||
+			ai.InventoryParameterSpecs[0].AirProtocolID != AirProtoEPCGlobalClass1Gen2 {
+			t.Errorf("AISpec %d has unexpected InventoryParameterSpecs: %+v", i, ai.InventoryParameterSpecs)
+		}
+	}
+
+	// Existing InventoryParameterSpecs are kept.
+	invSpec := InventoryParameterSpec{InventoryParameterSpecID: 9, AirProtocolID: AirProtoEPCGlobalClass1Gen2}
+	ros.AISpecs[0].InventoryParameterSpecs = []InventoryParameterSpec{invSpec}
+	ros.SetAntennaDwell(AntennaDwell{AntennaID: 3, DwellTime: 100})
+
+	if len(ros.AISpecs) != 1 || !reflect.DeepEqual(ros.AISpecs[0].InventoryParameterSpecs,
+		[]InventoryParameterSpec{invSpec}) {
+		t.Errorf("expected InventoryParameterSpecs to be kept; got %+v", ros.AISpecs)
+	}
+
+	b, err := ros.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var ros2 ROSpec
+	if err := ros2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !reflect.DeepEqual(ros, ros2) {
+		t.Errorf("mismatch:\n%# 02x\n%+v\n%+v", b, ros, ros2)
+	}
+}
+
+func TestROSpec_SetAntennas(t *testing.T) {
+	// Without AISpecs, it adds one.
+	ros := ROSpec{ROSpecID: 1}
+	ros.SetAntennas()
+
+	if len(ros.AISpecs) != 1 || !reflect.DeepEqual(ros.AISpecs[0].AntennaIDs, []AntennaID{AllAntennas}) {
+		t.Fatalf("expected one AISpec using all antennas; got %+v", ros.AISpecs)
+	}
+	if len(ros.AISpecs[0].InventoryParameterSpecs) != 1 {
+		t.Errorf("expected a default InventoryParameterSpec; got %+v", ros.AISpecs[0].InventoryParameterSpecs)
+	}
+
+	// Otherwise, it sets the antennas of each one.
+	ros.AISpecs = append(ros.AISpecs, ros.AISpecs[0])
+	ros.SetAntennas(1, 2)
+
+	for i, ai := range ros.AISpecs {
+		if !reflect.DeepEqual(ai.AntennaIDs, []AntennaID{1, 2}) {
+			t.Errorf("AISpec %d has the wrong antennas: %v", i, ai.AntennaIDs)
+		}
+	}
+
+	// The AISpecs don't share the list.
+	ros.AISpecs[0].AntennaIDs[0] = 3
+	if ros.AISpecs[1].AntennaIDs[0] != 1 {
+		t.Errorf("expected AISpecs to have their own AntennaIDs; got %+v", ros.AISpecs)
+	}
+
+	b, err := ros.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var ros2 ROSpec
+	if err := ros2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !reflect.DeepEqual(ros, ros2) {
+		t.Errorf("mismatch:\n%# 02x\n%+v\n%+v", b, ros, ros2)
+	}
+}
+
+// TestMirrorType checks that every response type has a request type,
+// that the mapping is symmetric, and that NewInstance agrees with it.
+func TestMirrorType(t *testing.T) {
+	for mt := minMsgType; mt <= maxMsgType; mt++ {
+		if !mt.IsValid() || mt.NewInstance() == nil {
+			continue
+		}
+
+		if got := mt.NewInstance().Type(); got != mt {
+			t.Errorf("NewInstance of %v returned a %v", mt, got)
+		}
+
+		if strings.HasSuffix(mt.String(), "Response") {
+			if _, ok := mt.Converse(); !ok {
+				t.Errorf("%v has no request type", mt)
+			}
+		}
+	}
+
+	for mt, conv := range mirrorType {
+		if back, ok := conv.Converse(); !ok || back != mt {
+			t.Errorf("%v -> %v, but %v -> %v", mt, conv, conv, back)
+		}
+	}
+}
+
+func TestMessage_isResponseTo(t *testing.T) {
+	errMsg, err := (&ErrorMessage{
+		LLRPStatus: LLRPStatus{Status: StatusMsgParamError, ErrorDescription: "bad param"},
+	}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, testCase := range []struct {
+		name     string
+		req      MessageType
+		respType MessageType
+		respData []byte
+		contains string // if set, the error must contain this
+	}{
+		{name: "matches", req: MsgGetROSpecs, respType: MsgGetROSpecsResponse},
+		{name: "custom", req: MsgCustomMessage, respType: MsgCustomMessage},
+		{name: "mismatch", req: MsgGetROSpecs, respType: MsgGetAccessSpecsResponse,
+			contains: "but got MsgGetAccessSpecsResponse"},
+		{name: "errorMessage", req: MsgSetProtocolVersion, respType: MsgErrorMessage,
+			respData: errMsg, contains: "bad param"},
+		{name: "noResponseType", req: MsgGetReport, respType: MsgROAccessReport,
+			contains: "no known response type"},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			m, err := NewByteMessage(testCase.respType, testCase.respData)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = m.isResponseTo(testCase.req)
+			switch {
+			case testCase.contains == "" && err != nil:
+				t.Errorf("expected no error; got %+v", err)
+			case testCase.contains != "" && err == nil:
+				t.Errorf("expected an error containing %q", testCase.contains)
+			case err != nil && !strings.Contains(err.Error(), testCase.contains):
+				t.Errorf("expected an error containing %q; got %v", testCase.contains, err)
+			}
+		})
+	}
+}
+
+func TestMessageType_ResponseType(t *testing.T) {
+	for mt, expected := range map[MessageType]MessageType{
+		MsgGetROSpecs:         MsgGetROSpecsResponse,
+		MsgCustomMessage:      MsgCustomMessage,
+		MsgCloseConnection:    MsgCloseConnectionResponse,
+		MsgGetROSpecsResponse: msgTypeInvalid,
+		MsgKeepAlive:          msgTypeInvalid,
+		MsgKeepAliveAck:       msgTypeInvalid,
+		MsgGetReport:          msgTypeInvalid,
+		MsgROAccessReport:     msgTypeInvalid,
+	} {
+		resp, ok := mt.ResponseType()
+		if resp != expected || ok != (expected != msgTypeInvalid) {
+			t.Errorf("expected %v's response type to be %v; got %v, %t", mt, expected, resp, ok)
+		}
+	}
+}
+
+func TestSpecStates(t *testing.T) {
+	for state, name := range map[ROSpecCurrentStateType]string{
+		ROSpecStateDisabled: "Disabled",
+		ROSpecStateInactive: "Inactive",
+		ROSpecStateActive:   "Active",
+		3:                   "ROSpecCurrentStateType(3)",
+	} {
+		if state.String() != name {
+			t.Errorf("expected ROSpec state %d to be %s; got %s", state, name, state)
+		}
+	}
+
+	if s := (&AccessSpec{}).State(); s != AccessSpecStateDisabled || s.String() != "Disabled" {
+		t.Errorf("expected a Disabled AccessSpec; got %d (%v)", s, s)
+	}
+	if s := (&AccessSpec{IsActive: true}).State(); s != AccessSpecStateActive || s.String() != "Active" {
+		t.Errorf("expected an Active AccessSpec; got %d (%v)", s, s)
+	}
+}
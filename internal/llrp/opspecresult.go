@@ -0,0 +1,173 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package llrp
+
+import "strconv"
+
+// Results of a C1G2Read OpSpec.
+const (
+	C1G2ReadSuccess                = C1G2ReadOpSpecResultType(0)
+	C1G2ReadNonspecificTagError    = C1G2ReadOpSpecResultType(1)
+	C1G2ReadNoResponseFromTag      = C1G2ReadOpSpecResultType(2)
+	C1G2ReadNonspecificReaderError = C1G2ReadOpSpecResultType(3)
+	C1G2ReadMemoryOverrunError     = C1G2ReadOpSpecResultType(4)
+	C1G2ReadMemoryLockedError      = C1G2ReadOpSpecResultType(5)
+	C1G2ReadIncorrectPasswordError = C1G2ReadOpSpecResultType(6)
+)
+
+// Results of a C1G2Write OpSpec.
+const (
+	C1G2WriteSuccess                = C1G2WriteOpSpecResultType(0)
+	C1G2WriteMemoryOverrunError     = C1G2WriteOpSpecResultType(1)
+	C1G2WriteMemoryLockedError      = C1G2WriteOpSpecResultType(2)
+	C1G2WriteInsufficientPower      = C1G2WriteOpSpecResultType(3)
+	C1G2WriteNonspecificTagError    = C1G2WriteOpSpecResultType(4)
+	C1G2WriteNoResponseFromTag      = C1G2WriteOpSpecResultType(5)
+	C1G2WriteNonspecificReaderError = C1G2WriteOpSpecResultType(6)
+	C1G2WriteIncorrectPasswordError = C1G2WriteOpSpecResultType(7)
+)
+
+// Results of a C1G2Kill OpSpec.
+const (
+	C1G2KillSuccess                = C1G2KillResultType(0)
+	C1G2KillZeroKillPasswordError  = C1G2KillResultType(1)
+	C1G2KillInsufficientPower      = C1G2KillResultType(2)
+	C1G2KillNonspecificTagError    = C1G2KillResultType(3)
+	C1G2KillNoResponseFromTag      = C1G2KillResultType(4)
+	C1G2KillNonspecificReaderError = C1G2KillResultType(5)
+	C1G2KillIncorrectPasswordError = C1G2KillResultType(6)
+)
+
+// Results of a C1G2Lock OpSpec.
+const (
+	C1G2LockSuccess                = C1G2LockResultType(0)
+	C1G2LockInsufficientPower      = C1G2LockResultType(1)
+	C1G2LockNonspecificTagError    = C1G2LockResultType(2)
+	C1G2LockNoResponseFromTag      = C1G2LockResultType(3)
+	C1G2LockNonspecificReaderError = C1G2LockResultType(4)
+	C1G2LockIncorrectPasswordError = C1G2LockResultType(5)
+	C1G2LockMemoryOverrunError     = C1G2LockResultType(6)
+	C1G2LockMemoryLockedError      = C1G2LockResultType(7)
+)
+
+// Results of a C1G2BlockErase OpSpec.
+const (
+	C1G2BlockEraseSuccess                = C1G2BlockEraseResultType(0)
+	C1G2BlockEraseMemoryOverrunError     = C1G2BlockEraseResultType(1)
+	C1G2BlockEraseMemoryLockedError      = C1G2BlockEraseResultType(2)
+	C1G2BlockEraseInsufficientPower      = C1G2BlockEraseResultType(3)
+	C1G2BlockEraseNonspecificTagError    = C1G2BlockEraseResultType(4)
+	C1G2BlockEraseNoResponseFromTag      = C1G2BlockEraseResultType(5)
+	C1G2BlockEraseNonspecificReaderError = C1G2BlockEraseResultType(6)
+	C1G2BlockEraseIncorrectPasswordError = C1G2BlockEraseResultType(7)
+)
+
+// Results of a C1G2BlockWrite OpSpec.
+const (
+	C1G2BlockWriteSuccess                = C1G2BlockWriteResultType(0)
+	C1G2BlockWriteMemoryOverrunError     = C1G2BlockWriteResultType(1)
+	C1G2BlockWriteMemoryLockedError      = C1G2BlockWriteResultType(2)
+	C1G2BlockWriteInsufficientPower      = C1G2BlockWriteResultType(3)
+	C1G2BlockWriteNonspecificTagError    = C1G2BlockWriteResultType(4)
+	C1G2BlockWriteNoResponseFromTag      = C1G2BlockWriteResultType(5)
+	C1G2BlockWriteNonspecificReaderError = C1G2BlockWriteResultType(6)
+	C1G2BlockWriteIncorrectPasswordError = C1G2BlockWriteResultType(7)
+)
+
+// Results of a C1G2Recommission OpSpec.
+const (
+	C1G2RecommissionSuccess                = C1G2RecommissionResultType(0)
+	C1G2RecommissionZeroKillPasswordError  = C1G2RecommissionResultType(1)
+	C1G2RecommissionInsufficientPower      = C1G2RecommissionResultType(2)
+	C1G2RecommissionNonspecificTagError    = C1G2RecommissionResultType(3)
+	C1G2RecommissionNoResponseFromTag      = C1G2RecommissionResultType(4)
+	C1G2RecommissionNonspecificReaderError = C1G2RecommissionResultType(5)
+	C1G2RecommissionIncorrectPasswordError = C1G2RecommissionResultType(6)
+)
+
+// Results of a C1G2GetBlockPermalockStatus OpSpec.
+const (
+	C1G2GetBPLockStatusSuccess                = C1G2GetBlockPermalockStatusResultType(0)
+	C1G2GetBPLockStatusNonspecificTagError    = C1G2GetBlockPermalockStatusResultType(1)
+	C1G2GetBPLockStatusNoResponseFromTag      = C1G2GetBlockPermalockStatusResultType(2)
+	C1G2GetBPLockStatusNonspecificReaderError = C1G2GetBlockPermalockStatusResultType(3)
+	C1G2GetBPLockStatusIncorrectPasswordError = C1G2GetBlockPermalockStatusResultType(4)
+	C1G2GetBPLockStatusMemoryOverrunError     = C1G2GetBlockPermalockStatusResultType(5)
+)
+
+// The names of OpSpec results, indexed by their values.
+// Several OpSpecs share a set of results, but not always in the same order.
+var (
+	readResultNames = [...]string{
+		"Success", "NonspecificTagError", "NoResponseFromTag", "NonspecificReaderError",
+		"MemoryOverrunError", "MemoryLockedError", "IncorrectPasswordError",
+	}
+	writeResultNames = [...]string{
+		"Success", "MemoryOverrunError", "MemoryLockedError", "InsufficientPower",
+		"NonspecificTagError", "NoResponseFromTag", "NonspecificReaderError", "IncorrectPasswordError",
+	}
+	killResultNames = [...]string{
+		"Success", "ZeroKillPasswordError", "InsufficientPower",
+		"NonspecificTagError", "NoResponseFromTag", "NonspecificReaderError", "IncorrectPasswordError",
+	}
+	lockResultNames = [...]string{
+		"Success", "InsufficientPower", "NonspecificTagError", "NoResponseFromTag",
+		"NonspecificReaderError", "IncorrectPasswordError", "MemoryOverrunError", "MemoryLockedError",
+	}
+	bpLockResultNames = [...]string{
+		"Success", "InsufficientPower", "NonspecificTagError", "NoResponseFromTag",
+		"NonspecificReaderError", "IncorrectPasswordError", "MemoryOverrunError",
+	}
+	getBPLockStatusResultNames = [...]string{
+		"Success", "NonspecificTagError", "NoResponseFromTag", "NonspecificReaderError",
+		"IncorrectPasswordError", "MemoryOverrunError",
+	}
+)
+
+// resultName returns names[result], or if the result is out of range,
+// the type name followed by the result's value in parentheses, as stringer does.
+func resultName(names []string, typeName string, result uint8) string {
+	if int(result) < len(names) {
+		return names[result]
+	}
+	return typeName + "(" + strconv.FormatInt(int64(result), 10) + ")"
+}
+
+func (r C1G2ReadOpSpecResultType) String() string {
+	return resultName(readResultNames[:], "C1G2ReadOpSpecResultType", uint8(r))
+}
+
+func (r C1G2WriteOpSpecResultType) String() string {
+	return resultName(writeResultNames[:], "C1G2WriteOpSpecResultType", uint8(r))
+}
+
+func (r C1G2KillResultType) String() string {
+	return resultName(killResultNames[:], "C1G2KillResultType", uint8(r))
+}
+
+func (r C1G2LockResultType) String() string {
+	return resultName(lockResultNames[:], "C1G2LockResultType", uint8(r))
+}
+
+func (r C1G2BlockEraseResultType) String() string {
+	return resultName(writeResultNames[:], "C1G2BlockEraseResultType", uint8(r))
+}
+
+func (r C1G2BlockWriteResultType) String() string {
+	return resultName(writeResultNames[:], "C1G2BlockWriteResultType", uint8(r))
+}
+
+func (r C1G2RecommissionResultType) String() string {
+	return resultName(killResultNames[:], "C1G2RecommissionResultType", uint8(r))
+}
+
+func (r C1G2BlockPermalockResultType) String() string {
+	return resultName(bpLockResultNames[:], "C1G2BlockPermalockResultType", uint8(r))
+}
+
+func (r C1G2GetBlockPermalockStatusResultType) String() string {
+	return resultName(getBPLockStatusResultNames[:], "C1G2GetBlockPermalockStatusResultType", uint8(r))
+}
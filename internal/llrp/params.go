@@ -5,11 +5,15 @@
 
 //go:generate python3 generate_param_code.py -i messages.yaml -s generated_structs.go -t binary_test.go -m generated_marshal.go -u generated_unmarshal.go -e generated_encoder.go
 //go:generate stringer -type=ParamType,ConnectionAttemptEventType,StatusCode,AirProtocolIDType
+//go:generate stringer -type=AntennaEventType -trimprefix=Antenna -output=antennaeventtype_string.go
 
 package llrp
 
 import (
+	"encoding/hex"
+	"net"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -168,6 +172,16 @@ func (pt ParamType) IsValid() bool {
 	return 0 < pt && pt <= 2047 && !(paramResvStart <= pt && pt <= paramResvEnd)
 }
 
+// IsValid returns true if the AccessReportTriggerType is one LLRP defines.
+func (t AccessReportTriggerType) IsValid() bool {
+	return t == AccessReportWithROReport || t == AccessReportEndOfAccessSpec
+}
+
+// IsValid returns true if the AccessSpecStopTriggerType is one LLRP defines.
+func (t AccessSpecStopTriggerType) IsValid() bool {
+	return t == AccessSpecStopTriggerNone || t == AccessSpecStopTriggerOperationCount
+}
+
 const (
 	statusMsgStart    = StatusMsgParamError
 	statusMsgEnd      = StatusMsgMsgUnexpected
@@ -254,26 +268,43 @@ var statusDeviceErrs = [...]string{
 }
 var _ = statusDeviceErrs[statusDeviceEnd-statusDeviceStart] // compile error == missing message
 
+// Error returns the field's index and the reason the Reader rejected it.
 func (fe FieldError) Error() string {
-	return fe.ErrorCode.defaultText() + " at index " + strconv.Itoa(int(fe.FieldIndex))
+	return "field " + strconv.Itoa(int(fe.FieldIndex)) + ": " + fe.ErrorCode.defaultText()
 }
 
 // Error constructs a string from the parameter's error.
+//
+// If the error is nested within other ParameterErrors or has a FieldError,
+// it follows the chain to the innermost error,
+// so the result reads like a path to the rejected parameter or field, e.g.
+// "parameter ROSpec, parameter ROReportSpec, field 0: invalid value".
 func (pe *ParameterError) Error() string {
-	msg := pe.ErrorCode.defaultText() + " " + pe.ParameterType.String()
-	if pe.ParameterType.IsValid() {
-		msg += " (type " + strconv.Itoa(int(pe.ParameterType)) + ")"
-	}
+	msg := "parameter " + pe.ParameterType.name()
 
-	if pe.FieldError != nil {
-		msg += ": " + pe.FieldError.Error()
+	switch {
+	case pe.ParameterError != nil:
+		return msg + ", " + pe.ParameterError.Error()
+	case pe.FieldError != nil:
+		return msg + ", " + pe.FieldError.Error()
 	}
 
-	if pe.ParameterError != nil {
-		msg += ": " + pe.ParameterError.Error()
+	return msg + ": " + pe.ErrorCode.defaultText()
+}
+
+// name returns the ParamType's name without its "Param" prefix,
+// or its type number if it's not a known parameter type.
+func (pt ParamType) name() string {
+	if !pt.isKnown() {
+		return "type " + strconv.Itoa(int(pt))
 	}
+	return strings.TrimPrefix(pt.String(), "Param")
+}
 
-	return msg
+// isKnown returns true if the ParamType is one this package can decode.
+func (pt ParamType) isKnown() bool {
+	s := pt.String()
+	return pt.IsValid() && strings.HasPrefix(s, "Param") && !strings.HasPrefix(s, "ParamType(")
 }
 
 // StatusError is an LLRPStatus that implements the Error interface.
@@ -283,8 +314,8 @@ type StatusError LLRPStatus
 
 // Error implements the error interface for a StatusError.
 //
-// It returns the ErrorDescription set by the Reader,
-// and appends to it
+// It returns the text for the StatusCode and the ErrorDescription set by the Reader,
+// and appends to it the FieldError or chain of ParameterErrors, if present.
 func (se *StatusError) Error() string {
 	msg := se.Status.defaultText()
 	if se.ErrorDescription != "" {
@@ -316,3 +347,72 @@ func (ls *LLRPStatus) Err() error {
 	se := StatusError(*ls)
 	return &se
 }
+
+// Warning returns the details a Reader included with a successful LLRPStatus,
+// or an empty string if there aren't any or the Status isn't Success.
+// Some Readers use these to report marginal conditions that didn't fail the request,
+// such as an antenna they couldn't activate when enabling an ROSpec.
+func (ls *LLRPStatus) Warning() string {
+	if ls.Status != StatusSuccess {
+		return ""
+	}
+
+	var details []string
+	if ls.ErrorDescription != "" {
+		details = append(details, ls.ErrorDescription)
+	}
+	if ls.FieldError != nil {
+		details = append(details, ls.FieldError.Error())
+	}
+	if ls.ParameterError != nil {
+		details = append(details, ls.ParameterError.Error())
+	}
+	return strings.Join(details, ": ")
+}
+
+// String returns the ReaderID in the format of its IDType:
+// colon-separated hex octets for MAC-based IDs, like "00:16:25:ff:fe:12:34:56",
+// and plain hex for EPC-based IDs, like the TagEPCs of tag reads.
+// Unknown IDTypes are formatted as hex.
+func (id Identification) String() string {
+	if id.IDType == ID_MAC_EUI64 && len(id.ReaderID) != 0 {
+		return net.HardwareAddr(id.ReaderID).String()
+	}
+	return hex.EncodeToString(id.ReaderID)
+}
+
+// EPC returns the tag's EPC, whichever way the Reader reported it:
+// as an EPC96, which Readers may use for 96-bit EPCs,
+// or as EPCData, which holds EPCs of any length.
+//
+// EPCData holds EPCNumBits bits, padded to a whole number of bytes;
+// if the Reader set any of the padding bits, they're cleared in the result
+// so that the same EPC always yields the same bytes.
+// If the read has no EPC, this returns nil.
+func (tr *TagReportData) EPC() []byte {
+	if len(tr.EPC96.EPC) != 0 {
+		return tr.EPC96.EPC
+	}
+
+	epc := tr.EPCData.EPC
+	pad := uint(len(epc)*8) - uint(tr.EPCData.EPCNumBits)
+	if len(epc) == 0 || pad == 0 || pad >= 8 || epc[len(epc)-1]&(1<<pad-1) == 0 {
+		return epc
+	}
+
+	masked := make([]byte, len(epc))
+	copy(masked, epc)
+	masked[len(masked)-1] &^= 1<<pad - 1
+	return masked
+}
+
+// Word returns the tag's 16-bit Protocol Control word as it's stored in EPC memory:
+// the EPC length in words, the user memory, XPC, and numbering system indicators,
+// and the Attribute Bits or ISO AFI.
+func (pc C1G2PC) Word() uint16 {
+	return uint16(pc.EPCMemoryLength)<<11 |
+		uint16(b2b(pc.HasUserMemory))<<10 |
+		uint16(b2b(pc.HasXPC))<<9 |
+		uint16(b2b(pc.IsISO15961))<<8 |
+		uint16(pc.AttributesOrAFI)
+}
@@ -6,7 +6,10 @@
 package llrp
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/json"
+	"fmt"
 	"testing"
 )
 
@@ -111,3 +114,657 @@ func TestReaderEventNotification_UnmarshalBinary(t *testing.T) {
 		t.Fatalf("expected ConnFailedReasonUnknown; got %+v", ren.ReaderEventNotificationData.ConnectionAttemptEvent)
 	}
 }
+
+func TestAISpecEvent_UnmarshalBinary_singulationDetails(t *testing.T) {
+	data := []byte{
+		0x0,                // AISpecEnded
+		0x0, 0x0, 0x0, 0x3, // ROSpecID
+		0x0, 0x1, // SpecIndex
+
+		18 | 0x80, // C1G2SingulationDetails TV
+		0x0, 0x4,  // NumCollisionSlots
+		0x0, 0x9, // NumEmptySlots
+	}
+
+	ais := AISpecEvent{}
+	if err := ais.UnmarshalBinary(data); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if ais.ROSpecID != 3 || ais.SpecIndex != 1 || ais.SingulationDetails == nil ||
+		*ais.SingulationDetails != (C1G2SingulationDetails{NumCollisionSlots: 4, NumEmptySlots: 9}) {
+		t.Errorf("expected singulation details with 4 collision and 9 empty slots; got %+v", ais)
+	}
+}
+
+func TestLLRPStatus_UnmarshalBinary_errorChain(t *testing.T) {
+	data := []byte{
+		0x0, 100, // StatusMsgParamError
+		0x0, 0x3, // ErrorDescription length
+		'b', 'a', 'd',
+
+		0x1, 0x21, // ParameterError
+		0x0, 24, // length
+		0x0, 177, // ROSpec
+		0x0, 200, // StatusParamParamError
+
+		/**/ 0x1, 0x21, // ParameterError
+		/**/ 0x0, 16, // length
+		/**/ 0x0, 237, // ROReportSpec
+		/**/ 0x0, 201, // StatusParamFieldError
+
+		/*    */ 0x1, 0x20, // FieldError
+		/*    */ 0x0, 0x8, // length
+		/*    */ 0x0, 0x1, // FieldIndex
+		/*    */ 0x1, 0x2c, // StatusFieldInvalid
+	}
+
+	ls := LLRPStatus{}
+	if err := ls.UnmarshalBinary(data); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	pe := ls.ParameterError
+	if pe == nil || pe.ParameterType != ParamROSpec || pe.ErrorCode != StatusParamParamError {
+		t.Fatalf("expected ROSpec ParameterError; got %+v", pe)
+	}
+
+	pe = pe.ParameterError
+	if pe == nil || pe.ParameterType != ParamROReportSpec || pe.ErrorCode != StatusParamFieldError {
+		t.Fatalf("expected ROReportSpec ParameterError; got %+v", pe)
+	}
+
+	fe := pe.FieldError
+	if fe == nil || fe.FieldIndex != 1 || fe.ErrorCode != StatusFieldInvalid {
+		t.Fatalf("expected FieldError at index 1; got %+v", fe)
+	}
+
+	err := ls.Err()
+	if err == nil {
+		t.Fatal("expected a StatusError, but didn't get one")
+	}
+
+	exp := "message parameter error: bad: " +
+		"parameter ROSpec, parameter ROReportSpec, field 1: invalid value"
+	if err.Error() != exp {
+		t.Errorf("expected %q; got %q", exp, err.Error())
+	}
+}
+
+func TestStatusError_Error(t *testing.T) {
+	for _, tc := range []struct {
+		status LLRPStatus
+		exp    string
+	}{
+		{
+			status: LLRPStatus{Status: StatusDeviceError},
+			exp:    "the Reader encountered a problem unrelated to the message",
+		},
+		{
+			status: LLRPStatus{
+				Status:     StatusMsgFieldError,
+				FieldError: &FieldError{FieldIndex: 2, ErrorCode: StatusFieldOutOfRange},
+			},
+			exp: "message field error: field 2: value out of range",
+		},
+		{
+			status: LLRPStatus{
+				Status: StatusMsgParamError,
+				ParameterError: &ParameterError{
+					ParameterType: ParamAISpec,
+					ErrorCode:     StatusParamParamMissing,
+				},
+			},
+			exp: "message parameter error: parameter AISpec: missing required sub-parameter",
+		},
+		{
+			status: LLRPStatus{
+				Status: StatusMsgParamError,
+				ParameterError: &ParameterError{
+					ParameterType: ParamType(2000),
+					ErrorCode:     StatusParamParamUnknown,
+				},
+			},
+			exp: "message parameter error: parameter type 2000: unknown sub-parameter",
+		},
+	} {
+		tc := tc
+		t.Run(tc.exp, func(t *testing.T) {
+			err := tc.status.Err()
+			if err == nil {
+				t.Fatal("expected a StatusError, but didn't get one")
+			}
+
+			if err.Error() != tc.exp {
+				t.Errorf("expected %q; got %q", tc.exp, err.Error())
+			}
+		})
+	}
+}
+
+func TestLLRPStatus_Warning(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status LLRPStatus
+		exp    string
+	}{
+		{name: "plain success", status: LLRPStatus{Status: StatusSuccess}},
+		{
+			name:   "failure",
+			status: LLRPStatus{Status: StatusDeviceError, ErrorDescription: "antenna 2 disconnected"},
+		},
+		{
+			name:   "description",
+			status: LLRPStatus{Status: StatusSuccess, ErrorDescription: "antenna 2 disconnected"},
+			exp:    "antenna 2 disconnected",
+		},
+		{
+			name: "parameter",
+			status: LLRPStatus{
+				Status:           StatusSuccess,
+				ErrorDescription: "partially enabled",
+				ParameterError: &ParameterError{
+					ParameterType: ParamAntennaID,
+					ErrorCode:     StatusParamParamUnsupported,
+				},
+			},
+			exp: "partially enabled: parameter AntennaID: unsupported sub-parameter",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.status.Err(); (err != nil) != (tc.status.Status != StatusSuccess) {
+				t.Errorf("expected warnings not to be errors; got %v", err)
+			}
+
+			if w := tc.status.Warning(); w != tc.exp {
+				t.Errorf("expected %q; got %q", tc.exp, w)
+			}
+		})
+	}
+}
+
+func TestStatusCode_String(t *testing.T) {
+	for _, tc := range []struct {
+		code StatusCode
+		name string
+		text string
+	}{
+		{code: StatusSuccess, name: "StatusSuccess", text: "success"},
+		{code: StatusMsgParamError, name: "StatusMsgParamError", text: "message parameter error"},
+		{code: StatusMsgFieldError, name: "StatusMsgFieldError", text: "message field error"},
+		{code: StatusMsgMsgUnsupported, name: "StatusMsgMsgUnsupported", text: "message type unsupported"},
+		{code: StatusMsgVerUnsupported, name: "StatusMsgVerUnsupported", text: "LLRP version not supported"},
+		{code: StatusParamParamError, name: "StatusParamParamError", text: "error in sub-parameter"},
+		{code: StatusParamParamMissing, name: "StatusParamParamMissing", text: "missing required sub-parameter"},
+		{code: StatusFieldInvalid, name: "StatusFieldInvalid", text: "invalid value"},
+		{code: StatusFieldOutOfRange, name: "StatusFieldOutOfRange", text: "value out of range"},
+		{code: StatusDeviceError, name: "StatusDeviceError", text: "the Reader encountered a problem unrelated to the message"},
+		{code: StatusCode(999), name: "StatusCode(999)", text: "unknown LLRP status code 999"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.code.String() != tc.name {
+				t.Errorf("expected name %q; got %q", tc.name, tc.code.String())
+			}
+
+			if tc.code.defaultText() != tc.text {
+				t.Errorf("expected text %q; got %q", tc.text, tc.code.defaultText())
+			}
+		})
+	}
+}
+
+func TestIdentification_String(t *testing.T) {
+	for _, testCase := range []struct {
+		id       Identification
+		expected string
+	}{
+		{Identification{IDType: ID_MAC_EUI64, ReaderID: []byte{0x00, 0x16, 0x25, 0xff, 0xfe, 0x12, 0x34, 0x56}}, "00:16:25:ff:fe:12:34:56"},
+		{Identification{IDType: ID_MAC_EUI64, ReaderID: []byte{0x00, 0x16, 0x25, 0x12, 0x34, 0x56}}, "00:16:25:12:34:56"},
+		{Identification{IDType: ID_EPC, ReaderID: []byte{0x30, 0x08, 0x33, 0xb2, 0xdd, 0xd9, 0x01, 0x40, 0, 0, 0, 1}}, "300833b2ddd9014000000001"},
+		{Identification{IDType: 7, ReaderID: []byte{0xab}}, "ab"},
+		{Identification{IDType: ID_MAC_EUI64}, ""},
+	} {
+		if s := testCase.id.String(); s != testCase.expected {
+			t.Errorf("expected %+v to be %q; got %q", testCase.id, testCase.expected, s)
+		}
+	}
+}
+
+// epcDataParam returns an EPCData parameter with the given number of bits.
+func epcDataParam(nBits uint16, epc ...byte) []byte {
+	p := make([]byte, 6, 6+len(epc))
+	binary.BigEndian.PutUint16(p, uint16(ParamEPCData))
+	binary.BigEndian.PutUint16(p[2:], uint16(6+len(epc)))
+	binary.BigEndian.PutUint16(p[4:], nBits)
+	return append(p, epc...)
+}
+
+func TestTagReportData_EPC(t *testing.T) {
+	epc96 := []byte{0x30, 0x08, 0x33, 0xb2, 0xdd, 0xd9, 0x01, 0x40, 0x00, 0x00, 0x00, 0x01}
+	epc128 := append(append([]byte{}, epc96...), 0xde, 0xad, 0xbe, 0xef)
+	antenna := []byte{0x81, 0x00, 0x02} // AntennaID 2, so the EPC isn't the last parameter
+
+	for _, testCase := range []struct {
+		name string
+		data []byte
+		epc  []byte
+	}{
+		{name: "EPC96", data: append([]byte{0x80 | byte(ParamEPC96)}, epc96...), epc: epc96},
+		{name: "EPCData96", data: epcDataParam(96, epc96...), epc: epc96},
+		{name: "EPCData128", data: epcDataParam(128, epc128...), epc: epc128},
+		{name: "EPCData64", data: epcDataParam(64, epc96[:8]...), epc: epc96[:8]},
+		{name: "EPCData100", data: epcDataParam(100, append(epc96, 0xA0)...), epc: append(epc96, 0xA0)},
+		{name: "EPCDataPadding", data: epcDataParam(100, append(epc96, 0xAF)...), epc: append(epc96, 0xA0)},
+		{name: "EPCDataEmpty", data: epcDataParam(0)},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			var tr TagReportData
+			if err := tr.UnmarshalBinary(append(testCase.data, antenna...)); err != nil {
+				t.Fatalf("%+v", err)
+			}
+
+			if epc := tr.EPC(); !bytes.Equal(epc, testCase.epc) {
+				t.Errorf("expected EPC %x; got %x", testCase.epc, epc)
+			}
+			if tr.AntennaID == nil || *tr.AntennaID != 2 {
+				t.Errorf("expected the parameters after the EPC to be decoded; got %+v", tr)
+			}
+		})
+	}
+
+	// Malformed EPCs must be rejected, not mis-decoded.
+	for _, testCase := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "EPC96Truncated", data: append([]byte{0x80 | byte(ParamEPC96)}, epc96[:5]...)},
+		{name: "EPCDataTruncated", data: epcDataParam(128, epc96...)},
+		{name: "EPCDataExtra", data: epcDataParam(64, epc96...)},
+		{name: "EPCDataShortHeader", data: []byte{0x00, byte(ParamEPCData), 0x00, 0x02, 0x00, 0x00}},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			var tr TagReportData
+			if err := tr.UnmarshalBinary(testCase.data); err == nil {
+				t.Errorf("expected an error; got %+v", tr)
+			}
+		})
+	}
+}
+
+// tlvParam returns a TLV parameter of the given type wrapping the parts.
+func tlvParam(pt ParamType, parts ...[]byte) []byte {
+	var payload []byte
+	for _, p := range parts {
+		payload = append(payload, p...)
+	}
+	b := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint16(b, uint16(pt))
+	binary.BigEndian.PutUint16(b[2:], uint16(4+len(payload)))
+	return append(b, payload...)
+}
+
+// customParam returns a Custom parameter with an arbitrary VendorID.
+func customParam(subtype uint32, data ...byte) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b, 25882)
+	binary.BigEndian.PutUint32(b[4:], subtype)
+	return tlvParam(ParamCustom, b, data)
+}
+
+func TestROSpec_customRoundTrip(t *testing.T) {
+	// An ROSpec with Custom parameters at each level that allows them,
+	// as a Reader might return it with vendor extensions.
+	roSpec := tlvParam(ParamROSpec,
+		[]byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x00}, // ID 1, priority 0, disabled
+		tlvParam(ParamROBoundarySpec,
+			tlvParam(ParamROSpecStartTrigger, []byte{0x00}),
+			tlvParam(ParamROSpecStopTrigger, []byte{0x00, 0x00, 0x00, 0x00, 0x00}),
+		),
+		tlvParam(ParamAISpec,
+			[]byte{0x00, 0x01, 0x00, 0x00}, // one AntennaID: 0
+			tlvParam(ParamAISpecStopTrigger, []byte{0x00, 0x00, 0x00, 0x00, 0x00}),
+			tlvParam(ParamInventoryParameterSpec,
+				[]byte{0x00, 0x01, 0x01}, // ID 1, EPCGlobalClass1Gen2
+				tlvParam(ParamAntennaConfiguration,
+					[]byte{0x00, 0x00},
+					tlvParam(ParamC1G2InventoryCommand,
+						[]byte{0x00},
+						customParam(1, 0x11),
+					),
+					customParam(2, 0x22, 0x22),
+				),
+				customParam(3),
+			),
+			customParam(4, 0x44),
+		),
+		customParam(5, 0x55, 0x55, 0x55),
+		tlvParam(ParamROReportSpec,
+			[]byte{0x01, 0x00, 0x01}, // report every tag
+			tlvParam(ParamTagReportContentSelector,
+				[]byte{0xFF, 0xC0},
+				customParam(6, 0x66),
+			),
+			customParam(7),
+		),
+	)
+
+	var add AddROSpec
+	if err := add.UnmarshalBinary(roSpec); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	ro := add.ROSpec
+	ips := ro.AISpecs[0].InventoryParameterSpecs[0]
+	ac := ips.AntennaConfigurations[0]
+	for _, c := range []struct {
+		name    string
+		customs []Custom
+		subtype uint32
+		data    []byte
+	}{
+		{"C1G2InventoryCommand", ac.C1G2InventoryCommand.Custom, 1, []byte{0x11}},
+		{"AntennaConfiguration", ac.Custom, 2, []byte{0x22, 0x22}},
+		{"InventoryParameterSpec", ips.Custom, 3, nil},
+		{"AISpec", ro.AISpecs[0].Custom, 4, []byte{0x44}},
+		{"ROSpec", ro.Custom, 5, []byte{0x55, 0x55, 0x55}},
+		{"TagReportContentSelector", ro.ROReportSpec.TagReportContentSelector.Custom, 6, []byte{0x66}},
+		{"ROReportSpec", ro.ROReportSpec.Custom, 7, nil},
+	} {
+		if len(c.customs) != 1 {
+			t.Errorf("expected one Custom in the %s; got %+v", c.name, c.customs)
+			continue
+		}
+		if got := c.customs[0]; got.VendorID != 25882 || got.Subtype != c.subtype ||
+			!bytes.Equal(got.Data, c.data) {
+			t.Errorf("expected the %s's Custom to have subtype %d and data %x; got %+v",
+				c.name, c.subtype, c.data, got)
+		}
+	}
+
+	encoded, err := add.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !bytes.Equal(encoded, roSpec) {
+		t.Errorf("expected the ROSpec to re-encode unchanged\nexpected: %x\n     got: %x", roSpec, encoded)
+	}
+
+	// Specs reach the service as JSON, so Customs must survive that, too.
+	j, err := json.Marshal(add)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var fromJSON AddROSpec
+	if err := json.Unmarshal(j, &fromJSON); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	encoded, err = fromJSON.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !bytes.Equal(encoded, roSpec) {
+		t.Errorf("expected the ROSpec to survive a JSON round trip unchanged\nexpected: %x\n     got: %x", roSpec, encoded)
+	}
+}
+
+func TestROAccessReport_readOpSpecResultRoundTrip(t *testing.T) {
+	// Two tag reads with the results of an AccessSpec's C1G2Read:
+	// one read two words of TID memory, and the other failed.
+	report := append(
+		tlvParam(ParamTagReportData,
+			[]byte{13 | 0x80}, // EPC96
+			[]byte{0x30, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA},
+			[]byte{16 | 0x80, 0x00, 0x00, 0x00, 0x07}, // AccessSpecID 7
+			tlvParam(ParamC1G2ReadOpSpecResult,
+				[]byte{0x00},       // Success
+				[]byte{0x00, 0x01}, // OpSpecID 1
+				[]byte{0x00, 0x02}, // two words
+				[]byte{0xE2, 0x80, 0x11, 0x05},
+			),
+		),
+		tlvParam(ParamTagReportData,
+			[]byte{13 | 0x80}, // EPC96
+			[]byte{0x30, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xBB},
+			[]byte{16 | 0x80, 0x00, 0x00, 0x00, 0x07}, // AccessSpecID 7
+			tlvParam(ParamC1G2ReadOpSpecResult,
+				[]byte{0x06},       // Incorrect password error
+				[]byte{0x00, 0x01}, // OpSpecID 1
+				[]byte{0x00, 0x00}, // no words
+			),
+		)...,
+	)
+
+	var ro ROAccessReport
+	if err := ro.UnmarshalBinary(report); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if len(ro.TagReportData) != 2 {
+		t.Fatalf("expected 2 TagReportData; got %+v", ro.TagReportData)
+	}
+
+	for i, expected := range []C1G2ReadOpSpecResult{
+		{C1G2ReadOpSpecResultType: 0, OpSpecID: 1, Data: []uint16{0xE280, 0x1105}},
+		{C1G2ReadOpSpecResultType: 6, OpSpecID: 1},
+	} {
+		tr := ro.TagReportData[i]
+		if tr.AccessSpecID == nil || *tr.AccessSpecID != 7 {
+			t.Errorf("tag read %d: expected AccessSpecID 7; got %v", i, tr.AccessSpecID)
+		}
+
+		got := tr.C1G2ReadOpSpecResult
+		if got == nil || got.C1G2ReadOpSpecResultType != expected.C1G2ReadOpSpecResultType ||
+			got.OpSpecID != expected.OpSpecID || len(got.Data) != len(expected.Data) {
+			t.Errorf("tag read %d: expected %+v; got %+v", i, expected, got)
+			continue
+		}
+		for j := range expected.Data {
+			if got.Data[j] != expected.Data[j] {
+				t.Errorf("tag read %d: expected %+v; got %+v", i, expected, got)
+				break
+			}
+		}
+	}
+
+	encoded, err := ro.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !bytes.Equal(encoded, report) {
+		t.Errorf("expected the report to re-encode unchanged\nexpected: %x\n     got: %x", report, encoded)
+	}
+
+	// Reports reach EdgeX as JSON, so the results must survive that, too.
+	j, err := json.Marshal(ro)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	var fromJSON ROAccessReport
+	if err := json.Unmarshal(j, &fromJSON); err != nil {
+		t.Fatalf("%+v", err)
+	}
+	encoded, err = fromJSON.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if !bytes.Equal(encoded, report) {
+		t.Errorf("expected the report to survive a JSON round trip unchanged\nexpected: %x\n     got: %x", report, encoded)
+	}
+}
+
+func TestTagReportData_seenTimestampVariants(t *testing.T) {
+	epc := append([]byte{13 | 0x80}, // EPC96
+		0x30, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA)
+	firstUTC := []byte{2 | 0x80, 0, 0, 0, 0, 0, 0, 0, 0x10}
+	firstUptime := []byte{3 | 0x80, 0, 0, 0, 0, 0, 0, 0, 0x20}
+	lastUTC := []byte{4 | 0x80, 0, 0, 0, 0, 0, 0, 0, 0x30}
+	lastUptime := []byte{5 | 0x80, 0, 0, 0, 0, 0, 0, 0, 0x40}
+
+	u64 := func(v uint64) *uint64 { return &v }
+	for _, testCase := range []struct {
+		name   string
+		params [][]byte
+		// expected FirstSeenUTC, FirstSeenUptime, LastSeenUTC, and LastSeenUptime
+		expected [4]*uint64
+	}{
+		{name: "utc", params: [][]byte{firstUTC, lastUTC},
+			expected: [4]*uint64{u64(0x10), nil, u64(0x30), nil}},
+		{name: "uptime", params: [][]byte{firstUptime, lastUptime},
+			expected: [4]*uint64{nil, u64(0x20), nil, u64(0x40)}},
+		{name: "all", params: [][]byte{firstUTC, firstUptime, lastUTC, lastUptime},
+			expected: [4]*uint64{u64(0x10), u64(0x20), u64(0x30), u64(0x40)}},
+		{name: "firstUptimeLastUTC", params: [][]byte{firstUptime, lastUTC},
+			expected: [4]*uint64{nil, u64(0x20), u64(0x30), nil}},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			data := tlvParam(ParamTagReportData, append([][]byte{epc}, testCase.params...)...)
+
+			var ro ROAccessReport
+			if err := ro.UnmarshalBinary(data); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if len(ro.TagReportData) != 1 {
+				t.Fatalf("expected 1 TagReportData; got %+v", ro.TagReportData)
+			}
+
+			tr := &ro.TagReportData[0]
+			got := [4]*uint64{(*uint64)(tr.FirstSeenUTC), (*uint64)(tr.FirstSeenUptime),
+				(*uint64)(tr.LastSeenUTC), (*uint64)(tr.LastSeenUptime)}
+			for i := range got {
+				if (got[i] == nil) != (testCase.expected[i] == nil) ||
+					(got[i] != nil && *got[i] != *testCase.expected[i]) {
+					t.Errorf("timestamp %d: expected %v; got %v", i, testCase.expected[i], got[i])
+				}
+			}
+
+			encoded, err := ro.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if !bytes.Equal(encoded, data) {
+				t.Errorf("expected the report to re-encode unchanged\nexpected: %x\n     got: %x", data, encoded)
+			}
+		})
+	}
+}
+
+func TestTagReportData_c1g2PCAndCRC(t *testing.T) {
+	epc := append([]byte{13 | 0x80}, // EPC96
+		0x30, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA)
+	pc := []byte{12 | 0x80, 0x34, 0x21}  // 6 words, user memory, Attribute Bits 0x21
+	crc := []byte{11 | 0x80, 0xBE, 0xEF} // C1G2CRC
+
+	for _, testCase := range []struct {
+		name   string
+		params [][]byte
+		pc     *C1G2PC
+		crc    *C1G2CRC
+	}{
+		{name: "neither"},
+		{name: "pc", params: [][]byte{pc},
+			pc: &C1G2PC{EPCMemoryLength: 6, HasUserMemory: true, AttributesOrAFI: 0x21}},
+		{name: "crc", params: [][]byte{crc}, crc: func() *C1G2CRC { c := C1G2CRC(0xBEEF); return &c }()},
+		{name: "both", params: [][]byte{pc, crc},
+			pc:  &C1G2PC{EPCMemoryLength: 6, HasUserMemory: true, AttributesOrAFI: 0x21},
+			crc: func() *C1G2CRC { c := C1G2CRC(0xBEEF); return &c }()},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			data := tlvParam(ParamTagReportData, append([][]byte{epc}, testCase.params...)...)
+
+			var ro ROAccessReport
+			if err := ro.UnmarshalBinary(data); err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if len(ro.TagReportData) != 1 {
+				t.Fatalf("expected 1 TagReportData; got %+v", ro.TagReportData)
+			}
+
+			tr := &ro.TagReportData[0]
+			if (tr.C1G2PC == nil) != (testCase.pc == nil) ||
+				(tr.C1G2PC != nil && *tr.C1G2PC != *testCase.pc) {
+				t.Errorf("expected C1G2PC %+v; got %+v", testCase.pc, tr.C1G2PC)
+			}
+			if tr.C1G2PC != nil && tr.C1G2PC.Word() != 0x3421 {
+				t.Errorf("expected PC word 3421; got %04x", tr.C1G2PC.Word())
+			}
+			if (tr.C1G2CRC == nil) != (testCase.crc == nil) ||
+				(tr.C1G2CRC != nil && *tr.C1G2CRC != *testCase.crc) {
+				t.Errorf("expected C1G2CRC %v; got %v", testCase.crc, tr.C1G2CRC)
+			}
+
+			encoded, err := ro.MarshalBinary()
+			if err != nil {
+				t.Fatalf("%+v", err)
+			}
+			if !bytes.Equal(encoded, data) {
+				t.Errorf("expected the report to re-encode unchanged\nexpected: %x\n     got: %x", data, encoded)
+			}
+		})
+	}
+}
+
+func TestC1G2PC_Word(t *testing.T) {
+	for _, pc := range []C1G2PC{
+		{},
+		{EPCMemoryLength: 31, HasUserMemory: true, HasXPC: true, IsISO15961: true, AttributesOrAFI: 0xFF},
+		{EPCMemoryLength: 6, HasXPC: true, AttributesOrAFI: 0x01},
+		{EPCMemoryLength: 8, IsISO15961: true, AttributesOrAFI: 0xA2},
+	} {
+		encoded, err := pc.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%+v", err)
+		}
+
+		// The encoded parameter is the word, big-endian.
+		if word := uint16(encoded[0])<<8 | uint16(encoded[1]); pc.Word() != word {
+			t.Errorf("%+v: expected word %04x; got %04x", pc, word, pc.Word())
+		}
+	}
+}
+
+func TestOpSpecResult_String(t *testing.T) {
+	for _, tc := range []struct {
+		result fmt.Stringer
+		name   string
+	}{
+		{result: C1G2ReadSuccess, name: "Success"},
+		{result: C1G2ReadNoResponseFromTag, name: "NoResponseFromTag"},
+		{result: C1G2ReadIncorrectPasswordError, name: "IncorrectPasswordError"},
+		{result: C1G2WriteInsufficientPower, name: "InsufficientPower"},
+		{result: C1G2WriteIncorrectPasswordError, name: "IncorrectPasswordError"},
+		{result: C1G2KillZeroKillPasswordError, name: "ZeroKillPasswordError"},
+		{result: C1G2LockMemoryLockedError, name: "MemoryLockedError"},
+		{result: C1G2BlockEraseNonspecificReaderError, name: "NonspecificReaderError"},
+		{result: C1G2BlockWriteMemoryOverrunError, name: "MemoryOverrunError"},
+		{result: C1G2RecommissionNonspecificTagError, name: "NonspecificTagError"},
+		{result: C1G2BPLockIncorrectPassword, name: "IncorrectPasswordError"},
+		{result: C1G2GetBPLockStatusMemoryOverrunError, name: "MemoryOverrunError"},
+		{result: C1G2ReadOpSpecResultType(7), name: "C1G2ReadOpSpecResultType(7)"},
+		{result: C1G2LockResultType(200), name: "C1G2LockResultType(200)"},
+	} {
+		if got := tc.result.String(); got != tc.name {
+			t.Errorf("expected %q; got %q", tc.name, got)
+		}
+	}
+}
+
+func TestAntennaEventType_String(t *testing.T) {
+	for _, tc := range []struct {
+		event AntennaEventType
+		name  string
+	}{
+		{event: AntennaDisconnected, name: "Disconnected"},
+		{event: AntennaConnected, name: "Connected"},
+		{event: AntennaEventType(2), name: "AntennaEventType(2)"},
+	} {
+		if got := tc.event.String(); got != tc.name {
+			t.Errorf("expected %q; got %q", tc.name, got)
+		}
+	}
+}
@@ -78,6 +78,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -98,6 +99,12 @@ type Client struct {
 	ready          chan struct{}  // closed when the connection is negotiated
 	isClosed       uint32         // used atomically to prevent duplicate closure of done
 	version        VersionNum     // sent in headers; established during negotiation
+	firstMsgID     messageID      // the ID given to the first message the Client generates an ID for
+
+	onVersionMismatch VersionMismatchFunc // if non-nil, called for messages with the wrong version
+	rejectMismatch    bool                // if true, close the connection on messages with the wrong version
+
+	discardLimit uint32 // if non-zero, the most unread payload bytes the Client drains; see WithDiscardLimit
 }
 
 const (
@@ -209,6 +216,66 @@ func WithTimeout(d time.Duration) ClientOpt {
 	})
 }
 
+// WithMessageIDStart sets the ID the Client assigns to the first message it sends.
+//
+// Message IDs correlate requests with their responses.
+// By default, they start at 0 and count up on each message,
+// wrapping around at the max uint32 and skipping any still awaiting a reply.
+// Setting the starting ID can make it easier to correlate messages
+// across multiple connections, e.g., in a packet capture.
+func WithMessageIDStart(id uint32) ClientOpt {
+	return clientOpt(func(c *Client) {
+		c.firstMsgID = messageID(id)
+	})
+}
+
+// WithDiscardLimit limits how much unread payload the Client discards
+// to move past a message, such as a huge ROAccessReport no one is listening for
+// or one whose handler returned before reading all of it.
+//
+// The Client must read each message in full before it can read the next,
+// and by default, it reads and discards whatever is left, however long that takes,
+// during which it can't process any other message, including replies.
+// With a limit, if more than that many bytes remain, the Client closes the connection instead,
+// and Connect returns an error wrapping ErrDiscardLimit.
+// That trades waiting out a pathological message for reconnecting to the Reader,
+// which drops any other messages it sent in the meantime,
+// so the limit should comfortably exceed the largest message you expect to ignore.
+// Likewise, Message.Close returns an error wrapping ErrDiscardLimit
+// rather than draining a message with too much left.
+//
+// A limit of 0, the default, means the Client always discards the remaining payload.
+func WithDiscardLimit(limit uint32) ClientOpt {
+	return clientOpt(func(c *Client) {
+		c.discardLimit = limit
+	})
+}
+
+// VersionMismatchFunc is called when a Client receives a message
+// whose header version differs from the version negotiated for the connection.
+//
+// It's called from the Client's read loop before the message is handled,
+// so it must not block.
+type VersionMismatchFunc func(c *Client, hdr Header, negotiated VersionNum)
+
+// WithVersionCheck checks the header version of the messages the Client receives
+// once the connection's version is negotiated,
+// which catches Readers that don't honor SetProtocolVersion.
+//
+// For each message with a different version, the Client calls onMismatch, if it's non-nil.
+// If reject is false, it then processes the message as usual.
+// Otherwise, rather than risk misinterpreting the message,
+// it closes the connection, and Connect returns an error wrapping ErrVersionMismatch.
+//
+// Replies to version negotiation messages and ErrorMessages are exempt,
+// as Readers may legitimately send those with a different version.
+func WithVersionCheck(reject bool, onMismatch VersionMismatchFunc) ClientOpt {
+	return clientOpt(func(c *Client) {
+		c.rejectMismatch = reject
+		c.onVersionMismatch = onMismatch
+	})
+}
+
 // ClientLogger is used by the Client to notify the user of certain events.
 // By default, new Clients log these message with the StdLogger,
 // but that can be changed via WithLogger.
@@ -217,6 +284,7 @@ type ClientLogger interface {
 	SendingMsg(Header)              // called just before writing a message to the connection
 	MsgHandled(Header)              // called after a message is sent to a handler or awaiting reply listener
 	MsgUnhandled(Header)            // called if a message is discarded because it had no handler or listener
+	MsgUnmatched(Header)            // called if a reply doesn't match a request awaiting that ID and type
 	HandlerPanic(Header, error)     // called if a handler panics while handling a message
 }
 
@@ -319,6 +387,7 @@ func (devNullLogger) ReceivedMsg(Header, VersionNum) {}
 func (devNullLogger) SendingMsg(Header)              {}
 func (devNullLogger) MsgHandled(Header)              {}
 func (devNullLogger) MsgUnhandled(Header)            {}
+func (devNullLogger) MsgUnmatched(Header)            {}
 func (devNullLogger) HandlerPanic(Header, error)     {}
 
 // StdLogger wraps the Go stdlib Logger.
@@ -352,6 +421,10 @@ func (l *StdLogger) MsgUnhandled(hdr Header) {
 	l.Printf("no handler for message{%v}", hdr)
 }
 
+func (l *StdLogger) MsgUnmatched(hdr Header) {
+	l.Printf("warning: reply doesn't match an outstanding request: message{%v}", hdr)
+}
+
 func (l *StdLogger) HandlerPanic(hdr Header, err error) {
 	l.Printf("error: recovered from panic while handling message{%v}: %v", hdr, err)
 }
@@ -361,6 +434,16 @@ var (
 	// indicating that Shutdown or Close was called.
 	// It may be wrapped, so to check for it, use errors.Is.
 	ErrClientClosed = goErrs.New("client closed")
+
+	// ErrVersionMismatch is returned by Connect if the Client rejects a message
+	// because its version differs from the one negotiated for the connection.
+	// See WithVersionCheck for more information.
+	ErrVersionMismatch = goErrs.New("message version mismatch")
+
+	// ErrDiscardLimit is returned by Connect and Message.Close
+	// if discarding a message would mean reading more than the Client's discard limit.
+	// See WithDiscardLimit for more information.
+	ErrDiscardLimit = goErrs.New("message exceeds discard limit")
 )
 
 // Connect to an LLRP-capable device and start processing messages.
@@ -573,10 +656,18 @@ func (c *Client) SendFor(ctx context.Context, out Outgoing, in Incoming) error {
 // it will return an error wrapping ErrClientClosed.
 // If the message does not require a payload,
 // you may pass an empty or nil data buffer.
+// If the type isn't valid for an LLRP header, this returns an error.
 //
 // Also see SendFor, which makes it easier to send specific LLRP messages
 // and SendNoWait, which sends a message without expecting a response.
 func (c *Client) SendMessage(ctx context.Context, typ MessageType, data []byte) (MessageType, []byte, error) {
+	// Validate the message before waiting on the connection,
+	// since there's no point in waiting to send something invalid.
+	mOut, err := NewByteMessage(typ, data)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	select {
 	case <-c.ready: // ensure the connection is negotiated
 	case <-c.done:
@@ -587,19 +678,7 @@ func (c *Client) SendMessage(ctx context.Context, typ MessageType, data []byte)
 
 	// It's possible one of the other two select channels is also ready,
 	// but it'll they'll get checked again within send,
-	// so adding another check here is just unnecessary expense
-	// that would only avoid a rather cheap allocation.
-
-	var mOut Message
-	if len(data) == 0 {
-		mOut = NewHdrOnlyMsg(typ)
-	} else {
-		var err error
-		mOut, err = NewByteMessage(typ, data)
-		if err != nil {
-			return 0, nil, err
-		}
-	}
+	// so adding another check here is just unnecessary expense.
 
 	resp, err := c.send(ctx, mOut)
 	if err != nil {
@@ -614,6 +693,40 @@ func (c *Client) SendMessage(ctx context.Context, typ MessageType, data []byte)
 	return resp.typ, respData, nil
 }
 
+// PendingRequest is a request a Client sent that's still awaiting its reply.
+type PendingRequest struct {
+	MessageID uint32
+	Type      MessageType
+	Sent      time.Time
+}
+
+// PendingRequests returns the requests the Client sent that are still awaiting replies,
+// oldest first, or nil if there aren't any.
+//
+// A request stops awaiting its reply when the reply arrives
+// or its sender stops waiting for it, e.g. because its context ended,
+// so requests a Reader hasn't answered in a long time suggest it stopped responding.
+func (c *Client) PendingRequests() []PendingRequest {
+	c.awaitMu.Lock()
+	defer c.awaitMu.Unlock()
+
+	if len(c.awaiting) == 0 {
+		return nil
+	}
+
+	pending := make([]PendingRequest, 0, len(c.awaiting))
+	for mid, a := range c.awaiting {
+		pending = append(pending, PendingRequest{MessageID: uint32(mid), Type: a.reqType, Sent: a.sent})
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].Sent.Equal(pending[j].Sent) {
+			return pending[i].Sent.Before(pending[j].Sent)
+		}
+		return pending[i].MessageID < pending[j].MessageID
+	})
+	return pending
+}
+
 // SendNoWait sends a message without awaiting the reply.
 //
 // It still blocks until the message is sent,
@@ -722,21 +835,62 @@ func (c *Client) handleIncoming() error {
 
 		c.logger.ReceivedMsg(hdr, c.version)
 
+		if err := c.checkVersion(hdr); err != nil {
+			return err
+		}
+
 		if hdr.typ == MsgCloseConnectionResponse {
 			receivedClosed = true
 		}
 
 		err = c.passToHandler(hdr)
-		switch err {
-		case nil:
-		case io.EOF:
-			return errors.Wrap(io.ErrUnexpectedEOF, "failed to read full payload")
+		switch {
+		case err == nil:
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			// The Reader closed the connection part way through the message.
+			// The handler (if any) has already seen the partial payload fail to read,
+			// so there's nothing left to do but report the connection as broken.
+			return errors.WithMessagef(err, "connection closed mid-message for %v", hdr)
 		default:
 			return errors.Wrap(err, "failed to process response")
 		}
 	}
 }
 
+// checkVersion compares an incoming message's version to the negotiated version
+// as configured by WithVersionCheck, returning an error if the Client rejects it.
+func (c *Client) checkVersion(hdr Header) error {
+	if c.onVersionMismatch == nil && !c.rejectMismatch {
+		return nil
+	}
+
+	// The version isn't settled until negotiation finishes.
+	select {
+	case <-c.ready:
+	default:
+		return nil
+	}
+
+	if hdr.version == c.version {
+		return nil
+	}
+
+	switch hdr.typ {
+	case MsgGetSupportedVersionResponse, MsgSetProtocolVersionResponse, MsgErrorMessage:
+		return nil
+	}
+
+	if c.onVersionMismatch != nil {
+		c.onVersionMismatch(c, hdr, c.version)
+	}
+
+	if c.rejectMismatch {
+		return errors.Wrapf(ErrVersionMismatch, "%v has version %v, but the connection uses %v",
+			hdr.typ, hdr.version, c.version)
+	}
+	return nil
+}
+
 // handleOutgoing manages the write side of the connection.
 // See send for information about sending a message.
 //
@@ -749,7 +903,7 @@ func (c *Client) handleIncoming() error {
 // While the connection is open,
 // KeepAliveAck messages are prioritized.
 func (c *Client) handleOutgoing() error {
-	var nextMsgID messageID
+	nextMsgID := c.firstMsgID
 
 	for {
 		// Get the next message to send, giving priority to ACKs.
@@ -772,8 +926,7 @@ func (c *Client) handleOutgoing() error {
 				// Generate the message ID if the message doesn't have one.
 				// This assumes you'll never reply to a message with ID 0.
 				if msg.id == 0 {
-					msg.id = nextMsgID
-					nextMsgID++
+					msg.id = c.nextMessageID(&nextMsgID)
 				}
 
 				// If the reply is unwanted (or unexpected), skip setting it up.
@@ -784,8 +937,10 @@ func (c *Client) handleOutgoing() error {
 				// Give the read-side a way to correlate the response
 				// with something the sender can listen to.
 				replyChan := make(chan Message, 1)
+				replyType, _ := msg.typ.ResponseType()
 				c.awaitMu.Lock()
-				c.awaiting[msg.id] = replyChan
+				c.awaiting[msg.id] = awaiter{replyChan: replyChan, replyType: replyType,
+					reqType: msg.typ, sent: time.Now()}
 				c.awaitMu.Unlock()
 
 				// Give the sender a way to clean up
@@ -796,8 +951,8 @@ func (c *Client) handleOutgoing() error {
 					cancel: func() {
 						c.awaitMu.Lock()
 						defer c.awaitMu.Unlock()
-						if replyCh, ok := c.awaiting[mid]; ok {
-							close(replyCh)
+						if a, ok := c.awaiting[mid]; ok {
+							close(a.replyChan)
 							delete(c.awaiting, mid)
 						}
 					},
@@ -850,6 +1005,27 @@ func (c *Client) handleOutgoing() error {
 	}
 }
 
+// nextMessageID returns the next ID that isn't awaiting a reply
+// and advances next past it.
+//
+// On a long-lived connection, the IDs eventually wrap around,
+// at which point a request sent long ago may still be awaiting a reply
+// (e.g., if its sender stopped waiting, but the Reader never answered).
+// Reusing its ID would send one request's reply to the other's sender,
+// so such IDs are skipped; there are never many, so this finishes quickly.
+func (c *Client) nextMessageID(next *messageID) messageID {
+	c.awaitMu.Lock()
+	defer c.awaitMu.Unlock()
+
+	for {
+		mid := *next
+		*next++ // wraps around at the max uint32
+		if _, pending := c.awaiting[mid]; !pending {
+			return mid
+		}
+	}
+}
+
 // ackHandler acknowledges KeepAlive messages.
 //
 // If the Client's connection is hung writing for some reason,
@@ -881,28 +1057,57 @@ func (ackHandler) HandleMessage(c *Client, msg Message) {
 // otherwise it's an io.LimitedReader with the connection and payload length.
 // After the handler returns, that LimitedReader is drained to ioutil.Discard
 // to ensure the full payload has been read from the net.Conn.
+// If the connection ends before the full payload arrives,
+// this returns an error wrapping io.ErrUnexpectedEOF;
+// in that case, a handler reading the payload sees the same error,
+// so it never receives a partial message as if it were complete.
 //
 // Handlers are called via handleGuarded to protect against panics.
 // A handler blocks reads from making progress.
 func (c *Client) passToHandler(hdr Header) (err error) {
 	handler := c.handlers[hdr.typ]
 
+	// A reply only goes to the sender awaiting its ID, and only if it's the right type;
+	// a buggy Reader's stray reply shouldn't be mistaken for another request's.
 	c.awaitMu.Lock()
-	replyChan, needsReply := c.awaiting[hdr.id]
-	delete(c.awaiting, hdr.id)
+	a, needsReply := c.awaiting[hdr.id]
+	needsReply = needsReply && a.accepts(hdr.typ)
+	if needsReply {
+		delete(c.awaiting, hdr.id)
+	}
 	c.awaitMu.Unlock()
+	replyChan := a.replyChan
+
+	if !needsReply && isReply(hdr.typ) {
+		c.logger.MsgUnmatched(hdr)
+	}
 
 	if !needsReply && handler == nil && c.defaultHandler == nil {
 		c.logger.MsgUnhandled(hdr)
+		if err := checkDiscardLimit(int64(hdr.payloadLen), c.discardLimit); err != nil {
+			return errors.WithMessagef(err, "failed to discard payload for %v", hdr)
+		}
 		_, err = io.CopyN(ioutil.Discard, c.conn, int64(hdr.payloadLen))
 		return errors.Wrapf(err, "failed to discard payload for %v", hdr)
 	}
 
-	payload := io.LimitReader(c.conn, int64(hdr.payloadLen))
+	// io.Copy treats EOF as success, so we check N to know if the payload was cut short.
+	connPayload := &io.LimitedReader{R: c.conn, N: int64(hdr.payloadLen)}
+	var payload io.Reader = connPayload
 	defer func() {
 		c.logger.MsgHandled(hdr)
-		_, err = io.Copy(ioutil.Discard, payload)
-		err = errors.Wrapf(err, "failed to discard payload for %v", hdr)
+		if err != nil {
+			return
+		}
+
+		if err = checkDiscardLimit(connPayload.N, c.discardLimit); err != nil {
+			err = errors.WithMessagef(err, "failed to discard payload for %v", hdr)
+		} else if _, err = io.Copy(ioutil.Discard, connPayload); err != nil {
+			err = errors.Wrapf(err, "failed to discard payload for %v", hdr)
+		} else if connPayload.N != 0 {
+			err = errors.Wrapf(io.ErrUnexpectedEOF, "missing %d of %d payload bytes for %v",
+				connPayload.N, hdr.payloadLen, hdr)
+		}
 	}()
 
 	if needsReply {
@@ -910,8 +1115,9 @@ func (c *Client) passToHandler(hdr Header) (err error) {
 			replyChan <- Message{Header: hdr} // SendMessage will reject it
 		} else {
 			buffResponse := make([]byte, hdr.payloadLen)
-			if _, err = io.ReadFull(c.conn, buffResponse); err != nil {
-				return err
+			if _, err = io.ReadFull(connPayload, buffResponse); err != nil {
+				// Don't send a partial reply; the sender is released when the Client closes.
+				return errors.Wrapf(err, "failed to read payload for %v", hdr)
 			}
 
 			// This doesn't have to be a bytes.Buffer necessarily,
@@ -924,15 +1130,26 @@ func (c *Client) passToHandler(hdr Header) (err error) {
 		close(replyChan)
 	}
 
+	msg := Message{Header: hdr, payload: payload, discardLimit: c.discardLimit}
 	if handler != nil {
-		c.handleGuarded(handler, Message{Header: hdr, payload: payload})
+		c.handleGuarded(handler, msg)
 	} else if c.defaultHandler != nil {
-		c.handleGuarded(c.defaultHandler, Message{Header: hdr, payload: payload})
+		c.handleGuarded(c.defaultHandler, msg)
 	}
 
 	return nil
 }
 
+// checkDiscardLimit returns an error wrapping ErrDiscardLimit
+// if the limit is non-zero and there are more than that many bytes to discard.
+func checkDiscardLimit(remaining int64, limit uint32) error {
+	if limit != 0 && remaining > int64(limit) {
+		return errors.Wrapf(ErrDiscardLimit, "%d unread payload bytes exceed the limit of %d",
+			remaining, limit)
+	}
+	return nil
+}
+
 // handleGuarded recovers from panic'ing MessageHandlers.
 func (c *Client) handleGuarded(handler MessageHandler, msg Message) {
 	defer func() {
@@ -1023,21 +1240,68 @@ func (c *Client) send(ctx context.Context, m Message) (Message, error) {
 	}
 }
 
-// checkInitialMessage reads the first message off the connection,
-// which should be a ReaderEventNotification with a successful ConnectEvent.
-// If it isn't, this returns an error.
+// maxEarlyMessages is the most messages checkInitialMessage accepts
+// before the ReaderEventNotification with the ConnectionAttemptEvent.
+// It's less than the ACK queue's size, so early KeepAlives are all acknowledged.
+const maxEarlyMessages = 4
+
+// checkInitialMessage reads messages off the connection until it gets
+// a ReaderEventNotification with a ConnectionAttemptEvent,
+// which should be the first message, and returns an error unless it reports success.
+//
+// Some Readers push other notifications, KeepAlives, or reports first,
+// so this passes up to maxEarlyMessages of those to their handlers
+// (acknowledging KeepAlives once the Client starts sending)
+// rather than mistaking them for a failed connection.
+// Other messages before the ConnectionAttemptEvent are errors.
 //
-// This skips the Client's send and
+// This skips the Client's send and receive loops,
+// since they aren't running yet.
 func (c *Client) checkInitialMessage() error {
+	for early := 0; ; early++ {
+		hdr, buf, err := c.readInitialMessage()
+		if err != nil {
+			return err
+		}
+
+		if h, ok := c.handlers[hdr.typ]; ok {
+			c.handleGuarded(h, Message{Header: hdr, payload: bytes.NewBuffer(buf)})
+		}
+
+		switch hdr.typ {
+		case MsgReaderEventNotification:
+			ren := ReaderEventNotification{}
+			if err := ren.UnmarshalBinary(buf); err != nil {
+				return errors.Wrap(err, "failed to unmarshal ReaderEventNotification")
+			}
+
+			if connAttempt := ren.ReaderEventNotificationData.ConnectionAttemptEvent; connAttempt != nil {
+				return checkConnectionAttempt(ConnectionAttemptEventType(*connAttempt))
+			}
+		case MsgKeepAlive, MsgROAccessReport:
+		default:
+			return errors.Errorf("expected %v, but got %v", MsgReaderEventNotification, hdr.typ)
+		}
+
+		if early == maxEarlyMessages {
+			return errors.Errorf("reader sent %d messages without a connection attempt event",
+				maxEarlyMessages+1)
+		}
+	}
+}
+
+// readInitialMessage reads a message sent before the Client's receive loop starts
+// and returns its header and payload.
+func (c *Client) readInitialMessage() (Header, []byte, error) {
 	hdr, err := c.readHeader()
 	if err != nil {
-		return err
+		return Header{}, nil, err
 	}
 
 	c.logger.ReceivedMsg(hdr, c.version)
 
 	if hdr.payloadLen > MaxBufferedPayloadSz {
-		return errors.Errorf("initial connection message has huge size; "+
+		return Header{}, nil, errors.Errorf("initial connection message has huge size; "+
 			"it almost certainly not a valid ReaderEventNotification: %d "+
 			"(note: max buffered payload size is %d)",
 			hdr.payloadLen, MaxBufferedPayloadSz)
@@ -1045,28 +1309,14 @@ func (c *Client) checkInitialMessage() error {
 
 	buf := make([]byte, hdr.payloadLen)
 	if _, err := io.ReadFull(c.conn, buf); err != nil {
-		return errors.Wrap(err, "failed to read message payload")
-	}
-
-	if h, ok := c.handlers[hdr.typ]; ok {
-		c.handleGuarded(h, Message{Header: hdr, payload: bytes.NewBuffer(buf)})
-	}
-
-	if hdr.typ != MsgReaderEventNotification {
-		return errors.Errorf("expected %v, but got %v", MsgReaderEventNotification, hdr.typ)
-	}
-
-	ren := ReaderEventNotification{}
-	if err := ren.UnmarshalBinary(buf); err != nil {
-		return errors.Wrap(err, "failed to unmarshal ReaderEventNotification")
-	}
-
-	connAttempt := ren.ReaderEventNotificationData.ConnectionAttemptEvent
-	if connAttempt == nil {
-		return errors.Errorf("initial reader event did not include connection attempt: %v", ren)
+		return Header{}, nil, errors.Wrap(err, "failed to read message payload")
 	}
+	return hdr, buf, nil
+}
 
-	switch ConnectionAttemptEventType(*connAttempt) {
+// checkConnectionAttempt returns an error unless the event reports a successful connection.
+func checkConnectionAttempt(event ConnectionAttemptEventType) error {
+	switch event {
 	case ConnSuccess:
 		return nil
 	case ConnExistsClientInitiated, ConnExistsReaderInitiated:
@@ -12,10 +12,12 @@ import (
 	"github.com/pkg/errors"
 	"io"
 	"io/ioutil"
+	"math"
 	"math/rand"
 	"net"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -147,6 +149,26 @@ func TestClient_newMessage(t *testing.T) {
 	}
 }
 
+func TestNewByteMessage_invalidType(t *testing.T) {
+	for _, mt := range []MessageType{msgResvStart, msgResvEnd, maxMsgType + 1} {
+		if _, err := NewByteMessage(mt, nil); err == nil {
+			t.Errorf("expected an error for message type %d, but didn't get one", mt)
+		}
+
+		if _, err := NewByteMessage(mt, []byte{1, 2, 3}); err == nil {
+			t.Errorf("expected an error for message type %d, but didn't get one", mt)
+		}
+	}
+
+	m, err := NewByteMessage(MsgGetReport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != NewHdrOnlyMsg(MsgGetReport) {
+		t.Errorf("expected a header-only message; got %+v", m)
+	}
+}
+
 // dummyRead reads a header into h and discards the payload, if present.
 func dummyRead(h *Header, rfid net.Conn) error {
 	buf := make([]byte, HeaderSz)
@@ -341,6 +363,426 @@ func TestClient_Connection(t *testing.T) {
 	}
 }
 
+func TestClient_ClosedMidReport(t *testing.T) {
+	client, rfid := net.Pipe()
+
+	// The handler must see the truncated report fail to unmarshal.
+	reports := make(chan error, 1)
+	opts := []ClientOpt{
+		WithVersion(Version1_0_1),
+		WithTimeout(3 * time.Second),
+		WithMessageHandler(MsgROAccessReport, MessageHandlerFunc(func(_ *Client, msg Message) {
+			reports <- msg.UnmarshalTo(&ROAccessReport{})
+		})),
+	}
+
+	if !testing.Verbose() {
+		opts = append(opts, WithLogger(nil))
+	}
+
+	c := NewClient(opts...)
+
+	connErrs := make(chan error, 1)
+	go func() {
+		defer close(connErrs)
+		connErrs <- c.Connect(client)
+	}()
+
+	// The Reader promises a large report, then drops the connection part way through.
+	rfidErrs := make(chan error, 1)
+	go func() {
+		defer close(rfidErrs)
+		defer rfid.Close()
+
+		if err := connectSuccess(nil, rfid); err != nil {
+			rfidErrs <- err
+			return
+		}
+
+		hdr := Header{version: Version1_0_1, typ: MsgROAccessReport, payloadLen: 1000}
+		data, err := hdr.MarshalBinary()
+		if err != nil {
+			rfidErrs <- err
+			return
+		}
+
+		if _, err := rfid.Write(data); err != nil {
+			rfidErrs <- err
+			return
+		}
+
+		_, err = rfid.Write(make([]byte, 100))
+		rfidErrs <- err
+	}()
+
+	select {
+	case err := <-reports:
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("expected the partial report to fail with %v; got %+v", io.ErrUnexpectedEOF, err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("handler never received the report")
+	}
+
+	err := <-connErrs
+	if errors.Is(err, ErrClientClosed) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected connection error wrapping %v; got %+v", io.ErrUnexpectedEOF, err)
+	}
+
+	if err := c.Close(); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("expected %q; got %+v", ErrClientClosed, err)
+	}
+
+	for err := range rfidErrs {
+		if err != nil {
+			t.Errorf("RFID reader error: %+v", err)
+		}
+	}
+}
+
+func TestClient_earlyMessages(t *testing.T) {
+	td, err := NewTestDevice(Version1_1, Version1_1, time.Second, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notifications := make(chan ReaderEventNotificationData, 5)
+	WithMessageHandler(MsgReaderEventNotification, MessageHandlerFunc(func(_ *Client, msg Message) {
+		ren := ReaderEventNotification{}
+		if err := msg.UnmarshalTo(&ren); err != nil {
+			t.Error(err)
+		}
+		notifications <- ren.ReaderEventNotificationData
+	})).do(td.Client)
+
+	acks := make(chan messageID, 1)
+	td.reader.handlers[MsgKeepAliveAck] = MessageHandlerFunc(func(_ *Client, msg Message) {
+		acks <- msg.id
+	})
+
+	// This Reader sends a notification and a KeepAlive before the ConnectionAttemptEvent,
+	// then another notification with the GetSupportedVersion request's ID.
+	antennaEvent := func() *ReaderEventNotification {
+		return &ReaderEventNotification{ReaderEventNotificationData: ReaderEventNotificationData{
+			UTCTimestamp: UTCTimestamp(time.Now().UnixNano() / 1000),
+			AntennaEvent: &AntennaEvent{Event: AntennaConnected, AntennaID: 1},
+		}}
+	}
+	td.reader.handlers[MsgGetSupportedVersion] = MessageHandlerFunc(func(c *Client, msg Message) {
+		td.write(msg.id, antennaEvent())
+		td.getSupportedVersion(c, msg)
+	})
+	td.SetResponse(MsgGetReaderConfig, &GetReaderConfigResponse{})
+
+	c := td.ConnectClient(t)
+	go func() {
+		td.write(100, antennaEvent())
+		td.write(101, &KeepAlive{})
+		td.ImpersonateReader()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	// The request only succeeds if version negotiation completed.
+	if err := c.SendFor(ctx, &GetReaderConfig{}, &GetReaderConfigResponse{}); err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	select {
+	case mid := <-acks:
+		if mid != 101 {
+			t.Errorf("expected to ACK KeepAlive 101; got %d", mid)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the early KeepAlive to be ACK'd")
+	}
+
+	var antennaEvents, connEvents int
+	for len(notifications) > 0 {
+		data := <-notifications
+		if data.AntennaEvent != nil {
+			antennaEvents++
+		}
+		if data.ConnectionAttemptEvent != nil {
+			connEvents++
+		}
+	}
+	if antennaEvents != 2 || connEvents != 1 {
+		t.Errorf("expected 2 antenna events and 1 connection event; got %d and %d",
+			antennaEvents, connEvents)
+	}
+}
+
+func TestClient_checkInitialMessage(t *testing.T) {
+	for _, testCase := range []struct {
+		name string
+		msgs []Outgoing
+		err  bool
+	}{
+		{name: "connected", msgs: []Outgoing{NewConnectMessage(ConnSuccess)}},
+		{name: "alreadyConnected", msgs: []Outgoing{NewConnectMessage(ConnExistsClientInitiated)}, err: true},
+		{name: "early", msgs: []Outgoing{&KeepAlive{}, &ROAccessReport{}, NewConnectMessage(ConnSuccess)}},
+		{name: "unexpected", msgs: []Outgoing{&GetReaderConfigResponse{}, NewConnectMessage(ConnSuccess)}, err: true},
+		{name: "tooMany", msgs: []Outgoing{&KeepAlive{}, &KeepAlive{}, &KeepAlive{}, &KeepAlive{}, &KeepAlive{},
+			NewConnectMessage(ConnSuccess)}, err: true},
+	} {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			client, rfid := net.Pipe()
+			defer client.Close()
+			defer rfid.Close()
+
+			c := NewClient(WithLogger(nil))
+			c.conn = client
+
+			go func() {
+				w := newMsgWriter(rfid, Version1_0_1)
+				for i, m := range testCase.msgs {
+					if err := w.Write(messageID(i), m); err != nil {
+						return
+					}
+				}
+			}()
+
+			if err := c.checkInitialMessage(); (err != nil) != testCase.err {
+				t.Errorf("expected error: %v; got %+v", testCase.err, err)
+			}
+		})
+	}
+}
+
+func TestClient_VersionCheck(t *testing.T) {
+	type mismatch struct {
+		typ        MessageType
+		version    VersionNum
+		negotiated VersionNum
+	}
+
+	// newDevice returns a TestDevice that negotiates v1.0.1,
+	// but then replies to GetReaderConfig with v1.1.
+	newDevice := func(t *testing.T, reject bool) (*TestDevice, chan mismatch) {
+		td, err := NewTestDevice(Version1_1, Version1_1, time.Second, !testing.Verbose())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mismatches := make(chan mismatch, 5)
+		WithVersionCheck(reject, func(_ *Client, hdr Header, negotiated VersionNum) {
+			mismatches <- mismatch{hdr.Type(), hdr.Version(), negotiated}
+		}).do(td.Client)
+
+		stale := newMsgWriter(td.rConn, Version1_1)
+		td.reader.handlers[MsgGetReaderConfig] = MessageHandlerFunc(func(_ *Client, msg Message) {
+			if td.errCheck(msg.UnmarshalTo(&GetReaderConfig{})) {
+				return
+			}
+			_ = td.errCheck(stale.Write(msg.id, &GetReaderConfigResponse{}))
+		})
+		td.SetResponse(MsgGetReaderCapabilities, &GetReaderCapabilitiesResponse{})
+
+		go td.ImpersonateReader()
+		return td, mismatches
+	}
+
+	expectMismatch := func(t *testing.T, mismatches chan mismatch) {
+		t.Helper()
+		select {
+		case m := <-mismatches:
+			want := mismatch{MsgGetReaderConfigResponse, Version1_1, Version1_0_1}
+			if m != want {
+				t.Errorf("expected %+v; got %+v", want, m)
+			}
+		default:
+			t.Error("expected a version mismatch")
+		}
+	}
+
+	t.Run("accept", func(t *testing.T) {
+		td, mismatches := newDevice(t, false)
+		c := td.ConnectClient(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		// Negotiating the version and matching replies don't count as mismatches.
+		if err := c.SendFor(ctx, &GetReaderCapabilities{}, &GetReaderCapabilitiesResponse{}); err != nil {
+			t.Fatal(err)
+		}
+		if len(mismatches) != 0 {
+			t.Errorf("expected no mismatches; got %+v", <-mismatches)
+		}
+
+		// Mismatched messages are still processed.
+		if err := c.SendFor(ctx, &GetReaderConfig{}, &GetReaderConfigResponse{}); err != nil {
+			t.Fatal(err)
+		}
+		expectMismatch(t, mismatches)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		td, mismatches := newDevice(t, true)
+		defer td.rConn.Close()
+
+		connErrs := make(chan error, 1)
+		go func() {
+			connErrs <- td.Client.Connect(td.cConn)
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		if err := td.Client.SendFor(ctx, &GetReaderConfig{}, &GetReaderConfigResponse{}); !errors.Is(err, ErrClientClosed) {
+			t.Errorf("expected the mismatched reply to be rejected; got %+v", err)
+		}
+		expectMismatch(t, mismatches)
+
+		if err := <-connErrs; !errors.Is(err, ErrVersionMismatch) {
+			t.Errorf("expected connection error wrapping %v; got %+v", ErrVersionMismatch, err)
+		}
+	})
+}
+
+// unmatchedLogger records the headers of unmatched replies.
+type unmatchedLogger struct {
+	devNullLogger
+	unmatched chan Header
+}
+
+func (l unmatchedLogger) MsgUnmatched(hdr Header) {
+	l.unmatched <- hdr
+}
+
+func TestClient_unmatchedReply(t *testing.T) {
+	td, err := NewTestDevice(Version1_0_1, Version1_1, time.Second, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := unmatchedLogger{unmatched: make(chan Header, 5)}
+	WithLogger(logger).do(td.Client)
+
+	// This Reader replies to GetReaderConfig with the wrong ID,
+	// and to GetReaderCapabilities with the ID of the outstanding GetReaderConfig.
+	configIDs := make(chan messageID, 1)
+	td.reader.handlers[MsgGetReaderConfig] = MessageHandlerFunc(func(_ *Client, msg Message) {
+		if td.errCheck(msg.UnmarshalTo(&GetReaderConfig{})) {
+			return
+		}
+		configIDs <- msg.id
+		td.write(msg.id+1000, &GetReaderConfigResponse{})
+	})
+	td.reader.handlers[MsgGetReaderCapabilities] = MessageHandlerFunc(func(_ *Client, msg Message) {
+		if td.errCheck(msg.UnmarshalTo(&GetReaderCapabilities{})) {
+			return
+		}
+		td.write(<-configIDs, &GetReaderCapabilitiesResponse{})
+	})
+
+	go td.ImpersonateReader()
+	c := td.ConnectClient(t)
+
+	expectUnmatched := func(typ MessageType) {
+		t.Helper()
+		select {
+		case hdr := <-logger.unmatched:
+			if hdr.Type() != typ {
+				t.Errorf("expected an unmatched %v; got %v", typ, hdr)
+			}
+		case <-time.After(3 * time.Second):
+			t.Errorf("expected an unmatched %v", typ)
+		}
+	}
+
+	configErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		configErr <- c.SendFor(ctx, &GetReaderConfig{}, &GetReaderConfigResponse{})
+	}()
+	expectUnmatched(MsgGetReaderConfigResponse)
+
+	// The reply has GetReaderConfig's ID, but the wrong type,
+	// so neither sender gets it.
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := c.SendFor(ctx, &GetReaderCapabilities{}, &GetReaderCapabilitiesResponse{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the capabilities request to time out; got %+v", err)
+	}
+	expectUnmatched(MsgGetReaderCapabilitiesResponse)
+
+	if err := <-configErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the config request to time out; got %+v", err)
+	}
+}
+
+func TestClient_nextMessageID(t *testing.T) {
+	c := NewClient(WithMessageIDStart(math.MaxUint32-1), WithLogger(nil))
+	if c.firstMsgID != math.MaxUint32-1 {
+		t.Fatalf("expected first ID %d; got %d", uint32(math.MaxUint32-1), c.firstMsgID)
+	}
+
+	// Requests sent long ago with IDs 0 and 2 are still outstanding.
+	c.awaiting[0] = awaiter{replyChan: make(chan Message, 1)}
+	c.awaiting[2] = awaiter{replyChan: make(chan Message, 1)}
+
+	next := c.firstMsgID
+	var got []messageID
+	for i := 0; i < 4; i++ {
+		got = append(got, c.nextMessageID(&next))
+	}
+
+	expected := []messageID{math.MaxUint32 - 1, math.MaxUint32, 1, 3}
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected IDs %v; got %v", expected, got)
+	}
+}
+
+func TestClient_PendingRequests(t *testing.T) {
+	td, err := NewTestDevice(Version1_0_1, Version1_0_1, 2*time.Second, !testing.Verbose())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The Reader doesn't reply to GetReaderConfig until it's released.
+	release := make(chan struct{})
+	td.SetResponseFunc(MsgGetReaderConfig, func(Message) Outgoing {
+		<-release
+		return &GetReaderConfigResponse{}
+	})
+
+	go td.ImpersonateReader()
+	c := td.ConnectClient(t)
+
+	if pending := c.PendingRequests(); pending != nil {
+		t.Fatalf("expected no pending requests; got %+v", pending)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	sent := time.Now()
+	configErr := make(chan error, 1)
+	go func() {
+		configErr <- c.SendFor(ctx, &GetReaderConfig{}, &GetReaderConfigResponse{})
+	}()
+
+	var pending []PendingRequest
+	for len(pending) == 0 && ctx.Err() == nil {
+		time.Sleep(10 * time.Millisecond)
+		pending = c.PendingRequests()
+	}
+	if len(pending) != 1 || pending[0].Type != MsgGetReaderConfig || pending[0].Sent.Before(sent) {
+		t.Errorf("expected the GetReaderConfig request to be pending; got %+v", pending)
+	}
+
+	close(release)
+	if err := <-configErr; err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if pending := c.PendingRequests(); pending != nil {
+		t.Errorf("expected no pending requests after the reply; got %+v", pending)
+	}
+}
+
 func TestClient_ManySenders(t *testing.T) {
 	client, rfid := net.Pipe()
 	if err := client.SetDeadline(time.Now().Add(3 * time.Second)); err != nil {
@@ -513,3 +955,108 @@ func BenchmarkReader_ManySenders(b *testing.B) {
 	}
 
 }
+
+func TestClient_discardLimit(t *testing.T) {
+	// The connection attempt fits within the limit, but the large notification doesn't.
+	const limit = 128
+	large := &ReaderEventNotification{ReaderEventNotificationData: ReaderEventNotificationData{
+		UTCTimestamp:         1600000000000000,
+		ReaderExceptionEvent: &ReaderExceptionEvent{Message: strings.Repeat("x", 4*limit)},
+	}}
+
+	// connect starts the TestDevice's Client with the discard limit and the handler
+	// and returns a channel with Connect's result, once its connection is ready.
+	connect := func(t *testing.T, handler MessageHandler) (*TestDevice, chan error) {
+		t.Helper()
+		td, err := NewTestDevice(Version1_0_1, Version1_1, time.Second, !testing.Verbose())
+		if err != nil {
+			t.Fatal(err)
+		}
+		WithDiscardLimit(limit).do(td.Client)
+		td.SetResponse(MsgGetReaderConfig, &GetReaderConfigResponse{})
+		if handler != nil {
+			td.Client.handlers[MsgReaderEventNotification] = handler
+		}
+
+		connErrs := make(chan error, 1)
+		go func() {
+			connErrs <- td.Client.Connect(td.cConn)
+		}()
+		go td.ImpersonateReader()
+
+		select {
+		case <-td.Client.ready:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the Client to connect")
+		}
+		return td, connErrs
+	}
+
+	// expectConnErr waits for the connection to end with ErrDiscardLimit,
+	// then closes it, as the Client's user would.
+	expectConnErr := func(t *testing.T, td *TestDevice, connErrs chan error) {
+		t.Helper()
+		select {
+		case err := <-connErrs:
+			if !errors.Is(err, ErrDiscardLimit) {
+				t.Errorf("expected connection error wrapping %v; got %+v", ErrDiscardLimit, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Error("expected the connection to end")
+		}
+		td.cConn.Close()
+		td.rConn.Close()
+	}
+
+	t.Run("handlerClose", func(t *testing.T) {
+		closeErrs := make(chan error, 1)
+		td, connErrs := connect(t, MessageHandlerFunc(func(_ *Client, msg Message) {
+			if err := msg.Close(); err != nil {
+				closeErrs <- err
+			}
+		}))
+
+		// The Client stops reading partway through, so this doesn't finish.
+		go td.write(100, large)
+
+		if err := <-closeErrs; !errors.Is(err, ErrDiscardLimit) {
+			t.Errorf("expected Close to return an error wrapping %v; got %+v", ErrDiscardLimit, err)
+		}
+		expectConnErr(t, td, connErrs)
+	})
+
+	t.Run("unhandled", func(t *testing.T) {
+		td, connErrs := connect(t, nil)
+		go td.write(100, large)
+		expectConnErr(t, td, connErrs)
+	})
+
+	t.Run("withinLimit", func(t *testing.T) {
+		handled := make(chan struct{}, 1)
+		td, connErrs := connect(t, MessageHandlerFunc(func(_ *Client, msg Message) {
+			if err := msg.Close(); err != nil {
+				t.Errorf("%+v", err)
+			}
+			handled <- struct{}{}
+		}))
+
+		td.write(100, &ReaderEventNotification{ReaderEventNotificationData: ReaderEventNotificationData{
+			UTCTimestamp: 1600000000000000,
+			AntennaEvent: &AntennaEvent{Event: AntennaDisconnected, AntennaID: 2},
+		}})
+		<-handled
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := td.Client.SendFor(ctx, &GetReaderConfig{}, &GetReaderConfigResponse{}); err != nil {
+			t.Errorf("expected the connection to remain usable; got %+v", err)
+		}
+
+		if err := td.Client.Close(); err != nil {
+			t.Error(err)
+		}
+		if err := <-connErrs; !errors.Is(err, ErrClientClosed) {
+			t.Errorf("expected %v; got %+v", ErrClientClosed, err)
+		}
+	})
+}
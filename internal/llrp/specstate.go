@@ -0,0 +1,26 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate stringer -type=ROSpecCurrentStateType -trimprefix=ROSpecState -output=rospecstate_string.go
+//go:generate stringer -type=AccessSpecState -trimprefix=AccessSpecState -output=accessspecstate_string.go
+
+package llrp
+
+// AccessSpecState is an AccessSpec's CurrentState.
+// LLRP encodes it as a single bit, which AccessSpec holds as IsActive.
+type AccessSpecState uint8
+
+const (
+	AccessSpecStateDisabled = AccessSpecState(0)
+	AccessSpecStateActive   = AccessSpecState(1)
+)
+
+// State returns the AccessSpec's IsActive as an AccessSpecState.
+func (as *AccessSpec) State() AccessSpecState {
+	if as.IsActive {
+		return AccessSpecStateActive
+	}
+	return AccessSpecStateDisabled
+}
@@ -76,6 +76,21 @@ func (emu *TestEmulator) Shutdown() error {
 	return nil
 }
 
+// SendCloseEvents sends CloseMessage to all currently connected clients,
+// but leaves their connections open.
+func (emu *TestEmulator) SendCloseEvents() error {
+	emu.devicesMu.Lock()
+	defer emu.devicesMu.Unlock()
+
+	for dev := range emu.devices {
+		if err := dev.SendCloseEvent(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // listenUntilCancelled listens forever on the net.Listener until emu.Shutdown() is called
 func (emu *TestEmulator) listenUntilCancelled() {
 	for {
@@ -109,6 +124,18 @@ func (emu *TestEmulator) handleNewConn(conn net.Conn) {
 	emu.devicesMu.Unlock()
 
 	td.ImpersonateReader()
+
+	// The connection has ended, whether or not the client closed it gracefully.
+	emu.devicesMu.Lock()
+	delete(emu.devices, td)
+	emu.devicesMu.Unlock()
+}
+
+// NumConnections returns the number of clients currently connected to the emulator.
+func (emu *TestEmulator) NumConnections() int {
+	emu.devicesMu.Lock()
+	defer emu.devicesMu.Unlock()
+	return len(emu.devices)
 }
 
 // SetResponse adds a canned response to all future clients. Optionally,
@@ -151,6 +151,18 @@ func (td *TestDevice) SetResponse(mt MessageType, out Outgoing) {
 	})
 }
 
+// SetResponseFunc is like SetResponse,
+// but calls f with each message of the given type to get its response,
+// so tests can inspect the messages the Reader receives or vary its replies.
+func (td *TestDevice) SetResponseFunc(mt MessageType, f func(msg Message) Outgoing) {
+	td.reader.handlers[mt] = MessageHandlerFunc(func(_ *Client, msg Message) {
+		if td.wrongVersion(msg) {
+			return
+		}
+		td.write(msg.id, f(msg))
+	})
+}
+
 // Errors returns accumulated errors.
 // It should only be called after the TestDevice is closed.
 func (td *TestDevice) Errors() []error {
@@ -291,6 +303,14 @@ func (td *TestDevice) Close() (err error) {
 	return
 }
 
+// SendCloseEvent sends CloseMessage, as a Reader does just before closing the connection,
+// but leaves the connection open, so the Client has to close it.
+func (td *TestDevice) SendCloseEvent() error {
+	return td.w.Write(
+		messageID(atomic.AddUint32((*uint32)(&td.mid), 1)),
+		NewCloseMessage())
+}
+
 // closeConnection handles a client request to close the connection.
 func (td *TestDevice) closeConnection(_ *Client, msg Message) {
 	if td.wrongVersion(msg) {
@@ -305,7 +325,7 @@ func (td *TestDevice) closeConnection(_ *Client, msg Message) {
 // ConnectClient correctly connects the Client to the TestDevice and returns it.
 // It registers a Cleanup function to Shutdown the Client and report errors
 // once the test is completed, so it is not necessary to do so yourself.
-func (td *TestDevice) ConnectClient(t *testing.T) (c *Client) {
+func (td *TestDevice) ConnectClient(t testing.TB) (c *Client) {
 	c = td.Client
 
 	connErrs := make(chan error)
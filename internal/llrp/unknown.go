@@ -0,0 +1,168 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package llrp
+
+import (
+	"encoding"
+	"encoding/binary"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// UnknownParam is a parameter of a type this package doesn't know,
+// such as one added by newer Reader firmware.
+type UnknownParam struct {
+	Type   ParamType
+	Parent string // name of the message or parameter that contained it
+	Data   []byte // the parameter's encoding, including its header
+}
+
+// tvParamLengths are the encoded lengths of TV parameters, including their 1 byte header.
+// Unlike TLV parameters, they don't encode their lengths,
+// so skipping past one requires knowing its type.
+var tvParamLengths = map[ParamType]int{
+	ParamAntennaID:                 3,
+	ParamFirstSeenUTC:              9,
+	ParamFirstSeenUptime:           9,
+	ParamLastSeenUTC:               9,
+	ParamLastSeenUptime:            9,
+	ParamPeakRSSI:                  2,
+	ParamChannelIndex:              3,
+	ParamTagSeenCount:              3,
+	ParamROSpecID:                  5,
+	ParamInventoryParameterSpecID:  3,
+	ParamC1G2CRC:                   3,
+	ParamC1G2PC:                    3,
+	ParamEPC96:                     13,
+	ParamSpecIndex:                 3,
+	ParamClientRequestOpSpecResult: 3,
+	ParamAccessSpecID:              5,
+	ParamOpSpecID:                  3,
+	ParamC1G2SingulationDetails:    5,
+	ParamC1G2XPCW1:                 3,
+	ParamC1G2XPCW2:                 3,
+}
+
+// paramListMessages and paramListParams are the messages and parameters
+// whose bodies are only lists of parameters,
+// so unknown parameters can be found among them without knowing their layouts.
+var (
+	paramListMessages = map[MessageType]bool{
+		MsgROAccessReport:          true,
+		MsgReaderEventNotification: true,
+	}
+
+	paramListParams = map[ParamType]bool{
+		ParamTagReportData:               true,
+		ParamRFSurveyReportData:          true,
+		ParamReaderEventNotificationData: true,
+	}
+)
+
+// UnmarshalSkippingUnknown is like UnmarshalTo,
+// but first removes TLV parameters of types this package doesn't know,
+// returning them so the caller can log or retain them.
+//
+// It only looks for them in ROAccessReports and ReaderEventNotifications,
+// within their TagReportData, RFSurveyReportData, and ReaderEventNotificationData,
+// whose contents are lists of parameters.
+// Other messages are unmarshaled as UnmarshalTo does.
+// Since TV parameters don't encode their lengths, unknown TV parameters are still an error.
+func (m *Message) UnmarshalSkippingUnknown(v encoding.BinaryUnmarshaler) ([]UnknownParam, error) {
+	data, err := m.data()
+	if err != nil {
+		return nil, err
+	}
+
+	if !paramListMessages[m.typ] {
+		return nil, v.UnmarshalBinary(data)
+	}
+
+	known, unknown, err := stripUnknownParams(strings.TrimPrefix(m.typ.String(), "Msg"), data)
+	if err != nil {
+		return nil, err
+	}
+
+	return unknown, v.UnmarshalBinary(known)
+}
+
+// stripUnknownParams returns a copy of a list of encoded parameters
+// without the TLV parameters of types this package doesn't know,
+// recursively removing them from parameters in paramListParams,
+// and returns the removed parameters.
+func stripUnknownParams(parent string, data []byte) ([]byte, []UnknownParam, error) {
+	known := make([]byte, 0, len(data))
+	var unknown []UnknownParam
+
+	for len(data) > 0 {
+		if data[0]&0x80 != 0 {
+			pt := ParamType(data[0] & 0x7F)
+			n, ok := tvParamLengths[pt]
+			if !ok {
+				return nil, nil, errors.Errorf("%s has an unknown TV parameter, %v, "+
+					"which can't be skipped since its length is unknown", parent, pt)
+			}
+			if n > len(data) {
+				return nil, nil, errors.Errorf("%v needs %d bytes, but only %d bytes remain",
+					pt, n, len(data))
+			}
+
+			known = append(known, data[:n]...)
+			data = data[n:]
+			continue
+		}
+
+		if len(data) < 4 {
+			return nil, nil, errors.Errorf("expecting a TLV header in %s, but %d < 4 bytes remain",
+				parent, len(data))
+		}
+
+		pt := ParamType(binary.BigEndian.Uint16(data) & 0x3FF)
+		n := int(binary.BigEndian.Uint16(data[2:]))
+		if n < 4 {
+			return nil, nil, errors.Errorf("%v says it has %d bytes, "+
+				"which is less than its 4 byte header", pt, n)
+		} else if n > len(data) {
+			return nil, nil, errors.Errorf("%v says it has %d bytes, but only %d bytes remain",
+				pt, n, len(data))
+		}
+
+		param := data[:n]
+		data = data[n:]
+
+		switch {
+		case !pt.isKnown():
+			unknown = append(unknown, UnknownParam{
+				Type:   pt,
+				Parent: parent,
+				Data:   append([]byte(nil), param...),
+			})
+
+		case paramListParams[pt]:
+			body, sub, err := stripUnknownParams(pt.name(), param[4:])
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(sub) == 0 {
+				known = append(known, param...)
+				continue
+			}
+
+			if len(body)+4 > len(param) {
+				panic("stripping parameters shouldn't lengthen them")
+			}
+			known = append(known, param[0], param[1], 0, 0)
+			binary.BigEndian.PutUint16(known[len(known)-2:], uint16(len(body)+4))
+			known = append(known, body...)
+			unknown = append(unknown, sub...)
+
+		default:
+			known = append(known, param...)
+		}
+	}
+
+	return known, unknown, nil
+}
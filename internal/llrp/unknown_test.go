@@ -0,0 +1,120 @@
+//
+// Copyright (C) 2020 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package llrp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// unknownTLV is a TLV parameter of a type this package doesn't know.
+var unknownTLV = []byte{0x03, 0xE8, 0x00, 0x06, 0xAB, 0xCD} // type 1000, 6 bytes
+
+// reportWithUnknown returns an encoded ROAccessReport with one TagReportData,
+// with unknownTLV injected into the TagReportData and after it.
+func reportWithUnknown(t *testing.T) []byte {
+	t.Helper()
+
+	ro := ROAccessReport{TagReportData: []TagReportData{{
+		EPC96:     EPC96{EPC: []byte{0x30, 0x08, 0x33, 0xB2, 0xDD, 0xD9, 0x01, 0x40, 0, 0, 0, 1}},
+		AntennaID: func() *AntennaID { a := AntennaID(1); return &a }(),
+	}}}
+
+	data, err := ro.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	// Grow the TagReportData's length and insert the unknown parameter at its end.
+	trdLen := binary.BigEndian.Uint16(data[2:])
+	binary.BigEndian.PutUint16(data[2:], trdLen+uint16(len(unknownTLV)))
+
+	withUnknown := append([]byte{}, data[:trdLen]...)
+	withUnknown = append(withUnknown, unknownTLV...)
+	withUnknown = append(withUnknown, data[trdLen:]...)
+	return append(withUnknown, unknownTLV...)
+}
+
+func TestMessage_UnmarshalSkippingUnknown(t *testing.T) {
+	data := reportWithUnknown(t)
+
+	m, err := NewByteMessage(MsgROAccessReport, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.UnmarshalTo(&ROAccessReport{}); err == nil {
+		t.Fatal("expected an error unmarshaling unknown parameters")
+	}
+
+	m, err = NewByteMessage(MsgROAccessReport, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ro := ROAccessReport{}
+	unknown, err := m.UnmarshalSkippingUnknown(&ro)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+
+	if len(ro.TagReportData) != 1 || ro.TagReportData[0].AntennaID == nil ||
+		*ro.TagReportData[0].AntennaID != 1 {
+		t.Errorf("expected the known parameters to survive; got %+v", ro)
+	}
+
+	if len(unknown) != 2 {
+		t.Fatalf("expected 2 unknown parameters; got %+v", unknown)
+	}
+	for i, parent := range []string{"TagReportData", "ROAccessReport"} {
+		u := unknown[i]
+		if u.Type != 1000 || u.Parent != parent || !bytes.Equal(u.Data, unknownTLV) {
+			t.Errorf("expected type 1000 in %s with data %x; got %+v", parent, unknownTLV, u)
+		}
+	}
+}
+
+func TestStripUnknownParams(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"unknown TV", []byte{0xFF, 0x00}},
+		{"truncated TV", []byte{0x81, 0x00}},
+		{"truncated header", []byte{0x03, 0xE8}},
+		{"short length", []byte{0x03, 0xE8, 0x00, 0x02}},
+		{"long length", []byte{0x03, 0xE8, 0x00, 0x10, 0x00}},
+		{"bad nested", []byte{0x00, 0xF0, 0x00, 0x06, 0xFF, 0x00}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := stripUnknownParams("test", tc.data); err == nil {
+				t.Errorf("expected an error for %x", tc.data)
+			}
+		})
+	}
+
+	// Lists without unknown parameters are unchanged.
+	data := []byte{0x81, 0x00, 0x01, 0x00, 0xF0, 0x00, 0x07, 0x81, 0x00, 0x02}
+	known, unknown, err := stripUnknownParams("test", data)
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+	if len(unknown) != 0 || !bytes.Equal(known, data) {
+		t.Errorf("expected %x and no unknowns; got %x and %+v", data, known, unknown)
+	}
+}
+
+func TestMessage_UnmarshalSkippingUnknown_otherMessages(t *testing.T) {
+	m, err := NewByteMessage(MsgCloseConnectionResponse, unknownTLV)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only parameter lists are searched, so this fails the usual way.
+	if _, err := m.UnmarshalSkippingUnknown(&CloseConnectionResponse{}); err == nil {
+		t.Error("expected an error for an unknown parameter in another message")
+	}
+}
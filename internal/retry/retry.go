@@ -237,6 +237,28 @@ func (e *FError) Is(target error) bool {
 	return true
 }
 
+// As finds the first error in MainErr's chain that matches target,
+// or failing that, the first in the chain of the most recent error in Others,
+// as determined by errors.As.
+// If it finds one, it sets target to that error and returns true.
+//
+// Unlike Is, this doesn't require all the Others to match,
+// so callers can inspect why the final attempt failed.
+func (e *FError) As(target interface{}) bool {
+	if errors.As(e.MainErr, target) {
+		return true
+	}
+
+	if len(e.Others) == 0 {
+		return false
+	}
+
+	// e.last is the index the next error will replace,
+	// so the most recent error is just before it.
+	latest := (e.last + len(e.Others) - 1) % len(e.Others)
+	return errors.As(e.Others[latest], target)
+}
+
 // Retry attempts f up to retries number of times until it returns nil.
 //
 // If the program receives SIGINT or SIGKILL, the retries are canceled;
@@ -156,6 +156,37 @@ func TestFError(t *testing.T) {
 	}
 }
 
+func TestFError_As(t *testing.T) {
+	ebo := ExpBackOff{KeepErrs: 2}
+
+	// The final attempt fails differently than the others.
+	attempts := 0
+	err := ebo.Retry(3, func() error {
+		attempts++
+		if attempts == 3 {
+			return errors.Wrap(&errType{e: "final error"}, "attempt failed")
+		}
+		return errors.New("some error")
+	})
+
+	var et *errType
+	if !errors.As(err, &et) {
+		t.Fatalf("expected errors.As(*errType) to succeed for %v", err)
+	}
+	if et.e != "final error" {
+		t.Errorf("expected the final attempt's error; got %q", et.e)
+	}
+
+	// Only the most recent error is considered.
+	fe := &FError{
+		MainErr: ErrRetriesExceeded,
+		Others:  []error{&errType{e: "first error"}, errors.New("second error")},
+	}
+	if errors.As(fe, &et) {
+		t.Errorf("expected errors.As(*errType) to fail; got %q", et.e)
+	}
+}
+
 func TestExpBackOff_RetryWithCtx(t *testing.T) {
 	const tests, callsPerTest = 20, 10
 	ebo := ExpBackOff{}